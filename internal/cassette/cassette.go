@@ -0,0 +1,413 @@
+// Package cassette records Notion API traffic to disk and replays it later
+// without a network connection.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secrets in recorded interactions.
+const Redacted = "REDACTED"
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded side of an outgoing request.
+type Request struct {
+	Method string `json:"method"`
+	// URL is the request path and query; the host is not recorded so a
+	// cassette replays regardless of the configured base URL.
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response is the recorded side of an API response.
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Body holds a payload. JSON objects and arrays are stored as-is so
+// cassettes stay readable; anything else is stored as a string.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte("null"), nil
+	}
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(b) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, b); err == nil {
+			return buf.Bytes(), nil
+		}
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*b = nil
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// redactedHeaders are replaced wholesale when recording.
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+var (
+	// secretFieldPattern matches JSON fields that carry credentials.
+	secretFieldPattern = regexp.MustCompile(`("(?:access_token|refresh_token|client_secret)"\s*:\s*)"[^"]*"`)
+	// tokenPattern matches Notion integration tokens anywhere in a payload.
+	tokenPattern = regexp.MustCompile(`\b(?:secret|ntn)_[A-Za-z0-9]{20,}`)
+)
+
+// Recorder saves every request/response pair that passes through the
+// transports it wraps into a cassette directory, one numbered file per
+// interaction. Secrets are redacted before anything is written.
+type Recorder struct {
+	dir  string
+	mu   sync.Mutex
+	next int
+}
+
+// NewRecorder creates a recorder writing to dir. Recording into a directory
+// that already holds interactions appends to it.
+func NewRecorder(dir string) *Recorder {
+	return &Recorder{dir: dir}
+}
+
+// Dir returns the cassette directory.
+func (r *Recorder) Dir() string {
+	return r.dir
+}
+
+// Wrap returns a transport that records traffic sent through base.
+func (r *Recorder) Wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordingTransport{recorder: r, base: base}
+}
+
+type recordingTransport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	respBody, err := drainBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	interaction := &Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.RequestURI(),
+			Headers: redactHeaders(req.Header),
+			Body:    redactBody(reqBody),
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header),
+			Body:    redactBody(respBody),
+		},
+	}
+	if err := t.recorder.save(interaction); err != nil {
+		// A failed write must not turn a successful API call into an error.
+		slog.Warn("failed to record interaction", "dir", t.recorder.dir, "error", err)
+	}
+	return resp, nil
+}
+
+// save writes an interaction to the next free slot in the cassette.
+func (r *Recorder) save(interaction *Interaction) error {
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode interaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if r.next == 0 {
+		files, err := interactionFiles(r.dir)
+		if err != nil {
+			return err
+		}
+		r.next = len(files) + 1
+	}
+
+	// Other processes may record into the same directory, so claim the
+	// file exclusively and move on if the slot is taken.
+	for {
+		name := filepath.Join(r.dir, interactionFileName(r.next, interaction.Request))
+		r.next++
+		f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write interaction: %w", err)
+		}
+		_, werr := f.Write(append(data, '\n'))
+		cerr := f.Close()
+		if werr != nil {
+			return fmt.Errorf("failed to write interaction: %w", werr)
+		}
+		if cerr != nil {
+			return fmt.Errorf("failed to write interaction: %w", cerr)
+		}
+		return nil
+	}
+}
+
+// Replayer is an http.RoundTripper that serves responses from a cassette
+// without touching the network. Requests are matched on method, path, query
+// and JSON body; identical requests get their recorded responses in order,
+// and the last one is repeated once they run out.
+type Replayer struct {
+	dir    string
+	mu     sync.Mutex
+	queues map[string][]*Interaction
+	last   map[string]*Interaction
+}
+
+// Load reads every interaction in a cassette directory.
+func Load(dir string) (*Replayer, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("cassette %s is not a directory", dir)
+	}
+
+	files, err := interactionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replayer{
+		dir:    dir,
+		queues: make(map[string][]*Interaction),
+		last:   make(map[string]*Interaction),
+	}
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read interaction %s: %w", name, err)
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("invalid interaction %s: %w", name, err)
+		}
+		key := matchKey(interaction.Request.Method, interaction.Request.URL, interaction.Request.Headers.Get("Content-Type"), interaction.Request.Body)
+		r.queues[key] = append(r.queues[key], &interaction)
+	}
+	return r, nil
+}
+
+// Dir returns the cassette directory.
+func (r *Replayer) Dir() string {
+	return r.dir
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	// Recorded bodies were redacted, so redact the live one the same way.
+	key := matchKey(req.Method, req.URL.RequestURI(), req.Header.Get("Content-Type"), redactBody(body))
+
+	r.mu.Lock()
+	interaction := r.last[key]
+	if queue := r.queues[key]; len(queue) > 0 {
+		interaction = queue[0]
+		r.queues[key] = queue[1:]
+		r.last[key] = interaction
+	}
+	r.mu.Unlock()
+
+	if interaction == nil {
+		return nil, fmt.Errorf("no recorded response for %s %s in cassette %s", req.Method, req.URL.RequestURI(), r.dir)
+	}
+
+	header := interaction.Response.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	status := interaction.Response.Status
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// matchKey identifies equivalent requests. Multipart bodies carry a random
+// boundary, so only JSON and plain bodies take part in matching.
+func matchKey(method, uri, contentType string, body []byte) string {
+	key := method + " " + uri
+	if strings.HasPrefix(contentType, "multipart/") || len(body) == 0 {
+		return key
+	}
+	if json.Valid(body) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, body); err == nil {
+			return key + " " + buf.String()
+		}
+	}
+	return key + " " + string(body)
+}
+
+// drainBody reads a body fully and replaces it with an equivalent reader.
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func redactHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := h.Clone()
+	for _, key := range redactedHeaders {
+		if out.Get(key) == "" {
+			continue
+		}
+		if key == "Authorization" && strings.HasPrefix(out.Get(key), "Bearer ") {
+			out.Set(key, "Bearer "+Redacted)
+			continue
+		}
+		out.Set(key, Redacted)
+	}
+	return out
+}
+
+func redactBody(body []byte) Body {
+	if len(body) == 0 {
+		return nil
+	}
+	body = secretFieldPattern.ReplaceAll(body, []byte(`${1}"`+Redacted+`"`))
+	body = tokenPattern.ReplaceAll(body, []byte(Redacted))
+	return Body(body)
+}
+
+// interactionFiles lists the interaction files in dir in recording order.
+func interactionFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	type numbered struct {
+		name string
+		seq  int
+	}
+	var files []numbered
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		prefix, _, _ := strings.Cut(strings.TrimSuffix(name, ".json"), "-")
+		seq, err := strconv.Atoi(prefix)
+		if err != nil {
+			continue
+		}
+		files = append(files, numbered{name: name, seq: seq})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].seq < files[j].seq })
+
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.name
+	}
+	return names, nil
+}
+
+// interactionFileName names an interaction file after its sequence number
+// and request, e.g. 000003-get-pages.json.
+func interactionFileName(seq int, req Request) string {
+	path, _, _ := strings.Cut(req.URL, "?")
+	resource := ""
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && segment != "v1" {
+			resource = segment
+			break
+		}
+	}
+	name := strings.ToLower(req.Method)
+	if resource != "" {
+		name += "-" + strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+				return r
+			default:
+				return '_'
+			}
+		}, strings.ToLower(resource))
+	}
+	return fmt.Sprintf("%06d-%s.json", seq, name)
+}
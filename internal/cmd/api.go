@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"math"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/salmonumbrella/notion-cli/internal/notion"
 	"github.com/salmonumbrella/notion-cli/internal/output"
 )
 
@@ -29,7 +32,10 @@ func newAPIStatusCmd() *cobra.Command {
 		Long: `Show the current Notion API rate limit status.
 
 By default, shows cached rate limit info from the most recent API call.
-Use --refresh to make a fresh API call and get updated rate limit info.`,
+Use --refresh to make a fresh API call and get updated rate limit info.
+
+With --shared-rate (or shared_rate in config), also shows the request budget
+shared by all ntn processes for this workspace and how many requests are queued.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			client, err := clientFromContext(ctx)
@@ -45,19 +51,35 @@ Use --refresh to make a fresh API call and get updated rate limit info.`,
 				}
 			}
 
+			var shared *notion.SharedBudgetStatus
+			if bucket, ok := client.Limiter().(*notion.SharedTokenBucket); ok {
+				shared, err = bucket.Status()
+				if err != nil {
+					return fmt.Errorf("failed to read shared rate limit state: %w", err)
+				}
+			}
+
 			info := client.GetRateLimitInfo()
 			format := output.FormatFromContext(ctx)
 			if info == nil {
 				// Check output format - for JSON/YAML, output empty object
 				if format == output.FormatJSON || format == output.FormatYAML {
-					return printerForContext(ctx).Print(ctx, map[string]interface{}{
+					data := map[string]interface{}{
 						"available": false,
 						"message":   "No rate limit information available. Make an API call first, or use --refresh to fetch fresh data.",
-					})
+					}
+					if shared != nil {
+						data["shared_budget"] = sharedBudgetData(shared)
+					}
+					return printerForContext(ctx).Print(ctx, data)
 				}
 				out := stdoutFromContext(ctx)
 				_, _ = fmt.Fprintln(out, "No rate limit information available.")
 				_, _ = fmt.Fprintln(out, "Make an API call first, or use --refresh to fetch fresh data.")
+				if shared != nil {
+					_, _ = fmt.Fprintln(out)
+					printSharedBudget(out, shared)
+				}
 				return nil
 			}
 
@@ -77,6 +99,10 @@ Use --refresh to make a fresh API call and get updated rate limit info.`,
 				}
 			}
 
+			if shared != nil {
+				data["shared_budget"] = sharedBudgetData(shared)
+			}
+
 			// Check output format
 			if format == output.FormatJSON || format == output.FormatYAML {
 				return printerForContext(ctx).Print(ctx, data)
@@ -101,6 +127,11 @@ Use --refresh to make a fresh API call and get updated rate limit info.`,
 				_, _ = fmt.Fprintf(out, "Request ID: %s\n", info.RequestID)
 			}
 
+			if shared != nil {
+				_, _ = fmt.Fprintln(out)
+				printSharedBudget(out, shared)
+			}
+
 			// Warn if low
 			if info.Limit > 0 {
 				pct := float64(info.Remaining) / float64(info.Limit) * 100
@@ -117,3 +148,30 @@ Use --refresh to make a fresh API call and get updated rate limit info.`,
 
 	return cmd
 }
+
+// sharedBudgetData converts a shared budget status into structured output.
+func sharedBudgetData(status *notion.SharedBudgetStatus) map[string]interface{} {
+	data := map[string]interface{}{
+		"path":        status.Path,
+		"rate":        status.Rate,
+		"burst":       status.Burst,
+		"available":   math.Floor(status.Available),
+		"queue_depth": status.QueueDepth,
+	}
+	if !status.NextSlotAt.IsZero() {
+		data["next_slot_at"] = status.NextSlotAt.Format(time.RFC3339Nano)
+	}
+	return data
+}
+
+// printSharedBudget renders the shared budget section of text output.
+func printSharedBudget(out io.Writer, status *notion.SharedBudgetStatus) {
+	_, _ = fmt.Fprintln(out, "Shared Budget")
+	_, _ = fmt.Fprintln(out, "─────────────")
+	_, _ = fmt.Fprintf(out, "Available:  %.0f / %d requests (%.4g req/s)\n", math.Floor(status.Available), status.Burst, status.Rate)
+	_, _ = fmt.Fprintf(out, "Queued:     %d\n", status.QueueDepth)
+	if !status.NextSlotAt.IsZero() {
+		_, _ = fmt.Fprintf(out, "Next slot:  in %s\n", time.Until(status.NextSlotAt).Round(time.Millisecond))
+	}
+	_, _ = fmt.Fprintf(out, "State file: %s\n", status.Path)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/salmonumbrella/notion-cli/internal/cmdutil"
 	"github.com/salmonumbrella/notion-cli/internal/errors"
 	"github.com/salmonumbrella/notion-cli/internal/notion"
+	"github.com/salmonumbrella/notion-cli/internal/output"
 )
 
 func newAPIRequestCmd() *cobra.Command {
@@ -103,45 +104,44 @@ Examples:
 }
 
 func runPaginatedAPIRequest(ctx context.Context, client rawRequester, method, path string, body []byte, headers http.Header, raw bool, includeHeaders bool) error {
-	var allResults []interface{}
 	var lastResponse *notion.RawResponse
-	var nextCursor string
 
-	for {
+	// The caller controls page_size through the path or body, so the
+	// iterator's page size is ignored; --limit still stops fetching early.
+	pages := notion.NewIterator(ctx, func(ctx context.Context, cursor string, _ int) ([]interface{}, *string, bool, error) {
 		reqPath := path
 		reqBody := body
 
 		if method == http.MethodGet {
-			reqPath = addQueryParam(reqPath, "start_cursor", nextCursor)
+			reqPath = addQueryParam(reqPath, "start_cursor", cursor)
 		} else if len(reqBody) > 0 || method == http.MethodPost || method == http.MethodPatch {
-			updatedBody, err := withStartCursor(reqBody, nextCursor)
+			updatedBody, err := withStartCursor(reqBody, cursor)
 			if err != nil {
-				return err
+				return nil, nil, false, err
 			}
 			reqBody = updatedBody
 		}
 
 		resp, err := client.DoRawRequest(ctx, method, reqPath, reqBody, headers)
 		if err != nil {
-			return err
+			return nil, nil, false, err
 		}
 		lastResponse = resp
 
 		payload, ok := decodeJSONBody(resp.Body)
 		if !ok {
-			break
+			return nil, nil, false, nil
 		}
-
-		results, hasMore, cursor, ok := extractPagination(payload)
+		results, hasMore, next, ok := extractPagination(payload)
 		if !ok {
-			break
+			return nil, nil, false, nil
 		}
+		return results, &next, hasMore, nil
+	}, notion.IterOptions{Limit: output.LimitFromContext(ctx)})
 
-		allResults = append(allResults, results...)
-		if !hasMore || cursor == "" {
-			break
-		}
-		nextCursor = cursor
+	allResults, err := pages.Collect()
+	if err != nil {
+		return err
 	}
 
 	if lastResponse == nil {
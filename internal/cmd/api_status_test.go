@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAPIStatus_SharedBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "90")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "user", "id": "bot-1", "type": "bot"})
+	}))
+	defer server.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+
+	var out bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &bytes.Buffer{}}
+	root := app.RootCommand()
+	root.SetArgs([]string{"api", "status", "--refresh", "--shared-rate", "--rate", "2", "-o", "json"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("api status failed: %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v\nraw: %s", err, out.String())
+	}
+
+	shared, ok := payload["shared_budget"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected shared_budget in output, got %v", payload)
+	}
+	if shared["rate"] != 2.0 {
+		t.Errorf("rate = %v, want 2", shared["rate"])
+	}
+	if shared["queue_depth"] != 0.0 {
+		t.Errorf("queue_depth = %v, want 0", shared["queue_depth"])
+	}
+	// The --refresh call spent one token from the full burst.
+	if shared["available"] != 9.0 {
+		t.Errorf("available = %v, want 9", shared["available"])
+	}
+	wantPath := filepath.Join(home, ".config", "notion-cli", "ratelimit", "default.json")
+	if shared["path"] != wantPath {
+		t.Errorf("path = %v, want %v", shared["path"], wantPath)
+	}
+}
+
+func TestAPIStatus_NoSharedBudgetByDefault(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "test-token")
+
+	var out bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &bytes.Buffer{}}
+	root := app.RootCommand()
+	root.SetArgs([]string{"api", "status", "-o", "json"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("api status failed: %v", err)
+	}
+	if strings.Contains(out.String(), "shared_budget") {
+		t.Fatalf("did not expect shared_budget without --shared-rate: %s", out.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -98,7 +99,9 @@ func newBlockChildrenCmd() *cobra.Command {
 	var startCursor string
 	var pageSize int
 	var all bool
+	var resumePath string
 	var depth int
+	var concurrency int
 	var plain bool
 
 	cmd := &cobra.Command{
@@ -112,6 +115,8 @@ If you provide a name instead of an ID, the CLI will search for matching pages.
 Use the --start-cursor flag to paginate through results.
 Use the --page-size flag to control the number of results per page (max 100).
 Use --all to fetch all pages of results automatically.
+Use --resume <state-file> (with -o ndjson) to checkpoint progress after every
+page and continue from it when the command is rerun.
 Use --depth to recursively fetch nested children (e.g., content inside toggles, columns).
 Note: global --items-only (alias --ro) outputs a bare array, so jq paths should use '.[]' instead of '.results[]'.
 
@@ -155,6 +160,7 @@ Example:
 				opts := &notion.BlockChildrenOptions{
 					StartCursor: startCursor,
 					PageSize:    pageSize,
+					Concurrency: concurrency,
 				}
 
 				blocks, err := client.GetBlockChildrenRecursive(ctx, blockID, depth, opts)
@@ -174,31 +180,24 @@ Example:
 			}
 
 			// If --all flag is set, fetch all pages
-			if all {
-				var allBlocks []notion.Block
-				cursor := startCursor
-
-				for {
-					opts := &notion.BlockChildrenOptions{
-						StartCursor: cursor,
-						PageSize:    pageSize,
+			if all || resumePath != "" {
+				opts := iterOptions(startCursor, pageSize, limit)
+				newIter := func(opts notion.IterOptions) *notion.Iterator[notion.Block] {
+					return client.IterBlockChildren(ctx, blockID, opts)
+				}
+				if output.CanStream(ctx) || resumePath != "" {
+					var transform func(notion.Block) interface{}
+					if plain {
+						transform = func(b notion.Block) interface{} { return simplifyBlock(b) }
 					}
-
-					blockList, err := client.GetBlockChildren(ctx, blockID, opts)
-					if err != nil {
+					return streamAllPages(ctx, resumePath, resumeKey("block children "+blockID, nil), opts, newIter, transform, func(err error) error {
 						return wrapAPIError(err, "access block", "block", args[0])
-					}
-
-					allBlocks = append(allBlocks, blockList.Results...)
-					if limit > 0 && len(allBlocks) >= limit {
-						allBlocks = allBlocks[:limit]
-						break
-					}
+					})
+				}
 
-					if !blockList.HasMore || blockList.NextCursor == nil || *blockList.NextCursor == "" {
-						break
-					}
-					cursor = *blockList.NextCursor
+				allBlocks, err := newIter(opts).Collect()
+				if err != nil {
+					return wrapAPIError(err, "access block", "block", args[0])
 				}
 
 				// Print all results
@@ -242,7 +241,9 @@ Example:
 	cmd.Flags().StringVar(&startCursor, "start-cursor", "", "Pagination cursor")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Number of results per page (max 100)")
 	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of results (may be slow for large datasets)")
+	addResumeFlag(cmd, &resumePath)
 	cmd.Flags().IntVar(&depth, "depth", 0, "Recursively fetch nested children up to this depth (0 = direct children only)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", notion.DefaultBlockFetchConcurrency, "Maximum parallel block fetches for --depth")
 	cmd.Flags().BoolVar(&plain, "plain", false, "Output simplified blocks (id, type, text, children)")
 
 	return cmd
@@ -255,6 +256,7 @@ func newBlockAppendCmd() *cobra.Command {
 	var blockType string
 	var content string
 	var markdownContent string
+	var htmlContent string
 
 	cmd := &cobra.Command{
 		Use:     "append <block-id>",
@@ -275,6 +277,10 @@ MARKDOWN USAGE (--md):
   ntn block append PAGE_ID --md @content.md
   cat content.md | ntn block append PAGE_ID --md -
 
+HTML USAGE (--html):
+  ntn block append PAGE_ID --html '<h2>Notes</h2><p>Some <b>bold</b> text.</p>'
+  ntn block append PAGE_ID --html @page.html
+
 ADVANCED USAGE (--children JSON):
   ntn block append PAGE_ID \
     --children '[{"type":"paragraph","paragraph":{"rich_text":[{"type":"text","text":{"content":"Hello"}}]}}]'
@@ -322,31 +328,54 @@ TIP: For convenience commands, see 'ntn block add --help'`,
 				return fmt.Errorf("--type is required when using --content")
 			}
 
-			// Handle --md flag: parse markdown to blocks
-			if markdownContent != "" {
+			// Handle --md and --html flags: parse markdown or HTML to blocks
+			if markdownContent != "" && htmlContent != "" {
+				return fmt.Errorf("use only one of --md or --html")
+			}
+			if markdownContent != "" || htmlContent != "" {
+				flag, format, text := "--md", "markdown", markdownContent
+				if htmlContent != "" {
+					flag, format, text = "--html", "HTML", htmlContent
+				}
 				if childrenJSON != "" || childrenFile != "" || blockType != "" {
-					return fmt.Errorf("--md cannot be combined with --children, --children-file, or --type")
+					return fmt.Errorf("%s cannot be combined with --children, --children-file, or --type", flag)
 				}
 				// Support @file, stdin (-), or inline string
-				mdText := markdownContent
-				if strings.HasPrefix(mdText, "@") {
-					data, err := os.ReadFile(strings.TrimPrefix(mdText, "@"))
+				baseDir := ""
+				if strings.HasPrefix(text, "@") {
+					file := strings.TrimPrefix(text, "@")
+					data, err := os.ReadFile(file)
 					if err != nil {
-						return fmt.Errorf("reading markdown file: %w", err)
+						return fmt.Errorf("reading %s file: %w", format, err)
 					}
-					mdText = string(data)
-				} else if mdText == "-" {
+					text = string(data)
+					baseDir = filepath.Dir(file)
+				} else if text == "-" {
 					data, err := readMarkdownFile("-")
 					if err != nil {
-						return fmt.Errorf("reading markdown from stdin: %w", err)
+						return fmt.Errorf("reading %s from stdin: %w", format, err)
 					}
-					mdText = data
+					text = data
+				}
+				var doc *markdownDocument
+				if htmlContent != "" {
+					doc = parseHTMLDocument(text, baseDir)
+				} else {
+					doc = parseMarkdownDocument(text, baseDir)
 				}
-				blocks := parseMarkdownToBlocks(mdText)
-				if len(blocks) == 0 {
-					return fmt.Errorf("no blocks parsed from markdown content")
+				if len(doc.Blocks) == 0 {
+					return fmt.Errorf("no blocks parsed from %s content", format)
+				}
+				if len(doc.Images) > 0 {
+					client, err := clientFromContext(ctx)
+					if err != nil {
+						return err
+					}
+					if err := doc.uploadImages(ctx, client); err != nil {
+						return err
+					}
 				}
-				marshaled, err := marshalJSON(blocks)
+				marshaled, err := marshalJSON(doc.Blocks)
 				if err != nil {
 					return err
 				}
@@ -359,7 +388,7 @@ TIP: For convenience commands, see 'ntn block add --help'`,
 
 			// Validate required flag
 			if childrenJSON == "" && childrenFile == "" {
-				return fmt.Errorf("either --children/--children-file, --md, or both --type and --content are required\n\nMarkdown usage:\n  ntn block append PAGE_ID --md '# Heading\\nParagraph text'\n\nSimple usage:\n  ntn block append PAGE_ID --type paragraph --content \"Your text\"\n\nAdvanced usage:\n  ntn block append PAGE_ID --children '[{\"type\":\"paragraph\",...}]'\n  ntn block append PAGE_ID --children-file /tmp/blocks.json")
+				return fmt.Errorf("either --children/--children-file, --md, --html, or both --type and --content are required\n\nMarkdown usage:\n  ntn block append PAGE_ID --md '# Heading\\nParagraph text'\n\nSimple usage:\n  ntn block append PAGE_ID --type paragraph --content \"Your text\"\n\nAdvanced usage:\n  ntn block append PAGE_ID --children '[{\"type\":\"paragraph\",...}]'\n  ntn block append PAGE_ID --children-file /tmp/blocks.json")
 			}
 
 			// Normalize after block ID if provided
@@ -396,10 +425,14 @@ TIP: For convenience commands, see 'ntn block add --help'`,
 			}
 
 			// Append children in batches (Notion API limit is 100 blocks per request).
+			detached := detachDeepChildren(children)
 			blockList, err := appendBlockChildrenBatched(ctx, client, blockID, children, afterBlockID)
 			if err != nil {
 				return wrapAPIError(err, "access block", "block", args[0])
 			}
+			if err := appendDetachedChildren(ctx, client, blockList.Results, detached); err != nil {
+				return wrapAPIError(err, "access block", "block", args[0])
+			}
 
 			// Print result
 			printer := printerForContext(ctx)
@@ -410,6 +443,7 @@ TIP: For convenience commands, see 'ntn block add --help'`,
 	cmd.Flags().StringVar(&childrenJSON, "children", "", "Children blocks as JSON array")
 	cmd.Flags().StringVar(&childrenFile, "children-file", "", "Read children JSON from file (or - for stdin)")
 	cmd.Flags().StringVar(&markdownContent, "md", "", "Markdown content to parse and append as blocks (supports @file and - for stdin)")
+	cmd.Flags().StringVar(&htmlContent, "html", "", "HTML content to parse and append as blocks (supports @file and - for stdin)")
 	cmd.Flags().StringVar(&afterBlockID, "after", "", "Insert blocks after this block ID (instead of at end)")
 	cmd.Flags().StringVar(&blockType, "type", "", "Block type for simple mode (paragraph, heading_1, etc.)")
 	cmd.Flags().StringVar(&content, "content", "", "Text content for simple mode (use with --type)")
@@ -638,15 +672,8 @@ Example:
 				printer.Field("Has children", fmt.Sprintf("%t", block.HasChildren))
 
 				// Show content preview if available
-				if len(block.Content) > 0 {
-					// Try to extract text content for common block types
-					if richText, ok := block.Content["rich_text"].([]interface{}); ok && len(richText) > 0 {
-						if textObj, ok := richText[0].(map[string]interface{}); ok {
-							if text, ok := textObj["plain_text"].(string); ok && text != "" {
-								printer.Content("Content preview", text)
-							}
-						}
-					}
+				if richText := block.RichText(); len(richText) > 0 && richText[0].PlainText != "" {
+					printer.Content("Content preview", richText[0].PlainText)
 				}
 
 				// Show parent information
@@ -962,3 +989,87 @@ func appendBlockChildrenBatched(
 
 	return combined, nil
 }
+
+// maxAppendNesting is how many levels of children one append request may
+// nest under the blocks it creates.
+const maxAppendNesting = 2
+
+// detachDeepChildren removes the children of blocks nested deeper than one
+// append request accepts, returning them by the block's index. Once the
+// blocks exist, appendDetachedChildren appends the children to them.
+func detachDeepChildren(blocks []map[string]interface{}) map[int][]map[string]interface{} {
+	detached := map[int][]map[string]interface{}{}
+	for i, block := range blocks {
+		if blockTreeDepth(block) <= maxAppendNesting {
+			continue
+		}
+		blockType, _ := block["type"].(string)
+		content, _ := block[blockType].(map[string]interface{})
+		stripped := make(map[string]interface{}, len(content))
+		for k, v := range content {
+			if k != "children" {
+				stripped[k] = v
+			}
+		}
+		copied := make(map[string]interface{}, len(block))
+		for k, v := range block {
+			copied[k] = v
+		}
+		copied[blockType] = stripped
+		blocks[i] = copied
+		detached[i] = blockPayloadChildren(block)
+	}
+	return detached
+}
+
+// appendDetachedChildren appends the children removed by detachDeepChildren
+// under the blocks created for them, in order.
+func appendDetachedChildren(ctx context.Context, client blockChildrenWriter, created []notion.Block, detached map[int][]map[string]interface{}) error {
+	for i := range created {
+		children, ok := detached[i]
+		if !ok {
+			continue
+		}
+		if created[i].ID == "" {
+			return fmt.Errorf("failed to append nested blocks: response missing block ID")
+		}
+		nested := detachDeepChildren(children)
+		result, err := appendBlockChildrenBatched(ctx, client, created[i].ID, children, "")
+		if err != nil {
+			return err
+		}
+		if err := appendDetachedChildren(ctx, client, result.Results, nested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockPayloadChildren returns the children of a block payload, built in
+// code or decoded from JSON.
+func blockPayloadChildren(block map[string]interface{}) []map[string]interface{} {
+	blockType, _ := block["type"].(string)
+	content, _ := block[blockType].(map[string]interface{})
+	switch children := content["children"].(type) {
+	case []map[string]interface{}:
+		return children
+	case []interface{}:
+		blocks := make([]map[string]interface{}, 0, len(children))
+		for _, child := range children {
+			if m, ok := child.(map[string]interface{}); ok {
+				blocks = append(blocks, m)
+			}
+		}
+		return blocks
+	}
+	return nil
+}
+
+// blockTreeDepth returns how many levels of children a block payload has.
+func blockTreeDepth(block map[string]interface{}) int {
+	depth := 0
+	for _, child := range blockPayloadChildren(block) {
+		depth = max(depth, blockTreeDepth(child)+1)
+	}
+	return depth
+}
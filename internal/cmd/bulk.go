@@ -54,7 +54,10 @@ Multiple --where flags are ANDed together.
 Property types are auto-detected from the database schema.
 
 The --set flag specifies property updates as PropertyName=Value pairs.
-Multiple --set flags update multiple properties.
+Multiple --set flags update multiple properties. Values are converted using
+the property's schema type: multi_select takes "a;b", date takes "start" or
+"start/end", people and relation take comma-separated IDs, files take URLs.
+An empty value clears the property.
 
 Examples:
   ntn bulk update <db-id> --where "Status=Done" --set "Status=Archived"
@@ -184,28 +187,13 @@ func runBulkOperation(ctx context.Context, dbArg string, whereClauses, setClause
 		}
 	}
 
-	// Query all matching pages (paginated)
-	allPages, _, _, err := fetchAllPages(ctx, "", NotionMaxPageSize, 0, func(ctx context.Context, cursor string, pageSize int) ([]notion.Page, *string, bool, error) {
-		req := &notion.QueryDataSourceRequest{
-			Filter:      filter,
-			StartCursor: cursor,
-			PageSize:    pageSize,
-		}
-		result, err := client.QueryDataSource(ctx, resolvedDataSourceID, req)
-		if err != nil {
-			return nil, nil, false, err
-		}
-		return result.Results, result.NextCursor, result.HasMore, nil
-	})
+	// Query matching pages, stopping once --limit pages have been found
+	req := &notion.QueryDataSourceRequest{Filter: filter}
+	allPages, err := client.IterQueryDataSource(ctx, resolvedDataSourceID, req, iterOptions("", NotionMaxPageSize, limit)).Collect()
 	if err != nil {
 		return wrapAPIError(err, "query database for bulk operation", "database", dbArg)
 	}
 
-	// Apply limit if set
-	if limit > 0 && len(allPages) > limit {
-		allPages = allPages[:limit]
-	}
-
 	if len(allPages) == 0 {
 		_, _ = fmt.Fprintf(stderr, "No pages matched the filter.\n")
 		return nil
@@ -475,55 +463,18 @@ func parseSetClauses(clauses []string, schema map[string]interface{}) (map[strin
 
 // buildPropertyPayloadForType creates a Notion property update payload for a given property type.
 func buildPropertyPayloadForType(propName, propType, value string) (interface{}, error) {
-	switch propType {
-	case "status":
-		return map[string]interface{}{
-			"status": map[string]interface{}{"name": value},
-		}, nil
-	case "select":
-		return map[string]interface{}{
-			"select": map[string]interface{}{"name": value},
-		}, nil
-	case "checkbox":
-		boolVal := strings.EqualFold(value, "true")
-		return map[string]interface{}{
-			"checkbox": boolVal,
-		}, nil
-	case "title":
-		return map[string]interface{}{
-			"title": []map[string]interface{}{
-				{"text": map[string]interface{}{"content": value}},
-			},
-		}, nil
-	case "rich_text":
-		return map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{"text": map[string]interface{}{"content": value}},
-			},
-		}, nil
-	case "number":
-		numVal, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			return nil, errors.NewUserError(
-				fmt.Sprintf("cannot parse %q as a number for property %q", value, propName),
-				"Provide a valid numeric value.",
-			)
+	payload, err := notion.PropertyPayload(propType, value)
+	if err != nil {
+		hint := "Check the value format for this property type."
+		if notion.IsReadOnlyPropertyType(propType) {
+			hint = "Read-only properties (formulas, rollups, unique IDs, ...) cannot be set."
 		}
-		return map[string]interface{}{
-			"number": numVal,
-		}, nil
-	case "people":
-		return map[string]interface{}{
-			"people": []map[string]interface{}{
-				{"id": value},
-			},
-		}, nil
-	default:
 		return nil, errors.NewUserError(
-			fmt.Sprintf("unsupported property type %q for --set on property %q", propType, propName),
-			"Supported types: status, select, checkbox, title, rich_text, number, people.",
+			fmt.Sprintf("cannot set property %q: %v", propName, err),
+			hint,
 		)
 	}
+	return payload, nil
 }
 
 // resolveSchemaProperty finds the canonical property name and type from the data source schema.
@@ -332,15 +332,57 @@ func TestBuildPropertyPayloadForType(t *testing.T) {
 			propName: "Assignee",
 			propType: "people",
 			value:    "user-id-123",
-			wantJSON: `{"people":[{"id":"user-id-123"}]}`,
+			wantJSON: `{"people":[{"id":"user-id-123","object":"user"}]}`,
 		},
 		{
-			name:     "unsupported type",
+			name:     "multi_select property",
+			propName: "Tags",
+			propType: "multi_select",
+			value:    "a; b",
+			wantJSON: `{"multi_select":[{"name":"a"},{"name":"b"}]}`,
+		},
+		{
+			name:     "date range",
+			propName: "When",
+			propType: "date",
+			value:    "2024-01-01/2024-01-05",
+			wantJSON: `{"date":{"end":"2024-01-05","start":"2024-01-01"}}`,
+		},
+		{
+			name:     "relation property",
+			propName: "Project",
+			propType: "relation",
+			value:    "https://example.invalid/Project-0123456789abcdef0123456789abcdef",
+			wantJSON: `{"relation":[{"id":"0123456789abcdef0123456789abcdef"}]}`,
+		},
+		{
+			name:     "files property",
+			propName: "Files",
+			propType: "files",
+			value:    "https://example.invalid/docs/spec.pdf",
+			wantJSON: `{"files":[{"external":{"url":"https://example.invalid/docs/spec.pdf"},"name":"spec.pdf","type":"external"}]}`,
+		},
+		{
+			name:     "files property requires URL",
 			propName: "Files",
 			propType: "files",
 			value:    "test",
 			wantErr:  true,
 		},
+		{
+			name:     "invalid number",
+			propName: "Score",
+			propType: "number",
+			value:    "lots",
+			wantErr:  true,
+		},
+		{
+			name:     "read-only type",
+			propName: "ID",
+			propType: "unique_id",
+			value:    "TASK-1",
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notion-cli/internal/config"
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+	"github.com/salmonumbrella/notion-cli/internal/output"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and clear the response cache",
+		Long: `Inspect and clear the on-disk response cache.
+
+With --cache (or cache: true in config), GET requests for pages, databases,
+data sources, users and block children are served from disk until they expire
+(cache_ttl, default 10m) or a newer last_edited_time is seen. Writes made
+through ntn drop the entries for the objects they touch.
+
+The cache is kept per workspace under ~/.config/notion-cli/cache/.`,
+	}
+
+	cmd.AddCommand(newCacheStatsCmd())
+	cmd.AddCommand(newCacheClearCmd())
+	return cmd
+}
+
+func newCacheStatsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show response cache size and contents",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cache, err := responseCacheForContext(ctx)
+			if err != nil {
+				return err
+			}
+			stats, err := cache.Stats()
+			if err != nil {
+				return err
+			}
+
+			format := output.FormatFromContext(ctx)
+			if format != output.FormatText {
+				return printerForContext(ctx).Print(ctx, stats)
+			}
+
+			out := stdoutFromContext(ctx)
+			_, _ = fmt.Fprintf(out, "Directory: %s\n", stats.Dir)
+			_, _ = fmt.Fprintf(out, "Entries:   %d (%d expired)\n", stats.Entries, stats.Expired)
+			_, _ = fmt.Fprintf(out, "Size:      %d bytes\n", stats.SizeBytes)
+			_, _ = fmt.Fprintf(out, "TTL:       %s\n", stats.TTL)
+			kinds := make([]string, 0, len(stats.ByKind))
+			for kind := range stats.ByKind {
+				kinds = append(kinds, kind)
+			}
+			sort.Strings(kinds)
+			for _, kind := range kinds {
+				_, _ = fmt.Fprintf(out, "  %-15s %d\n", kind, stats.ByKind[kind])
+			}
+			return nil
+		},
+	}
+}
+
+func newCacheClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached responses for the workspace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cache, err := responseCacheForContext(ctx)
+			if err != nil {
+				return err
+			}
+			removed, err := cache.Clear()
+			if err != nil {
+				return err
+			}
+
+			if output.FormatFromContext(ctx) != output.FormatText {
+				return printerForContext(ctx).Print(ctx, map[string]interface{}{
+					"dir":     cache.Dir(),
+					"removed": removed,
+				})
+			}
+			if !output.QuietFromContext(ctx) {
+				_, _ = fmt.Fprintf(stdoutFromContext(ctx), "Removed %d cached responses from %s\n", removed, cache.Dir())
+			}
+			return nil
+		},
+	}
+}
+
+// responseCacheForContext opens the response cache of the current workspace,
+// whether or not caching is enabled for this invocation.
+func responseCacheForContext(ctx context.Context) (*notion.ResponseCache, error) {
+	dir, err := config.ResponseCacheDir(stateWorkspace(ctx, ConfigFromContext(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cache directory: %w", err)
+	}
+	return notion.NewResponseCache(dir, ResponseCacheTTLFromContext(ctx)), nil
+}
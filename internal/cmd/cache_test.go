@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCache_RepeatedReadsAndStats(t *testing.T) {
+	const userID = "11111111-2222-3333-4444-555555555555"
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "user", "id": userID, "type": "person", "name": "Ada"})
+	}))
+	defer server.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out, errBuf bytes.Buffer
+		app := &App{Stdout: &out, Stderr: &errBuf}
+		root := app.RootCommand()
+		root.SetArgs(args)
+		if err := root.ExecuteContext(context.Background()); err != nil {
+			t.Fatalf("%v failed: %v\nstderr=%s", args, err, errBuf.String())
+		}
+		return out.String()
+	}
+
+	run("user", "get", userID, "--cache", "-o", "json")
+	run("user", "get", userID, "--cache", "-o", "json")
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("API hits with --cache = %d, want 1", got)
+	}
+
+	run("user", "get", userID, "-o", "json")
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("API hits without --cache = %d, want 2", got)
+	}
+
+	var stats map[string]interface{}
+	if err := json.Unmarshal([]byte(run("cache", "stats", "-o", "json")), &stats); err != nil {
+		t.Fatalf("decode stats: %v", err)
+	}
+	if stats["entries"] != 1.0 {
+		t.Errorf("entries = %v, want 1", stats["entries"])
+	}
+	if want := filepath.Join(home, ".config", "notion-cli", "cache", "default"); stats["dir"] != want {
+		t.Errorf("dir = %v, want %v", stats["dir"], want)
+	}
+
+	var cleared map[string]interface{}
+	if err := json.Unmarshal([]byte(run("cache", "clear", "-o", "json")), &cleared); err != nil {
+		t.Fatalf("decode clear output: %v", err)
+	}
+	if cleared["removed"] != 1.0 {
+		t.Errorf("removed = %v, want 1", cleared["removed"])
+	}
+	run("user", "get", userID, "--cache", "-o", "json")
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("API hits after clear = %d, want 3", got)
+	}
+}
+
+func TestCache_RejectsCacheAndNoCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	app := &App{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	root := app.RootCommand()
+	root.SetArgs([]string{"cache", "stats", "--cache", "--no-cache"})
+	err := root.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "use only one of --cache or --no-cache") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay_ServesOfflineWithoutToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "user", "id": "bot-1", "type": "bot", "name": "Recorder"})
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "secret_recordtesttoken0123456789")
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+	dir := filepath.Join(t.TempDir(), "cassette")
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out, errBuf bytes.Buffer
+		app := &App{Stdout: &out, Stderr: &errBuf}
+		root := app.RootCommand()
+		root.SetArgs(args)
+		if err := root.ExecuteContext(context.Background()); err != nil {
+			t.Fatalf("%v failed: %v\nstderr=%s", args, err, errBuf.String())
+		}
+		return out.String()
+	}
+
+	recorded := run("user", "me", "--record", dir, "-o", "json")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("cassette files = %v (err %v), want 1", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	if strings.Contains(string(data), "recordtesttoken") {
+		t.Fatalf("token leaked into cassette:\n%s", data)
+	}
+
+	server.Close()
+	t.Setenv("NOTION_TOKEN", "")
+	replayed := run("user", "me", "--replay", dir, "-o", "json")
+	if replayed != recorded {
+		t.Fatalf("replayed output differs:\nrecorded=%s\nreplayed=%s", recorded, replayed)
+	}
+}
+
+func TestRecordReplay_RejectsBoth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	app := &App{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	root := app.RootCommand()
+	root.SetArgs([]string{"user", "me", "--record", t.TempDir(), "--replay", t.TempDir()})
+	err := root.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "use only one of --record or --replay") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
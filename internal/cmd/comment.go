@@ -53,6 +53,7 @@ func newCommentListCmd() *cobra.Command {
 	var startCursor string
 	var pageSize int
 	var all bool
+	var resumePath string
 	var light bool
 
 	cmd := &cobra.Command{
@@ -72,6 +73,8 @@ Note: Notion search only finds pages/databases; resolving by name is page-only.
 Use --page-size to control the number of results per page (max 100).
 Use --start-cursor for pagination.
 Use --all to fetch all pages of results automatically.
+Use --resume <state-file> (with -o ndjson) to checkpoint progress after every
+page and continue from it when the command is rerun.
 Use --light (or --li) for compact output (id, discussion_id, text, created_by).
 Use global --results-only to output just the results array (useful for piping to jq).
 
@@ -119,31 +122,29 @@ Example - Output only results array:
 			}
 
 			// If --all flag is set, fetch all pages
-			if all {
-				var allComments []*notion.Comment
-				cursor := startCursor
-
-				for {
-					opts := &notion.ListCommentsOptions{
-						StartCursor: cursor,
-						PageSize:    pageSize,
+			if all || resumePath != "" {
+				opts := iterOptions(startCursor, pageSize, limit)
+				newIter := func(opts notion.IterOptions) *notion.Iterator[*notion.Comment] {
+					return client.IterComments(ctx, blockID, opts)
+				}
+				if output.CanStream(ctx) || resumePath != "" {
+					var transform func(*notion.Comment) interface{}
+					if light {
+						transform = func(c *notion.Comment) interface{} {
+							if l := toLightComments([]*notion.Comment{c}); len(l) > 0 {
+								return l[0]
+							}
+							return nil
+						}
 					}
-
-					result, err := client.ListComments(ctx, blockID, opts)
-					if err != nil {
+					return streamAllPages(ctx, resumePath, resumeKey("comment list "+blockID, nil), opts, newIter, transform, func(err error) error {
 						return wrapAPIError(err, "list comments", "block", blockID)
-					}
-
-					allComments = append(allComments, result.Results...)
-					if limit > 0 && len(allComments) >= limit {
-						allComments = allComments[:limit]
-						break
-					}
+					})
+				}
 
-					if !result.HasMore || result.NextCursor == nil || *result.NextCursor == "" {
-						break
-					}
-					cursor = *result.NextCursor
+				allComments, nextCursor, hasMore, err := collectPages(newIter(opts))
+				if err != nil {
+					return wrapAPIError(err, "list comments", "block", blockID)
 				}
 
 				// Print all results
@@ -152,15 +153,15 @@ Example - Output only results array:
 					return printer.Print(ctx, map[string]interface{}{
 						"object":      "list",
 						"results":     toLightComments(allComments),
-						"has_more":    false,
-						"next_cursor": nil,
+						"has_more":    hasMore,
+						"next_cursor": nextCursor,
 					})
 				}
 				return printer.Print(ctx, map[string]interface{}{
 					"object":      "list",
 					"results":     allComments,
-					"has_more":    false,
-					"next_cursor": nil,
+					"has_more":    hasMore,
+					"next_cursor": nextCursor,
 				})
 			}
 
@@ -197,6 +198,7 @@ Example - Output only results array:
 	cmd.Flags().StringVar(&startCursor, "start-cursor", "", "Pagination cursor")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Number of results per page (max 100)")
 	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of results (may be slow for large datasets)")
+	addResumeFlag(cmd, &resumePath)
 	cmd.Flags().BoolVar(&light, "light", false, "Return compact payload (id, discussion_id, text, created_by)")
 	flagAlias(cmd.Flags(), "light", "li")
 
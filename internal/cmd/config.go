@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -65,13 +67,19 @@ func newConfigSetCmd() *cobra.Command {
 		Long: `Set a configuration value in ~/.config/notion-cli/config.yaml
 
 Supported keys:
-  output            - Default output format (text, json, ndjson/jsonl, table, yaml)
+  output            - Default output format (text, json, ndjson/jsonl, table, yaml, csv, tsv)
   color             - Default color mode (auto, always, never)
+  rate              - Max API requests per second (0 disables client-side pacing)
+  shared_rate       - Share the rate budget across ntn processes (true, false)
+  cache             - Serve repeated reads from the on-disk cache (true, false)
+  cache_ttl         - How long cached responses stay fresh (e.g. 10m, 1h)
   default_workspace - Default workspace name
 
 Examples:
   ntn config set output json
   ntn config set color always
+  ntn config set rate 2
+  ntn config set cache_ttl 30m
   ntn config set default_workspace personal`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -89,7 +97,7 @@ Examples:
 			case "output":
 				format, err := output.ParseFormat(value)
 				if err != nil {
-					validFormats := []string{"text", "json", "ndjson", "jsonl", "table", "yaml"}
+					validFormats := []string{"text", "json", "ndjson", "jsonl", "table", "yaml", "csv", "tsv"}
 					return fmt.Errorf("invalid output format %q, must be one of: %s", value, strings.Join(validFormats, ", "))
 				}
 				cfg.Output = string(format)
@@ -101,10 +109,34 @@ Examples:
 					return fmt.Errorf("invalid color mode %q, must be one of: %s", value, strings.Join(validModes, ", "))
 				}
 				cfg.Color = value
+			case "rate":
+				rate, err := strconv.ParseFloat(value, 64)
+				if err != nil || rate < 0 {
+					return fmt.Errorf("invalid rate %q, must be a number >= 0 (requests per second)", value)
+				}
+				cfg.Rate = &rate
+			case "shared_rate":
+				shared, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid shared_rate %q, must be true or false", value)
+				}
+				cfg.SharedRate = shared
+			case "cache":
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid cache %q, must be true or false", value)
+				}
+				cfg.Cache = enabled
+			case "cache_ttl":
+				ttl, err := time.ParseDuration(value)
+				if err != nil || ttl <= 0 {
+					return fmt.Errorf("invalid cache_ttl %q, must be a positive duration such as 10m", value)
+				}
+				cfg.CacheTTL = ttl.String()
 			case "default_workspace":
 				cfg.DefaultWorkspace = value
 			default:
-				return fmt.Errorf("unknown config key %q\n\nSupported keys: output, color, default_workspace", key)
+				return fmt.Errorf("unknown config key %q\n\nSupported keys: output, color, rate, shared_rate, cache, cache_ttl, default_workspace", key)
 			}
 
 			// Save the config
@@ -61,3 +61,43 @@ func TestConfigSetOutput_RejectsInvalidFormat(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestConfigSetRate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"config", "set", "rate", "1.5"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("config set rate failed: %v\nstderr=%s", err, errBuf.String())
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if rate, ok := cfg.GetRate(); !ok || rate != 1.5 {
+		t.Fatalf("config rate = %v (set=%v), want 1.5", rate, ok)
+	}
+}
+
+func TestConfigSetRate_RejectsInvalid(t *testing.T) {
+	for _, value := range []string{"fast", "-1"} {
+		t.Run(value, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+
+			var out, errBuf bytes.Buffer
+			app := &App{Stdout: &out, Stderr: &errBuf}
+			root := app.RootCommand()
+			root.SetArgs([]string{"config", "set", "--", "rate", value})
+
+			err := root.ExecuteContext(context.Background())
+			if err == nil || !strings.Contains(err.Error(), "invalid rate") {
+				t.Fatalf("expected invalid rate error, got %v", err)
+			}
+		})
+	}
+}
@@ -2,14 +2,22 @@ package cmd
 
 import (
 	"context"
+	"time"
 
+	"github.com/salmonumbrella/notion-cli/internal/cassette"
 	"github.com/salmonumbrella/notion-cli/internal/config"
 )
 
 type (
-	workspaceKey   struct{}
-	errorFormatKey struct{}
-	configKey      struct{}
+	workspaceKey     struct{}
+	errorFormatKey   struct{}
+	configKey        struct{}
+	rateLimitKey     struct{}
+	sharedRateKey    struct{}
+	responseCacheKey struct{}
+	cacheTTLKey      struct{}
+	recorderKey      struct{}
+	replayerKey      struct{}
 )
 
 // WithWorkspace stores a workspace name in the context
@@ -50,3 +58,70 @@ func ConfigFromContext(ctx context.Context) *config.Config {
 	}
 	return nil
 }
+
+// WithRateLimit stores the client-side request rate (requests per second) in context.
+func WithRateLimit(ctx context.Context, rate float64) context.Context {
+	return context.WithValue(ctx, rateLimitKey{}, rate)
+}
+
+// RateLimitFromContext retrieves the client-side request rate from context.
+// The second return value is false when no rate was configured.
+func RateLimitFromContext(ctx context.Context) (float64, bool) {
+	v, ok := ctx.Value(rateLimitKey{}).(float64)
+	return v, ok
+}
+
+// WithSharedRate stores whether the request budget is shared across processes.
+func WithSharedRate(ctx context.Context, shared bool) context.Context {
+	return context.WithValue(ctx, sharedRateKey{}, shared)
+}
+
+// SharedRateFromContext reports whether the request budget is shared across processes.
+func SharedRateFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(sharedRateKey{}).(bool)
+	return v
+}
+
+// WithResponseCache stores whether read responses are served from the on-disk cache.
+func WithResponseCache(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, responseCacheKey{}, enabled)
+}
+
+// ResponseCacheFromContext reports whether the on-disk response cache is enabled.
+func ResponseCacheFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(responseCacheKey{}).(bool)
+	return v
+}
+
+// WithResponseCacheTTL stores how long cached responses stay fresh.
+func WithResponseCacheTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, cacheTTLKey{}, ttl)
+}
+
+// ResponseCacheTTLFromContext retrieves the cache TTL, or 0 for the default.
+func ResponseCacheTTLFromContext(ctx context.Context) time.Duration {
+	v, _ := ctx.Value(cacheTTLKey{}).(time.Duration)
+	return v
+}
+
+// WithRecorder stores the cassette recorder used for --record in context.
+func WithRecorder(ctx context.Context, recorder *cassette.Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// RecorderFromContext retrieves the cassette recorder, or nil when not recording.
+func RecorderFromContext(ctx context.Context) *cassette.Recorder {
+	v, _ := ctx.Value(recorderKey{}).(*cassette.Recorder)
+	return v
+}
+
+// WithReplayer stores the cassette replayer used for --replay in context.
+func WithReplayer(ctx context.Context, replayer *cassette.Replayer) context.Context {
+	return context.WithValue(ctx, replayerKey{}, replayer)
+}
+
+// ReplayerFromContext retrieves the cassette replayer, or nil when not replaying.
+func ReplayerFromContext(ctx context.Context) *cassette.Replayer {
+	v, _ := ctx.Value(replayerKey{}).(*cassette.Replayer)
+	return v
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -382,21 +381,8 @@ incorrectly, causing "accepts 1 arg(s), received N" errors.`,
 
 			// If --all flag is set, fetch all pages
 			if all {
-				allPages, nextCursor, hasMore, err := fetchAllPages(ctx, startCursor, pageSize, limit, func(ctx context.Context, cursor string, pageSize int) ([]notion.Page, *string, bool, error) {
-					req := &notion.QueryDataSourceRequest{
-						Filter:      filter,
-						Sorts:       sorts,
-						StartCursor: cursor,
-						PageSize:    pageSize,
-					}
-
-					result, err := client.QueryDataSource(ctx, dataSourceID, req)
-					if err != nil {
-						return nil, nil, false, err
-					}
-
-					return result.Results, result.NextCursor, result.HasMore, nil
-				})
+				req := &notion.QueryDataSourceRequest{Filter: filter, Sorts: sorts}
+				allPages, nextCursor, hasMore, err := collectPages(client.IterQueryDataSource(ctx, dataSourceID, req, iterOptions(startCursor, pageSize, limit)))
 				if err != nil {
 					return wrapAPIError(err, "query data source", "data source", args[0])
 				}
@@ -473,6 +459,24 @@ incorrectly, causing "accepts 1 arg(s), received N" errors.`,
 }
 
 func filterResultsBySelect(results []notion.Page, propName, equals, notEquals, match string) ([]notion.Page, error) {
+	keep, err := selectMatcher(propName, equals, notEquals, match)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]notion.Page, 0, len(results))
+	for _, item := range results {
+		if keep(item) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
+}
+
+// selectMatcher returns a predicate implementing the --select-* flags for a
+// single page, so streamed results can be filtered as they arrive.
+func selectMatcher(propName, equals, notEquals, match string) (func(notion.Page) bool, error) {
 	var re *regexp.Regexp
 	var err error
 	if match != "" {
@@ -482,50 +486,36 @@ func filterResultsBySelect(results []notion.Page, propName, equals, notEquals, m
 		}
 	}
 
-	filtered := make([]notion.Page, 0, len(results))
-	for _, item := range results {
+	return func(item notion.Page) bool {
 		prop, ok := item.Properties[propName].(map[string]interface{})
 		if !ok {
-			continue
+			return false
 		}
 
 		names := extractSelectNames(prop)
 		if len(names) == 0 {
-			continue
+			return false
 		}
 
 		if notEquals != "" {
-			excluded := false
 			for _, name := range names {
 				if name == notEquals {
-					excluded = true
-					break
+					return false
 				}
 			}
-			if !excluded {
-				filtered = append(filtered, item)
-			}
-			continue
+			return true
 		}
 
-		matched := false
 		for _, name := range names {
 			if equals != "" && name == equals {
-				matched = true
-				break
+				return true
 			}
 			if re != nil && re.MatchString(name) {
-				matched = true
-				break
+				return true
 			}
 		}
-
-		if matched {
-			filtered = append(filtered, item)
-		}
-	}
-
-	return filtered, nil
+		return false
+	}, nil
 }
 
 func extractSelectNames(prop map[string]interface{}) []string {
@@ -636,20 +626,8 @@ Example:
 			}
 
 			if all {
-				allResults, nextCursor, hasMore, err := fetchAllPages(ctx, startCursor, pageSize, limit, func(ctx context.Context, cursor string, pageSize int) ([]map[string]interface{}, *string, bool, error) {
-					req := &notion.SearchRequest{
-						Filter:      filter,
-						StartCursor: cursor,
-						PageSize:    pageSize,
-					}
-
-					result, err := client.Search(ctx, req)
-					if err != nil {
-						return nil, nil, false, err
-					}
-
-					return result.Results, result.NextCursor, result.HasMore, nil
-				})
+				req := &notion.SearchRequest{Filter: filter}
+				allResults, nextCursor, hasMore, err := collectPages(client.IterSearch(ctx, req, iterOptions(startCursor, pageSize, limit)))
 				if err != nil {
 					return wrapAPIError(err, "list data sources", "data source", "workspace")
 				}
@@ -99,21 +99,8 @@ Example - Fetch all results:
 			}
 
 			if all {
-				allResults, nextCursor, hasMore, err := fetchAllPages(ctx, startCursor, pageSize, limit, func(ctx context.Context, cursor string, pageSize int) ([]map[string]interface{}, *string, bool, error) {
-					req := &notion.SearchRequest{
-						Query:       query,
-						Filter:      filter,
-						StartCursor: cursor,
-						PageSize:    pageSize,
-					}
-
-					result, err := client.Search(ctx, req)
-					if err != nil {
-						return nil, nil, false, err
-					}
-
-					return result.Results, result.NextCursor, result.HasMore, nil
-				})
+				req := &notion.SearchRequest{Query: query, Filter: filter}
+				allResults, nextCursor, hasMore, err := collectPages(client.IterSearch(ctx, req, iterOptions(startCursor, pageSize, limit)))
 				if err != nil {
 					return wrapAPIError(err, "list databases", "database", query)
 				}
@@ -339,6 +326,7 @@ func newDBQueryCmd() *cobra.Command {
 	var startCursor string
 	var pageSize int
 	var all bool
+	var resumePath string
 	var dataSourceID string
 	var selectProperty string
 	var selectEquals string
@@ -365,9 +353,15 @@ The --sorts flag accepts a JSON array of sort objects (supports @file or - for s
 The --sorts-file flag reads sorts JSON from a file (- for stdin).
 Use --page-size to control the number of results per page (max 100).
 Use --start-cursor for pagination.
-Use --all to fetch all pages of results automatically.
+Use --all to fetch all pages of results automatically. With -o ndjson, results
+are streamed as each page arrives and a trailing _meta record carries the
+cursor to resume from with --start-cursor.
+Use --resume <state-file> (with -o ndjson) to checkpoint the cursor after every
+page; rerunning the same command continues where the last run stopped.
 Use --datasource to query a specific data source in a multi-source database.
 Use global --results-only to output just the results array.
+In text mode each row is printed on one line: the title, then the non-empty
+properties in schema order.
 
 AGENT-FRIENDLY FILTER SHORTHANDS:
 You can avoid writing JSON filters for common fields:
@@ -399,6 +393,9 @@ Example - Query with pagination:
 Example - Fetch all results:
   ntn db query 12345678-1234-1234-1234-123456789012 --all
 
+Example - Resumable export (rerun after an interruption to continue):
+  ntn db query 12345678-1234-1234-1234-123456789012 --resume q.state -o ndjson >> rows.ndjson
+
 Note: When using multi-line commands with backslash (\), ensure there are no
 trailing spaces after the backslash. Otherwise the shell may split the command
 incorrectly, causing "accepts 1 arg(s), received N" errors.`,
@@ -516,22 +513,32 @@ incorrectly, causing "accepts 1 arg(s), received N" errors.`,
 			}
 
 			// If --all flag is set, fetch all pages
-			if all {
-				allPages, nextCursor, hasMore, err := fetchAllPages(ctx, startCursor, pageSize, limit, func(ctx context.Context, cursor string, pageSize int) ([]notion.Page, *string, bool, error) {
-					req := &notion.QueryDataSourceRequest{
-						Filter:      filter,
-						Sorts:       sorts,
-						StartCursor: cursor,
-						PageSize:    pageSize,
-					}
-
-					result, err := client.QueryDataSource(ctx, resolvedDataSourceID, req)
-					if err != nil {
-						return nil, nil, false, err
+			if all || resumePath != "" {
+				req := &notion.QueryDataSourceRequest{Filter: filter, Sorts: sorts}
+				opts := iterOptions(startCursor, pageSize, limit)
+				newIter := func(opts notion.IterOptions) *notion.Iterator[notion.Page] {
+					return client.IterQueryDataSource(ctx, resolvedDataSourceID, req, opts)
+				}
+				if output.CanStream(ctx) || resumePath != "" {
+					var transform func(notion.Page) interface{}
+					if selectProperty != "" && (selectEquals != "" || selectNot != "" || selectMatch != "") {
+						keep, err := selectMatcher(selectProperty, selectEquals, selectNot, selectMatch)
+						if err != nil {
+							return err
+						}
+						transform = func(p notion.Page) interface{} {
+							if !keep(p) {
+								return nil
+							}
+							return p
+						}
 					}
+					return streamAllPages(ctx, resumePath, resumeKey("db query "+resolvedDataSourceID, req), opts, newIter, transform, func(err error) error {
+						return wrapAPIError(err, "query database", "database", args[0])
+					})
+				}
 
-					return result.Results, result.NextCursor, result.HasMore, nil
-				})
+				allPages, nextCursor, hasMore, err := collectPages(newIter(opts))
 				if err != nil {
 					return wrapAPIError(err, "query database", "database", args[0])
 				}
@@ -544,6 +551,10 @@ incorrectly, causing "accepts 1 arg(s), received N" errors.`,
 					allPages = filtered
 				}
 
+				if textViewEnabled(ctx) {
+					return printPageRows(ctx, client, resolvedDataSourceID, allPages, hasMore, nextCursor)
+				}
+
 				printer := printerForContext(ctx)
 				return printer.Print(ctx, map[string]interface{}{
 					"object":      "list",
@@ -574,6 +585,10 @@ incorrectly, causing "accepts 1 arg(s), received N" errors.`,
 				result.Results = filtered
 			}
 
+			if textViewEnabled(ctx) {
+				return printPageRows(ctx, client, resolvedDataSourceID, result.Results, result.HasMore, result.NextCursor)
+			}
+
 			// Print result
 			printer := printerForContext(ctx)
 			return printer.Print(ctx, result)
@@ -587,6 +602,7 @@ incorrectly, causing "accepts 1 arg(s), received N" errors.`,
 	cmd.Flags().StringVar(&startCursor, "start-cursor", "", "Pagination cursor")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Number of results per page (max 100)")
 	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of results (may be slow for large datasets)")
+	addResumeFlag(cmd, &resumePath)
 	cmd.Flags().StringVar(&dataSourceID, "datasource", "", "Data source ID to query (optional)")
 	cmd.Flags().StringVar(&selectProperty, "select-property", "", "Property name to match (select, multi_select, or status)")
 	cmd.Flags().StringVar(&selectEquals, "select-equals", "", "Match select name exactly")
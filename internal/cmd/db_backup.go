@@ -27,6 +27,7 @@ func newDBBackupCmd() *cobra.Command {
 	var includeContent bool
 	var incremental bool
 	var format string
+	var concurrency int
 
 	cmd := &cobra.Command{
 		Use:     "backup <database-id-or-name>",
@@ -140,26 +141,10 @@ Example - Custom output directory:
 			}
 
 			// Step 7: Query all pages (handle pagination)
-			var allPages []notion.Page
-			cursor := ""
-			for {
-				req := &notion.QueryDataSourceRequest{
-					Filter:      filter,
-					StartCursor: cursor,
-					PageSize:    100,
-				}
-
-				result, err := client.QueryDataSource(ctx, resolvedDataSourceID, req)
-				if err != nil {
-					return wrapAPIError(err, "query database", "database", args[0])
-				}
-
-				allPages = append(allPages, result.Results...)
-
-				if !result.HasMore || result.NextCursor == nil || *result.NextCursor == "" {
-					break
-				}
-				cursor = *result.NextCursor
+			req := &notion.QueryDataSourceRequest{Filter: filter}
+			allPages, err := client.IterQueryDataSource(ctx, resolvedDataSourceID, req, iterOptions("", NotionMaxPageSize, 0)).Collect()
+			if err != nil {
+				return wrapAPIError(err, "query database", "database", args[0])
 			}
 
 			// Step 8: Write each page
@@ -175,7 +160,7 @@ Example - Custom output directory:
 
 				// Fetch and write block children if --content
 				if includeContent || isMarkdown {
-					blocks, err := fetchExportBlocks(ctx, client, page.ID)
+					blocks, err := fetchExportBlocks(ctx, client, page.ID, concurrency)
 					if err != nil {
 						_, _ = fmt.Fprintf(stderr, "Warning: failed to fetch blocks for page %s: %v\n", page.ID, err)
 						continue
@@ -227,6 +212,7 @@ Example - Custom output directory:
 	cmd.Flags().BoolVar(&includeContent, "content", false, "Include page body (block children)")
 	cmd.Flags().BoolVar(&incremental, "incremental", false, "Only backup pages changed since last run")
 	cmd.Flags().StringVar(&format, "export-format", "json", "Export format for pages (json or markdown)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", notion.DefaultBlockFetchConcurrency, "Maximum parallel block fetches per page")
 
 	return cmd
 }
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/salmonumbrella/notion-cli/internal/cmdutil"
+	"github.com/salmonumbrella/notion-cli/internal/errors"
+)
+
+// inferTypeOverrides are the property types --type may give a column.
+var inferTypeOverrides = keywordSet("title rich_text number select multi_select date checkbox url email phone_number")
+
+// readCSVTable reads the headers and rows of a CSV file.
+func readCSVTable(path string) ([]string, [][]string, error) {
+	records, err := readCSVFile(path)
+	if err != nil {
+		return nil, nil, errors.WrapUserError(err, "failed to read CSV file", "Check that the file exists and is valid CSV")
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.NewUserError("CSV file has no headers", "The first row must contain column headers")
+	}
+	headers := records[0]
+	headers[0] = strings.TrimPrefix(headers[0], "\ufeff")
+	return headers, records[1:], nil
+}
+
+// jsonListSeparator joins the items of a JSON array in a cell. It is not
+// --delimiter, so arrays split back into items whatever --delimiter is,
+// and it cannot occur in the items themselves.
+const jsonListSeparator = "\x1f"
+
+// readJSONTable reads a JSON array or NDJSON stream of objects as a table:
+// the headers are the keys in the order they first appear, and arrays of
+// values are joined with jsonListSeparator so they infer as multi-selects.
+func readJSONTable(path string) ([]string, [][]string, error) {
+	data, err := cmdutil.ReadInputSource(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var objects []json.RawMessage
+	if strings.HasPrefix(data, "[") {
+		if err := json.Unmarshal([]byte(data), &objects); err != nil {
+			return nil, nil, errors.WrapUserError(err, "failed to parse JSON array", "Pass an array of objects or one object per line (NDJSON)")
+		}
+	} else {
+		dec := json.NewDecoder(strings.NewReader(data))
+		for {
+			var object json.RawMessage
+			if err := dec.Decode(&object); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, nil, errors.WrapUserError(err, "failed to parse NDJSON", "Pass an array of objects or one object per line (NDJSON)")
+			}
+			objects = append(objects, object)
+		}
+	}
+	if len(objects) == 0 {
+		return nil, nil, errors.NewUserError("JSON input has no records", "Pass an array of objects or one object per line (NDJSON)")
+	}
+
+	var headers []string
+	index := map[string]int{}
+	var records []map[string]string
+	for i, raw := range objects {
+		keys, values, err := jsonObjectFields(raw)
+		if err != nil {
+			return nil, nil, errors.WrapUserError(err, fmt.Sprintf("record %d is not a JSON object", i+1), "Every record must be an object of column values")
+		}
+		record := make(map[string]string, len(keys))
+		for _, key := range keys {
+			if _, ok := index[key]; !ok {
+				index[key] = len(headers)
+				headers = append(headers, key)
+			}
+			record[key] = jsonCellValue(values[key], jsonListSeparator)
+		}
+		records = append(records, record)
+	}
+
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		rows[i] = make([]string, len(headers))
+		for key, value := range record {
+			rows[i][index[key]] = value
+		}
+	}
+	return headers, rows, nil
+}
+
+// jsonObjectFields returns the keys of a JSON object in document order
+// with their raw values.
+func jsonObjectFields(raw json.RawMessage) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected an object")
+	}
+	var keys []string
+	values := map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+	return keys, values, nil
+}
+
+// jsonCellValue renders a JSON value as a cell: scalars as text, arrays of
+// scalars joined with delimiter, and other values as compact JSON.
+func jsonCellValue(raw json.RawMessage, delimiter string) string {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return ""
+	}
+	if items, ok := value.([]interface{}); ok {
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			text, ok := jsonScalarText(item)
+			if !ok {
+				parts = nil
+				break
+			}
+			if text != "" {
+				parts = append(parts, text)
+			}
+		}
+		if parts != nil || len(items) == 0 {
+			return strings.Join(parts, delimiter)
+		}
+	}
+	if text, ok := jsonScalarText(value); ok {
+		return text
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return string(raw)
+	}
+	return compact.String()
+}
+
+func jsonScalarText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// parseTypeOverrides parses repeated "Column=type" --type values.
+func parseTypeOverrides(raw []string) (map[string]string, error) {
+	overrides := map[string]string{}
+	for _, entry := range raw {
+		column, propType, ok := strings.Cut(entry, "=")
+		column, propType = strings.TrimSpace(column), strings.ToLower(strings.TrimSpace(propType))
+		if !ok || column == "" || propType == "" {
+			return nil, errors.NewUserError(
+				fmt.Sprintf("invalid --type %q", entry),
+				"Use --type Column=type, e.g. --type Status=select",
+			)
+		}
+		if !inferTypeOverrides[propType] {
+			types := make([]string, 0, len(inferTypeOverrides))
+			for t := range inferTypeOverrides {
+				types = append(types, t)
+			}
+			sort.Strings(types)
+			return nil, errors.NewUserError(
+				fmt.Sprintf("unsupported property type %q for column %q", propType, column),
+				"Use one of: "+strings.Join(types, ", "),
+			)
+		}
+		overrides[column] = propType
+	}
+	return overrides, nil
+}
+
+// applyTypeOverrides gives columns the types from --type. Columns made
+// selects get the options found in their values, and a column made the
+// title replaces the inferred one, which becomes text.
+func applyTypeOverrides(columns []csvColumn, rows [][]string, overrides map[string]string, delimiter string) error {
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propType := overrides[name]
+		i := -1
+		for j, column := range columns {
+			if strings.EqualFold(column.Name, name) {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			known := make([]string, 0, len(columns))
+			for _, column := range columns {
+				known = append(known, column.Name)
+			}
+			return errors.NewUserError(
+				fmt.Sprintf("--type column %q is not in the input", name),
+				"Columns: "+strings.Join(known, ", "),
+			)
+		}
+		if propType == "title" {
+			for j := range columns {
+				if columns[j].Type == "title" {
+					columns[j].Type, columns[j].Options = "rich_text", nil
+				}
+			}
+		}
+		columns[i].Type, columns[i].Options = propType, nil
+		if propType == "select" || propType == "multi_select" {
+			columns[i].Options = columnOptions(rows, i, propType == "multi_select", delimiter)
+		}
+	}
+
+	titles := 0
+	for _, column := range columns {
+		if column.Type == "title" {
+			titles++
+		}
+	}
+	if titles != 1 {
+		return errors.NewUserError(
+			"the database needs exactly one title column",
+			"The first column is the title unless --type Column=title picks another",
+		)
+	}
+	return nil
+}
+
+// columnOptions lists the distinct values of a column, split on delimiter
+// for multi-selects, in the order they first appear.
+func columnOptions(rows [][]string, index int, multi bool, delimiter string) []string {
+	var options []string
+	seen := map[string]bool{}
+	for _, row := range rows {
+		if index >= len(row) {
+			continue
+		}
+		items := []string{strings.ReplaceAll(strings.TrimSpace(row[index]), jsonListSeparator, ", ")}
+		if multi && delimiter != "" {
+			items = splitCSVList(row[index], delimiter)
+		}
+		for _, item := range items {
+			if item != "" && !seen[item] {
+				seen[item] = true
+				options = append(options, item)
+			}
+		}
+	}
+	return options
+}
+
+// inputTitle names a database after its input file.
+func inputTitle(path string) string {
+	if path == "-" {
+		return ""
+	}
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
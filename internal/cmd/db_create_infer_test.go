@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDBCreate_FromCSV(t *testing.T) {
+	server := newImportDirServer(t, nil)
+
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "Contacts.csv")
+	csv := "\ufeffName,Email,Age,Joined,Active,Team,Stage,Notes\n" +
+		"Ada,ada@example.invalid,36,2026-05-01,yes,eng;ops,Lead,first\n" +
+		"Grace,grace@example.invalid,45,\"May 2, 2026\",no,eng,Lead,second\n" +
+		"Linus,linus@example.invalid,,2026-05-03,yes,ops,Customer,bad;row\n"
+	if err := os.WriteFile(csvFile, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parentID := "bbbbbbbb-0000-0000-0000-000000000000"
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"db", "create", "--parent", parentID, "--from-csv", csvFile,
+		"--type", "stage=select", "--type", "Notes=multi_select", "--import", "-o", "json"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("db create failed: %v\nstderr=%s", err, errBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "Imported 3 rows into database Contacts") {
+		t.Errorf("summary = %q", errBuf.String())
+	}
+
+	if len(server.databases) != 1 {
+		t.Fatalf("created %d databases, want 1", len(server.databases))
+	}
+	var dbID string
+	var db map[string]any
+	for id, req := range server.databases {
+		dbID, db = id, req
+	}
+	if !strings.Contains(out.String(), dbID) {
+		t.Errorf("output should print the database:\n%s", out.String())
+	}
+	if title, _ := json.Marshal(db["title"]); !strings.Contains(string(title), `"content":"Contacts"`) {
+		t.Errorf("database title = %s", title)
+	}
+	props := db["initial_data_source"].(map[string]any)["properties"].(map[string]any)
+	types := map[string]string{}
+	for name, prop := range props {
+		for propType := range prop.(map[string]any) {
+			types[name] = propType
+		}
+	}
+	wantTypes := map[string]string{
+		"Name": "title", "Email": "email", "Age": "number", "Joined": "date", "Active": "checkbox",
+		"Team": "multi_select", "Stage": "select", "Notes": "multi_select",
+	}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Errorf("schema = %v, want %v", types, wantTypes)
+	}
+	options, _ := json.Marshal(props["Stage"])
+	if string(options) != `{"select":{"options":[{"name":"Lead"},{"name":"Customer"}]}}` {
+		t.Errorf("Stage options = %s", options)
+	}
+
+	if len(server.created) != 3 {
+		t.Fatalf("created %d rows, want 3", len(server.created))
+	}
+	if got := server.created[0]["parent"].(map[string]any)["data_source_id"]; got != "ds-"+dbID {
+		t.Errorf("row parent = %v", got)
+	}
+	grace, _ := json.Marshal(server.created[1]["properties"])
+	for _, want := range []string{
+		`"Joined":{"date":{"start":"2026-05-02"}}`,
+		`"Active":{"checkbox":false}`,
+		`"Age":{"number":45}`,
+		`"Team":{"multi_select":[{"name":"eng"}]}`,
+	} {
+		if !strings.Contains(string(grace), want) {
+			t.Errorf("row properties missing %s:\n%s", want, grace)
+		}
+	}
+}
+
+func TestReadJSONTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.ndjson")
+	ndjson := `{"title": "One", "count": 1, "done": true, "tags": ["a", "b"], "meta": {"k": 1}}
+{"title": "Two", "extra": null, "count": 2.5, "tags": []}
+`
+	if err := os.WriteFile(path, []byte(ndjson), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	headers, rows, err := readJSONTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"title", "count", "done", "tags", "meta", "extra"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("headers = %q, want %q", headers, want)
+	}
+	want := [][]string{
+		{"One", "1", "true", "a" + jsonListSeparator + "b", `{"k":1}`, ""},
+		{"Two", "2.5", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+
+	columns := inferCSVSchema(headers, rows, jsonListSeparator)
+	if columns[1].Type != "number" || columns[2].Type != "checkbox" || columns[3].Type != "multi_select" {
+		t.Errorf("columns = %+v", columns)
+	}
+
+	// With multi-select inference off, arrays become text, and --type still
+	// splits them into options.
+	columns = inferCSVSchema(headers, rows, "")
+	if columns[3].Type != "rich_text" {
+		t.Errorf("tags type = %q, want rich_text", columns[3].Type)
+	}
+	if got := csvCellValue(rows[0][3], columns[3], jsonListSeparator); got != "a, b" {
+		t.Errorf("tags text = %q, want %q", got, "a, b")
+	}
+	if err := applyTypeOverrides(columns, rows, map[string]string{"tags": "multi_select"}, jsonListSeparator); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(columns[3].Options, []string{"a", "b"}) {
+		t.Errorf("tags options = %q", columns[3].Options)
+	}
+	if got := csvCellValue(rows[0][3], columns[3], jsonListSeparator); got != "a;b" {
+		t.Errorf("tags value = %q, want %q", got, "a;b")
+	}
+
+	arrayPath := filepath.Join(t.TempDir(), "records.json")
+	if err := os.WriteFile(arrayPath, []byte(`[{"b": 1, "a": 2}, {"a": 3, "c": "x"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	headers, rows, err = readJSONTable(arrayPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(headers, []string{"b", "a", "c"}) || !reflect.DeepEqual(rows[1], []string{"", "3", "x"}) {
+		t.Errorf("array table = %q %q", headers, rows)
+	}
+}
+
+func TestApplyTypeOverrides(t *testing.T) {
+	rows := [][]string{{"1", "Ada"}, {"2", "Grace"}}
+	columns := inferCSVSchema([]string{"ID", "Name"}, rows, ";")
+	if err := applyTypeOverrides(columns, rows, map[string]string{"Name": "title"}, ";"); err != nil {
+		t.Fatal(err)
+	}
+	if columns[0].Type != "rich_text" || columns[1].Type != "title" {
+		t.Errorf("columns = %+v", columns)
+	}
+
+	if err := applyTypeOverrides(columns, rows, map[string]string{"Missing": "number"}, ";"); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+	if err := applyTypeOverrides(columns, rows, map[string]string{"Name": "number"}, ";"); err == nil {
+		t.Error("expected an error when no column is the title")
+	}
+	if _, err := parseTypeOverrides([]string{"Name=formula"}); err == nil {
+		t.Error("expected an error for an unsupported type")
+	}
+}
@@ -19,7 +19,7 @@ func TestDBList_WrapsEnvelope(t *testing.T) {
 		w.Header().Set("Content-Type", "application/json")
 
 		// First call returns one result with has_more=true; second call returns
-		// one result with has_more=false. fetchAllPages merges both pages.
+		// one result with has_more=false. the iterator merges both pages.
 		if callCount == 1 {
 			next := "cursor-2"
 			_ = json.NewEncoder(w).Encode(map[string]interface{}{
@@ -19,6 +19,11 @@ func newDBCreateCmd() *cobra.Command {
 	var iconJSON string
 	var coverJSON string
 	var isInline bool
+	var fromCSV string
+	var fromJSON string
+	var typeOverrides []string
+	var delimiter string
+	var importRows bool
 
 	cmd := &cobra.Command{
 		Use:     "create",
@@ -31,6 +36,14 @@ The --title flag specifies the database title as plain text.
 The --properties flag accepts a JSON object defining the database schema (required).
 The --datasource-title flag sets the title of the initial data source (optional).
 
+Instead of --properties, --from-csv or --from-json (an array of objects or
+NDJSON) infers the schema from a file: the first column becomes the title,
+and the other columns become numbers, dates, checkboxes, URLs, emails,
+selects with the values as options, multi-selects when values contain the
+--delimiter or are JSON arrays, or text. Override a column with --type Column=select, and add
+--import to also create a row for every record. The title defaults to the
+file name.
+
 Example - Create a simple task database:
   ntn db create \
     --parent 12345678-1234-1234-1234-123456789012 \
@@ -42,7 +55,11 @@ Example - Create with description:
     --parent 12345678-1234-1234-1234-123456789012 \
     --title "Projects" \
     --description '[{"type":"text","text":{"content":"My projects database"}}]' \
-    --properties '{"Name":{"title":{}}}'`,
+    --properties '{"Name":{"title":{}}}'
+
+Example - Create a database from a CSV file and import its rows:
+  ntn db create --parent 12345678-1234-1234-1234-123456789012 \
+    --from-csv contacts.csv --type Team=multi_select --import`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			sf := SkillFileFromContext(ctx)
@@ -50,7 +67,15 @@ Example - Create with description:
 			if parentID == "" {
 				return fmt.Errorf("--parent flag is required")
 			}
-			if !hasJSONInput(propertiesJSON, propertiesFile) {
+			inferred := fromCSV != "" || fromJSON != ""
+			switch {
+			case fromCSV != "" && fromJSON != "":
+				return fmt.Errorf("use only one of --from-csv or --from-json")
+			case inferred && hasJSONInput(propertiesJSON, propertiesFile):
+				return fmt.Errorf("--properties cannot be combined with --from-csv or --from-json")
+			case !inferred && (importRows || len(typeOverrides) > 0):
+				return fmt.Errorf("--import and --type require --from-csv or --from-json")
+			case !inferred && !hasJSONInput(propertiesJSON, propertiesFile):
 				return fmt.Errorf("--properties or --properties-file is required")
 			}
 
@@ -60,11 +85,51 @@ Example - Create with description:
 			}
 			parentID = normalizedParent
 
-			properties, resolvedProperties, err := resolveAndDecodeJSON[map[string]map[string]interface{}](propertiesJSON, propertiesFile, "failed to parse properties JSON")
-			if err != nil {
-				return err
+			var properties map[string]map[string]interface{}
+			var columns []csvColumn
+			var rows [][]string
+			// listDelimiter separates the items of a cell: --delimiter for
+			// CSV, and a fixed separator for the arrays of JSON input.
+			listDelimiter := delimiter
+			if inferred {
+				var headers []string
+				source := fromCSV
+				if fromCSV != "" {
+					headers, rows, err = readCSVTable(fromCSV)
+				} else {
+					source = fromJSON
+					headers, rows, err = readJSONTable(fromJSON)
+					listDelimiter = jsonListSeparator
+				}
+				if err != nil {
+					return err
+				}
+				overrides, err := parseTypeOverrides(typeOverrides)
+				if err != nil {
+					return err
+				}
+				// --delimiter "" keeps JSON arrays from inferring as
+				// multi-selects, but --type can still split them.
+				inferDelimiter := listDelimiter
+				if delimiter == "" {
+					inferDelimiter = ""
+				}
+				columns = inferCSVSchema(headers, rows, inferDelimiter)
+				if err := applyTypeOverrides(columns, rows, overrides, listDelimiter); err != nil {
+					return err
+				}
+				properties = databaseProperties(columns)
+				if titleText == "" {
+					titleText = inputTitle(source)
+				}
+			} else {
+				var resolvedProperties string
+				properties, resolvedProperties, err = resolveAndDecodeJSON[map[string]map[string]interface{}](propertiesJSON, propertiesFile, "failed to parse properties JSON")
+				if err != nil {
+					return err
+				}
+				propertiesJSON = resolvedProperties
 			}
-			propertiesJSON = resolvedProperties
 
 			parent := map[string]interface{}{
 				"type":    "page_id",
@@ -143,7 +208,51 @@ Example - Create with description:
 				return wrapAPIError(err, "create database", "database", parentID)
 			}
 
-			return printerForContext(ctx).Print(ctx, database)
+			var failed []csvRowError
+			if importRows {
+				dataSourceID := ""
+				if len(database.DataSources) > 0 {
+					dataSourceID = database.DataSources[0].ID
+				} else if dataSourceID, err = resolveDataSourceID(ctx, client, database.ID, ""); err != nil {
+					return err
+				}
+				cells := &csvCells{client: client, sf: sf}
+				mappings := columnMappings(columns)
+				imported := 0
+				for i, row := range rows {
+					for j := range row {
+						if j < len(columns) {
+							row[j] = csvCellValue(row[j], columns[j], listDelimiter)
+						}
+					}
+					props, err := cells.properties(ctx, row, mappings)
+					if err != nil {
+						failed = append(failed, csvRowError{row: i + 1, err: err})
+						continue
+					}
+					if props == nil {
+						continue
+					}
+					if _, err := client.CreatePage(ctx, &notion.CreatePageRequest{
+						Parent:     map[string]interface{}{"data_source_id": dataSourceID},
+						Properties: props,
+					}); err != nil {
+						failed = append(failed, csvRowError{row: i + 1, err: fmt.Errorf("failed to create page: %w", err)})
+						continue
+					}
+					imported++
+				}
+				dbName := titleText
+				if dbName == "" {
+					dbName = database.ID
+				}
+				_, _ = fmt.Fprintf(stderrFromContext(ctx), "Imported %d rows into database %s\n", imported, dbName)
+			}
+
+			if err := printerForContext(ctx).Print(ctx, database); err != nil {
+				return err
+			}
+			return reportCSVRowErrors(stderrFromContext(ctx), failed, len(rows))
 		},
 	}
 
@@ -156,6 +265,11 @@ Example - Create with description:
 	cmd.Flags().StringVar(&iconJSON, "icon", "", "Database icon as JSON object")
 	cmd.Flags().StringVar(&coverJSON, "cover", "", "Database cover as JSON object")
 	cmd.Flags().BoolVar(&isInline, "inline", false, "Create as inline database")
+	cmd.Flags().StringVar(&fromCSV, "from-csv", "", "Infer the properties from a CSV file (- for stdin)")
+	cmd.Flags().StringVar(&fromJSON, "from-json", "", "Infer the properties from a JSON array or NDJSON file (- for stdin)")
+	cmd.Flags().StringArrayVar(&typeOverrides, "type", nil, "Property type for a column as Column=type, overriding inference (repeatable)")
+	cmd.Flags().StringVar(&delimiter, "delimiter", ";", "Separator of multi-select values in a CSV cell (\"\" disables multi-select inference)")
+	cmd.Flags().BoolVar(&importRows, "import", false, "With --from-csv or --from-json, also create a row for every record")
 
 	// Flag aliases
 	flagAlias(cmd.Flags(), "parent", "pa")
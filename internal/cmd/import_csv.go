@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strconv"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -22,6 +22,8 @@ func newImportCSVCmd() *cobra.Command {
 		batchSize int
 		dryRun    bool
 		skipRows  int
+		key       string
+		prune     bool
 	)
 
 	cmd := &cobra.Command{
@@ -36,8 +38,16 @@ database properties by name (case-sensitive first, then case-insensitive).
 Use --column-map to override the mapping: "CSV Header=Notion Property,Name=Title"
 
 Supported property types:
-  - title, rich_text, number, select, multi_select (semicolon-separated),
-    date, checkbox, url, email, phone_number
+  - title, rich_text, number, select, status, multi_select (semicolon-separated),
+    date (ranges as "start→end" or start/end), checkbox, url, email, phone_number
+  - people: emails, names, user aliases or IDs (semicolon-separated)
+  - relation: titles of rows in the related database, page IDs or URLs
+    (semicolon-separated)
+  - files: URLs, attached as external files, or local paths relative to the
+    CSV, which are uploaded (semicolon-separated)
+
+Rows with values that cannot be converted, such as an unknown user or a
+missing file, are skipped and listed at the end; the other rows are imported.
 
 Examples:
   ntn import csv abc123 --file data.csv
@@ -45,7 +55,16 @@ Examples:
   ntn import csv abc123 --file - < data.csv
   ntn import csv abc123 --file data.csv --dry-run
   ntn import csv abc123 --file data.csv --batch-size 5
-  ntn import csv abc123 --file data.csv --skip-rows 2`,
+  ntn import csv abc123 --file data.csv --skip-rows 2
+  ntn import csv abc123 --file data.csv --key "Email" --prune
+
+With --key, re-running an import updates rows instead of duplicating them:
+the rows of the database are matched by the key column's property (a title,
+rich_text, number or unique_id). Matched rows get the properties whose
+values changed, rows without a match are created, and with --prune rows
+missing from the CSV are archived. Empty cells leave properties unchanged.
+Rows keyed by a unique_id that matches no row are skipped, as Notion
+assigns unique IDs to the rows it creates.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if filePath == "" {
@@ -79,6 +98,10 @@ Examples:
 				}
 			}
 
+			if prune && key == "" {
+				return errors.NewUserError("--prune requires --key", "Pass the column that identifies rows with --key")
+			}
+
 			if len(dataRows) == 0 && !dryRun {
 				return errors.NewUserError("CSV file has no data rows", "Add data rows after the header row")
 			}
@@ -126,6 +149,41 @@ Examples:
 				)
 			}
 
+			baseDir := "."
+			if filePath != "-" {
+				baseDir = filepath.Dir(filePath)
+			}
+			cells := &csvCells{
+				client:  client,
+				sf:      SkillFileFromContext(ctx),
+				dbProps: db.Properties,
+				baseDir: baseDir,
+				dryRun:  dryRun,
+			}
+
+			var upsert *csvUpsert
+			if key != "" {
+				keyMapping, err := upsertKeyMapping(mappings, key)
+				if err != nil {
+					return err
+				}
+				dataSourceID, err := resolveDataSourceID(ctx, client, dbID, "")
+				if err != nil {
+					return err
+				}
+				upsert = &csvUpsert{
+					client:       client,
+					stderr:       stderr,
+					cells:        cells,
+					parent:       map[string]interface{}{"database_id": dbID},
+					dataSourceID: dataSourceID,
+					mappings:     mappings,
+					key:          keyMapping,
+					prune:        prune,
+					dryRun:       dryRun,
+				}
+			}
+
 			// Dry run
 			if dryRun {
 				printer := NewDryRunPrinter(stderr)
@@ -142,6 +200,29 @@ Examples:
 				if skipRows > 0 {
 					printer.Field("Skipped rows", fmt.Sprintf("%d", skipRows))
 				}
+				var failed []csvRowError
+				if upsert != nil {
+					if err := upsert.run(ctx, dataRows); err != nil {
+						return err
+					}
+					failed = upsert.failed
+					printer.Field("Key column", fmt.Sprintf("%s -> %s (%s)", upsert.key.csvHeader, upsert.key.notionProp, upsert.key.propType))
+					printer.Field("Rows to create", fmt.Sprintf("%d", upsert.created))
+					printer.Field("Rows to update", fmt.Sprintf("%d", upsert.updated))
+					printer.Field("Rows unchanged", fmt.Sprintf("%d", upsert.unchanged))
+					if upsert.skipped > 0 {
+						printer.Field("Rows skipped", fmt.Sprintf("%d", upsert.skipped))
+					}
+					if prune {
+						printer.Field("Rows to archive", fmt.Sprintf("%d", upsert.archived))
+					}
+				} else {
+					for i, row := range dataRows {
+						if _, err := cells.properties(ctx, row, mappings); err != nil {
+							failed = append(failed, csvRowError{row: i + 1, err: err})
+						}
+					}
+				}
 
 				printer.Section("Column mappings:")
 				for _, m := range mappings {
@@ -168,7 +249,25 @@ Examples:
 				}
 
 				printer.Footer()
-				return nil
+				return reportCSVRowErrors(stderr, failed, len(dataRows))
+			}
+
+			dbName := extractDatabaseTitle(*db)
+			if dbName == "" {
+				dbName = dbID
+			}
+
+			if upsert != nil {
+				if err := upsert.run(ctx, dataRows); err != nil {
+					return err
+				}
+				summary := fmt.Sprintf("Upserted %d rows into database %s: %d created, %d updated, %d unchanged, %d archived",
+					upsert.created+upsert.updated+upsert.unchanged, dbName, upsert.created, upsert.updated, upsert.unchanged, upsert.archived)
+				if upsert.skipped > 0 {
+					summary += fmt.Sprintf(", %d skipped", upsert.skipped)
+				}
+				_, _ = fmt.Fprintln(stderr, summary)
+				return reportCSVRowErrors(stderr, upsert.failed, len(dataRows))
 			}
 
 			// Import rows in batches
@@ -177,6 +276,7 @@ Examples:
 			}
 
 			var totalCreated int
+			var failed []csvRowError
 			for i := 0; i < len(dataRows); i += batchSize {
 				end := i + batchSize
 				if end > len(dataRows) {
@@ -185,7 +285,11 @@ Examples:
 
 				batch := dataRows[i:end]
 				for rowIdx, row := range batch {
-					props := buildPageProperties(row, mappings)
+					props, err := cells.properties(ctx, row, mappings)
+					if err != nil {
+						failed = append(failed, csvRowError{row: i + rowIdx + 1, err: err})
+						continue
+					}
 					if props == nil {
 						continue
 					}
@@ -197,23 +301,18 @@ Examples:
 						Properties: props,
 					}
 
-					_, err := client.CreatePage(ctx, req)
-					if err != nil {
-						return fmt.Errorf("failed to create page for row %d: %w", i+rowIdx+1, err)
+					if _, err := client.CreatePage(ctx, req); err != nil {
+						failed = append(failed, csvRowError{row: i + rowIdx + 1, err: fmt.Errorf("failed to create page: %w", err)})
+						continue
 					}
 					totalCreated++
 				}
 			}
 
 			// Print summary
-			dbName := extractDatabaseTitle(*db)
-			if dbName != "" {
-				_, _ = fmt.Fprintf(stderr, "Imported %d pages into database %s\n", totalCreated, dbName)
-			} else {
-				_, _ = fmt.Fprintf(stderr, "Imported %d pages into database %s\n", totalCreated, dbID)
-			}
+			_, _ = fmt.Fprintf(stderr, "Imported %d pages into database %s\n", totalCreated, dbName)
 
-			return nil
+			return reportCSVRowErrors(stderr, failed, len(dataRows))
 		},
 	}
 
@@ -222,6 +321,8 @@ Examples:
 	cmd.Flags().IntVar(&batchSize, "batch-size", 10, "Number of pages to create per batch")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview import without creating pages")
 	cmd.Flags().IntVar(&skipRows, "skip-rows", 0, "Number of rows to skip after header")
+	cmd.Flags().StringVar(&key, "key", "", "Column identifying rows: update matching rows instead of creating duplicates")
+	cmd.Flags().BoolVar(&prune, "prune", false, "With --key, archive rows missing from the CSV")
 
 	return cmd
 }
@@ -368,84 +469,13 @@ func buildPageProperties(row []string, mappings []columnMapping) map[string]inte
 }
 
 // csvValueToProperty converts a CSV string value to a Notion property value
-// based on the property type.
+// based on the property type. Values that cannot be converted are skipped.
 func csvValueToProperty(value string, propType string) map[string]interface{} {
-	switch propType {
-	case "title":
-		return map[string]interface{}{
-			"title": []map[string]interface{}{
-				{"text": map[string]interface{}{"content": value}},
-			},
-		}
-
-	case "rich_text":
-		return map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{"text": map[string]interface{}{"content": value}},
-			},
-		}
-
-	case "number":
-		n, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			return nil
-		}
-		return map[string]interface{}{
-			"number": n,
-		}
-
-	case "select":
-		return map[string]interface{}{
-			"select": map[string]interface{}{"name": value},
-		}
-
-	case "multi_select":
-		parts := strings.Split(value, ";")
-		var options []map[string]interface{}
-		for _, p := range parts {
-			p = strings.TrimSpace(p)
-			if p != "" {
-				options = append(options, map[string]interface{}{"name": p})
-			}
-		}
-		if len(options) == 0 {
-			return nil
-		}
-		return map[string]interface{}{
-			"multi_select": options,
-		}
-
-	case "date":
-		return map[string]interface{}{
-			"date": map[string]interface{}{"start": value},
-		}
-
-	case "checkbox":
-		lower := strings.ToLower(value)
-		checked := lower == "true" || lower == "yes" || lower == "1"
-		return map[string]interface{}{
-			"checkbox": checked,
-		}
-
-	case "url":
-		return map[string]interface{}{
-			"url": value,
-		}
-
-	case "email":
-		return map[string]interface{}{
-			"email": value,
-		}
-
-	case "phone_number":
-		return map[string]interface{}{
-			"phone_number": value,
-		}
-
-	default:
-		// Unsupported property type — skip
+	payload, err := notion.PropertyPayload(propType, value)
+	if err != nil {
 		return nil
 	}
+	return payload
 }
 
 // extractDatabaseTitle is defined in skill.go
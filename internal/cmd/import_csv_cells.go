@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/salmonumbrella/notion-cli/internal/cmdutil"
+	"github.com/salmonumbrella/notion-cli/internal/errors"
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+	"github.com/salmonumbrella/notion-cli/internal/skill"
+)
+
+// csvCells converts the cells of CSV rows to property values. Unlike
+// buildPageProperties it resolves people by email, name or skill alias and
+// relations by the title of the related row, uploads local files, and
+// reports the cells it cannot convert instead of dropping them.
+type csvCells struct {
+	client  *notion.Client
+	sf      *skill.SkillFile
+	dbProps map[string]map[string]interface{}
+	// baseDir is where relative file paths are found: the CSV's directory.
+	baseDir string
+	// dryRun checks that local files exist instead of uploading them.
+	dryRun bool
+
+	// users maps lowercased emails and names to user IDs, loaded on first use.
+	users map[string][]string
+	// titles maps a related data source to its rows' lowercased titles.
+	titles  map[string]map[string][]string
+	uploads map[string]string
+}
+
+// csvRowError records why a CSV row was not imported.
+type csvRowError struct {
+	row int
+	err error
+}
+
+// properties converts a row to page properties. It returns nil for a row
+// without values, and an error listing every cell that failed.
+func (c *csvCells) properties(ctx context.Context, row []string, mappings []columnMapping) (map[string]interface{}, error) {
+	props := make(map[string]interface{})
+	var failed []string
+	for _, m := range mappings {
+		value := ""
+		if m.csvIndex < len(row) {
+			value = strings.TrimSpace(row[m.csvIndex])
+		}
+		// Read-only columns, such as a unique ID used as --key, are not written.
+		if value == "" || notion.IsReadOnlyPropertyType(m.propType) {
+			continue
+		}
+		prop, err := c.cell(ctx, value, m)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", m.csvHeader, err))
+			continue
+		}
+		props[m.notionProp] = prop
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	if len(props) == 0 {
+		return nil, nil
+	}
+	return props, nil
+}
+
+// cell converts one non-empty cell.
+func (c *csvCells) cell(ctx context.Context, value string, m columnMapping) (map[string]interface{}, error) {
+	switch m.propType {
+	case "people":
+		var ids []string
+		for _, item := range splitCSVItems(value) {
+			id, err := c.user(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return notion.PropertyPayload("people", strings.Join(ids, ","))
+	case "relation":
+		var ids []string
+		for _, item := range splitCSVItems(value) {
+			id, err := c.relatedPage(ctx, item, m.notionProp)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return notion.PropertyPayload("relation", strings.Join(ids, ","))
+	case "files":
+		return c.files(ctx, value)
+	case "date":
+		// Dates as Notion exports them, "May 1, 2026 → May 3, 2026", become ISO.
+		if date, ok := parseCSVDate(value); ok {
+			value = date
+		}
+	}
+	return notion.PropertyPayload(m.propType, value)
+}
+
+// splitCSVItems splits a people, relation or files cell into its items.
+// Items are separated by semicolons, as multi-selects are, so titles and
+// names such as "Acme, Inc." keep their commas.
+func splitCSVItems(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// notionIDValue returns the ID in a bare Notion ID or URL.
+func notionIDValue(value string) (string, bool) {
+	id, err := cmdutil.NormalizeNotionID(value)
+	if err != nil || !looksLikeUUID(id) {
+		return "", false
+	}
+	return id, true
+}
+
+// user resolves a skill alias, a user ID, an email or a user name.
+func (c *csvCells) user(ctx context.Context, value string) (string, error) {
+	if c.sf != nil {
+		if id, ok := c.sf.ResolveUser(value); ok {
+			return id, nil
+		}
+	}
+	if id, ok := notionIDValue(value); ok {
+		return id, nil
+	}
+
+	if c.users == nil {
+		users, err := c.client.IterUsers(ctx, iterOptions("", NotionMaxPageSize, 0)).Collect()
+		if err != nil {
+			return "", fmt.Errorf("failed to list users: %w", err)
+		}
+		c.users = map[string][]string{}
+		for _, u := range users {
+			if u.Person != nil && u.Person.Email != "" {
+				email := strings.ToLower(u.Person.Email)
+				c.users[email] = append(c.users[email], u.ID)
+			}
+			if u.Name != "" {
+				name := strings.ToLower(u.Name)
+				c.users[name] = append(c.users[name], u.ID)
+			}
+		}
+	}
+	switch ids := c.users[strings.ToLower(value)]; len(ids) {
+	case 0:
+		return "", fmt.Errorf("no user with email or name %q", value)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d users are named %q; use the email instead", len(ids), value)
+	}
+}
+
+// relatedPage resolves a page ID, a Notion URL or the title of a row in
+// the data source the relation property points to.
+func (c *csvCells) relatedPage(ctx context.Context, value, propName string) (string, error) {
+	if id, ok := notionIDValue(value); ok {
+		return id, nil
+	}
+
+	dataSourceID, err := c.relatedDataSource(ctx, propName)
+	if err != nil {
+		return "", err
+	}
+	if c.titles == nil {
+		c.titles = map[string]map[string][]string{}
+	}
+	titles, ok := c.titles[dataSourceID]
+	if !ok {
+		pages, err := c.client.IterQueryDataSource(ctx, dataSourceID, &notion.QueryDataSourceRequest{}, iterOptions("", NotionMaxPageSize, 0)).Collect()
+		if err != nil {
+			return "", fmt.Errorf("failed to query related database: %w", err)
+		}
+		titles = map[string][]string{}
+		for _, page := range pages {
+			title := strings.ToLower(strings.TrimSpace(extractPageTitleFromProperties(page.Properties)))
+			titles[title] = append(titles[title], page.ID)
+		}
+		c.titles[dataSourceID] = titles
+	}
+	switch ids := titles[strings.ToLower(value)]; len(ids) {
+	case 0:
+		return "", fmt.Errorf("no related row titled %q", value)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d related rows are titled %q; use the page ID or URL instead", len(ids), value)
+	}
+}
+
+// relatedDataSource returns the data source a relation property points to.
+func (c *csvCells) relatedDataSource(ctx context.Context, propName string) (string, error) {
+	relation, _ := c.dbProps[propName]["relation"].(map[string]interface{})
+	if id, _ := relation["data_source_id"].(string); id != "" {
+		return id, nil
+	}
+	if id, _ := relation["database_id"].(string); id != "" {
+		return resolveDataSourceID(ctx, c.client, id, "")
+	}
+	return "", fmt.Errorf("cannot find the database property %q relates to", propName)
+}
+
+// files attaches URLs as external files and uploads local paths, once per
+// file.
+func (c *csvCells) files(ctx context.Context, value string) (map[string]interface{}, error) {
+	pv := &notion.PropertyValue{Type: "files", Files: []notion.FileReference{}}
+	for _, item := range splitCSVItems(value) {
+		if isWebURL(item) {
+			external, err := notion.ParsePropertyValue("files", item)
+			if err != nil {
+				return nil, err
+			}
+			pv.Files = append(pv.Files, external.Files...)
+			continue
+		}
+		path := item
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.baseDir, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("file %q is neither a URL nor a readable file", item)
+		}
+		if c.uploads == nil {
+			c.uploads = map[string]string{}
+		}
+		id, ok := c.uploads[path]
+		if !ok && !c.dryRun {
+			upload, err := uploadLocalFile(ctx, c.client, path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to upload %s: %w", item, err)
+			}
+			id = upload.ID
+			c.uploads[path] = id
+		}
+		pv.Files = append(pv.Files, notion.FileReference{
+			Name:       filepath.Base(path),
+			Type:       "file_upload",
+			FileUpload: &notion.FileUploadRef{ID: id},
+		})
+	}
+	return pv.Encode()
+}
+
+// reportCSVRowErrors lists the rows that were not imported and returns an
+// error when there are any.
+func reportCSVRowErrors(w io.Writer, failed []csvRowError, total int) error {
+	if len(failed) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(w, "%d of %d rows failed:\n", len(failed), total)
+	for _, f := range failed {
+		_, _ = fmt.Fprintf(w, "  row %d: %v\n", f.row, f.err)
+	}
+	return errors.NewUserError(
+		fmt.Sprintf("%d rows could not be imported", len(failed)),
+		"Fix the rows listed above and import again; with --key, rows already imported are left unchanged",
+	)
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestImportCSV_ResolvesCells(t *testing.T) {
+	const (
+		dbID     = "12345678123412341234123456789012"
+		aliceID  = "aaaaaaaa-0000-0000-0000-000000000001"
+		bobID    = "aaaaaaaa-0000-0000-0000-000000000002"
+		launchID = "bbbbbbbb-0000-0000-0000-000000000001"
+		acmeID   = "bbbbbbbb-0000-0000-0000-000000000002"
+		directID = "bbbbbbbb000000000000000000000009"
+	)
+
+	var (
+		mu      sync.Mutex
+		created []map[string]interface{}
+		uploads int
+	)
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("GET /databases/"+dbID, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"object": "database",
+			"id":     dbID,
+			"title":  []map[string]interface{}{{"plain_text": "Tasks"}},
+			"properties": map[string]interface{}{
+				"Name":    map[string]interface{}{"type": "title"},
+				"Owner":   map[string]interface{}{"type": "people"},
+				"Project": map[string]interface{}{"type": "relation", "relation": map[string]interface{}{"data_source_id": "ds-projects"}},
+				"Files":   map[string]interface{}{"type": "files"},
+				"Status":  map[string]interface{}{"type": "status"},
+				"When":    map[string]interface{}{"type": "date"},
+			},
+		})
+	})
+	mux.HandleFunc("GET /users", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"object": "list", "has_more": false, "results": []interface{}{
+			map[string]interface{}{"object": "user", "id": aliceID, "name": "Alice Doe", "type": "person", "person": map[string]interface{}{"email": "alice@example.invalid"}},
+			map[string]interface{}{"object": "user", "id": bobID, "name": "Lovelace, Ada", "type": "person"},
+		}})
+	})
+	mux.HandleFunc("POST /data_sources/ds-projects/query", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"object": "list", "has_more": false, "results": []interface{}{
+			map[string]interface{}{"object": "page", "id": launchID, "properties": map[string]interface{}{
+				"Project name": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Launch"}}},
+			}},
+			map[string]interface{}{"object": "page", "id": acmeID, "properties": map[string]interface{}{
+				"Project name": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Acme, Inc."}}},
+			}},
+		}})
+	})
+	var server *httptest.Server
+	mux.HandleFunc("POST /file_uploads", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		uploads++
+		mu.Unlock()
+		writeJSON(w, map[string]interface{}{"object": "file_upload", "id": "upload-1", "upload_url": server.URL + "/file_uploads/upload-1/send"})
+	})
+	mux.HandleFunc("POST /file_uploads/upload-1/send", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"object": "file_upload", "id": "upload-1", "status": "uploaded"})
+	})
+	mux.HandleFunc("POST /pages", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		created = append(created, body["properties"].(map[string]interface{}))
+		mu.Unlock()
+		writeJSON(w, map[string]interface{}{"object": "page", "id": "new-page"})
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.pdf"), []byte("%PDF-1.4"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	csvFile := filepath.Join(dir, "tasks.csv")
+	csv := "Name,Owner,Project,Files,Status,When\n" +
+		"First,\"alice@example.invalid; Lovelace, Ada\",\"launch; Acme, Inc.\",\"report.pdf; https://example.invalid/spec.txt\",In progress,2026-05-01\n" +
+		"Second,Carol,Launch,,,\n" +
+		"Third,,https://example.invalid/Some-Page-" + directID + ",report.pdf,,\"May 1, 2026 → May 3, 2026\"\n" +
+		"Fourth,,Unknown,missing.pdf,,\n"
+	if err := os.WriteFile(csvFile, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"import", "csv", dbID, "--file", csvFile})
+	err := root.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "2 rows could not be imported") {
+		t.Fatalf("expected an error for the failed rows, got %v", err)
+	}
+
+	stderr := errBuf.String()
+	for _, want := range []string{
+		"Imported 2 pages into database Tasks",
+		"2 of 4 rows failed:",
+		`row 2: Owner: no user with email or name "Carol"`,
+		`row 4: Project: no related row titled "Unknown"; Files: file "missing.pdf" is neither a URL nor a readable file`,
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr missing %q:\n%s", want, stderr)
+		}
+	}
+	if uploads != 1 {
+		t.Errorf("uploaded %d files, want 1 (the same file is uploaded once)", uploads)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created %d pages, want 2", len(created))
+	}
+
+	first, _ := json.Marshal(created[0])
+	for _, want := range []string{
+		`"Owner":{"people":[{"id":"` + aliceID + `","object":"user"},{"id":"` + bobID + `","object":"user"}]}`,
+		`"Project":{"relation":[{"id":"` + launchID + `"},{"id":"` + acmeID + `"}]}`,
+		`{"file_upload":{"id":"upload-1"},"name":"report.pdf","type":"file_upload"}`,
+		`{"external":{"url":"https://example.invalid/spec.txt"},"name":"spec.txt","type":"external"}`,
+		`"Status":{"status":{"name":"In progress"}}`,
+	} {
+		if !strings.Contains(string(first), want) {
+			t.Errorf("first row missing %s:\n%s", want, first)
+		}
+	}
+	third, _ := json.Marshal(created[1])
+	for _, want := range []string{
+		`"Project":{"relation":[{"id":"` + directID + `"}]}`,
+		`"When":{"date":{"end":"2026-05-03","start":"2026-05-01"}}`,
+	} {
+		if !strings.Contains(string(third), want) {
+			t.Errorf("third row missing %s:\n%s", want, third)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxInferredOptions is the most distinct values a column may have to be
+// inferred as a select or multi-select.
+const maxInferredOptions = 25
+
+// csvColumn is a CSV column with the property type inferred from its values.
+type csvColumn struct {
+	Name string
+	Type string
+	// Options lists the select and multi-select options in the order they
+	// first appear.
+	Options []string
+}
+
+// inferCSVSchema infers a property type for every column. The first column
+// becomes the title. delimiter separates multi-select values; "" never
+// infers multi-select.
+func inferCSVSchema(headers []string, rows [][]string, delimiter string) []csvColumn {
+	columns := make([]csvColumn, len(headers))
+	for i, header := range headers {
+		columns[i].Name = strings.TrimSpace(header)
+		if i == 0 {
+			if columns[i].Name == "" {
+				columns[i].Name = "Name"
+			}
+			columns[i].Type = "title"
+			continue
+		}
+		var values []string
+		for _, row := range rows {
+			if i < len(row) {
+				if value := strings.TrimSpace(row[i]); value != "" {
+					values = append(values, value)
+				}
+			}
+		}
+		columns[i].Type, columns[i].Options = inferColumnType(values, delimiter)
+	}
+	return columns
+}
+
+// inferColumnType picks the narrowest property type that fits every value,
+// falling back to rich_text. Columns with few distinct, repeated values
+// become selects, or multi-selects when values contain the delimiter.
+func inferColumnType(values []string, delimiter string) (string, []string) {
+	if len(values) == 0 {
+		return "rich_text", nil
+	}
+
+	checks := []struct {
+		propType string
+		match    func(string) bool
+	}{
+		{"checkbox", isCSVCheckbox},
+		{"number", isCSVNumber},
+		{"date", func(v string) bool { _, ok := parseCSVDate(v); return ok }},
+		{"url", isWebURL},
+		{"email", isCSVEmail},
+	}
+	for _, check := range checks {
+		if allValues(values, check.match) {
+			return check.propType, nil
+		}
+	}
+
+	multi := false
+	var options []string
+	seen := map[string]bool{}
+	for _, value := range values {
+		items := []string{value}
+		if delimiter != "" && strings.Contains(value, delimiter) {
+			multi = true
+			items = splitCSVList(value, delimiter)
+		}
+		for _, item := range items {
+			if !seen[item] {
+				seen[item] = true
+				options = append(options, item)
+			}
+		}
+	}
+	// The API rejects option names with commas, and a JSON array that is not
+	// split into options reads as a comma-separated list.
+	for _, option := range options {
+		if strings.Contains(option, ",") || strings.Contains(option, jsonListSeparator) {
+			return "rich_text", nil
+		}
+	}
+	if len(options) > maxInferredOptions || len(options) == len(values) && !multi {
+		return "rich_text", nil
+	}
+	if multi {
+		return "multi_select", options
+	}
+	return "select", options
+}
+
+func allValues(values []string, match func(string) bool) bool {
+	for _, value := range values {
+		if !match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func isCSVCheckbox(value string) bool {
+	switch strings.ToLower(value) {
+	case "yes", "no", "true", "false":
+		return true
+	}
+	return false
+}
+
+// csvNumberPattern matches plain decimals. Values with a leading zero, such
+// as ZIP codes, stay text so the zero is kept.
+var csvNumberPattern = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?$`)
+
+func isCSVNumber(value string) bool {
+	return csvNumberPattern.MatchString(value)
+}
+
+func isCSVEmail(value string) bool {
+	addr, err := mail.ParseAddress(value)
+	return err == nil && addr.Address == value
+}
+
+// csvDateLayouts are the date formats recognised in CSV cells: ISO 8601 and
+// the formats Notion's CSV export writes.
+var csvDateLayouts = []struct {
+	layout string
+	time   bool
+}{
+	{"2006-01-02", false},
+	{time.RFC3339, true},
+	{"2006-01-02T15:04:05", true},
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02 15:04", true},
+	{"2006/01/02", false},
+	{"January 2, 2006", false},
+	{"January 2, 2006 3:04 PM", true},
+	{"January 2, 2006 15:04", true},
+	{"Jan 2, 2006", false},
+	{"Jan 2, 2006 3:04 PM", true},
+}
+
+// parseCSVDate converts a date or a date range ("start → end") to the ISO
+// 8601 form property values take.
+func parseCSVDate(value string) (string, bool) {
+	for _, sep := range []string{"→", "->"} {
+		if start, end, ok := strings.Cut(value, sep); ok {
+			s, okStart := parseCSVDate(strings.TrimSpace(start))
+			e, okEnd := parseCSVDate(strings.TrimSpace(end))
+			if !okStart || !okEnd {
+				return "", false
+			}
+			return s + "→" + e, true
+		}
+	}
+	value = strings.TrimSpace(value)
+	// Notion appends the time zone to date-times: "May 1, 2026 3:04 PM (GMT+2)".
+	if i := strings.LastIndex(value, " ("); i > 0 && strings.HasSuffix(value, ")") {
+		value = value[:i]
+	}
+	for _, layout := range csvDateLayouts {
+		t, err := time.Parse(layout.layout, value)
+		if err != nil {
+			continue
+		}
+		if !layout.time {
+			return t.Format("2006-01-02"), true
+		}
+		if layout.layout == time.RFC3339 {
+			return value, true
+		}
+		return t.Format("2006-01-02T15:04:05"), true
+	}
+	return "", false
+}
+
+// splitCSVList splits a cell on delimiter, dropping empty items.
+func splitCSVList(value, delimiter string) []string {
+	var items []string
+	for _, item := range strings.Split(value, delimiter) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// csvCellValue rewrites a cell in the form notion.ParsePropertyValue reads
+// for the column's type.
+func csvCellValue(value string, column csvColumn, delimiter string) string {
+	value = strings.TrimSpace(value)
+	switch column.Type {
+	case "date":
+		if date, ok := parseCSVDate(value); ok {
+			return date
+		}
+	case "multi_select":
+		if delimiter != "" {
+			return strings.Join(splitCSVList(value, delimiter), ";")
+		}
+	}
+	return strings.ReplaceAll(value, jsonListSeparator, ", ")
+}
+
+// databaseProperties returns the schema of a database with the columns.
+func databaseProperties(columns []csvColumn) map[string]map[string]interface{} {
+	props := make(map[string]map[string]interface{}, len(columns))
+	for _, column := range columns {
+		if column.Name == "" {
+			continue
+		}
+		config := map[string]interface{}{}
+		if column.Type == "select" || column.Type == "multi_select" {
+			options := make([]map[string]interface{}, 0, len(column.Options))
+			for _, option := range column.Options {
+				options = append(options, map[string]interface{}{"name": option})
+			}
+			config["options"] = options
+		}
+		props[column.Name] = map[string]interface{}{column.Type: config}
+	}
+	return props
+}
+
+// columnMappings maps the columns to the properties databaseProperties
+// creates.
+func columnMappings(columns []csvColumn) []columnMapping {
+	var mappings []columnMapping
+	for i, column := range columns {
+		if column.Name == "" {
+			continue
+		}
+		mappings = append(mappings, columnMapping{
+			csvIndex:   i,
+			csvHeader:  column.Name,
+			notionProp: column.Name,
+			propType:   column.Type,
+		})
+	}
+	return mappings
+}
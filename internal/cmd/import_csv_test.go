@@ -283,12 +283,24 @@ func TestImportCSV_PropertyTypes(t *testing.T) {
 			},
 		},
 		{
-			name:     "unsupported type returns nil",
-			value:    "anything",
+			name:     "relation",
+			value:    "0123456789abcdef0123456789abcdef",
 			propType: "relation",
+			wantKey:  "relation",
+			check: func(t *testing.T, result map[string]interface{}) {
+				rel := result["relation"].([]map[string]interface{})
+				if len(rel) != 1 || rel[0]["id"] != "0123456789abcdef0123456789abcdef" {
+					t.Errorf("unexpected relation payload %v", result["relation"])
+				}
+			},
+		},
+		{
+			name:     "read-only type returns nil",
+			value:    "anything",
+			propType: "formula",
 			check: func(t *testing.T, result map[string]interface{}) {
 				if result != nil {
-					t.Error("expected nil for unsupported type")
+					t.Error("expected nil for read-only type")
 				}
 			},
 		},
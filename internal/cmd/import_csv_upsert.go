@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/notion-cli/internal/errors"
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// upsertKeyTypes are the property types --key can match rows by.
+var upsertKeyTypes = keywordSet("title rich_text number unique_id")
+
+// csvUpsert imports CSV rows into a data source, updating the rows whose
+// key property matches the row's key column instead of creating them.
+type csvUpsert struct {
+	client       *notion.Client
+	stderr       io.Writer
+	cells        *csvCells
+	parent       map[string]interface{}
+	dataSourceID string
+	mappings     []columnMapping
+	key          columnMapping
+	prune        bool
+	// dryRun counts the changes without making them.
+	dryRun bool
+
+	// rows finds the existing rows by key; several rows may share one.
+	rows  map[string][]*notion.Page
+	pages []*notion.Page
+	seen  map[string]bool
+
+	created, updated, unchanged, archived int
+	// skipped counts rows keyed by a unique ID that matches no row: Notion
+	// assigns unique IDs, so creating them would not give them their key.
+	skipped int
+	// failed lists the rows that could not be converted or written.
+	failed []csvRowError
+}
+
+// upsertKeyMapping finds the mapping of the --key column, by CSV header or
+// by property name.
+func upsertKeyMapping(mappings []columnMapping, key string) (columnMapping, error) {
+	for _, m := range mappings {
+		if strings.EqualFold(m.csvHeader, key) || strings.EqualFold(m.notionProp, key) {
+			if !upsertKeyTypes[m.propType] {
+				return columnMapping{}, errors.NewUserError(
+					fmt.Sprintf("--key column %q maps to a %s property", key, m.propType),
+					"Use a column mapped to a title, rich_text, number or unique_id property",
+				)
+			}
+			return m, nil
+		}
+	}
+	return columnMapping{}, errors.NewUserError(
+		fmt.Sprintf("--key column %q does not map to a database property", key),
+		"Pass a CSV column header mapped to a title, rich_text, number or unique_id property",
+	)
+}
+
+// load queries the existing rows of the data source.
+func (u *csvUpsert) load(ctx context.Context) error {
+	u.rows = map[string][]*notion.Page{}
+	u.seen = map[string]bool{}
+	req := &notion.QueryDataSourceRequest{PageSize: NotionMaxPageSize}
+	for {
+		result, err := u.client.QueryDataSource(ctx, u.dataSourceID, req)
+		if err != nil {
+			return fmt.Errorf("failed to query existing rows: %w", err)
+		}
+		for i := range result.Results {
+			page := &result.Results[i]
+			u.pages = append(u.pages, page)
+			for _, key := range u.pageKeys(page) {
+				u.rows[key] = append(u.rows[key], page)
+			}
+		}
+		if !result.HasMore || result.NextCursor == nil || *result.NextCursor == "" {
+			return nil
+		}
+		req.StartCursor = *result.NextCursor
+	}
+}
+
+// pageKeys returns the keys a row is found by: its key property's value,
+// and for unique IDs also the number without the prefix.
+func (u *csvUpsert) pageKeys(page *notion.Page) []string {
+	prop, _ := page.Properties[u.key.notionProp].(map[string]interface{})
+	if prop == nil {
+		return nil
+	}
+	value := prop[u.key.propType]
+	switch u.key.propType {
+	case "number":
+		if n, ok := value.(float64); ok {
+			return []string{strconv.FormatFloat(n, 'f', -1, 64)}
+		}
+		return nil
+	case "unique_id":
+		pv, err := notion.DecodePropertyValue(prop)
+		if err != nil || pv.UniqueID == nil {
+			return nil
+		}
+		keys := []string{strconv.Itoa(pv.UniqueID.Number)}
+		if id := pv.UniqueID.String(); id != keys[0] {
+			keys = append(keys, strings.ToLower(id))
+		}
+		return keys
+	}
+	if text, _ := simplifyPropertyValue(u.key.propType, value).(string); strings.TrimSpace(text) != "" {
+		return []string{strings.TrimSpace(text)}
+	}
+	return nil
+}
+
+// rowKey returns the key of a CSV row, in the form pageKeys returns.
+func (u *csvUpsert) rowKey(row []string) string {
+	value := ""
+	if u.key.csvIndex < len(row) {
+		value = strings.TrimSpace(row[u.key.csvIndex])
+	}
+	switch u.key.propType {
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return strconv.FormatFloat(n, 'f', -1, 64)
+		}
+	case "unique_id":
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+// run creates or updates a page for every row, then archives the rows
+// missing from the CSV when pruning.
+func (u *csvUpsert) run(ctx context.Context, dataRows [][]string) error {
+	if err := u.load(ctx); err != nil {
+		return err
+	}
+
+	for i, row := range dataRows {
+		key := u.rowKey(row)
+		if key == "" {
+			_, _ = fmt.Fprintf(u.stderr, "Warning: row %d has no %s value (skipping)\n", i+1, u.key.csvHeader)
+			continue
+		}
+		// The rows a CSV row matches are kept even when its cells fail, so
+		// --prune never archives them.
+		existing := u.rows[key]
+		for _, page := range existing {
+			u.seen[page.ID] = true
+		}
+		if len(existing) == 0 && u.key.propType == "unique_id" {
+			_, _ = fmt.Fprintf(u.stderr, "Warning: row %d: no row has %s %q, and new rows get their unique ID from Notion (skipping)\n", i+1, u.key.notionProp, strings.TrimSpace(row[u.key.csvIndex]))
+			u.skipped++
+			continue
+		}
+		props, err := u.cells.properties(ctx, row, u.mappings)
+		if err != nil {
+			u.failed = append(u.failed, csvRowError{row: i + 1, err: err})
+			continue
+		}
+
+		if len(existing) == 0 {
+			if props == nil {
+				continue
+			}
+			if u.dryRun {
+				u.created++
+				// Later rows with the same key update the row this one creates.
+				u.rows[key] = []*notion.Page{{Properties: props}}
+				continue
+			}
+			page, err := u.client.CreatePage(ctx, &notion.CreatePageRequest{Parent: u.parent, Properties: props})
+			if err != nil {
+				u.failed = append(u.failed, csvRowError{row: i + 1, err: fmt.Errorf("failed to create page: %w", err)})
+				continue
+			}
+			u.created++
+			u.seen[page.ID] = true
+			u.rows[key] = []*notion.Page{{ID: page.ID, Properties: props}}
+			continue
+		}
+
+		if len(existing) > 1 {
+			_, _ = fmt.Fprintf(u.stderr, "Warning: %d rows have %s %q; updating the first\n", len(existing), u.key.notionProp, key)
+		}
+		page := existing[0]
+		changed := changedProperties(page.Properties, props, u.mappings)
+		if len(changed) == 0 {
+			u.unchanged++
+			continue
+		}
+		if !u.dryRun {
+			if _, err := u.client.UpdatePage(ctx, page.ID, &notion.UpdatePageRequest{Properties: changed}); err != nil {
+				u.failed = append(u.failed, csvRowError{row: i + 1, err: fmt.Errorf("failed to update page: %w", err)})
+				continue
+			}
+		}
+		u.updated++
+		for name, value := range changed {
+			if page.Properties == nil {
+				page.Properties = map[string]interface{}{}
+			}
+			page.Properties[name] = value
+		}
+	}
+
+	if !u.prune {
+		return nil
+	}
+	for _, page := range u.pages {
+		if u.seen[page.ID] {
+			continue
+		}
+		u.archived++
+		if u.dryRun {
+			continue
+		}
+		if _, err := u.client.UpdatePage(ctx, page.ID, &notion.UpdatePageRequest{Archived: ptrBool(true)}); err != nil {
+			return fmt.Errorf("failed to archive page %s: %w", page.ID, err)
+		}
+	}
+	return nil
+}
+
+// changedProperties returns the properties of a row payload whose values
+// differ from the page's current values.
+func changedProperties(current, props map[string]interface{}, mappings []columnMapping) map[string]interface{} {
+	changed := map[string]interface{}{}
+	for _, m := range mappings {
+		payload, ok := props[m.notionProp].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		existing, _ := current[m.notionProp].(map[string]interface{})
+		if !reflect.DeepEqual(comparablePropertyValue(m.propType, existing[m.propType]), comparablePropertyValue(m.propType, payload[m.propType])) {
+			changed[m.notionProp] = payload
+		}
+	}
+	return changed
+}
+
+// comparablePropertyValue reduces a property value, from the API or from a
+// request payload, to the parts a CSV cell sets, so equal values compare
+// equal.
+func comparablePropertyValue(propType string, value interface{}) interface{} {
+	// Payloads built in Go compare with decoded JSON after a round trip.
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return value
+	}
+
+	switch propType {
+	case "date":
+		date, _ := decoded.(map[string]interface{})
+		if date == nil {
+			return nil
+		}
+		start, _ := date["start"].(string)
+		end, _ := date["end"].(string)
+		return [2]string{comparableDate(start), comparableDate(end)}
+	case "people", "relation":
+		items, _ := decoded.([]interface{})
+		ids := make([]string, 0, len(items))
+		for _, item := range items {
+			if entry, ok := item.(map[string]interface{}); ok {
+				id, _ := entry["id"].(string)
+				ids = append(ids, dashlessID(id))
+			}
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	simple := simplifyPropertyValue(propType, decoded)
+	if names, ok := simple.([]string); ok {
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil
+		}
+	}
+	if text, ok := simple.(string); ok && text == "" {
+		return nil
+	}
+	return simple
+}
+
+// comparableDateLayouts are the forms of dates the API returns and CSV
+// cells give. Times without an offset are taken as UTC, as Notion does.
+var comparableDateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// comparableDate normalises a date or date-time, so that the API's
+// "2026-05-01T09:00:00.000+00:00" equals the CSV's "2026-05-01T09:00:00".
+// Dates without a time are compared as written.
+func comparableDate(value string) string {
+	for _, layout := range comparableDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return value
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// upsertServer fakes a database with existing rows, served over two pages
+// of query results, and records the pages created and updated.
+type upsertServer struct {
+	mu      sync.Mutex
+	created []map[string]interface{}
+	updated map[string]map[string]interface{}
+}
+
+func newUpsertServer(t *testing.T, dbID string) *upsertServer {
+	t.Helper()
+	s := &upsertServer{updated: map[string]map[string]interface{}{}}
+	row := func(id, name string, count float64, tags ...string) map[string]interface{} {
+		var options []interface{}
+		for _, tag := range tags {
+			options = append(options, map[string]interface{}{"name": tag})
+		}
+		return map[string]interface{}{
+			"object": "page",
+			"id":     id,
+			"properties": map[string]interface{}{
+				"Name":  map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": name}}},
+				"Count": map[string]interface{}{"type": "number", "number": count},
+				"Tags":  map[string]interface{}{"type": "multi_select", "multi_select": options},
+				"ID":    map[string]interface{}{"type": "unique_id", "unique_id": map[string]interface{}{"prefix": "ROW", "number": count}},
+			},
+		}
+	}
+
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("GET /databases/"+dbID, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"object":       "database",
+			"id":           dbID,
+			"title":        []map[string]interface{}{{"plain_text": "Contacts"}},
+			"data_sources": []map[string]interface{}{{"id": "ds-1"}},
+			"properties": map[string]interface{}{
+				"Name":  map[string]interface{}{"type": "title"},
+				"Count": map[string]interface{}{"type": "number"},
+				"Tags":  map[string]interface{}{"type": "multi_select"},
+				"ID":    map[string]interface{}{"type": "unique_id"},
+				"Owner": map[string]interface{}{"type": "people"},
+			},
+		})
+	})
+	mux.HandleFunc("GET /users", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"object": "list", "has_more": false, "results": []interface{}{}})
+	})
+	mux.HandleFunc("POST /data_sources/ds-1/query", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["start_cursor"] == "page-2" {
+			writeJSON(w, map[string]interface{}{"object": "list", "has_more": false, "results": []interface{}{
+				row("row-carol", "Carol", 30),
+			}})
+			return
+		}
+		writeJSON(w, map[string]interface{}{"object": "list", "has_more": true, "next_cursor": "page-2", "results": []interface{}{
+			row("row-alice", "Alice", 10, "a", "b"),
+			row("row-bob", "Bob", 20),
+		}})
+	})
+	mux.HandleFunc("POST /pages", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.mu.Lock()
+		s.created = append(s.created, body)
+		s.mu.Unlock()
+		writeJSON(w, map[string]interface{}{"object": "page", "id": "row-new"})
+	})
+	mux.HandleFunc("PATCH /pages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.mu.Lock()
+		s.updated[r.PathValue("id")] = body
+		s.mu.Unlock()
+		writeJSON(w, map[string]interface{}{"object": "page", "id": r.PathValue("id")})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("HOME", t.TempDir())
+	return s
+}
+
+func runImportCSV(t *testing.T, csvContent string, args ...string) string {
+	t.Helper()
+	csvFile := filepath.Join(t.TempDir(), "rows.csv")
+	if err := os.WriteFile(csvFile, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs(append([]string{"import", "csv", "--file", csvFile}, args...))
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("import csv failed: %v\nstderr=%s", err, errBuf.String())
+	}
+	return errBuf.String()
+}
+
+func TestImportCSV_Upsert(t *testing.T) {
+	const dbID = "12345678123412341234123456789012"
+	server := newUpsertServer(t, dbID)
+
+	csv := "Name,Count,Tags\n" +
+		"Alice,10,b;a\n" +
+		"Bob,25,\n" +
+		",5,\n" +
+		"Dave,40,c\n"
+	stderr := runImportCSV(t, csv, dbID, "--key", "Name", "--prune")
+
+	if !strings.Contains(stderr, "Upserted 3 rows into database Contacts: 1 created, 1 updated, 1 unchanged, 1 archived") {
+		t.Errorf("summary = %q", stderr)
+	}
+	if !strings.Contains(stderr, "Warning: row 3 has no Name value (skipping)") {
+		t.Errorf("expected a warning for the row without a key, got %q", stderr)
+	}
+
+	if len(server.created) != 1 {
+		t.Fatalf("created %d pages, want 1", len(server.created))
+	}
+	if got := server.created[0]["parent"].(map[string]interface{})["database_id"]; got != dbID {
+		t.Errorf("created page parent = %v", got)
+	}
+
+	var updatedIDs []string
+	for id := range server.updated {
+		updatedIDs = append(updatedIDs, id)
+	}
+	sort.Strings(updatedIDs)
+	if want := []string{"row-bob", "row-carol"}; !reflect.DeepEqual(updatedIDs, want) {
+		t.Fatalf("updated pages = %v, want %v", updatedIDs, want)
+	}
+	bob := server.updated["row-bob"]["properties"].(map[string]interface{})
+	if len(bob) != 1 || bob["Count"].(map[string]interface{})["number"] != float64(25) {
+		t.Errorf("Bob should only get the changed Count: %v", bob)
+	}
+	if server.updated["row-carol"]["archived"] != true {
+		t.Errorf("Carol should be archived: %v", server.updated["row-carol"])
+	}
+}
+
+func TestImportCSV_Upsert_PruneKeepsFailedRows(t *testing.T) {
+	const dbID = "12345678123412341234123456789012"
+	server := newUpsertServer(t, dbID)
+
+	csvFile := filepath.Join(t.TempDir(), "rows.csv")
+	csv := "Name,Count,Owner\n" +
+		"Alice,10,\n" +
+		"Bob,25,nobody@example.invalid\n" +
+		"Carol,30,\n"
+	if err := os.WriteFile(csvFile, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"import", "csv", dbID, "--file", csvFile, "--key", "Name", "--prune"})
+	err := root.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 rows could not be imported") {
+		t.Fatalf("expected an error for Bob's row, got %v\nstderr=%s", err, errBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), `row 2: Owner: no user with email or name "nobody@example.invalid"`) {
+		t.Errorf("stderr = %s", errBuf.String())
+	}
+	if len(server.updated) != 0 {
+		t.Errorf("Bob's row must not be updated or archived: %v", server.updated)
+	}
+}
+
+func TestImportCSV_Upsert_DryRunAndUniqueID(t *testing.T) {
+	const dbID = "12345678123412341234123456789012"
+	server := newUpsertServer(t, dbID)
+
+	csv := "ID,Name,Count\n" +
+		"ROW-10,Alice,10\n" +
+		"20,Bobby,20\n" +
+		"ROW-99,Zed,99\n"
+	stderr := runImportCSV(t, csv, dbID, "--key", "ID", "--prune", "--dry-run")
+
+	for _, want := range []string{
+		"Key column: ID -> ID (unique_id)",
+		"Rows to create: 0",
+		"Rows to update: 1",
+		"Rows unchanged: 1",
+		"Rows skipped: 1",
+		"Rows to archive: 1",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("dry run output missing %q:\n%s", want, stderr)
+		}
+	}
+	if len(server.created) != 0 || len(server.updated) != 0 {
+		t.Errorf("dry run wrote pages: created=%v updated=%v", server.created, server.updated)
+	}
+}
+
+func TestImportCSV_Upsert_UniqueIDNeverCreates(t *testing.T) {
+	const dbID = "12345678123412341234123456789012"
+	server := newUpsertServer(t, dbID)
+
+	csv := "ID,Name,Count\n" +
+		"ROW-10,Alice,10\n" +
+		"ROW-99,Zed,99\n"
+	// Re-running must not create the unmatched row either: a created row
+	// would get a new unique ID, not ROW-99.
+	for run := 1; run <= 2; run++ {
+		stderr := runImportCSV(t, csv, dbID, "--key", "ID")
+		if !strings.Contains(stderr, `Warning: row 2: no row has ID "ROW-99"`) {
+			t.Errorf("run %d: expected a warning for ROW-99, got %q", run, stderr)
+		}
+		if !strings.Contains(stderr, "0 created, 0 updated, 1 unchanged, 0 archived, 1 skipped") {
+			t.Errorf("run %d: summary = %q", run, stderr)
+		}
+	}
+	if len(server.created) != 0 {
+		t.Errorf("created %d pages, want none", len(server.created))
+	}
+}
+
+func TestComparablePropertyValue(t *testing.T) {
+	tests := []struct {
+		propType string
+		current  interface{}
+		payload  interface{}
+	}{
+		{"date", map[string]interface{}{"start": "2026-05-01", "end": nil, "time_zone": nil}, map[string]interface{}{"start": "2026-05-01"}},
+		{"date", map[string]interface{}{"start": "2026-05-01T09:00:00.000+00:00", "end": "2026-05-01T12:00:00.000+02:00"}, map[string]interface{}{"start": "2026-05-01T09:00:00", "end": "2026-05-01T10:00"}},
+		{"relation", []interface{}{map[string]interface{}{"id": "aaaa-bbbb"}}, []map[string]interface{}{{"id": "AAAABBBB"}}},
+		{"rich_text", []interface{}{map[string]interface{}{"plain_text": "hi"}}, []map[string]interface{}{{"type": "text", "text": map[string]interface{}{"content": "hi"}}}},
+		{"multi_select", []interface{}{map[string]interface{}{"name": "b", "color": "red"}, map[string]interface{}{"name": "a"}}, []map[string]interface{}{{"name": "a"}, {"name": "b"}}},
+		{"number", float64(3), 3},
+	}
+	for _, tt := range tests {
+		if a, b := comparablePropertyValue(tt.propType, tt.current), comparablePropertyValue(tt.propType, tt.payload); !reflect.DeepEqual(a, b) {
+			t.Errorf("%s: %v != %v", tt.propType, a, b)
+		}
+	}
+	if a, b := comparablePropertyValue("date", map[string]interface{}{"start": "2026-05-01T09:00:00.000+00:00"}), comparablePropertyValue("date", map[string]interface{}{"start": "2026-05-01T10:00:00"}); reflect.DeepEqual(a, b) {
+		t.Errorf("different times should differ")
+	}
+	if a, b := comparablePropertyValue("select", nil), comparablePropertyValue("select", map[string]interface{}{"name": "x"}); reflect.DeepEqual(a, b) {
+		t.Errorf("empty and set selects should differ")
+	}
+}
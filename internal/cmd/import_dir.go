@@ -0,0 +1,876 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notion-cli/internal/cmdutil"
+	clierrors "github.com/salmonumbrella/notion-cli/internal/errors"
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// importMappingName is the default mapping file of ntn import dir, kept in
+// the imported directory.
+const importMappingName = ".ntn-import.json"
+
+func newImportDirCmd() *cobra.Command {
+	var parentID string
+	var mappingPath string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:     "dir <path>",
+		Aliases: []string{"d"},
+		Short:   "Import a directory of markdown notes as nested pages",
+		Long: `Import a directory of markdown notes, such as an Obsidian or Logseq vault,
+as a tree of pages under --parent.
+
+Every folder that holds notes becomes a page with its notes and subfolders
+as child pages. A note next to a folder of the same name (Projects.md and
+Projects/) becomes that folder's page. Hidden files and folders are skipped.
+
+The import runs in two passes: the first creates every page and the second
+fills in their content, so [[wiki links]], [[note|aliased links]] and
+relative links to other notes become page mentions wherever they point.
+Images and ![[embedded]] attachments are uploaded, and a leading # heading
+that repeats the note's title is dropped.
+
+When --parent is a database, the top-level notes become its entries and
+their YAML frontmatter fills the properties with matching names. Lists in
+frontmatter become multi-select options.
+
+The IDs of the pages created are recorded in a mapping file, .ntn-import.json
+in the directory unless --mapping is given. Running the import again reuses
+those pages: notes that changed have their content replaced, new notes are
+added and unchanged notes are left alone.
+
+Examples:
+  ntn import dir ./vault --parent abc123
+  ntn import dir ./vault --parent abc123 --dry-run
+  ntn import dir ./notes --parent <database-id> --mapping notes-import.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if parentID == "" {
+				return clierrors.NewUserError("--parent is required", "Pass the page or database to import the notes under")
+			}
+
+			ctx := cmd.Context()
+			sf := SkillFileFromContext(ctx)
+			normalizedParent, err := cmdutil.NormalizeNotionID(resolveID(sf, parentID))
+			if err != nil {
+				return err
+			}
+
+			root := args[0]
+			info, err := os.Stat(root)
+			if err != nil {
+				return clierrors.WrapUserError(err, "failed to read directory", "Check that the directory exists")
+			}
+			if !info.IsDir() {
+				return clierrors.NewUserError(fmt.Sprintf("%s is not a directory", root), "Use ntn import --file to import a single markdown file")
+			}
+			if mappingPath == "" {
+				mappingPath = filepath.Join(root, importMappingName)
+			}
+
+			v, err := scanVault(root, false)
+			if err != nil {
+				return err
+			}
+			if len(v.notes) == 0 {
+				return clierrors.NewUserError(fmt.Sprintf("no markdown files found in %s", root), "Notes must have a .md extension")
+			}
+
+			mapping, err := loadImportMapping(mappingPath)
+			if err != nil {
+				return err
+			}
+			if mapping.Parent != "" && mapping.Parent != normalizedParent {
+				return clierrors.NewUserError(
+					fmt.Sprintf("%s records an import under %s, not %s", mappingPath, mapping.Parent, normalizedParent),
+					"Use --mapping to record this import in another file",
+				)
+			}
+			mapping.Parent = normalizedParent
+			v.mapping = mapping
+
+			stderr := stderrFromContext(ctx)
+			if dryRun {
+				v.printDryRun(stderr, "directory", root, normalizedParent, mappingPath)
+				return nil
+			}
+
+			client, err := clientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			v.client = client
+			v.stderr = stderr
+
+			err = v.run(ctx, normalizedParent)
+			// Save the pages created so far even when the import fails, so
+			// that a re-run does not create them again.
+			if saveErr := mapping.save(mappingPath); err == nil {
+				err = saveErr
+			}
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(stderr, "Imported %d pages from %s (%d created, %d updated, %d unchanged)\n",
+				len(v.notes), root, v.created, v.updated, v.unchanged)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&parentID, "parent", "", "Parent page or database ID")
+	cmd.Flags().StringVar(&mappingPath, "mapping", "", "Mapping file of imported pages (default: <path>/"+importMappingName+")")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the pages that would be imported without making changes")
+
+	flagAlias(cmd.Flags(), "parent", "pa")
+	flagAlias(cmd.Flags(), "dry-run", "dr")
+
+	return cmd
+}
+
+// vaultNote is a page of an imported directory: a markdown note, a folder
+// of notes, or both when a note sits next to a folder of the same name. In
+// Notion exports it may also be a database, read from a CSV, or one of its
+// entries.
+type vaultNote struct {
+	// key identifies the page in the mapping file: the slash path of the
+	// note, or of the folder with a trailing slash.
+	key string
+	// name is the file or folder name without its extension.
+	name     string
+	title    string
+	file     string
+	parent   *vaultNote
+	children []*vaultNote
+	// table is set for databases, and row for their entries.
+	table *vaultTable
+	row   []string
+
+	id      string
+	created bool
+}
+
+// vaultImport imports a scanned directory of notes.
+type vaultImport struct {
+	// notes lists every page, parents before their children.
+	notes []*vaultNote
+	// byPath and byName find notes by their lower-cased slash path and
+	// file name, without the .md extension.
+	byPath map[string]*vaultNote
+	byName map[string]*vaultNote
+	// files finds attachments by their lower-cased slash path and file name.
+	files   map[string]string
+	mapping *importMapping
+	// export is set for Notion exports, whose names end in IDs and whose
+	// CSV files are databases.
+	export bool
+
+	client *notion.Client
+	stderr io.Writer
+	// schema and titleProp are set when the parent is a database.
+	schema    map[string]map[string]interface{}
+	titleProp string
+	// linked finds the imported pages and databases by dashless ID.
+	linked map[string]*vaultNote
+	warned map[string]bool
+
+	created, updated, unchanged int
+}
+
+// scanVault reads the notes and attachments under root, and for Notion
+// exports the databases.
+func scanVault(root string, export bool) (*vaultImport, error) {
+	v := &vaultImport{
+		byPath: map[string]*vaultNote{},
+		byName: map[string]*vaultNote{},
+		files:  map[string]string{},
+		export: export,
+		linked: map[string]*vaultNote{},
+		warned: map[string]bool{},
+	}
+	top, err := scanVaultDir(root, "", nil, export)
+	if err != nil {
+		return nil, err
+	}
+	if export {
+		if err := loadExportNotes(top); err != nil {
+			return nil, err
+		}
+	}
+	v.addNotes(top)
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || isMarkdownFile(d.Name()) || export && isCSVFile(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = strings.ToLower(filepath.ToSlash(rel))
+		v.files[rel] = p
+		if _, ok := v.files[path.Base(rel)]; !ok {
+			v.files[path.Base(rel)] = p
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	return v, nil
+}
+
+// scanVaultDir returns the pages of the notes and note folders in dir, and
+// for Notion exports the databases, in name order.
+func scanVaultDir(dir, rel string, parent *vaultNote, export bool) ([]*vaultNote, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var notes []*vaultNote
+	folders := map[string]*vaultNote{}
+	files := map[string]*vaultNote{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		relPath := path.Join(rel, name)
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		switch {
+		case entry.IsDir():
+			folder := &vaultNote{key: relPath + "/", name: name, title: name, parent: parent}
+			children, err := scanVaultDir(filepath.Join(dir, name), relPath, folder, export)
+			if err != nil {
+				return nil, err
+			}
+			if len(children) == 0 {
+				continue
+			}
+			folder.children = children
+			folders[name] = folder
+			notes = append(notes, folder)
+		case isMarkdownFile(name):
+			note := &vaultNote{key: relPath, name: base, title: base, file: filepath.Join(dir, name), parent: parent}
+			files[base] = note
+			notes = append(notes, note)
+		case export && isCSVFile(name):
+			// Newer exports write every row to "<name>_all.csv" next to the
+			// CSV of the database's default view.
+			all := strings.HasSuffix(base, "_all")
+			base = strings.TrimSuffix(base, "_all")
+			if existing, ok := files[base]; ok && existing.table != nil {
+				if all {
+					existing.table.file = filepath.Join(dir, name)
+				}
+				continue
+			}
+			note := &vaultNote{
+				key:    path.Join(rel, base+".csv"),
+				name:   base,
+				title:  base,
+				parent: parent,
+				table:  &vaultTable{file: filepath.Join(dir, name)},
+			}
+			files[base] = note
+			notes = append(notes, note)
+		}
+	}
+
+	// A note next to a folder of the same name becomes the folder's page,
+	// and a database's folder holds the pages of its entries.
+	merged := notes[:0]
+	for _, note := range notes {
+		if note.file == "" && note.table == nil {
+			if _, ok := files[note.name]; ok {
+				continue
+			}
+		} else if folder, ok := folders[note.name]; ok {
+			note.children = folder.children
+			for _, child := range note.children {
+				child.parent = note
+			}
+		}
+		merged = append(merged, note)
+	}
+	return merged, nil
+}
+
+// addNotes lists notes and their descendants, parents first, and indexes
+// them for link resolution.
+func (v *vaultImport) addNotes(notes []*vaultNote) {
+	for _, note := range notes {
+		v.notes = append(v.notes, note)
+		if note.file != "" || note.table != nil {
+			key := strings.ToLower(strings.TrimSuffix(note.key, filepath.Ext(note.key)))
+			v.byPath[key] = note
+			// The shortest path wins when notes in different folders share a
+			// name, as in Obsidian.
+			if existing, ok := v.byName[path.Base(key)]; !ok || len(existing.key) > len(note.key) {
+				v.byName[path.Base(key)] = note
+			}
+		}
+		v.addNotes(note.children)
+	}
+}
+
+func isMarkdownFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+func isCSVFile(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".csv")
+}
+
+// run imports the notes: the first pass creates the pages that are not in
+// the mapping yet, the second writes their content.
+func (v *vaultImport) run(ctx context.Context, parentID string) error {
+	parent := map[string]interface{}{"page_id": parentID}
+	if _, err := v.client.GetDatabase(ctx, parentID); err == nil {
+		dataSourceID, err := resolveDataSourceID(ctx, v.client, parentID, "")
+		if err != nil {
+			return err
+		}
+		ds, err := v.client.GetDataSource(ctx, dataSourceID)
+		if err != nil {
+			return fmt.Errorf("failed to get database schema: %w", err)
+		}
+		v.schema = map[string]map[string]interface{}{}
+		for name, prop := range ds.Properties {
+			if m, ok := prop.(map[string]interface{}); ok {
+				v.schema[name] = m
+			}
+		}
+		v.titleProp = findTitlePropertyNameFromDataSource(ds.Properties)
+		parent = map[string]interface{}{"data_source_id": dataSourceID}
+	}
+
+	for _, note := range v.notes {
+		if entry := v.mapping.Pages[note.key]; entry != nil {
+			note.id = entry.ID
+		}
+		if note.id != "" {
+			v.linked[dashlessID(note.id)] = note
+		}
+	}
+
+	for _, note := range v.notes {
+		if note.id != "" {
+			continue
+		}
+		noteParent := parent
+		switch {
+		case note.parent != nil && note.parent.table != nil:
+			noteParent = map[string]interface{}{"data_source_id": note.parent.table.dataSourceID}
+		case note.parent != nil:
+			noteParent = map[string]interface{}{"page_id": note.parent.id}
+		}
+		if note.table != nil {
+			if err := v.createDatabase(ctx, note, noteParent); err != nil {
+				return err
+			}
+		} else {
+			fields, _, err := v.readNote(note)
+			if err != nil {
+				return err
+			}
+			page, err := v.client.CreatePage(ctx, &notion.CreatePageRequest{
+				Parent:     noteParent,
+				Properties: v.properties(note, fields),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create page for %s: %w", note.key, err)
+			}
+			note.id = page.ID
+		}
+		note.created = true
+		v.linked[dashlessID(note.id)] = note
+		v.mapping.Pages[note.key] = &importedPage{ID: note.id}
+	}
+
+	for _, note := range v.notes {
+		if err := v.writeNote(ctx, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readNote reads a note's frontmatter and body. Folders have neither.
+func (v *vaultImport) readNote(note *vaultNote) ([]frontmatterField, string, error) {
+	if note.file == "" {
+		return nil, "", nil
+	}
+	content, err := os.ReadFile(note.file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", note.key, err)
+	}
+	fields, body := parseFrontmatterFields(string(content))
+	for _, field := range fields {
+		if field.Key == "title" && len(field.Values) > 0 && field.Values[0] != "" {
+			note.title = field.Values[0]
+		}
+	}
+	return fields, body, nil
+}
+
+// writeNote writes the properties and content of a note's page, unless they
+// are unchanged since the last import.
+func (v *vaultImport) writeNote(ctx context.Context, note *vaultNote) error {
+	if note.table != nil {
+		// Databases are complete once their entries exist.
+		if note.created {
+			v.created++
+		} else {
+			v.unchanged++
+		}
+		return nil
+	}
+	fields, body, err := v.readNote(note)
+	if err != nil {
+		return err
+	}
+	props := v.properties(note, fields)
+	doc := v.parseNote(note, body)
+
+	hash, err := importHash(props, doc.Blocks)
+	if err != nil {
+		return err
+	}
+	entry := v.mapping.Pages[note.key]
+	if entry.Hash == hash {
+		v.unchanged++
+		return nil
+	}
+
+	if note.created {
+		v.created++
+	} else {
+		v.updated++
+		if _, err := v.client.UpdatePage(ctx, note.id, &notion.UpdatePageRequest{Properties: props}); err != nil {
+			return fmt.Errorf("failed to update page for %s: %w", note.key, err)
+		}
+		if err := v.clearContent(ctx, note.id); err != nil {
+			return fmt.Errorf("failed to replace content of %s: %w", note.key, err)
+		}
+	}
+
+	if len(doc.Blocks) > 0 {
+		if err := doc.uploadImages(ctx, v.client); err != nil {
+			return err
+		}
+		detached := detachDeepChildren(doc.Blocks)
+		created, err := appendBlockChildrenBatched(ctx, v.client, note.id, doc.Blocks, "")
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", note.key, err)
+		}
+		if err := appendDetachedChildren(ctx, v.client, created.Results, detached); err != nil {
+			return fmt.Errorf("failed to import %s: %w", note.key, err)
+		}
+	}
+	entry.Hash = hash
+	return nil
+}
+
+// clearContent deletes the blocks of a page, keeping its child pages and
+// databases.
+func (v *vaultImport) clearContent(ctx context.Context, pageID string) error {
+	blocks, err := fetchAllBlockChildren(ctx, v.client, pageID)
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		if block.Type == "child_page" || block.Type == "child_database" {
+			continue
+		}
+		if _, err := v.client.DeleteBlock(ctx, block.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// properties returns the page properties of a note: its title, and for the
+// entries of a database the frontmatter fields matching its properties.
+func (v *vaultImport) properties(note *vaultNote, fields []frontmatterField) map[string]interface{} {
+	if note.parent != nil && note.parent.table != nil {
+		return note.parent.table.properties(note)
+	}
+	titleProp := "title"
+	props := map[string]interface{}{}
+	if v.schema != nil && note.parent == nil {
+		titleProp = v.titleProp
+		var headers, row []string
+		for _, field := range fields {
+			if field.Key == "title" {
+				continue
+			}
+			headers = append(headers, field.Key)
+			row = append(row, strings.Join(field.Values, ";"))
+		}
+		mappings, _ := buildColumnMappings(headers, v.schema, nil)
+		mapped := map[string]bool{}
+		var writable []columnMapping
+		for _, m := range mappings {
+			mapped[m.csvHeader] = true
+			if m.propType != "title" && !notion.IsReadOnlyPropertyType(m.propType) {
+				writable = append(writable, m)
+			}
+		}
+		for _, header := range headers {
+			if !mapped[header] && !v.warned[header] {
+				v.warned[header] = true
+				_, _ = fmt.Fprintf(v.stderr, "Warning: frontmatter key %q does not match any database property (skipping)\n", header)
+			}
+		}
+		for name, value := range buildPageProperties(row, writable) {
+			props[name] = value
+		}
+	}
+	props[titleProp] = csvValueToProperty(note.title, "title")
+	return props
+}
+
+// parseNote converts a note's body to blocks, resolving its links and
+// embeds against the imported pages and attachments.
+func (v *vaultImport) parseNote(note *vaultNote, body string) *markdownDocument {
+	if note.file == "" {
+		return &markdownDocument{}
+	}
+	dir := path.Dir(note.key)
+	p := newMarkdownParser(filepath.Dir(note.file))
+	p.resolveLink = func(dest string, wiki bool) string {
+		var target *vaultNote
+		if wiki {
+			target = v.wikiTarget(dest)
+		} else {
+			target = v.linkTarget(dir, dest)
+		}
+		if target == nil || target.id == "" {
+			return ""
+		}
+		return notionPageURL(target.id)
+	}
+	p.resolveEmbed = func(target string, wiki bool) string {
+		return v.embedTarget(dir, target, wiki)
+	}
+
+	body = stripTitleHeading(body, note.title)
+	if note.parent != nil && note.parent.table != nil {
+		body = stripExportProperties(body, note.parent.table.headers)
+	}
+	doc := p.document(body)
+	for _, block := range doc.Blocks {
+		v.mentionPageLinks(block)
+	}
+	return doc
+}
+
+// wikiTarget finds the note a [[wiki link]] points to, by path or by name.
+func (v *vaultImport) wikiTarget(target string) *vaultNote {
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "^")
+	target = strings.ToLower(strings.TrimSpace(target))
+	if isMarkdownFile(target) {
+		target = strings.TrimSuffix(target, filepath.Ext(target))
+	}
+	if note, ok := v.byPath[strings.TrimPrefix(target, "/")]; ok {
+		return note
+	}
+	return v.byName[path.Base(target)]
+}
+
+// linkTarget finds the note or database a relative link from a note in dir
+// points to.
+func (v *vaultImport) linkTarget(dir, dest string) *vaultNote {
+	target := vaultPath(dir, dest)
+	if !isMarkdownFile(target) && !(v.export && isCSVFile(target)) {
+		return nil
+	}
+	return v.byPath[strings.TrimSuffix(target, path.Ext(target))]
+}
+
+// embedTarget finds the attachment an ![[embed]] shows, by path or by name,
+// or the local file a link from a note in dir points to. Embedded notes are
+// left to be linked.
+func (v *vaultImport) embedTarget(dir, target string, wiki bool) string {
+	if !wiki {
+		return v.files[vaultPath(dir, target)]
+	}
+	target, _, _ = strings.Cut(target, "#")
+	target = strings.ToLower(strings.TrimSpace(target))
+	if file, ok := v.files[strings.TrimPrefix(target, "/")]; ok {
+		return file
+	}
+	return v.files[path.Base(target)]
+}
+
+// vaultPath resolves a relative link from a note in dir to the lower-cased
+// slash path it points to. Paths starting with / are relative to the root.
+func vaultPath(dir, dest string) string {
+	dest, _, _ = strings.Cut(dest, "#")
+	dest, _, _ = strings.Cut(dest, "?")
+	if unescaped, err := url.PathUnescape(dest); err == nil {
+		dest = unescaped
+	}
+	target := path.Join(dir, dest)
+	if strings.HasPrefix(dest, "/") {
+		target = path.Clean(strings.TrimPrefix(dest, "/"))
+	}
+	return strings.ToLower(target)
+}
+
+// mentionPageLinks replaces rich text linking to an imported page with a
+// mention of the page, throughout a block payload.
+func (v *vaultImport) mentionPageLinks(value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, child := range value {
+			v.mentionPageLinks(child)
+		}
+	case []map[string]interface{}:
+		for i, item := range value {
+			if mention := v.pageMention(item); mention != nil {
+				value[i] = mention
+				continue
+			}
+			v.mentionPageLinks(item)
+		}
+	case [][]map[string]interface{}:
+		for _, cell := range value {
+			v.mentionPageLinks(cell)
+		}
+	case []interface{}:
+		for _, child := range value {
+			v.mentionPageLinks(child)
+		}
+	}
+}
+
+// pageMention returns a page or database mention replacing a rich text
+// item that links to an imported page or database, or nil.
+func (v *vaultImport) pageMention(item map[string]interface{}) map[string]interface{} {
+	if item["type"] != "text" {
+		return nil
+	}
+	text, _ := item["text"].(map[string]interface{})
+	link, _ := text["link"].(map[string]interface{})
+	href, _ := link["url"].(string)
+	target, ok := v.linked[notionLinkID(href)]
+	if href == "" || !ok {
+		return nil
+	}
+	kind := "page"
+	if target.table != nil {
+		kind = "database"
+	}
+	mention := map[string]interface{}{
+		"type":    "mention",
+		"mention": map[string]interface{}{"type": kind, kind: map[string]interface{}{"id": target.id}},
+	}
+	if ann, ok := item["annotations"]; ok {
+		mention["annotations"] = ann
+	}
+	return mention
+}
+
+// printDryRun lists the pages an import would create or update. An empty
+// mappingPath leaves out the mapping file.
+func (v *vaultImport) printDryRun(w io.Writer, resource, source, parentID, mappingPath string) {
+	printer := NewDryRunPrinter(w)
+	printer.Header("import", resource, source)
+	printer.Field("Parent", parentID)
+	printer.Field("Pages", fmt.Sprintf("%d", len(v.notes)))
+	imported := 0
+	for _, note := range v.notes {
+		if v.mapping.Pages[note.key] != nil {
+			imported++
+		}
+	}
+	if imported > 0 {
+		printer.Field("Already imported", fmt.Sprintf("%d", imported))
+	}
+	if mappingPath != "" {
+		printer.Field("Mapping file", mappingPath)
+	}
+
+	printer.Section("Pages:")
+	for _, note := range v.notes {
+		depth := 0
+		for p := note.parent; p != nil; p = p.parent {
+			depth++
+		}
+		detail := note.key
+		if note.table != nil {
+			detail = fmt.Sprintf("database, %d rows", len(note.table.rows))
+		}
+		_, _ = fmt.Fprintf(w, "  %s%s (%s)\n", strings.Repeat("  ", depth), note.title, detail)
+	}
+	printer.Footer()
+}
+
+// stripTitleHeading drops a leading # heading that repeats the title, as
+// ntn page export writes.
+func stripTitleHeading(body, title string) string {
+	line, rest, _ := strings.Cut(strings.TrimLeft(body, "\n"), "\n")
+	if heading, ok := strings.CutPrefix(strings.TrimSpace(line), "# "); ok && strings.TrimSpace(heading) == title {
+		return rest
+	}
+	return body
+}
+
+// importHash fingerprints the properties and blocks written for a note, so
+// that re-runs skip notes that did not change.
+func importHash(props map[string]interface{}, blocks []map[string]interface{}) (string, error) {
+	// encoding/json sorts map keys, so equal payloads hash equally.
+	data, err := json.Marshal([]interface{}{props, blocks})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode page: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func dashlessID(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
+
+// importMapping records the pages created by ntn import dir, keyed by the
+// path of their note or folder, so that imports can be re-run.
+type importMapping struct {
+	Parent string                   `json:"parent"`
+	Pages  map[string]*importedPage `json:"pages"`
+}
+
+// importedPage is an imported page and the hash of the content last written
+// to it.
+type importedPage struct {
+	ID   string `json:"id"`
+	Hash string `json:"hash,omitempty"`
+}
+
+// loadImportMapping reads a mapping file, or returns an empty mapping when
+// it does not exist yet.
+func loadImportMapping(path string) (*importMapping, error) {
+	mapping := &importMapping{Pages: map[string]*importedPage{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return mapping, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file: %w", err)
+	}
+	if err := json.Unmarshal(data, mapping); err != nil {
+		return nil, clierrors.WrapUserError(err, "failed to parse mapping file "+path, "Delete the file to import the directory from scratch")
+	}
+	if mapping.Pages == nil {
+		mapping.Pages = map[string]*importedPage{}
+	}
+	return mapping, nil
+}
+
+func (m *importMapping) save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode mapping file: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write mapping file: %w", err)
+	}
+	return nil
+}
+
+// frontmatterField is a key of YAML frontmatter with its value, or its
+// values for a list.
+type frontmatterField struct {
+	Key    string
+	Values []string
+}
+
+// parseFrontmatterFields parses YAML frontmatter as parseFrontmatter does,
+// but also reads lists, written inline ([a, b]) or as "- item" lines, and
+// strips quotes. Fields keep their order.
+func parseFrontmatterFields(content string) ([]frontmatterField, string) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+	if strings.TrimSpace(lines[0]) != "---" {
+		return nil, content
+	}
+	closing := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			closing = i
+			break
+		}
+	}
+	if closing < 0 {
+		return nil, content
+	}
+
+	var fields []frontmatterField
+	for _, line := range lines[1:closing] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && len(fields) > 0 {
+			last := &fields[len(fields)-1]
+			last.Values = append(last.Values, unquoteYAML(item))
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		field := frontmatterField{Key: strings.TrimSpace(key)}
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = unquoteYAML(item); item != "" {
+					field.Values = append(field.Values, item)
+				}
+			}
+		case value != "":
+			field.Values = []string{unquoteYAML(value)}
+		}
+		fields = append(fields, field)
+	}
+	return fields, strings.Join(lines[closing+1:], "\n")
+}
+
+func unquoteYAML(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
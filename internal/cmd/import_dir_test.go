@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// importDirServer fakes the endpoints ntn import dir uses, recording the
+// pages it creates and the blocks it appends.
+type importDirServer struct {
+	mu       sync.Mutex
+	nextID   int
+	created  []map[string]any
+	titles   map[string]string
+	appended map[string][]any
+	updated  []string
+	deleted  []string
+	// databases records the databases created, by ID.
+	databases map[string]map[string]any
+	// database, when set, is served as the parent database.
+	database map[string]any
+}
+
+func newImportDirServer(t *testing.T, database map[string]any) *importDirServer {
+	t.Helper()
+	t.Setenv("NOTION_TOKEN", "test-token")
+
+	s := &importDirServer{
+		titles:    map[string]string{},
+		appended:  map[string][]any{},
+		databases: map[string]map[string]any{},
+		database:  database,
+	}
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/databases/", func(w http.ResponseWriter, r *http.Request) {
+		if s.database == nil {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]any{"object": "error", "status": 404, "code": "object_not_found", "message": "not found"})
+			return
+		}
+		writeJSON(w, s.database)
+	})
+	mux.HandleFunc("POST /databases", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.mu.Lock()
+		s.nextID++
+		id := fmt.Sprintf("cccccccc-0000-0000-0000-%012d", s.nextID)
+		s.databases[id] = req
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"object": "database", "id": id, "data_sources": []any{
+			map[string]any{"id": "ds-" + id},
+		}})
+	})
+	mux.HandleFunc("/data_sources/ds-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"object": "data_source",
+			"id":     "ds-1",
+			"properties": map[string]any{
+				"Name": map[string]any{"type": "title"},
+				"Tags": map[string]any{"type": "multi_select"},
+				"Due":  map[string]any{"type": "date"},
+			},
+		})
+	})
+	mux.HandleFunc("POST /pages", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.mu.Lock()
+		s.nextID++
+		id := fmt.Sprintf("aaaaaaaa-0000-0000-0000-%012d", s.nextID)
+		s.created = append(s.created, req)
+		props, _ := req["properties"].(map[string]any)
+		for _, prop := range props {
+			if title, ok := prop.(map[string]any)["title"].([]any); ok && len(title) > 0 {
+				s.titles[id] = title[0].(map[string]any)["text"].(map[string]any)["content"].(string)
+			}
+		}
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"object": "page", "id": id})
+	})
+	mux.HandleFunc("PATCH /pages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.updated = append(s.updated, r.PathValue("id"))
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"object": "page", "id": r.PathValue("id")})
+	})
+	mux.HandleFunc("GET /blocks/{id}/children", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"object": "list", "has_more": false, "results": []any{
+			map[string]any{"object": "block", "id": "old-paragraph", "type": "paragraph", "paragraph": map[string]any{"rich_text": []any{}}},
+			map[string]any{"object": "block", "id": "old-child", "type": "child_page", "child_page": map[string]any{"title": "Child"}},
+		}})
+	})
+	mux.HandleFunc("PATCH /blocks/{id}/children", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Children []any `json:"children"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.mu.Lock()
+		id := r.PathValue("id")
+		s.appended[id] = append(s.appended[id], req.Children...)
+		var results []any
+		for i := range req.Children {
+			results = append(results, map[string]any{"object": "block", "id": fmt.Sprintf("%s-block-%d", id, i)})
+		}
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"object": "list", "results": results})
+	})
+	mux.HandleFunc("DELETE /blocks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.deleted = append(s.deleted, r.PathValue("id"))
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"object": "block", "id": r.PathValue("id")})
+	})
+	mux.HandleFunc("POST /file_uploads", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"object": "file_upload", "id": "upload-1", "upload_url": server.URL + "/file_uploads/upload-1/send"})
+	})
+	mux.HandleFunc("POST /file_uploads/upload-1/send", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"object": "file_upload", "id": "upload-1", "status": "uploaded"})
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+	return s
+}
+
+// idOf returns the ID of the page created with title.
+func (s *importDirServer) idOf(t *testing.T, title string) string {
+	t.Helper()
+	for id, got := range s.titles {
+		if got == title {
+			return id
+		}
+	}
+	t.Fatalf("no page created with title %q (have %v)", title, s.titles)
+	return ""
+}
+
+func writeVault(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func runImportDir(t *testing.T, args ...string) string {
+	t.Helper()
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs(append([]string{"import", "dir"}, args...))
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("import dir failed: %v\nstderr=%s", err, errBuf.String())
+	}
+	return errBuf.String()
+}
+
+func TestImportDir(t *testing.T) {
+	server := newImportDirServer(t, nil)
+	vault := writeVault(t, map[string]string{
+		"Home.md": "---\ntitle: Welcome\n---\n# Welcome\n\n" +
+			"See [[Ideas|my ideas]] and [setup](Projects/Setup%20Guide.md#install).\n\n![[diagram.png]]\n",
+		"Projects.md":                    "Project index\n",
+		"Projects/Setup Guide.md":        "Back to [[Home]]\n",
+		"Projects/Archive/Ideas.md":      "- one\n",
+		"attachments/diagram.png":        "PNG",
+		".obsidian/workspace.md":         "hidden\n",
+		"Projects/Archive/.trash/Old.md": "deleted\n",
+	})
+	parentID := "bbbbbbbb-0000-0000-0000-000000000000"
+
+	stderr := runImportDir(t, vault, "--parent", parentID)
+	if !strings.Contains(stderr, "Imported 5 pages") || !strings.Contains(stderr, "5 created, 0 updated, 0 unchanged") {
+		t.Errorf("summary = %q", stderr)
+	}
+
+	home := server.idOf(t, "Welcome")
+	projects := server.idOf(t, "Projects")
+	archive := server.idOf(t, "Archive")
+	ideas := server.idOf(t, "Ideas")
+	setup := server.idOf(t, "Setup Guide")
+
+	parents := map[string]string{}
+	for i, req := range server.created {
+		id := fmt.Sprintf("aaaaaaaa-0000-0000-0000-%012d", i+1)
+		parents[id], _ = req["parent"].(map[string]any)["page_id"].(string)
+	}
+	wantParents := map[string]string{home: parentID, projects: parentID, archive: projects, ideas: archive, setup: projects}
+	for id, want := range wantParents {
+		if parents[id] != want {
+			t.Errorf("parent of %s (%s) = %q, want %q", server.titles[id], id, parents[id], want)
+		}
+	}
+
+	blocks, _ := json.Marshal(server.appended[home])
+	for _, want := range []string{
+		`{"mention":{"page":{"id":"` + ideas + `"},"type":"page"},"type":"mention"}`,
+		`{"mention":{"page":{"id":"` + setup + `"},"type":"page"},"type":"mention"}`,
+		`"file_upload":{"id":"upload-1"}`,
+	} {
+		if !strings.Contains(string(blocks), want) {
+			t.Errorf("Home content missing %s:\n%s", want, blocks)
+		}
+	}
+	if strings.Contains(string(blocks), "heading_1") {
+		t.Errorf("Home content should drop the title heading:\n%s", blocks)
+	}
+	back, _ := json.Marshal(server.appended[setup])
+	if !strings.Contains(string(back), `"page":{"id":"`+home+`"}`) {
+		t.Errorf("Setup Guide should mention Home:\n%s", back)
+	}
+
+	var mapping importMapping
+	data, err := os.ReadFile(filepath.Join(vault, importMappingName))
+	if err != nil {
+		t.Fatalf("mapping file: %v", err)
+	}
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		t.Fatalf("mapping file: %v", err)
+	}
+	if mapping.Parent != parentID || mapping.Pages["Projects/Archive/"].ID != archive || mapping.Pages["Home.md"].ID != home {
+		t.Errorf("mapping = %s", data)
+	}
+
+	// Re-running creates nothing and skips unchanged notes.
+	stderr = runImportDir(t, vault, "--parent", parentID)
+	if !strings.Contains(stderr, "0 created, 0 updated, 5 unchanged") || len(server.created) != 5 {
+		t.Errorf("re-run: summary = %q, %d pages created", stderr, len(server.created))
+	}
+
+	// A changed note has its content replaced, keeping its child pages.
+	if err := os.WriteFile(filepath.Join(vault, "Projects.md"), []byte("New index\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr = runImportDir(t, vault, "--parent", parentID)
+	if !strings.Contains(stderr, "0 created, 1 updated, 4 unchanged") {
+		t.Errorf("update: summary = %q", stderr)
+	}
+	if len(server.updated) != 1 || server.updated[0] != projects {
+		t.Errorf("updated pages = %v, want [%s]", server.updated, projects)
+	}
+	if len(server.deleted) != 1 || server.deleted[0] != "old-paragraph" {
+		t.Errorf("deleted blocks = %v, want [old-paragraph]", server.deleted)
+	}
+}
+
+func TestVaultImport_WriteNoteCountsDatabases(t *testing.T) {
+	v := &vaultImport{}
+	for _, created := range []bool{true, false} {
+		if err := v.writeNote(context.Background(), &vaultNote{table: &vaultTable{}, created: created}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v.created != 1 || v.unchanged != 1 {
+		t.Errorf("created = %d, unchanged = %d; want a database counted as created only when it was", v.created, v.unchanged)
+	}
+}
+
+func TestImportDir_DatabaseFrontmatter(t *testing.T) {
+	server := newImportDirServer(t, map[string]any{
+		"object":       "database",
+		"id":           "cccccccc-0000-0000-0000-000000000000",
+		"data_sources": []any{map[string]any{"id": "ds-1", "name": "Tasks"}},
+	})
+	vault := writeVault(t, map[string]string{
+		"Plan.md": "---\ntags:\n  - work\n  - \"q1\"\ndue: 2026-01-02\naliases: [plan]\n---\nBody\n",
+	})
+
+	stderr := runImportDir(t, vault, "--parent", "cccccccc-0000-0000-0000-000000000000")
+	if !strings.Contains(stderr, `frontmatter key "aliases" does not match any database property`) {
+		t.Errorf("stderr = %q, want a warning for aliases", stderr)
+	}
+
+	if len(server.created) != 1 {
+		t.Fatalf("created %d pages, want 1", len(server.created))
+	}
+	req, _ := json.Marshal(server.created[0])
+	for _, want := range []string{
+		`"parent":{"data_source_id":"ds-1"}`,
+		`"Name":{"title":[{"text":{"content":"Plan"}`,
+		`"Tags":{"multi_select":[{"name":"work"},{"name":"q1"}]}`,
+		`"Due":{"date":{"start":"2026-01-02"}}`,
+	} {
+		if !strings.Contains(string(req), want) {
+			t.Errorf("create request missing %s:\n%s", want, req)
+		}
+	}
+}
+
+func TestParseFrontmatterFields(t *testing.T) {
+	fields, body := parseFrontmatterFields("---\ntitle: \"A: B\"\ntags: [x, 'y']\nlist:\n  - one\n  - two\n# comment\nempty:\n---\nBody\n")
+	want := []frontmatterField{
+		{Key: "title", Values: []string{"A: B"}},
+		{Key: "tags", Values: []string{"x", "y"}},
+		{Key: "list", Values: []string{"one", "two"}},
+		{Key: "empty"},
+	}
+	if fmt.Sprint(fields) != fmt.Sprint(want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+	if body != "Body\n" {
+		t.Errorf("body = %q", body)
+	}
+
+	if fields, body := parseFrontmatterFields("No frontmatter\n"); fields != nil || body != "No frontmatter\n" {
+		t.Errorf("without frontmatter: %v, %q", fields, body)
+	}
+}
@@ -0,0 +1,667 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// The HTML importer converts HTML, such as Confluence exports, saved web
+// pages and email bodies, to the same Notion blocks as the Markdown
+// importer. The HTML is parsed leniently into an element tree, closing the
+// elements browsers close implicitly, and the tree is walked with the
+// Markdown parser's rich text, image and code block handling.
+
+// htmlNode is an element of the parsed HTML tree, or a text node when tag
+// is "".
+type htmlNode struct {
+	tag      string
+	attrs    map[string]string
+	text     string
+	children []*htmlNode
+}
+
+var (
+	// htmlVoidElements have no content and no closing tag.
+	htmlVoidElements = keywordSet("area base br col embed hr img input link meta param source track wbr")
+	// htmlRawTextElements contain text that is not parsed as HTML.
+	htmlRawTextElements = keywordSet("script style textarea title")
+	// htmlParagraphClosers close an open <p>.
+	htmlParagraphClosers = keywordSet("address article aside blockquote details div dl fieldset figure footer form h1 h2 h3 h4 h5 h6 header hr main nav ol p pre section table ul")
+)
+
+// htmlImpliedEnd lists, for a start tag, the open elements it closes and the
+// elements the search for them stops at.
+func htmlImpliedEnd(tag string) (closes, scope map[string]bool) {
+	switch tag {
+	case "li":
+		return keywordSet("li"), keywordSet("ul ol")
+	case "dt", "dd":
+		return keywordSet("dt dd"), keywordSet("dl")
+	case "tr":
+		return keywordSet("tr td th"), keywordSet("table thead tbody tfoot")
+	case "td", "th":
+		return keywordSet("td th"), keywordSet("tr table")
+	case "thead", "tbody", "tfoot":
+		return keywordSet("thead tbody tfoot tr td th"), keywordSet("table")
+	}
+	if htmlParagraphClosers[tag] {
+		return keywordSet("p"), keywordSet("td th li dd dt blockquote div table body html")
+	}
+	return nil, nil
+}
+
+var (
+	htmlTagNamePattern = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9:-]*)`)
+	htmlAttrPattern    = regexp.MustCompile(`([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+)
+
+// parseHTML parses HTML into a tree under a root node. Comments, doctypes
+// and unknown closing tags are dropped; entities are decoded.
+func parseHTML(content string) *htmlNode {
+	root := &htmlNode{}
+	stack := []*htmlNode{root}
+	popTo := func(i int) { stack = stack[:i] }
+
+	for i := 0; i < len(content); {
+		rest := content[i:]
+		if rest[0] != '<' {
+			end := strings.IndexByte(rest, '<')
+			if end < 0 {
+				end = len(rest)
+			}
+			appendHTMLText(stack[len(stack)-1], html.UnescapeString(rest[:end]))
+			i += end
+			continue
+		}
+		if strings.HasPrefix(rest, "<!--") {
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+		if strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?") {
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				break
+			}
+			i += end + 1
+			continue
+		}
+
+		m := htmlTagNamePattern.FindStringSubmatch(rest)
+		end := htmlTagEnd(rest)
+		if m == nil || end < 0 {
+			appendHTMLText(stack[len(stack)-1], "<")
+			i++
+			continue
+		}
+		i += end + 1
+		tag := strings.ToLower(m[2])
+
+		if m[1] == "/" {
+			for j := len(stack) - 1; j > 0; j-- {
+				if stack[j].tag == tag {
+					popTo(j)
+					break
+				}
+			}
+			continue
+		}
+
+		if closes, scope := htmlImpliedEnd(tag); closes != nil {
+			found := 0
+			for j := len(stack) - 1; j > 0 && !scope[stack[j].tag]; j-- {
+				if closes[stack[j].tag] {
+					found = j
+				}
+			}
+			if found > 0 {
+				popTo(found)
+			}
+		}
+
+		node := &htmlNode{tag: tag, attrs: parseHTMLAttrs(rest[len(m[0]):end])}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, node)
+		if htmlVoidElements[tag] || strings.HasSuffix(rest[:end], "/") {
+			continue
+		}
+		if htmlRawTextElements[tag] {
+			closing := strings.Index(strings.ToLower(content[i:]), "</"+tag)
+			if closing < 0 {
+				closing = len(content) - i
+			}
+			appendHTMLText(node, html.UnescapeString(content[i:i+closing]))
+			i += closing
+			if gt := strings.IndexByte(content[i:], '>'); gt >= 0 {
+				i += gt + 1
+			}
+			continue
+		}
+		stack = append(stack, node)
+	}
+	return root
+}
+
+// htmlTagEnd returns the index of the '>' ending the tag at the start of
+// s, skipping quoted attribute values, or -1.
+func htmlTagEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '>':
+			return i
+		case s[i] == '<':
+			return -1
+		}
+	}
+	return -1
+}
+
+func parseHTMLAttrs(s string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range htmlAttrPattern.FindAllStringSubmatch(s, -1) {
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+	}
+	return attrs
+}
+
+func appendHTMLText(node *htmlNode, text string) {
+	if text == "" {
+		return
+	}
+	if n := len(node.children); n > 0 && node.children[n-1].tag == "" {
+		node.children[n-1].text += text
+		return
+	}
+	node.children = append(node.children, &htmlNode{text: text})
+}
+
+// htmlTextContent returns the text of a node and its descendants, with
+// <br> as a newline.
+func htmlTextContent(node *htmlNode) string {
+	if node.tag == "" {
+		return node.text
+	}
+	if node.tag == "br" {
+		return "\n"
+	}
+	var b strings.Builder
+	for _, child := range node.children {
+		b.WriteString(htmlTextContent(child))
+	}
+	return b.String()
+}
+
+// parseHTMLToBlocks converts HTML to Notion blocks. Local images are left
+// without an upload; use parseHTMLDocument to upload them.
+func parseHTMLToBlocks(content string) []map[string]interface{} {
+	return parseHTMLDocument(content, "").Blocks
+}
+
+// parseHTMLDocument converts HTML to Notion blocks, resolving local image
+// paths against baseDir.
+func parseHTMLDocument(content, baseDir string) *markdownDocument {
+	p := newMarkdownParser(baseDir)
+	h := &htmlConverter{p: p}
+	blocks := h.flow(parseHTML(strings.ReplaceAll(content, "\r\n", "\n")).children)
+	return &markdownDocument{Blocks: blocks, Images: p.images}
+}
+
+// htmlConverter converts an HTML tree to Notion blocks.
+type htmlConverter struct {
+	p *markdownParser
+}
+
+var (
+	// htmlSkippedElements are dropped with their content.
+	htmlSkippedElements = keywordSet("head title script style template noscript button select input textarea iframe object svg canvas map")
+	// htmlContainerElements hold blocks and inline content; their inline
+	// content becomes paragraphs.
+	htmlContainerElements = keywordSet("html body main article section header footer nav aside div p address center form fieldset dl dt dd figcaption legend caption")
+	// htmlBlockElements become blocks of their own.
+	htmlBlockElements = keywordSet("h1 h2 h3 h4 h5 h6 ul ol blockquote pre table hr figure details")
+)
+
+// htmlFlow collects the blocks of mixed block and inline content: the
+// inline content between blocks becomes paragraphs.
+type htmlFlow struct {
+	h      *htmlConverter
+	c      *inlineConverter
+	blocks []map[string]interface{}
+	// inline, for headings and table cells, puts the content of block
+	// elements on lines of its own and drops images.
+	inline bool
+}
+
+// flow converts nodes to blocks.
+func (h *htmlConverter) flow(nodes []*htmlNode) []map[string]interface{} {
+	f := &htmlFlow{h: h, c: &inlineConverter{p: h.p}}
+	f.walk(nodes, inlineStyle{})
+	f.flush()
+	return f.blocks
+}
+
+// richText converts the inline content of nodes to rich text.
+func (h *htmlConverter) richText(nodes []*htmlNode) []map[string]interface{} {
+	f := &htmlFlow{h: h, c: &inlineConverter{p: h.p}, inline: true}
+	f.walk(nodes, inlineStyle{})
+	return richTextItems(f.takeRuns())
+}
+
+func (f *htmlFlow) walk(nodes []*htmlNode, st inlineStyle) {
+	for _, node := range nodes {
+		switch {
+		case node.tag == "":
+			f.text(node.text, st)
+		case htmlSkippedElements[node.tag]:
+		case node.tag == "br":
+			f.c.add("\n", st, false)
+		case node.tag == "img":
+			if !f.inline {
+				f.flush()
+				f.add(f.h.image(node, nil))
+			}
+		case f.inline && (htmlContainerElements[node.tag] || htmlBlockElements[node.tag] || node.tag == "li" || node.tag == "tr"):
+			f.newline(st)
+			f.walk(node.children, st)
+			f.newline(st)
+		case htmlContainerElements[node.tag]:
+			f.flush()
+			f.walk(node.children, st)
+			f.flush()
+		case htmlBlockElements[node.tag]:
+			f.flush()
+			f.blocks = append(f.blocks, f.h.block(node)...)
+		default:
+			f.walk(node.children, f.h.inlineStyle(node, st))
+		}
+	}
+}
+
+var htmlSpacePattern = regexp.MustCompile(`[ \t\n\r\f]+`)
+
+// text adds a text node, collapsing white space as browsers render it.
+func (f *htmlFlow) text(text string, st inlineStyle) {
+	text = htmlSpacePattern.ReplaceAllString(text, " ")
+	if strings.HasPrefix(text, " ") {
+		if n := len(f.c.runs); n == 0 || strings.HasSuffix(f.c.runs[n-1].text, " ") || strings.HasSuffix(f.c.runs[n-1].text, "\n") {
+			text = text[1:]
+		}
+	}
+	f.c.add(text, st, false)
+}
+
+// newline starts a new line of inline content, unless one was just started.
+func (f *htmlFlow) newline(st inlineStyle) {
+	if n := len(f.c.runs); n > 0 && !strings.HasSuffix(f.c.runs[n-1].text, "\n") {
+		f.c.add("\n", st, false)
+	}
+}
+
+// takeRuns returns the inline content collected since the last block,
+// without leading and trailing white space.
+func (f *htmlFlow) takeRuns() []inlineRun {
+	runs := f.c.runs
+	f.c.runs = nil
+	for len(runs) > 0 {
+		if text := strings.TrimLeft(runs[0].text, " \n"); text != "" || runs[0].equation {
+			runs[0].text = text
+			break
+		}
+		runs = runs[1:]
+	}
+	for len(runs) > 0 {
+		n := len(runs) - 1
+		if text := strings.TrimRight(runs[n].text, " \n"); text != "" || runs[n].equation {
+			runs[n].text = text
+			break
+		}
+		runs = runs[:n]
+	}
+	return runs
+}
+
+// flush adds the inline content collected since the last block as a
+// paragraph.
+func (f *htmlFlow) flush() {
+	if runs := f.takeRuns(); len(runs) > 0 {
+		f.add(htmlTextBlock("paragraph", richTextItems(runs), nil, nil))
+	}
+}
+
+func (f *htmlFlow) add(block map[string]interface{}) {
+	if block != nil {
+		f.blocks = append(f.blocks, block)
+	}
+}
+
+var (
+	htmlBoldStylePattern   = regexp.MustCompile(`font-weight\s*:\s*(bold|[6-9]00)`)
+	htmlNormalStylePattern = regexp.MustCompile(`font-weight\s*:\s*(normal|[1-4]00)`)
+	htmlCodeClassPattern   = regexp.MustCompile(`(?:^|\s)(?:language|lang)-([\w+#-]+)`)
+)
+
+// inlineStyle returns the formatting of an inline element's content: its
+// tag, and the inline styles word processors and Confluence write.
+func (h *htmlConverter) inlineStyle(node *htmlNode, st inlineStyle) inlineStyle {
+	switch node.tag {
+	case "b", "strong":
+		st.bold = true
+	case "i", "em", "cite", "var", "dfn":
+		st.italic = true
+	case "u", "ins":
+		st.underline = true
+	case "s", "del", "strike":
+		st.strikethrough = true
+	case "code", "kbd", "samp", "tt":
+		st.code = true
+	case "mark":
+		st.color = "yellow_background"
+	case "a":
+		if href := strings.TrimSpace(node.attrs["href"]); href != "" && st.link == "" {
+			st.link = h.p.linkURL(href)
+		}
+	}
+
+	style := strings.ToLower(node.attrs["style"])
+	switch {
+	case htmlBoldStylePattern.MatchString(style):
+		st.bold = true
+	case htmlNormalStylePattern.MatchString(style):
+		// Google Docs wraps pasted text in <b style="font-weight:normal">.
+		st.bold = false
+	}
+	if strings.Contains(style, "italic") {
+		st.italic = true
+	}
+	if strings.Contains(style, "underline") {
+		st.underline = true
+	}
+	if strings.Contains(style, "line-through") {
+		st.strikethrough = true
+	}
+	if color := htmlColor(style + ";color:" + node.attrs["color"]); color != "" {
+		st.color = color
+	}
+	return st
+}
+
+// block converts an element of htmlBlockElements.
+func (h *htmlConverter) block(node *htmlNode) []map[string]interface{} {
+	switch node.tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := h.richText(node.children)
+		if len(text) == 0 {
+			return nil
+		}
+		level := min(int(node.tag[1]-'0'), 3)
+		return []map[string]interface{}{htmlTextBlock(fmt.Sprintf("heading_%d", level), text, nil, nil)}
+	case "ul", "ol":
+		return h.list(node)
+	case "blockquote":
+		text, children := leadingRichText(h.flow(node.children))
+		return []map[string]interface{}{htmlTextBlock("quote", text, children, nil)}
+	case "pre":
+		return []map[string]interface{}{h.code(node)}
+	case "table":
+		return h.table(node)
+	case "hr":
+		return []map[string]interface{}{notion.NewDivider()}
+	case "figure":
+		return h.figure(node)
+	case "details":
+		summary := []map[string]interface{}{}
+		var rest []*htmlNode
+		for _, child := range node.children {
+			if child.tag == "summary" && len(summary) == 0 {
+				summary = h.richText(child.children)
+				continue
+			}
+			rest = append(rest, child)
+		}
+		return []map[string]interface{}{htmlTextBlock("toggle", summary, h.flow(rest), nil)}
+	}
+	return nil
+}
+
+// htmlTextBlock builds a block of the given type from rich text, with its
+// children and any extra fields.
+func htmlTextBlock(blockType string, text, children []map[string]interface{}, extra map[string]interface{}) map[string]interface{} {
+	content := map[string]interface{}{"rich_text": text}
+	for k, v := range extra {
+		content[k] = v
+	}
+	if len(children) > 0 {
+		content["children"] = children
+	}
+	return map[string]interface{}{"type": blockType, blockType: content}
+}
+
+// leadingRichText splits a leading paragraph from blocks, to become the
+// text of the list item or quote holding them.
+func leadingRichText(blocks []map[string]interface{}) ([]map[string]interface{}, []map[string]interface{}) {
+	if len(blocks) > 0 && blocks[0]["type"] == "paragraph" {
+		content := blocks[0]["paragraph"].(map[string]interface{})
+		return content["rich_text"].([]map[string]interface{}), blocks[1:]
+	}
+	return []map[string]interface{}{}, blocks
+}
+
+// list converts a <ul> or <ol> to list item blocks.
+func (h *htmlConverter) list(node *htmlNode) []map[string]interface{} {
+	var items []map[string]interface{}
+	for _, child := range node.children {
+		switch child.tag {
+		case "li":
+			items = append(items, h.listItem(child, node.tag == "ol"))
+		case "ul", "ol":
+			// A list nested directly in a list belongs to the item before it.
+			nested := h.list(child)
+			if len(items) == 0 {
+				items = append(items, nested...)
+				continue
+			}
+			content := items[len(items)-1][items[len(items)-1]["type"].(string)].(map[string]interface{})
+			existing, _ := content["children"].([]map[string]interface{})
+			content["children"] = append(existing, nested...)
+		}
+	}
+	return items
+}
+
+// listItem converts an <li>. Items starting with a checkbox, as task lists
+// are written, become to-dos.
+func (h *htmlConverter) listItem(node *htmlNode, ordered bool) map[string]interface{} {
+	text, children := leadingRichText(h.flow(node.children))
+	if checked, ok := htmlTaskCheckbox(node); ok {
+		return htmlTextBlock("to_do", text, children, map[string]interface{}{"checked": checked})
+	}
+	if ordered {
+		return htmlTextBlock("numbered_list_item", text, children, nil)
+	}
+	return htmlTextBlock("bulleted_list_item", text, children, nil)
+}
+
+// htmlTaskCheckbox finds the checkbox at the start of a list item, directly
+// or in its first paragraph or label.
+func htmlTaskCheckbox(item *htmlNode) (bool, bool) {
+	nodes := item.children
+	for _, child := range item.children {
+		if child.tag == "p" || child.tag == "label" {
+			nodes = child.children
+			break
+		}
+		if child.tag != "" {
+			break
+		}
+	}
+	for _, child := range nodes {
+		if child.tag == "input" && strings.EqualFold(child.attrs["type"], "checkbox") {
+			_, checked := child.attrs["checked"]
+			return checked, true
+		}
+	}
+	return false, false
+}
+
+// code converts a <pre> to a code block, reading the language from a
+// language-* class on it or its <code>.
+func (h *htmlConverter) code(node *htmlNode) map[string]interface{} {
+	language := ""
+	for _, n := range append([]*htmlNode{node}, node.children...) {
+		if m := htmlCodeClassPattern.FindStringSubmatch(n.attrs["class"]); m != nil {
+			language = m[1]
+			break
+		}
+	}
+	text := strings.TrimPrefix(htmlTextContent(node), "\n")
+	text = strings.TrimRight(text, "\n")
+	return h.p.code(&mdNode{kind: mdCode, text: text, language: language})
+}
+
+// table converts a <table> to a table block. Tables holding tables or
+// with a single column, as emails are laid out, are converted cell by cell
+// instead.
+func (h *htmlConverter) table(node *htmlNode) []map[string]interface{} {
+	var rows [][]*htmlNode
+	header := false
+	layout := false
+	var collect func(nodes []*htmlNode, head bool)
+	collect = func(nodes []*htmlNode, head bool) {
+		for _, n := range nodes {
+			switch n.tag {
+			case "thead":
+				collect(n.children, true)
+			case "tbody", "tfoot":
+				collect(n.children, false)
+			case "tr":
+				var cells []*htmlNode
+				allHeaders := true
+				for _, cell := range n.children {
+					if cell.tag != "td" && cell.tag != "th" {
+						continue
+					}
+					cells = append(cells, cell)
+					allHeaders = allHeaders && cell.tag == "th"
+					layout = layout || hasHTMLElement(cell, "table")
+				}
+				if len(cells) == 0 {
+					continue
+				}
+				if len(rows) == 0 {
+					header = head || allHeaders
+				}
+				rows = append(rows, cells)
+			}
+		}
+	}
+	collect(node.children, false)
+
+	width := 0
+	for _, cells := range rows {
+		width = max(width, len(cells))
+	}
+	if layout || width == 1 {
+		var blocks []map[string]interface{}
+		for _, cells := range rows {
+			for _, cell := range cells {
+				blocks = append(blocks, h.flow(cell.children)...)
+			}
+		}
+		return blocks
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	tableRows := make([]map[string]interface{}, 0, len(rows))
+	for _, cells := range rows {
+		texts := make([][]map[string]interface{}, width)
+		for j := range texts {
+			texts[j] = []map[string]interface{}{}
+			if j < len(cells) {
+				texts[j] = h.richText(cells[j].children)
+			}
+		}
+		tableRows = append(tableRows, notion.NewTableRow(texts))
+	}
+	return []map[string]interface{}{notion.NewTable(width, header, tableRows)}
+}
+
+func hasHTMLElement(node *htmlNode, tag string) bool {
+	for _, child := range node.children {
+		if child.tag == tag || hasHTMLElement(child, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// figure converts a <figure> holding an image to an image captioned with
+// its <figcaption>, and any other figure to its content.
+func (h *htmlConverter) figure(node *htmlNode) []map[string]interface{} {
+	var img, caption *htmlNode
+	for _, child := range node.children {
+		switch child.tag {
+		case "img":
+			img = child
+		case "figcaption":
+			caption = child
+		case "a", "picture":
+			for _, grandchild := range child.children {
+				if grandchild.tag == "img" {
+					img = grandchild
+				}
+			}
+		}
+	}
+	if img == nil {
+		return h.flow(node.children)
+	}
+	var text []map[string]interface{}
+	if caption != nil {
+		text = h.richText(caption.children)
+	}
+	if block := h.image(img, text); block != nil {
+		return []map[string]interface{}{block}
+	}
+	return nil
+}
+
+// image converts an <img>, captioned with caption or its alt text. Web
+// images are linked and local files uploaded, as in Markdown; images that
+// are inline data or email attachments keep only their alt text.
+func (h *htmlConverter) image(node *htmlNode, caption []map[string]interface{}) map[string]interface{} {
+	src := strings.TrimSpace(node.attrs["src"])
+	alt := strings.TrimSpace(node.attrs["alt"])
+	if src == "" {
+		return nil
+	}
+	if schemePattern.MatchString(src) && !isWebURL(src) && !strings.HasPrefix(src, "file://") {
+		if alt == "" {
+			return nil
+		}
+		return htmlTextBlock("paragraph", plainRichText(alt), nil, nil)
+	}
+	if len(caption) == 0 && alt != "" {
+		caption = plainRichText(alt)
+	}
+	block := h.p.image(&mdNode{kind: mdImage, src: src})
+	if len(caption) > 0 {
+		block[block["type"].(string)].(map[string]interface{})["caption"] = caption
+	}
+	return block
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHTMLToBlocks_MatchesMarkdown(t *testing.T) {
+	// The same document in Markdown and HTML must give the same payloads.
+	md := "# Title\n\n" +
+		"Plain **bold** *italic* `code` [link](https://example.invalid/a) <u>under</u>\n\n" +
+		"- one\n  - nested\n- two\n\n" +
+		"1. first\n\n" +
+		"- [x] done\n\n" +
+		"> quoted\n\n" +
+		"```go\nfmt.Println(\"<hi>\")\n```\n\n" +
+		"| Name | Role |\n| --- | --- |\n| Ada | **Eng** |\n\n" +
+		"---\n\n" +
+		"![Chart](https://example.invalid/c.png)\n"
+	html := `<h1>Title</h1>
+<p>Plain <strong>bold</strong> <em>italic</em> <code>code</code>
+   <a href="https://example.invalid/a">link</a> <u>under</u></p>
+<ul>
+  <li>one
+    <ul><li>nested</li></ul>
+  </li>
+  <li>two</li>
+</ul>
+<ol><li>first</li></ol>
+<ul><li><input type="checkbox" checked> done</li></ul>
+<blockquote><p>quoted</p></blockquote>
+<pre><code class="language-go">fmt.Println(&quot;&lt;hi&gt;&quot;)
+</code></pre>
+<table>
+  <thead><tr><th>Name</th><th>Role</th></tr></thead>
+  <tbody><tr><td>Ada</td><td><b>Eng</b></td></tr></tbody>
+</table>
+<hr>
+<img src="https://example.invalid/c.png" alt="Chart">`
+
+	got := parseHTMLToBlocks(html)
+	want := parseMarkdownToBlocks(md)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HTML blocks:\n%s\nMarkdown blocks:\n%s",
+			strings.Join(blockOutline(got, ""), "\n"), strings.Join(blockOutline(want, ""), "\n"))
+		for i := range min(len(got), len(want)) {
+			if !reflect.DeepEqual(got[i], want[i]) {
+				t.Errorf("block %d:\n got %v\nwant %v", i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestParseHTMLDocument_Lenient(t *testing.T) {
+	dir := t.TempDir()
+	html := `<!DOCTYPE html>
+<html><head><title>Page</title><style>p { color: red }</style></head>
+<body>
+<!-- navigation -->
+<div class="wiki-content">
+<h4>Deep   heading</h4>
+<p>First paragraph
+<p>Second<br>line with <span style="font-weight: bold; color: red">red bold</span>
+<ul>
+  <li><p>Item</p><p>More</p>
+  <li>Other
+    <ol><li>Sub</ol>
+</ul>
+<p><img src="images/my%20chart.png" alt="Local"> after the image</p>
+<figure><img src="https://example.invalid/f.png"><figcaption>A <i>figure</i></figcaption></figure>
+<details><summary>More</summary><p>Hidden</p></details>
+<table><tr><td>a<td>b<tr><td>c</table>
+<table><tr><td><table><tr><td>Layout</td></tr></table></td></tr></table>
+<img src="cid:logo@mail" alt="Logo">
+<script>ignored()</script>
+</div>`
+
+	doc := parseHTMLDocument(html, dir)
+	want := []string{
+		"heading_3 Deep heading",
+		"paragraph First paragraph",
+		"paragraph Second\nline with red bold",
+		"bulleted_list_item Item",
+		"  paragraph More",
+		"bulleted_list_item Other",
+		"  numbered_list_item Sub",
+		"image",
+		"paragraph after the image",
+		"image",
+		"toggle More",
+		"  paragraph Hidden",
+		"table",
+		"  table_row",
+		"  table_row",
+		"paragraph Layout",
+		"paragraph Logo",
+	}
+	if got := blockOutline(doc.Blocks, ""); !reflect.DeepEqual(got, want) {
+		t.Fatalf("blocks:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	styled := doc.Blocks[2]["paragraph"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+	if ann := styled[1]["annotations"].(map[string]interface{}); ann["bold"] != true || ann["color"] != "red" {
+		t.Errorf("span style = %v", ann)
+	}
+	if len(doc.Images) != 1 || doc.Images[0].Path != filepath.Join(dir, "images", "my chart.png") {
+		t.Fatalf("local images = %+v", doc.Images)
+	}
+	local := doc.Blocks[5]["image"].(map[string]interface{})
+	if local["type"] != "file_upload" || local["caption"] == nil {
+		t.Errorf("local image = %v", local)
+	}
+	figure := doc.Blocks[7]["image"].(map[string]interface{})
+	caption := figure["caption"].([]map[string]interface{})
+	if len(caption) != 2 || caption[1]["annotations"].(map[string]interface{})["italic"] != true {
+		t.Errorf("figure caption = %v", caption)
+	}
+	table := doc.Blocks[9]["table"].(map[string]interface{})
+	if table["table_width"] != 2 || table["has_column_header"] != false {
+		t.Errorf("table = %v", table)
+	}
+}
+
+func TestParseHTML_Entities(t *testing.T) {
+	root := parseHTML(`<p title="a &amp; b">x &lt; y&nbsp;&#8212; <b>z</p>`)
+	p := root.children[0]
+	if p.attrs["title"] != "a & b" {
+		t.Errorf("attribute = %q", p.attrs["title"])
+	}
+	if got := htmlTextContent(p); got != "x < y\u00a0— z" {
+		t.Errorf("text = %q", got)
+	}
+}
@@ -0,0 +1,1200 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// The Markdown importer parses CommonMark with the GitHub extensions
+// (tables, task lists, strikethrough, autolinks, footnotes and alerts), plus
+// $$ math and <details> toggles, into a block tree, then maps that tree to
+// Notion blocks. It reads everything the Markdown exporter writes, so
+// exporting a page and importing the result keeps its structure and
+// formatting.
+
+// mdKind is the kind of a Markdown block node.
+type mdKind int
+
+const (
+	mdParagraph mdKind = iota
+	mdHeading
+	mdQuote
+	mdListItem
+	mdCode
+	mdEquation
+	mdDivider
+	mdTable
+	mdToggle
+	mdImage
+	mdBreadcrumb
+)
+
+// mdNode is a node of the parsed Markdown block tree.
+type mdNode struct {
+	kind mdKind
+	// text is the inline Markdown of paragraphs, headings, list items and
+	// toggle summaries, the source of code and equations, and the alt text
+	// of images.
+	text     string
+	level    int
+	ordered  bool
+	task     bool
+	checked  bool
+	language string
+	caption  string
+	src      string
+	rows     [][]string
+	children []*mdNode
+}
+
+// markdownDocument is Markdown converted to Notion blocks. Images that
+// reference local files are listed in Images: their blocks are only valid
+// once uploadImages has uploaded the files.
+type markdownDocument struct {
+	Blocks []map[string]interface{}
+	Images []*markdownImage
+}
+
+// markdownImage is an image or file block pointing at a local file.
+type markdownImage struct {
+	Path  string
+	image map[string]interface{}
+}
+
+// markdownParser holds the state shared by the block and inline passes:
+// link reference definitions, footnotes and the local images found.
+type markdownParser struct {
+	baseDir       string
+	linkRefs      map[string]string
+	footnotes     map[string]*mdNode
+	footnoteOrder []string
+	images        []*markdownImage
+
+	// resolveLink, when set, maps the destinations of relative links, and
+	// the targets of [[wiki links]] when wiki is true, to URLs. Links it
+	// maps to "" keep only their text. Without it wiki links stay literal.
+	resolveLink func(dest string, wiki bool) string
+	// resolveEmbed, when set, maps the target of an ![[embed]] (wiki) or
+	// the destination of a relative [link](file.pdf) making up a paragraph
+	// to a local file, which is uploaded. "" leaves the paragraph as text.
+	resolveEmbed func(target string, wiki bool) string
+}
+
+func newMarkdownParser(baseDir string) *markdownParser {
+	return &markdownParser{
+		baseDir:   baseDir,
+		linkRefs:  map[string]string{},
+		footnotes: map[string]*mdNode{},
+	}
+}
+
+// parseMarkdownToBlocks converts Markdown to Notion blocks. Local images are
+// left without an upload; use parseMarkdownDocument to upload them.
+func parseMarkdownToBlocks(content string) []map[string]interface{} {
+	return parseMarkdownDocument(content, "").Blocks
+}
+
+// parseMarkdownDocument converts Markdown to Notion blocks, resolving local
+// image paths against baseDir.
+func parseMarkdownDocument(content, baseDir string) *markdownDocument {
+	return newMarkdownParser(baseDir).document(content)
+}
+
+// document converts Markdown to Notion blocks.
+func (p *markdownParser) document(content string) *markdownDocument {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	nodes := p.parseBlocks(strings.Split(content, "\n"), " ")
+	blocks := p.blocks(nodes)
+	blocks = append(blocks, p.footnoteBlocks()...)
+	return &markdownDocument{Blocks: blocks, Images: p.images}
+}
+
+// parseBlocks parses lines into block nodes. softBreak joins the lines of a
+// paragraph: a space, or a newline inside quotes where Notion keeps lines.
+func (p *markdownParser) parseBlocks(lines []string, softBreak string) []*mdNode {
+	var nodes []*mdNode
+	for i := 0; i < len(lines); {
+		indent, text := lineIndent(lines[i])
+		if text == "" {
+			i++
+			continue
+		}
+
+		var node *mdNode
+		next := i + 1
+		switch {
+		case indent >= 4:
+			node, next = indentedCode(lines, i)
+		case fenceMarker(text) != "":
+			node, next = fencedCode(lines, i)
+		case strings.HasPrefix(text, "$$"):
+			node, next = mathBlock(lines, i)
+			if node == nil {
+				node, next = p.paragraph(lines, i, softBreak)
+			}
+		case atxHeadingLevel(text) > 0:
+			node = atxHeading(text)
+		case isThematicBreak(text):
+			node = &mdNode{kind: mdDivider}
+		case text[0] == '>':
+			node, next = p.blockquote(lines, i)
+		case hasHTMLTagPrefix(text, "details"):
+			node, next = p.details(lines, i, softBreak)
+		case strings.HasPrefix(text, "<!--"):
+			node, next = htmlComment(lines, i)
+		case isListItem(text):
+			node, next = p.listItem(lines, i, softBreak)
+		case footnoteDefPattern.MatchString(text):
+			next = p.footnoteDefinition(lines, i)
+		case p.linkReferenceDefinition(text):
+		case i+1 < len(lines) && isTableStart(text, lines[i+1]):
+			node, next = p.parseTable(lines, i)
+		default:
+			node, next = p.paragraph(lines, i, softBreak)
+			if node.kind == mdParagraph {
+				next = p.paragraphChildren(node, lines, next, softBreak)
+			}
+		}
+
+		if node != nil {
+			if embed := p.embedNode(node); embed != nil {
+				nodes = append(nodes, embed)
+			} else if images := imageNodes(node); images != nil {
+				nodes = append(nodes, images...)
+			} else {
+				nodes = append(nodes, node)
+			}
+		}
+		i = next
+	}
+	return nodes
+}
+
+// lineIndent returns the indentation of line in columns (tabs stop every
+// four) and the text after it.
+func lineIndent(line string) (int, string) {
+	col := 0
+	for i, r := range line {
+		switch r {
+		case ' ':
+			col++
+		case '\t':
+			col += 4 - col%4
+		default:
+			return col, line[i:]
+		}
+	}
+	return col, ""
+}
+
+// stripIndent removes up to n columns of indentation from line.
+func stripIndent(line string, n int) string {
+	col := 0
+	for i, r := range line {
+		if col >= n {
+			return line[i:]
+		}
+		switch r {
+		case ' ':
+			col++
+		case '\t':
+			width := 4 - col%4
+			if col+width > n {
+				return strings.Repeat(" ", col+width-n) + line[i+1:]
+			}
+			col += width
+		default:
+			return line[i:]
+		}
+	}
+	return ""
+}
+
+// interrupts reports whether line starts a block that ends a paragraph.
+func (p *markdownParser) interrupts(line string) bool {
+	indent, text := lineIndent(line)
+	if indent >= 4 || text == "" {
+		return false
+	}
+	if marker, _ := listMarker(text); marker > 0 {
+		// An empty item, or a numbered one not starting at 1, cannot
+		// interrupt a paragraph.
+		if strings.TrimSpace(text[marker:]) == "" {
+			return false
+		}
+		if text[0] >= '0' && text[0] <= '9' && !strings.HasPrefix(text, "1.") && !strings.HasPrefix(text, "1)") {
+			return false
+		}
+		return true
+	}
+	return fenceMarker(text) != "" ||
+		atxHeadingLevel(text) > 0 ||
+		isThematicBreak(text) ||
+		text[0] == '>' ||
+		strings.TrimSpace(text) == "$$" ||
+		hasHTMLTagPrefix(text, "details") ||
+		strings.HasPrefix(text, "<!--")
+}
+
+func (p *markdownParser) paragraph(lines []string, start int, softBreak string) (*mdNode, int) {
+	var parts []string
+	i := start
+	for ; i < len(lines); i++ {
+		indent, text := lineIndent(lines[i])
+		if text == "" {
+			break
+		}
+		if i > start {
+			if indent < 4 {
+				if level := setextLevel(text); level > 0 {
+					return &mdNode{kind: mdHeading, level: level, text: joinParagraph(parts, " ")}, i + 1
+				}
+			}
+			if p.interrupts(lines[i]) {
+				break
+			}
+		}
+		parts = append(parts, text)
+	}
+	return &mdNode{kind: mdParagraph, text: joinParagraph(parts, softBreak)}, i
+}
+
+// joinParagraph joins paragraph lines with softBreak, keeping hard breaks
+// (two trailing spaces or a backslash) as newlines.
+func joinParagraph(parts []string, softBreak string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		last := i == len(parts)-1
+		trimmed := strings.TrimRight(part, " \t")
+		switch {
+		case last:
+			b.WriteString(trimmed)
+		case strings.HasSuffix(trimmed, `\`) && !strings.HasSuffix(trimmed, `\\`):
+			b.WriteString(strings.TrimSuffix(trimmed, `\`))
+			b.WriteString("\n")
+		case strings.HasSuffix(part, "  "):
+			b.WriteString(trimmed)
+			b.WriteString("\n")
+		default:
+			b.WriteString(trimmed)
+			b.WriteString(softBreak)
+		}
+	}
+	return b.String()
+}
+
+// paragraphChildren collects the blocks indented under a paragraph after a
+// blank line, which is how the exporter writes the children of paragraphs.
+// Deeper indentation is an indented code block, as in CommonMark.
+func (p *markdownParser) paragraphChildren(node *mdNode, lines []string, i int, softBreak string) int {
+	j := i
+	for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+		j++
+	}
+	if j == i || j >= len(lines) {
+		return i
+	}
+	childIndent, _ := lineIndent(lines[j])
+	if childIndent < 2 || childIndent >= 4 {
+		return i
+	}
+
+	var body []string
+	for ; j < len(lines); j++ {
+		indent, text := lineIndent(lines[j])
+		if text != "" && indent < childIndent {
+			break
+		}
+		body = append(body, stripIndent(lines[j], childIndent))
+	}
+	node.children = p.parseBlocks(body, softBreak)
+	return j
+}
+
+func setextLevel(text string) int {
+	trimmed := strings.TrimRight(text, " \t")
+	switch {
+	case trimmed == "":
+		return 0
+	case strings.Trim(trimmed, "=") == "":
+		return 1
+	case strings.Trim(trimmed, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+func atxHeadingLevel(text string) int {
+	level := 0
+	for level < len(text) && text[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0
+	}
+	if level < len(text) && text[level] != ' ' && text[level] != '\t' {
+		return 0
+	}
+	return level
+}
+
+func atxHeading(text string) *mdNode {
+	level := atxHeadingLevel(text)
+	content := strings.TrimSpace(text[level:])
+	// Drop an optional closing sequence of #s.
+	if trimmed := strings.TrimRight(content, "#"); trimmed != content {
+		if trimmed == "" {
+			content = ""
+		} else if strings.HasSuffix(trimmed, " ") || strings.HasSuffix(trimmed, "\t") {
+			content = strings.TrimSpace(trimmed)
+		}
+	}
+	return &mdNode{kind: mdHeading, level: level, text: content}
+}
+
+func isThematicBreak(text string) bool {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || !strings.ContainsRune("-*_", rune(trimmed[0])) {
+		return false
+	}
+	count := 0
+	for _, r := range trimmed {
+		switch {
+		case r == rune(trimmed[0]):
+			count++
+		case r != ' ' && r != '\t':
+			return false
+		}
+	}
+	return count >= 3
+}
+
+// fenceMarker returns the opening code fence at the start of text, if any.
+func fenceMarker(text string) string {
+	if len(text) < 3 || (text[0] != '`' && text[0] != '~') {
+		return ""
+	}
+	n := runLength(text, 0, text[0])
+	if n < 3 {
+		return ""
+	}
+	if text[0] == '`' && strings.Contains(text[n:], "`") {
+		return ""
+	}
+	return text[:n]
+}
+
+func fencedCode(lines []string, start int) (*mdNode, int) {
+	openIndent, text := lineIndent(lines[start])
+	fence := fenceMarker(text)
+	info := strings.Fields(strings.TrimSpace(text[len(fence):]))
+
+	var body []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		indent, line := lineIndent(lines[i])
+		if indent < 4 && strings.HasPrefix(line, fence) && strings.Trim(strings.TrimRight(line, " \t"), fence[:1]) == "" {
+			i++
+			break
+		}
+		body = append(body, stripIndent(lines[i], openIndent))
+	}
+
+	node := &mdNode{kind: mdCode, text: strings.Join(body, "\n")}
+	if len(info) > 0 {
+		node.language = info[0]
+	}
+	// The exporter writes a code block's caption in italics on the line
+	// right after the closing fence.
+	if i < len(lines) {
+		if _, line := lineIndent(lines[i]); isItalicLine(line) {
+			node.caption = strings.TrimSpace(line)
+			node.caption = node.caption[1 : len(node.caption)-1]
+			i++
+		}
+	}
+	return node, i
+}
+
+func isItalicLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if len(line) < 3 {
+		return false
+	}
+	for _, marker := range []byte{'*', '_'} {
+		if line[0] == marker && line[len(line)-1] == marker && line[1] != marker && line[len(line)-2] != marker && line[1] != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+func indentedCode(lines []string, start int) (*mdNode, int) {
+	var body []string
+	i := start
+	for ; i < len(lines); i++ {
+		indent, text := lineIndent(lines[i])
+		if text != "" && indent < 4 {
+			break
+		}
+		body = append(body, stripIndent(lines[i], 4))
+	}
+	for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+		body = body[:len(body)-1]
+	}
+	return &mdNode{kind: mdCode, text: strings.Join(body, "\n")}, i
+}
+
+// mathBlock parses a $$ display math block, on one line or several. It
+// returns nil when the block is never closed.
+func mathBlock(lines []string, start int) (*mdNode, int) {
+	_, text := lineIndent(lines[start])
+	text = strings.TrimSpace(text)
+	if len(text) > 4 && strings.HasSuffix(text, "$$") {
+		return &mdNode{kind: mdEquation, text: strings.TrimSpace(text[2 : len(text)-2])}, start + 1
+	}
+
+	body := []string{strings.TrimPrefix(text, "$$")}
+	for i := start + 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasSuffix(line, "$$") {
+			body = append(body, strings.TrimSuffix(line, "$$"))
+			expression := strings.TrimSpace(strings.Join(body, "\n"))
+			return &mdNode{kind: mdEquation, text: expression}, i + 1
+		}
+		body = append(body, line)
+	}
+	return nil, start
+}
+
+func (p *markdownParser) blockquote(lines []string, start int) (*mdNode, int) {
+	var body []string
+	i := start
+	for ; i < len(lines); i++ {
+		indent, text := lineIndent(lines[i])
+		if indent < 4 && strings.HasPrefix(text, ">") {
+			rest := text[1:]
+			if strings.HasPrefix(rest, " ") || strings.HasPrefix(rest, "\t") {
+				rest = stripIndent(rest, 1)
+			}
+			body = append(body, rest)
+			continue
+		}
+		// A lazy continuation line carries on the quoted paragraph.
+		if text != "" && len(body) > 0 && strings.TrimSpace(body[len(body)-1]) != "" && !p.interrupts(lines[i]) {
+			body = append(body, text)
+			continue
+		}
+		break
+	}
+	return &mdNode{kind: mdQuote, children: p.parseBlocks(body, "\n")}, i
+}
+
+// hasHTMLTagPrefix reports whether text starts with the HTML tag name.
+func hasHTMLTagPrefix(text, name string) bool {
+	if len(text) < len(name)+2 || text[0] != '<' || !strings.EqualFold(text[1:len(name)+1], name) {
+		return false
+	}
+	next := text[len(name)+1]
+	return next == '>' || next == ' ' || next == '/'
+}
+
+// details parses a <details> element, with an optional <summary>, into a
+// toggle whose children are the element's content.
+func (p *markdownParser) details(lines []string, start int, softBreak string) (*mdNode, int) {
+	const closing = "</details>"
+	var body []string
+	end := len(lines)
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		_, text := lineIndent(line)
+		if i == start {
+			line = text[strings.IndexByte(text, '>')+1:]
+		} else if hasHTMLTagPrefix(text, "details") {
+			depth++
+		}
+		trimmed := strings.TrimRight(line, " \t")
+		if len(trimmed) >= len(closing) && strings.EqualFold(trimmed[len(trimmed)-len(closing):], closing) {
+			if depth == 0 {
+				body = append(body, trimmed[:len(trimmed)-len(closing)])
+				end = i + 1
+				break
+			}
+			depth--
+		}
+		body = append(body, line)
+	}
+
+	// The summary, if any, opens the content.
+	for len(body) > 0 && strings.TrimSpace(body[0]) == "" {
+		body = body[1:]
+	}
+	summary := ""
+	if len(body) > 0 && hasHTMLTagPrefix(strings.TrimSpace(body[0]), "summary") {
+		joined := strings.Join(body, "\n")
+		open := strings.IndexByte(joined, '>') + 1
+		if closeAt := strings.Index(strings.ToLower(joined), "</summary>"); closeAt >= open {
+			summary = strings.Join(strings.Fields(joined[open:closeAt]), " ")
+			body = strings.Split(joined[closeAt+len("</summary>"):], "\n")
+		}
+	}
+
+	return &mdNode{kind: mdToggle, text: summary, children: p.parseBlocks(body, softBreak)}, end
+}
+
+// htmlComment skips an HTML comment. The exporter's <!-- breadcrumb -->
+// placeholder becomes a breadcrumb block again.
+func htmlComment(lines []string, start int) (*mdNode, int) {
+	var body []string
+	for i := start; i < len(lines); i++ {
+		body = append(body, lines[i])
+		if strings.Contains(lines[i], "-->") {
+			comment := strings.TrimSpace(strings.Join(body, "\n"))
+			comment = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(comment, "<!--"), "-->"))
+			if comment == "breadcrumb" {
+				return &mdNode{kind: mdBreadcrumb}, i + 1
+			}
+			return nil, i + 1
+		}
+	}
+	return nil, len(lines)
+}
+
+func isListItem(text string) bool {
+	marker, _ := listMarker(text)
+	return marker > 0
+}
+
+// listMarker returns the length of the list marker at the start of text
+// and the columns of space after it, or 0 when text is not a list item.
+func listMarker(text string) (int, int) {
+	n := 0
+	switch {
+	case text[0] == '-' || text[0] == '*' || text[0] == '+':
+		n = 1
+	case text[0] >= '0' && text[0] <= '9':
+		for n < len(text) && n < 9 && text[n] >= '0' && text[n] <= '9' {
+			n++
+		}
+		if n == len(text) || (text[n] != '.' && text[n] != ')') {
+			return 0, 0
+		}
+		n++
+	default:
+		return 0, 0
+	}
+	if n == len(text) {
+		return n, 1
+	}
+	if text[n] != ' ' && text[n] != '\t' {
+		return 0, 0
+	}
+	spaces, rest := lineIndent(text[n:])
+	// Five or more spaces start indented code inside the item.
+	if spaces > 4 || rest == "" {
+		spaces = 1
+	}
+	return n, spaces
+}
+
+// listItem parses a list item. Its first paragraph is the item's text and
+// everything else its children. Nested content may be indented to the
+// item's content column, as in CommonMark, or by two spaces, as the
+// exporter writes it under numbered items.
+func (p *markdownParser) listItem(lines []string, start int, softBreak string) (*mdNode, int) {
+	indent, text := lineIndent(lines[start])
+	marker, spaces := listMarker(text)
+	node := &mdNode{kind: mdListItem, ordered: text[0] >= '0' && text[0] <= '9'}
+	body := []string{stripIndent(text[marker:], spaces)}
+
+	contentCol := indent + marker + spaces
+	minIndent := min(contentCol, indent+2)
+	strip := -1
+	i := start + 1
+	for ; i < len(lines); i++ {
+		lineInd, line := lineIndent(lines[i])
+		if line == "" {
+			body = append(body, "")
+			continue
+		}
+		if lineInd >= minIndent {
+			if strip < 0 {
+				strip = min(lineInd, contentCol)
+			}
+			body = append(body, stripIndent(lines[i], min(lineInd, strip)))
+			continue
+		}
+		if body[len(body)-1] != "" && !p.interrupts(lines[i]) && !isListItem(line) {
+			body = append(body, line)
+			continue
+		}
+		break
+	}
+	for len(body) > 1 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+	}
+
+	node.text, node.children = leadingParagraph(p.parseBlocks(body, softBreak))
+
+	if box, rest, ok := taskMarker(node.text); ok {
+		node.task = true
+		node.checked = box != " "
+		node.text = rest
+	}
+	return node, i
+}
+
+// leadingParagraph splits the text of a leading paragraph from the blocks
+// after it, for blocks whose own text is their first paragraph.
+func leadingParagraph(nodes []*mdNode) (string, []*mdNode) {
+	if len(nodes) == 0 || nodes[0].kind != mdParagraph {
+		return "", nodes
+	}
+	rest := append([]*mdNode{}, nodes[0].children...)
+	return nodes[0].text, append(rest, nodes[1:]...)
+}
+
+// taskMarker splits a GitHub task list checkbox from the item text.
+func taskMarker(text string) (string, string, bool) {
+	if len(text) < 3 || text[0] != '[' || text[2] != ']' || !strings.ContainsRune(" xX", rune(text[1])) {
+		return "", "", false
+	}
+	if len(text) > 3 && text[3] != ' ' && text[3] != '\n' {
+		return "", "", false
+	}
+	return text[1:2], strings.TrimLeft(text[3:], " \n"), true
+}
+
+var (
+	footnoteDefPattern = regexp.MustCompile(`^\[\^([^\]\s]+)\]:[ \t]?(.*)$`)
+	linkRefDefPattern  = regexp.MustCompile(`^\[([^\]^][^\]]*)\]:\s*<?([^\s>]+)>?(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*$`)
+)
+
+// footnoteDefinition records a footnote's content, indented four spaces
+// under its first line. Footnotes are appended to the end of the page.
+func (p *markdownParser) footnoteDefinition(lines []string, start int) int {
+	_, text := lineIndent(lines[start])
+	match := footnoteDefPattern.FindStringSubmatch(text)
+	body := []string{match[2]}
+	i := start + 1
+	for ; i < len(lines); i++ {
+		indent, line := lineIndent(lines[i])
+		switch {
+		case line == "":
+			body = append(body, "")
+		case indent >= 4:
+			body = append(body, stripIndent(lines[i], 4))
+		case body[len(body)-1] != "" && !p.interrupts(lines[i]) && !footnoteDefPattern.MatchString(line):
+			body = append(body, line)
+		default:
+			p.addFootnote(match[1], body)
+			return i
+		}
+	}
+	p.addFootnote(match[1], body)
+	return i
+}
+
+func (p *markdownParser) addFootnote(label string, body []string) {
+	label = strings.ToLower(label)
+	if _, ok := p.footnotes[label]; !ok {
+		p.footnotes[label] = &mdNode{kind: mdListItem, ordered: true, children: p.parseBlocks(body, " ")}
+	}
+}
+
+// footnoteNumber numbers footnotes in the order they are first referenced.
+func (p *markdownParser) footnoteNumber(label string) (int, bool) {
+	label = strings.ToLower(label)
+	if _, ok := p.footnotes[label]; !ok {
+		return 0, false
+	}
+	for i, seen := range p.footnoteOrder {
+		if seen == label {
+			return i + 1, true
+		}
+	}
+	p.footnoteOrder = append(p.footnoteOrder, label)
+	return len(p.footnoteOrder), true
+}
+
+// linkReferenceDefinition records a [label]: url definition.
+func (p *markdownParser) linkReferenceDefinition(text string) bool {
+	match := linkRefDefPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return false
+	}
+	label := normalizeLinkLabel(match[1])
+	if _, ok := p.linkRefs[label]; !ok {
+		p.linkRefs[label] = match[2]
+	}
+	return true
+}
+
+func normalizeLinkLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// isTableStart reports whether text and the next line open a GFM table: a
+// header row followed by a delimiter row with as many cells.
+func isTableStart(text, next string) bool {
+	if !strings.Contains(text, "|") {
+		return false
+	}
+	indent, delimiter := lineIndent(next)
+	if indent >= 4 || !strings.Contains(delimiter, "-") {
+		return false
+	}
+	cells := parseTableCells(delimiter)
+	for _, cell := range cells {
+		cell = strings.TrimSpace(cell)
+		cell = strings.TrimSuffix(strings.TrimPrefix(cell, ":"), ":")
+		if cell == "" || strings.Trim(cell, "-") != "" {
+			return false
+		}
+	}
+	return len(cells) == len(parseTableCells(text))
+}
+
+func (p *markdownParser) parseTable(lines []string, start int) (*mdNode, int) {
+	header := parseTableCells(strings.TrimSpace(lines[start]))
+	rows := [][]string{header}
+	i := start + 2
+	for ; i < len(lines); i++ {
+		_, text := lineIndent(lines[i])
+		if text == "" || (!strings.Contains(text, "|") && p.interrupts(lines[i])) {
+			break
+		}
+		cells := parseTableCells(strings.TrimSpace(text))
+		row := make([]string, len(header))
+		copy(row, cells)
+		rows = append(rows, row)
+	}
+	return &mdNode{kind: mdTable, rows: rows}, i
+}
+
+// parseTableCells splits a table row into trimmed cells. Outer pipes are
+// optional and \| is a literal pipe.
+func parseTableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+var imagePattern = regexp.MustCompile(`!\[((?:[^\]\\]|\\.)*)\]\(\s*(<[^>]*>|[^\s)]+)(?:\s+(?:"[^"]*"|'[^']*'))?\s*\)`)
+
+// imageNodes splits a paragraph made only of images into image nodes. It
+// returns nil for anything else.
+func imageNodes(node *mdNode) []*mdNode {
+	if node.kind != mdParagraph || len(node.children) > 0 {
+		return nil
+	}
+	matches := imagePattern.FindAllStringSubmatchIndex(node.text, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	var images []*mdNode
+	last := 0
+	for _, m := range matches {
+		if strings.TrimSpace(node.text[last:m[0]]) != "" {
+			return nil
+		}
+		src := strings.TrimSuffix(strings.TrimPrefix(node.text[m[4]:m[5]], "<"), ">")
+		images = append(images, &mdNode{kind: mdImage, text: node.text[m[2]:m[3]], src: src})
+		last = m[1]
+	}
+	if strings.TrimSpace(node.text[last:]) != "" {
+		return nil
+	}
+	return images
+}
+
+func (p *markdownParser) blocks(nodes []*mdNode) []map[string]interface{} {
+	var blocks []map[string]interface{}
+	for _, node := range nodes {
+		if block := p.block(node); block != nil {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func (p *markdownParser) block(node *mdNode) map[string]interface{} {
+	switch node.kind {
+	case mdParagraph:
+		return p.textBlock("paragraph", node.text, node.children, nil)
+	case mdHeading:
+		level := min(node.level, 3)
+		return p.textBlock(fmt.Sprintf("heading_%d", level), strings.ReplaceAll(node.text, "\n", " "), nil, nil)
+	case mdListItem:
+		switch {
+		case node.task:
+			return p.textBlock("to_do", node.text, node.children, map[string]interface{}{"checked": node.checked})
+		case node.ordered:
+			return p.textBlock("numbered_list_item", node.text, node.children, nil)
+		}
+		return p.textBlock("bulleted_list_item", node.text, node.children, nil)
+	case mdQuote:
+		return p.quote(node)
+	case mdToggle:
+		return p.textBlock("toggle", node.text, node.children, nil)
+	case mdCode:
+		return p.code(node)
+	case mdEquation:
+		return map[string]interface{}{
+			"type":     "equation",
+			"equation": map[string]interface{}{"expression": node.text},
+		}
+	case mdDivider:
+		return notion.NewDivider()
+	case mdBreadcrumb:
+		return notion.NewBreadcrumb()
+	case mdTable:
+		return p.tableBlock(node)
+	case mdImage:
+		return p.image(node)
+	}
+	return nil
+}
+
+// textBlock builds a block of the given type from inline Markdown, with its
+// children and any extra fields.
+func (p *markdownParser) textBlock(blockType, text string, children []*mdNode, extra map[string]interface{}) map[string]interface{} {
+	content := map[string]interface{}{"rich_text": p.richText(text)}
+	for k, v := range extra {
+		content[k] = v
+	}
+	if childBlocks := p.blocks(children); len(childBlocks) > 0 {
+		content["children"] = childBlocks
+	}
+	return map[string]interface{}{"type": blockType, blockType: content}
+}
+
+// admonitions maps GitHub alert types to callout icons and colours.
+var admonitions = map[string]struct{ emoji, color string }{
+	"NOTE":      {"ℹ️", "blue_background"},
+	"TIP":       {"💡", "green_background"},
+	"IMPORTANT": {"❗", "purple_background"},
+	"WARNING":   {"⚠️", "yellow_background"},
+	"CAUTION":   {"🛑", "red_background"},
+}
+
+var admonitionPattern = regexp.MustCompile(`^\[!([A-Za-z]+)\][ \t]*(?:\n|$)`)
+
+// quote converts a blockquote. A GitHub alert (> [!NOTE]) or a quote that
+// starts with an emoji, as the exporter writes callouts, becomes a callout.
+func (p *markdownParser) quote(node *mdNode) map[string]interface{} {
+	text, children := leadingParagraph(node.children)
+	if m := admonitionPattern.FindStringSubmatch(text); m != nil {
+		if style, ok := admonitions[strings.ToUpper(m[1])]; ok {
+			text = text[len(m[0]):]
+			if text == "" {
+				text, children = leadingParagraph(children)
+			}
+			return p.callout(text, style.emoji, style.color, children)
+		}
+	}
+	if emoji, rest, ok := leadingEmoji(text); ok {
+		return p.callout(rest, emoji, "", children)
+	}
+	return p.textBlock("quote", text, children, nil)
+}
+
+func (p *markdownParser) callout(text, emoji, color string, children []*mdNode) map[string]interface{} {
+	extra := map[string]interface{}{"icon": map[string]interface{}{"type": "emoji", "emoji": emoji}}
+	if color != "" {
+		extra["color"] = color
+	}
+	return p.textBlock("callout", text, children, extra)
+}
+
+// leadingEmoji splits an emoji followed by a space from the start of text.
+func leadingEmoji(text string) (string, string, bool) {
+	r, size := utf8.DecodeRuneInString(text)
+	if !isEmojiRune(r) {
+		return "", "", false
+	}
+	joined := false
+	for i := size; i < len(text); i += size {
+		r, size = utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == ' ':
+			return text[:i], text[i+1:], true
+		case r == 0x200d:
+			// A zero-width joiner combines the next emoji with this one.
+			joined = true
+			continue
+		case r == 0xfe0f, r == 0x20e3, r >= 0x1f3fb && r <= 0x1f3ff, r >= 0x1f1e6 && r <= 0x1f1ff:
+		case joined && isEmojiRune(r):
+		default:
+			return "", "", false
+		}
+		joined = false
+	}
+	return "", "", false
+}
+
+func isEmojiRune(r rune) bool {
+	return (r >= 0x1f000 && r <= 0x1faff) ||
+		(r >= 0x2600 && r <= 0x27bf) ||
+		(r >= 0x2300 && r <= 0x23ff) ||
+		(r >= 0x2b00 && r <= 0x2bff) ||
+		r == 0x2139 || r == 0x203c || r == 0x2049
+}
+
+func (p *markdownParser) code(node *mdNode) map[string]interface{} {
+	content := map[string]interface{}{
+		"rich_text": plainRichText(node.text),
+		"language":  notionCodeLanguage(node.language),
+	}
+	if node.caption != "" {
+		content["caption"] = p.richText(node.caption)
+	}
+	return map[string]interface{}{"type": "code", "code": content}
+}
+
+func (p *markdownParser) tableBlock(node *mdNode) map[string]interface{} {
+	width := len(node.rows[0])
+	rows := make([]map[string]interface{}, 0, len(node.rows))
+	for _, row := range node.rows {
+		cells := make([][]map[string]interface{}, width)
+		for j := range cells {
+			cells[j] = p.richText(row[j])
+		}
+		rows = append(rows, notion.NewTableRow(cells))
+	}
+	return notion.NewTable(width, true, rows)
+}
+
+var (
+	embedPattern    = regexp.MustCompile(`^!\[\[([^\[\]\n]+)\]\]$`)
+	fileLinkPattern = regexp.MustCompile(`^\[((?:[^\]\\]|\\.)*)\]\(\s*(<[^>]*>|[^\s)]+)\s*\)$`)
+)
+
+// embedNode converts a paragraph made of one ![[embed]] of a local file, or
+// of one link to a local file, to a file node. It returns nil for anything
+// else.
+func (p *markdownParser) embedNode(node *mdNode) *mdNode {
+	if p.resolveEmbed == nil || node.kind != mdParagraph || len(node.children) > 0 {
+		return nil
+	}
+	text := strings.TrimSpace(node.text)
+	if m := embedPattern.FindStringSubmatch(text); m != nil {
+		target, _, _ := strings.Cut(m[1], "|")
+		if path := p.resolveEmbed(strings.TrimSpace(target), true); path != "" {
+			return &mdNode{kind: mdImage, src: path}
+		}
+		return nil
+	}
+	if m := fileLinkPattern.FindStringSubmatch(text); m != nil {
+		dest := strings.TrimSuffix(strings.TrimPrefix(m[2], "<"), ">")
+		if schemePattern.MatchString(dest) {
+			return nil
+		}
+		path := p.resolveEmbed(dest, false)
+		if path == "" {
+			return nil
+		}
+		caption := m[1]
+		if caption == filepath.Base(path) {
+			caption = ""
+		}
+		return &mdNode{kind: mdImage, src: path, text: caption}
+	}
+	return nil
+}
+
+// image converts an image. Web images are linked; local files are uploaded
+// later by markdownDocument.uploadImages, as PDF, video, audio or file
+// blocks when they are not images.
+func (p *markdownParser) image(node *mdNode) map[string]interface{} {
+	image := map[string]interface{}{}
+	if node.text != "" {
+		image["caption"] = p.richText(node.text)
+	}
+
+	switch {
+	case isWebURL(node.src):
+		image["type"] = "external"
+		image["external"] = map[string]interface{}{"url": node.src}
+	case strings.HasPrefix(node.src, "data:"):
+		// Inline data cannot be uploaded by reference; keep the alt text.
+		return p.textBlock("paragraph", node.text, nil, nil)
+	default:
+		path := p.localPath(node.src)
+		image["type"] = "file_upload"
+		image["file_upload"] = map[string]interface{}{"id": ""}
+		p.images = append(p.images, &markdownImage{Path: path, image: image})
+		blockType := localFileBlockType(path)
+		return map[string]interface{}{"type": blockType, blockType: image}
+	}
+	return map[string]interface{}{"type": "image", "image": image}
+}
+
+// localFileBlockType returns the block type that shows a local file, from
+// its extension.
+func localFileBlockType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pdf":
+		return "pdf"
+	case ".mp4", ".mov", ".webm", ".m4v", ".avi", ".mkv":
+		return "video"
+	case ".mp3", ".wav", ".m4a", ".ogg", ".flac", ".aac":
+		return "audio"
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg", ".bmp", ".tif", ".tiff", ".heic", ".ico", "":
+		return "image"
+	}
+	return "file"
+}
+
+// localPath resolves an image source to a file path.
+func (p *markdownParser) localPath(src string) string {
+	src = strings.TrimPrefix(src, "file://")
+	if unescaped, err := url.PathUnescape(src); err == nil {
+		src = unescaped
+	}
+	if filepath.IsAbs(src) || p.baseDir == "" {
+		return filepath.Clean(src)
+	}
+	return filepath.Join(p.baseDir, src)
+}
+
+func isWebURL(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+// footnoteBlocks lists the referenced footnotes, in reference order, after a
+// divider at the end of the page.
+func (p *markdownParser) footnoteBlocks() []map[string]interface{} {
+	if len(p.footnoteOrder) == 0 {
+		return nil
+	}
+	blocks := []map[string]interface{}{notion.NewDivider()}
+	// Footnotes may reference further footnotes, growing the list.
+	for i := 0; i < len(p.footnoteOrder); i++ {
+		text, children := leadingParagraph(p.footnotes[p.footnoteOrder[i]].children)
+		blocks = append(blocks, p.textBlock("numbered_list_item", text, children, nil))
+	}
+	return blocks
+}
+
+// notionCodeLanguages are the code block languages the API accepts.
+var notionCodeLanguages = keywordSet("abap agda arduino assembly bash basic bnf c c# c++ clojure coffeescript coq css dart dhall diff docker ebnf elixir elm erlang f# flow fortran gherkin glsl go graphql groovy haskell hcl html idris java javascript json julia kotlin latex less lisp livescript lua makefile markdown markup matlab mathematica mermaid nix objective-c ocaml pascal perl php powershell prolog protobuf purescript python r racket reason ruby rust sass scala scheme scss shell smalltalk solidity sql swift toml typescript verilog vhdl webassembly xml yaml")
+
+// codeLanguageAliases maps common fence info strings to Notion languages.
+var codeLanguageAliases = map[string]string{
+	"js": "javascript", "jsx": "javascript", "mjs": "javascript", "node": "javascript",
+	"ts": "typescript", "tsx": "typescript",
+	"py": "python", "python3": "python",
+	"sh": "shell", "zsh": "shell", "console": "shell", "shell-session": "shell",
+	"ps1": "powershell", "pwsh": "powershell",
+	"rb": "ruby", "rs": "rust", "golang": "go", "kt": "kotlin",
+	"cpp": "c++", "cc": "c++", "hpp": "c++", "h": "c",
+	"cs": "c#", "csharp": "c#", "fsharp": "f#",
+	"objc": "objective-c", "yml": "yaml", "md": "markdown",
+	"dockerfile": "docker", "make": "makefile", "tex": "latex",
+	"proto": "protobuf", "wasm": "webassembly", "ex": "elixir", "exs": "elixir",
+	"hs": "haskell", "clj": "clojure", "jl": "julia", "terraform": "hcl", "tf": "hcl",
+	"vb": "visual basic", "vbnet": "vb.net", "ascii": "ascii art", "llvm": "llvm ir",
+}
+
+// notionCodeLanguage maps a fence info string to a Notion code language,
+// falling back to plain text for languages Notion does not know.
+func notionCodeLanguage(info string) string {
+	lang := strings.ToLower(info)
+	if alias, ok := codeLanguageAliases[lang]; ok {
+		return alias
+	}
+	if notionCodeLanguages[lang] {
+		return lang
+	}
+	return "plain text"
+}
+
+// uploadImages uploads the document's local images, once per file, and
+// points their blocks at the uploads.
+func (d *markdownDocument) uploadImages(ctx context.Context, client fileUploader) error {
+	uploaded := map[string]string{}
+	for _, img := range d.Images {
+		id, ok := uploaded[img.Path]
+		if !ok {
+			upload, err := uploadLocalFile(ctx, client, img.Path)
+			if err != nil {
+				return fmt.Errorf("failed to upload image %s: %w", img.Path, err)
+			}
+			id = upload.ID
+			uploaded[img.Path] = id
+		}
+		img.image["file_upload"] = map[string]interface{}{"id": id}
+	}
+	return nil
+}
+
+// maxSinglePartUpload is the largest file sent in a single part; larger
+// files use a multi-part upload.
+const maxSinglePartUpload = 20 * 1024 * 1024
+
+// uploadLocalFile uploads the file at path to Notion.
+func uploadLocalFile(ctx context.Context, client fileUploader, path string) (*notion.FileUpload, error) {
+	if err := validateFileExtension(path); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	filename := filepath.Base(path)
+	if info.Size() > maxSinglePartUpload {
+		return client.UploadLargeFile(ctx, filename, file)
+	}
+
+	buffer := make([]byte, 512)
+	n, _ := file.Read(buffer)
+	contentType := http.DetectContentType(buffer[:n])
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("failed to reset file position: %w", err)
+	}
+
+	upload, err := client.CreateFileUpload(ctx, &notion.CreateFileUploadRequest{
+		FileName:    filename,
+		ContentType: contentType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file upload: %w", err)
+	}
+	return client.SendFileUpload(ctx, upload.UploadURL, file, filename, contentType)
+}
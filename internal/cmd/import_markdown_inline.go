@@ -0,0 +1,735 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxRichTextChars is the API limit on the content of one text object.
+const maxRichTextChars = 2000
+
+// inlineStyle is the formatting of a run of inline text.
+type inlineStyle struct {
+	bold, italic, strikethrough, underline, code bool
+
+	color string
+	link  string
+}
+
+func (s inlineStyle) annotations() map[string]interface{} {
+	if !s.bold && !s.italic && !s.strikethrough && !s.underline && !s.code && s.color == "" {
+		return nil
+	}
+	color := s.color
+	if color == "" {
+		color = "default"
+	}
+	return map[string]interface{}{
+		"bold":          s.bold,
+		"italic":        s.italic,
+		"strikethrough": s.strikethrough,
+		"underline":     s.underline,
+		"code":          s.code,
+		"color":         color,
+	}
+}
+
+// inlineRun is text, or an equation, with one style.
+type inlineRun struct {
+	text     string
+	style    inlineStyle
+	equation bool
+}
+
+// inlineConverter converts inline Markdown to rich text: emphasis, code,
+// links and autolinks, $math$, footnote references, and the inline HTML the
+// exporter writes for underline and colours.
+type inlineConverter struct {
+	p    *markdownParser
+	runs []inlineRun
+}
+
+// richText converts inline Markdown to a Notion rich_text array.
+func (p *markdownParser) richText(text string) []map[string]interface{} {
+	c := &inlineConverter{p: p}
+	c.parse(text, inlineStyle{})
+	return richTextItems(c.runs)
+}
+
+// plainRichText converts text to unformatted rich text, split to fit the
+// API's length limit.
+func plainRichText(text string) []map[string]interface{} {
+	return richTextItems([]inlineRun{{text: text}})
+}
+
+func richTextItems(runs []inlineRun) []map[string]interface{} {
+	items := []map[string]interface{}{}
+	for _, run := range runs {
+		if run.equation {
+			item := map[string]interface{}{
+				"type":     "equation",
+				"equation": map[string]interface{}{"expression": run.text},
+			}
+			if ann := run.style.annotations(); ann != nil {
+				item["annotations"] = ann
+			}
+			items = append(items, item)
+			continue
+		}
+		for _, chunk := range splitRichTextContent(run.text) {
+			text := map[string]interface{}{"content": chunk}
+			if run.style.link != "" {
+				text["link"] = map[string]interface{}{"url": run.style.link}
+			}
+			item := map[string]interface{}{"type": "text", "text": text}
+			if ann := run.style.annotations(); ann != nil {
+				item["annotations"] = ann
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func splitRichTextContent(text string) []string {
+	var chunks []string
+	for utf8.RuneCountInString(text) > maxRichTextChars {
+		cut := 0
+		for i := 0; i < maxRichTextChars; i++ {
+			_, size := utf8.DecodeRuneInString(text[cut:])
+			cut += size
+		}
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+func (c *inlineConverter) add(text string, st inlineStyle, equation bool) {
+	if text == "" {
+		return
+	}
+	if n := len(c.runs); n > 0 && !equation && !c.runs[n-1].equation && c.runs[n-1].style == st {
+		c.runs[n-1].text += text
+		return
+	}
+	c.runs = append(c.runs, inlineRun{text: text, style: st, equation: equation})
+}
+
+func (c *inlineConverter) parse(s string, st inlineStyle) {
+	var buf strings.Builder
+	flush := func() {
+		c.add(buf.String(), st, false)
+		buf.Reset()
+	}
+	for i := 0; i < len(s); {
+		if n := c.inline(s, i, st, flush); n > 0 {
+			i += n
+			continue
+		}
+		ch := s[i]
+		switch {
+		case ch == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			buf.WriteByte(s[i+1])
+			i += 2
+		case strings.IndexByte("`*_~", ch) >= 0:
+			// An unmatched delimiter run stays literal as a whole.
+			run := runLength(s, i, ch)
+			buf.WriteString(s[i : i+run])
+			i += run
+		default:
+			buf.WriteByte(ch)
+			i++
+		}
+	}
+	flush()
+}
+
+var footnoteRefPattern = regexp.MustCompile(`^\[\^([^\]\s]+)\]`)
+
+// inline parses the inline construct starting at s[i], returning the bytes
+// it consumed, or 0 when there is none.
+func (c *inlineConverter) inline(s string, i int, st inlineStyle, flush func()) int {
+	switch s[i] {
+	case '`':
+		code, n := codeSpan(s[i:])
+		if n == 0 {
+			return 0
+		}
+		flush()
+		st.code = true
+		c.add(code, st, false)
+		return n
+	case '$':
+		expression, n := inlineMath(s, i)
+		if n == 0 {
+			return 0
+		}
+		flush()
+		c.add(expression, st, true)
+		return n
+	case '!':
+		if i+1 >= len(s) || s[i+1] != '[' {
+			return 0
+		}
+		if c.p.resolveLink != nil && strings.HasPrefix(s[i+1:], "[[") {
+			// An embed inside text links to its target.
+			if n := c.wikiLink(s[i+1:], st, flush); n > 0 {
+				return n + 1
+			}
+		}
+		alt, dest, n := c.link(s[i+1:])
+		if n == 0 {
+			return 0
+		}
+		// Images cannot sit inside text: keep their alt text, linked to
+		// the image when it is on the web.
+		flush()
+		if st.link == "" && isWebURL(dest) {
+			st.link = dest
+		}
+		if alt == "" {
+			alt = dest
+		}
+		c.parse(alt, st)
+		return n + 1
+	case '[':
+		if m := footnoteRefPattern.FindStringSubmatch(s[i:]); m != nil {
+			if number, ok := c.p.footnoteNumber(m[1]); ok {
+				flush()
+				c.add(fmt.Sprintf("[%d]", number), st, false)
+				return len(m[0])
+			}
+		}
+		if st.link != "" {
+			return 0
+		}
+		if c.p.resolveLink != nil && strings.HasPrefix(s[i:], "[[") {
+			if n := c.wikiLink(s[i:], st, flush); n > 0 {
+				return n
+			}
+		}
+		text, dest, n := c.link(s[i:])
+		if n == 0 {
+			return 0
+		}
+		flush()
+		st.link = c.p.linkURL(dest)
+		c.parse(text, st)
+		return n
+	case '<':
+		return c.html(s[i:], st, flush)
+	case '*', '_', '~':
+		inner, n, run := emphasis(s, i)
+		if n == 0 {
+			return 0
+		}
+		flush()
+		switch {
+		case s[i] == '~':
+			st.strikethrough = true
+		case run == 1:
+			st.italic = true
+		case run == 2:
+			st.bold = true
+		default:
+			st.bold, st.italic = true, true
+		}
+		c.parse(inner, st)
+		return n
+	case 'h', 'w':
+		if st.link != "" || (i > 0 && !strings.ContainsRune(" \t\n(*_~\"'", rune(s[i-1]))) {
+			return 0
+		}
+		link, n := bareURL(s[i:])
+		if n == 0 {
+			return 0
+		}
+		flush()
+		st.link = link
+		c.add(s[i:i+n], st, false)
+		return n
+	}
+	return 0
+}
+
+var schemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// linkURL returns the URL a link keeps in Notion. The API only takes
+// absolute URLs, so relative links keep their text without the link unless
+// resolveLink maps them.
+func (p *markdownParser) linkURL(dest string) string {
+	if schemePattern.MatchString(dest) {
+		return dest
+	}
+	if p.resolveLink != nil && dest != "" {
+		return p.resolveLink(dest, false)
+	}
+	return ""
+}
+
+var wikiLinkPattern = regexp.MustCompile(`^\[\[([^\[\]\n|]+)(?:\|([^\[\]\n]*))?\]\]`)
+
+// wikiLink parses a [[target]] or [[target|text]] link at the start of s,
+// linking its text to the URL resolveLink gives the target.
+func (c *inlineConverter) wikiLink(s string, st inlineStyle, flush func()) int {
+	m := wikiLinkPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	target := strings.TrimSpace(m[1])
+	text := strings.TrimSpace(m[2])
+	if text == "" {
+		text = target
+	}
+	flush()
+	st.link = c.p.resolveLink(target, true)
+	c.add(text, st, false)
+	return len(m[0])
+}
+
+func isASCIIPunct(b byte) bool {
+	return b < utf8.RuneSelf && (unicode.IsPunct(rune(b)) || unicode.IsSymbol(rune(b)))
+}
+
+func runLength(s string, i int, ch byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == ch {
+		n++
+	}
+	return n
+}
+
+// codeSpan matches a code span at the start of s, returning its content and
+// length.
+func codeSpan(s string) (string, int) {
+	n := runLength(s, 0, '`')
+	for j := n; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		m := runLength(s, j, '`')
+		if m == n {
+			code := strings.ReplaceAll(s[n:j], "\n", " ")
+			if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			return code, j + m
+		}
+		j += m
+	}
+	return "", 0
+}
+
+// inlineMath matches $...$ or $$...$$ at s[i]. As in Pandoc, the opening $
+// must be followed by a non-space and the closing one must not be preceded
+// by a space or followed by a digit. Unlike Pandoc, the first unescaped $
+// must close the span, so "$5 and $10" is left alone.
+func inlineMath(s string, i int) (string, int) {
+	if strings.HasPrefix(s[i:], "$$") {
+		if end := strings.Index(s[i+2:], "$$"); end > 0 {
+			return strings.TrimSpace(s[i+2 : i+2+end]), end + 4
+		}
+		return "", 0
+	}
+	if isWordRune(runeBefore(s, i)) || i+1 >= len(s) || s[i+1] == ' ' || s[i+1] == '\t' {
+		return "", 0
+	}
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\\':
+			j++
+		case s[j] == '$':
+			if s[j-1] == ' ' || s[j-1] == '\t' || (j+1 < len(s) && s[j+1] >= '0' && s[j+1] <= '9') {
+				return "", 0
+			}
+			return s[i+1 : j], j + 1 - i
+		}
+	}
+	return "", 0
+}
+
+// link matches a link at the start of s ([text](dest "title"), or a
+// reference link), returning its text, destination and length.
+func (c *inlineConverter) link(s string) (string, string, int) {
+	end := closingBracket(s)
+	if end < 0 {
+		return "", "", 0
+	}
+	text := s[1:end]
+	rest := s[end+1:]
+	if strings.HasPrefix(rest, "(") {
+		if dest, n := linkDestination(rest); n > 0 {
+			return text, dest, end + 1 + n
+		}
+	}
+	if strings.HasPrefix(rest, "[") {
+		if e := strings.IndexByte(rest, ']'); e > 0 {
+			label := rest[1:e]
+			if label == "" {
+				label = text
+			}
+			if dest, ok := c.p.linkRefs[normalizeLinkLabel(label)]; ok {
+				return text, dest, end + 1 + e + 1
+			}
+		}
+	}
+	if dest, ok := c.p.linkRefs[normalizeLinkLabel(text)]; ok {
+		return text, dest, end + 1
+	}
+	return "", "", 0
+}
+
+// closingBracket returns the index of the ] matching the [ at s[0],
+// skipping escapes and code spans.
+func closingBracket(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '`':
+			if _, n := codeSpan(s[i:]); n > 0 {
+				i += n - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// linkDestination parses (dest "title") at the start of s, returning the
+// destination and the length of the parenthesised part.
+func linkDestination(s string) (string, int) {
+	i := skipSpace(s, 1)
+	var dest string
+	if i < len(s) && s[i] == '<' {
+		end := strings.IndexByte(s[i:], '>')
+		if end < 0 {
+			return "", 0
+		}
+		dest = s[i+1 : i+end]
+		i += end + 1
+	} else {
+		start, depth := i, 0
+	loop:
+		for ; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case ' ', '\t', '\n':
+				break loop
+			case '(':
+				depth++
+			case ')':
+				if depth == 0 {
+					break loop
+				}
+				depth--
+			}
+		}
+		if i > len(s) {
+			return "", 0
+		}
+		dest = s[start:i]
+	}
+
+	i = skipSpace(s, i)
+	if i < len(s) && strings.IndexByte(`"'(`, s[i]) >= 0 {
+		closer := s[i]
+		if closer == '(' {
+			closer = ')'
+		}
+		end := strings.IndexByte(s[i+1:], closer)
+		if end < 0 {
+			return "", 0
+		}
+		i = skipSpace(s, i+end+2)
+	}
+	if i >= len(s) || s[i] != ')' {
+		return "", 0
+	}
+	return unescapeMarkdown(dest), i + 1
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func unescapeMarkdown(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// emphasis matches a run of *, _ or ~ at s[i] with its closing run,
+// following CommonMark's flanking rules. It returns the text between them,
+// the bytes consumed and the run length: 1 italic, 2 bold, 3 both, and ~
+// or ~~ strikethrough.
+func emphasis(s string, i int) (string, int, int) {
+	ch := s[i]
+	run := runLength(s, i, ch)
+	if run > 3 || (ch == '~' && run > 2) {
+		return "", 0, 0
+	}
+	if !leftFlanking(runeBefore(s, i), runeAt(s, i+run)) || (ch == '_' && isWordRune(runeBefore(s, i))) {
+		return "", 0, 0
+	}
+
+	// Runs opened inside, each waiting for its own closer.
+	var open []int
+	for j := i + run; j < len(s); {
+		switch s[j] {
+		case '\\':
+			j += 2
+			continue
+		case '`':
+			if _, n := codeSpan(s[j:]); n > 0 {
+				j += n
+			} else {
+				j += runLength(s, j, '`')
+			}
+			continue
+		case ch:
+		default:
+			j++
+			continue
+		}
+
+		m := runLength(s, j, ch)
+		before, after := runeBefore(s, j), runeAt(s, j+m)
+		closes := rightFlanking(before, after) && (ch != '_' || !isWordRune(after))
+		opens := leftFlanking(before, after) && (ch != '_' || !isWordRune(before))
+		if closes {
+			left := m
+			for left > 0 && len(open) > 0 {
+				top := &open[len(open)-1]
+				if *top > left {
+					*top -= left
+					left = 0
+					break
+				}
+				left -= *top
+				open = open[:len(open)-1]
+			}
+			if len(open) == 0 && left >= run && (ch != '~' || left == run) {
+				end := j + m
+				return s[i+run : end-run], end - i, run
+			}
+		} else if opens {
+			open = append(open, m)
+		}
+		j += m
+	}
+	return "", 0, 0
+}
+
+func runeBefore(s string, i int) rune {
+	if i <= 0 {
+		return ' '
+	}
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return r
+}
+
+func runeAt(s string, i int) rune {
+	if i >= len(s) {
+		return ' '
+	}
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return r
+}
+
+func isPunctRune(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func leftFlanking(prev, next rune) bool {
+	return !unicode.IsSpace(next) && (!isPunctRune(next) || unicode.IsSpace(prev) || isPunctRune(prev))
+}
+
+func rightFlanking(prev, next rune) bool {
+	return !unicode.IsSpace(prev) && (!isPunctRune(prev) || unicode.IsSpace(next) || isPunctRune(next))
+}
+
+var (
+	autolinkPattern      = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailAutolinkPattern = regexp.MustCompile(`^<([^\s<>@]+@[^\s<>@]+\.[^\s<>@]+)>`)
+	htmlTagPattern       = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^<>]*)?)(/?)>`)
+	cssColorPattern      = regexp.MustCompile(`(?i)(background(?:-color)?|\bcolor)\s*[:=]\s*["']?([a-z]+)`)
+	hrefPattern          = regexp.MustCompile(`(?i)\bhref\s*=\s*["']([^"']*)["']`)
+)
+
+// notionTextColors are the colours Notion rich text accepts, also with a
+// _background suffix.
+var notionTextColors = keywordSet("gray brown orange yellow green blue purple pink red")
+
+// html parses inline HTML at the start of s: autolinks, <br>, comments, and
+// elements such as <u>, <span style="color: red"> or <a href>, whose
+// content takes the element's formatting. Unknown elements keep their
+// content; a tag without its closing tag stays literal text.
+func (c *inlineConverter) html(s string, st inlineStyle, flush func()) int {
+	if strings.HasPrefix(s, "<!--") {
+		end := strings.Index(s, "-->")
+		if end < 0 {
+			return 0
+		}
+		return end + 3
+	}
+	if m := autolinkPattern.FindStringSubmatch(s); m != nil {
+		flush()
+		if st.link == "" {
+			st.link = m[1]
+		}
+		c.add(m[1], st, false)
+		return len(m[0])
+	}
+	if m := emailAutolinkPattern.FindStringSubmatch(s); m != nil {
+		flush()
+		if st.link == "" {
+			st.link = "mailto:" + m[1]
+		}
+		c.add(m[1], st, false)
+		return len(m[0])
+	}
+
+	m := htmlTagPattern.FindStringSubmatch(s)
+	if m == nil || m[1] == "/" {
+		return 0
+	}
+	name := strings.ToLower(m[2])
+	if name == "br" {
+		flush()
+		c.add("\n", st, false)
+		return len(m[0])
+	}
+	if m[4] == "/" {
+		return 0
+	}
+	inner, n := closingTag(s[len(m[0]):], name)
+	if n == 0 {
+		return 0
+	}
+
+	switch name {
+	case "u", "ins":
+		st.underline = true
+	case "b", "strong":
+		st.bold = true
+	case "i", "em":
+		st.italic = true
+	case "s", "del", "strike":
+		st.strikethrough = true
+	case "code", "kbd":
+		st.code = true
+	case "mark":
+		st.color = "yellow_background"
+	case "span", "font":
+		if color := htmlColor(m[3]); color != "" {
+			st.color = color
+		}
+	case "a":
+		if href := hrefPattern.FindStringSubmatch(m[3]); href != nil && st.link == "" {
+			st.link = c.p.linkURL(href[1])
+		}
+	}
+	flush()
+	c.parse(inner, st)
+	return len(m[0]) + n
+}
+
+// closingTag finds the tag closing an element named name in s, which
+// follows the opening tag, returning the element's content and the bytes
+// up to the end of the closing tag.
+func closingTag(s, name string) (string, int) {
+	lower := strings.ToLower(s)
+	depth := 0
+	for i := 0; i < len(lower); {
+		next := strings.IndexByte(lower[i:], '<')
+		if next < 0 {
+			break
+		}
+		i += next
+		switch {
+		case strings.HasPrefix(lower[i:], "</"+name+">"):
+			if depth == 0 {
+				return s[:i], i + len(name) + 3
+			}
+			depth--
+		case hasHTMLTagPrefix(lower[i:], name):
+			depth++
+		}
+		i++
+	}
+	return "", 0
+}
+
+// htmlColor reads a Notion colour from a style or color attribute.
+func htmlColor(attrs string) string {
+	color := ""
+	for _, m := range cssColorPattern.FindAllStringSubmatch(attrs, -1) {
+		name := strings.ToLower(m[2])
+		if name == "grey" {
+			name = "gray"
+		}
+		if !notionTextColors[name] {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(m[1]), "background") {
+			color = name + "_background"
+		} else {
+			color = name
+		}
+	}
+	return color
+}
+
+var bareURLPattern = regexp.MustCompile(`^(?:https?://|www\.)[^\s<]+`)
+
+// bareURL matches a GitHub-style bare URL at the start of s, leaving out
+// trailing punctuation and unbalanced closing parentheses.
+func bareURL(s string) (string, int) {
+	match := bareURLPattern.FindString(s)
+	for match != "" {
+		last := match[len(match)-1]
+		if strings.IndexByte(`?!.,:;*_~'"`, last) >= 0 ||
+			(last == ')' && strings.Count(match, ")") > strings.Count(match, "(")) {
+			match = match[:len(match)-1]
+			continue
+		}
+		break
+	}
+	if match == "" || match == "www." || strings.HasSuffix(match, "://") {
+		return "", 0
+	}
+	link := match
+	if strings.HasPrefix(link, "www.") {
+		link = "http://" + link
+	}
+	return link, len(match)
+}
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// importedExportBlocks turns parsed block payloads into export blocks, so
+// imported Markdown can be rendered by the exporter again.
+func importedExportBlocks(blocks []map[string]interface{}) []exportBlock {
+	var nodes []exportBlock
+	for _, block := range blocks {
+		blockType := block["type"].(string)
+		content, _ := block[blockType].(map[string]interface{})
+		nodes = append(nodes, exportBlock{
+			Type:     blockType,
+			Content:  content,
+			Children: importedExportBlocks(blockPayloadChildren(block)),
+		})
+	}
+	return nodes
+}
+
+// blockOutline lists block types with their plain text, children indented.
+func blockOutline(blocks []map[string]interface{}, indent string) []string {
+	var lines []string
+	for _, node := range importedExportBlocks(blocks) {
+		lines = append(lines, exportOutline(node, indent)...)
+	}
+	return lines
+}
+
+func exportOutline(node exportBlock, indent string) []string {
+	text := ""
+	if items, ok := node.Content["rich_text"].([]map[string]interface{}); ok {
+		for _, item := range items {
+			if content, ok := item["text"].(map[string]interface{}); ok {
+				text += content["content"].(string)
+			}
+		}
+	}
+	lines := []string{strings.TrimRight(indent+node.Type+" "+text, " ")}
+	for _, child := range node.Children {
+		lines = append(lines, exportOutline(child, indent+"  ")...)
+	}
+	return lines
+}
+
+func TestMarkdownRoundTrip_Exporter(t *testing.T) {
+	// Markdown as the exporter writes it: importing and exporting it again
+	// must give the same text.
+	md := "# Title\n\n" +
+		"Plain **bold** *italic* ***both*** `code` ~~gone~~ [link](https://example.invalid/a) <u>under</u> <span style=\"color: red\">red</span> <span style=\"background-color: blue\">**blue**</span> $x^2$\n\n" +
+		"## Lists\n\n" +
+		"- one\n  - nested\n    1. deep\n\n- two\n\n" +
+		"1. first\n\n  Second paragraph\n\n" +
+		"- [x] done\n\n- [ ] todo\n\n" +
+		"### Blocks\n\n" +
+		"> A quote\n\n" +
+		"> 💡 A callout\n\n" +
+		"```go\nfmt.Println(\"hi\")\n```\n*Example*\n\n" +
+		"$$\nE = mc^2\n$$\n\n" +
+		"---\n\n" +
+		"| A | B |\n| --- | --- |\n| `1` | 2 \\| 3 |\n\n" +
+		"<details>\n<summary>More</summary>\n\nHidden\n\n- item\n\n</details>\n\n" +
+		"![Diagram](https://example.invalid/d.png)\n\n" +
+		"Parent\n\n  Child\n\n" +
+		"Literal \\*not italic\\* a \\[b\\](c) d \\\\ \\` \\< \\$ \\~ \\| \\_\n\n" +
+		"\\# not heading\n\n" +
+		"\\- not list\n\n" +
+		"1\\. not list\n\n" +
+		"<!-- breadcrumb -->"
+
+	blocks := parseMarkdownToBlocks(md)
+	if got := renderMarkdown(importedExportBlocks(blocks), 0); got != md {
+		t.Errorf("round trip changed the Markdown:\ngot:\n%s\nwant:\n%s", got, md)
+	}
+
+	want := []string{
+		"heading_1 Title",
+		"paragraph Plain bold italic both code gone link under red blue",
+		"heading_2 Lists",
+		"bulleted_list_item one",
+		"  bulleted_list_item nested",
+		"    numbered_list_item deep",
+		"bulleted_list_item two",
+		"numbered_list_item first",
+		"  paragraph Second paragraph",
+		"to_do done",
+		"to_do todo",
+		"heading_3 Blocks",
+		"quote A quote",
+		"callout A callout",
+		"code fmt.Println(\"hi\")",
+		"equation",
+		"divider",
+		"table",
+		"  table_row",
+		"  table_row",
+		"toggle More",
+		"  paragraph Hidden",
+		"  bulleted_list_item item",
+		"image",
+		"paragraph Parent",
+		"  paragraph Child",
+		"paragraph Literal *not italic* a [b](c) d \\ ` < $ ~ | _",
+		"paragraph # not heading",
+		"paragraph - not list",
+		"paragraph 1. not list",
+		"breadcrumb",
+	}
+	if got := blockOutline(blocks, ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("blocks:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMarkdownRoundTrip_LiteralText(t *testing.T) {
+	// Plain text that looks like Markdown is escaped on export, so it is
+	// imported as the same text.
+	for _, text := range []string{
+		"*not italic*",
+		"# not heading",
+		"- not list",
+		"1. not list",
+		"a [b](c) d",
+		"> not quote\n+ not list\n===",
+		"back\\slash `tick` <b> $x$ ~~gone~~ a|b snake_case",
+	} {
+		md := richTextMarkdown(notion.DecodeRichText([]interface{}{mdText(text, nil)}))
+		got := blockOutline(parseMarkdownToBlocks(md), "")
+		// Soft line breaks import as spaces.
+		if want := []string{"paragraph " + strings.ReplaceAll(text, "\n", " ")}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%q exported as %q imports as %q", text, md, got)
+		}
+	}
+}
+
+func TestParseMarkdownDocument_CommonMark(t *testing.T) {
+	md := strings.Join([]string{
+		"Setext title",
+		"============",
+		"",
+		"1. Item with",
+		"lazy continuation",
+		"",
+		"   Second paragraph",
+		"",
+		"   * Nested star",
+		"",
+		"         indented code",
+		"",
+		"~~~~python",
+		"print('```')",
+		"~~~~",
+		"",
+		"#### Deep heading ####",
+		"",
+		"Line one  ",
+		"line two",
+		"",
+		"> [!WARNING]",
+		"> Mind the gap",
+		">",
+		"> - inside",
+		"",
+		"Name | Role",
+		":-- | --:",
+		"Ada | Eng",
+		"",
+		"Text with a note[^n].",
+		"",
+		"[^n]: The note.",
+	}, "\n")
+
+	blocks := parseMarkdownToBlocks(md)
+	want := []string{
+		"heading_1 Setext title",
+		"numbered_list_item Item with lazy continuation",
+		"  paragraph Second paragraph",
+		"  bulleted_list_item Nested star",
+		"    code indented code",
+		"code print('```')",
+		"heading_3 Deep heading",
+		"paragraph Line one\nline two",
+		"callout Mind the gap",
+		"  bulleted_list_item inside",
+		"table",
+		"  table_row",
+		"  table_row",
+		"paragraph Text with a note[1].",
+		"divider",
+		"numbered_list_item The note.",
+	}
+	if got := blockOutline(blocks, ""); !reflect.DeepEqual(got, want) {
+		t.Fatalf("blocks:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	code := blocks[2]["code"].(map[string]interface{})
+	if code["language"] != "python" {
+		t.Errorf("language = %v, want python", code["language"])
+	}
+	callout := blocks[5]["callout"].(map[string]interface{})
+	if callout["color"] != "yellow_background" || callout["icon"].(map[string]interface{})["emoji"] != "⚠️" {
+		t.Errorf("alert callout = %v", callout)
+	}
+}
+
+func TestParseMarkdownDocument_MathAndImages(t *testing.T) {
+	dir := t.TempDir()
+	md := "$$\\int_0^1 x\\,dx$$\n\n" +
+		"![Local](images/my%20chart.png) ![Again](images/my%20chart.png)\n\n" +
+		"![Remote](https://example.invalid/r.png \"title\")\n\n" +
+		"Inline ![badge](https://example.invalid/b.svg) image"
+
+	doc := parseMarkdownDocument(md, dir)
+	var types []string
+	for _, block := range doc.Blocks {
+		types = append(types, block["type"].(string))
+	}
+	if want := []string{"equation", "image", "image", "image", "paragraph"}; !reflect.DeepEqual(types, want) {
+		t.Fatalf("types = %v, want %v", types, want)
+	}
+
+	if got := doc.Blocks[0]["equation"].(map[string]interface{})["expression"]; got != `\int_0^1 x\,dx` {
+		t.Errorf("expression = %q", got)
+	}
+	if len(doc.Images) != 2 || doc.Images[0].Path != filepath.Join(dir, "images", "my chart.png") {
+		t.Fatalf("local images = %+v", doc.Images)
+	}
+	remote := doc.Blocks[3]["image"].(map[string]interface{})
+	if remote["type"] != "external" || remote["external"].(map[string]interface{})["url"] != "https://example.invalid/r.png" {
+		t.Errorf("remote image = %v", remote)
+	}
+	inline := doc.Blocks[4]["paragraph"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+	if link := inline[1]["text"].(map[string]interface{})["link"]; link == nil {
+		t.Errorf("inline image should link to its source: %v", inline)
+	}
+}
+
+func TestMarkdownRichText(t *testing.T) {
+	p := &markdownParser{linkRefs: map[string]string{"docs": "https://example.invalid/docs"}, footnotes: map[string]*mdNode{}}
+
+	tests := []struct {
+		in   string
+		want []inlineRun
+	}{
+		{
+			in: "**bold *both***",
+			want: []inlineRun{
+				{text: "bold ", style: inlineStyle{bold: true}},
+				{text: "both", style: inlineStyle{bold: true, italic: true}},
+			},
+		},
+		{
+			in:   "snake_case_name and a * b",
+			want: []inlineRun{{text: "snake_case_name and a * b"}},
+		},
+		{
+			in: "costs $5 and $10, but $e^x$ is math",
+			want: []inlineRun{
+				{text: "costs $5 and $10, but "},
+				{text: "e^x", equation: true},
+				{text: " is math"},
+			},
+		},
+		{
+			in: "see [the docs][docs], <https://example.invalid/x> or www.example.invalid.",
+			want: []inlineRun{
+				{text: "see "},
+				{text: "the docs", style: inlineStyle{link: "https://example.invalid/docs"}},
+				{text: ", "},
+				{text: "https://example.invalid/x", style: inlineStyle{link: "https://example.invalid/x"}},
+				{text: " or "},
+				{text: "www.example.invalid", style: inlineStyle{link: "http://www.example.invalid"}},
+				{text: "."},
+			},
+		},
+		{
+			in: `<mark>hi</mark><br><s>old</s> \*literal\* [rel](./page.md) List<T>`,
+			want: []inlineRun{
+				{text: "hi", style: inlineStyle{color: "yellow_background"}},
+				{text: "\n"},
+				{text: "old", style: inlineStyle{strikethrough: true}},
+				{text: " *literal* rel List<T>"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			c := &inlineConverter{p: p}
+			c.parse(tt.in, inlineStyle{})
+			if !reflect.DeepEqual(c.runs, tt.want) {
+				t.Errorf("runs = %+v\nwant %+v", c.runs, tt.want)
+			}
+		})
+	}
+}
+
+type fakeFileUploader struct {
+	uploads []string
+}
+
+func (f *fakeFileUploader) CreateFileUpload(_ context.Context, req *notion.CreateFileUploadRequest) (*notion.FileUpload, error) {
+	f.uploads = append(f.uploads, req.FileName)
+	return &notion.FileUpload{ID: "upload-" + req.FileName, UploadURL: "https://example.invalid/upload"}, nil
+}
+
+func (f *fakeFileUploader) SendFileUpload(_ context.Context, _ string, _ io.Reader, filename, _ string) (*notion.FileUpload, error) {
+	return &notion.FileUpload{ID: "upload-" + filename}, nil
+}
+
+func (f *fakeFileUploader) UploadLargeFile(_ context.Context, filename string, _ io.Reader) (*notion.FileUpload, error) {
+	return &notion.FileUpload{ID: "large-" + filename}, nil
+}
+
+func TestMarkdownDocument_UploadImages(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chart.png"), []byte("\x89PNG\r\n\x1a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := parseMarkdownDocument("![a](chart.png)\n\n- ![b](./chart.png)", dir)
+	uploader := &fakeFileUploader{}
+	if err := doc.uploadImages(context.Background(), uploader); err != nil {
+		t.Fatalf("uploadImages: %v", err)
+	}
+	if len(uploader.uploads) != 1 {
+		t.Errorf("uploads = %v, want the file uploaded once", uploader.uploads)
+	}
+	for _, img := range doc.Images {
+		if got := img.image["file_upload"].(map[string]interface{})["id"]; got != "upload-chart.png" {
+			t.Errorf("file_upload id = %v", got)
+		}
+	}
+
+	missing := parseMarkdownDocument("![x](missing.png)", dir)
+	if err := missing.uploadImages(context.Background(), uploader); err == nil {
+		t.Error("expected an error for a missing image")
+	}
+}
+
+func TestDetachDeepChildren(t *testing.T) {
+	blocks := parseMarkdownToBlocks("- a\n  - b\n    - c\n      - d\n- e\n  - f")
+	detached := detachDeepChildren(blocks)
+
+	if len(detached) != 1 || len(detached[0]) != 1 {
+		t.Fatalf("detached = %v, want the children of the first item", detached)
+	}
+	if got := blockTreeDepth(blocks[0]); got != 0 {
+		t.Errorf("first item still has depth %d", got)
+	}
+	if got := blockTreeDepth(blocks[1]); got != 1 {
+		t.Errorf("shallow item depth = %d, want 1", got)
+	}
+	if got := blockTreeDepth(detached[0][0]); got != 2 {
+		t.Errorf("detached subtree depth = %d, want 2", got)
+	}
+}
+
+type recordingBlockWriter struct {
+	calls []string
+}
+
+func (w *recordingBlockWriter) AppendBlockChildren(_ context.Context, blockID string, req *notion.AppendBlockChildrenRequest) (*notion.BlockList, error) {
+	w.calls = append(w.calls, blockID)
+	list := &notion.BlockList{Object: "list"}
+	for i := range req.Children {
+		list.Results = append(list.Results, notion.Block{ID: blockID + "/" + string(rune('0'+i))})
+	}
+	return list, nil
+}
+
+func TestAppendDetachedChildren(t *testing.T) {
+	blocks := parseMarkdownToBlocks("- a\n  - b\n    - c\n      - d")
+	detached := detachDeepChildren(blocks)
+	writer := &recordingBlockWriter{}
+
+	created, err := appendBlockChildrenBatched(context.Background(), writer, "page", blocks, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := appendDetachedChildren(context.Background(), writer, created.Results, detached); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"page", "page/0"}; !reflect.DeepEqual(writer.calls, want) {
+		t.Errorf("append calls = %v, want %v", writer.calls, want)
+	}
+}
@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notion-cli/internal/cmdutil"
+	clierrors "github.com/salmonumbrella/notion-cli/internal/errors"
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// exportListDelimiter separates multi-select values in Notion's CSV export.
+const exportListDelimiter = ", "
+
+func newImportNotionExportCmd() *cobra.Command {
+	var parentID string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:     "notion-export <file.zip>",
+		Aliases: []string{"ne"},
+		Short:   "Import a Notion Markdown & CSV export",
+		Long: `Import a ZIP written by Notion's "Markdown & CSV" export under --parent,
+to move pages between workspaces.
+
+The archive is unpacked, including the ZIP parts Notion splits large exports
+into. Pages are recreated from the .md files and databases from the CSV
+files, without the IDs Notion appends to their names:
+  - Database schemas are inferred from the column values: checkboxes,
+    numbers, dates, URLs, emails, selects and multi-selects, otherwise text.
+    The first column is the title.
+  - Each CSV row becomes an entry, with the content of its page when the
+    export has one.
+  - Links between exported pages and databases become mentions of the new
+    ones, and images and attached files are uploaded.
+
+Relations, people and rollups are imported as text, as the export does not
+identify the pages and users they point to.
+
+Examples:
+  ntn import notion-export Export-1234.zip --parent abc123
+  ntn import notion-export Export-1234.zip --parent abc123 --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if parentID == "" {
+				return clierrors.NewUserError("--parent is required", "Pass the page to import the export under")
+			}
+
+			ctx := cmd.Context()
+			sf := SkillFileFromContext(ctx)
+			normalizedParent, err := cmdutil.NormalizeNotionID(resolveID(sf, parentID))
+			if err != nil {
+				return err
+			}
+
+			dir, err := os.MkdirTemp("", "ntn-notion-export-")
+			if err != nil {
+				return fmt.Errorf("failed to create temporary directory: %w", err)
+			}
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			if err := extractZip(args[0], dir); err != nil {
+				return clierrors.WrapUserError(err, "failed to unpack export", "Pass the ZIP file Notion's Markdown & CSV export downloads")
+			}
+
+			v, err := scanVault(dir, true)
+			if err != nil {
+				return err
+			}
+			if len(v.notes) == 0 {
+				return clierrors.NewUserError(fmt.Sprintf("no pages found in %s", args[0]), "Export with the Markdown & CSV format")
+			}
+			v.mapping = &importMapping{Parent: normalizedParent, Pages: map[string]*importedPage{}}
+
+			stderr := stderrFromContext(ctx)
+			if dryRun {
+				v.printDryRun(stderr, "notion export", args[0], normalizedParent, "")
+				return nil
+			}
+
+			client, err := clientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			v.client = client
+			v.stderr = stderr
+
+			if err := v.run(ctx, normalizedParent); err != nil {
+				return err
+			}
+
+			databases := 0
+			for _, note := range v.notes {
+				if note.table != nil {
+					databases++
+				}
+			}
+			_, _ = fmt.Fprintf(stderr, "Imported %d pages and %d databases from %s\n", len(v.notes)-databases, databases, args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&parentID, "parent", "", "Parent page ID")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the pages and databases that would be imported without making changes")
+
+	flagAlias(cmd.Flags(), "parent", "pa")
+	flagAlias(cmd.Flags(), "dry-run", "dr")
+
+	return cmd
+}
+
+// extractZip unpacks archive into dir, and the ZIP files inside it next to
+// them, as Notion splits large exports into parts.
+func extractZip(archive, dir string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	root := filepath.Clean(dir) + string(os.PathSeparator)
+	for _, f := range r.File {
+		target := filepath.Join(dir, filepath.FromSlash(f.Name))
+		if !strings.HasPrefix(target, root) {
+			return fmt.Errorf("archive entry %q points outside the archive", f.Name)
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := extractZipFile(f, target); err != nil {
+			return err
+		}
+		if strings.EqualFold(filepath.Ext(target), ".zip") {
+			if err := extractZip(target, filepath.Dir(target)); err != nil {
+				return err
+			}
+			if err := os.Remove(target); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func extractZipFile(f *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	dst, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// vaultTable is a database of a Notion export, read from its CSV.
+type vaultTable struct {
+	file    string
+	headers []string
+	rows    [][]string
+	columns []csvColumn
+
+	dataSourceID string
+}
+
+var (
+	// exportIDPattern matches the ID Notion appends to exported names.
+	exportIDPattern = regexp.MustCompile(`\s+[0-9a-fA-F]{32}$`)
+	// exportCellLinkPattern matches the paths Notion appends to the titles
+	// in relation cells: "Title (Other%20Page%20<id>.md)".
+	exportCellLinkPattern = regexp.MustCompile(`\s*\([^()\s]+\.(?:md|csv)\)`)
+)
+
+// loadExportNotes titles the notes of a Notion export, without the IDs in
+// their names, and reads its databases, making their CSV rows entries.
+func loadExportNotes(notes []*vaultNote) error {
+	for _, note := range notes {
+		note.title = exportIDPattern.ReplaceAllString(note.title, "")
+		if note.file != "" {
+			content, err := os.ReadFile(note.file)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", note.key, err)
+			}
+			line, _, _ := strings.Cut(strings.TrimLeft(string(content), "\ufeff\n"), "\n")
+			if title, ok := strings.CutPrefix(strings.TrimSpace(line), "# "); ok && strings.TrimSpace(title) != "" {
+				note.title = strings.TrimSpace(title)
+			}
+		}
+		if err := loadExportNotes(note.children); err != nil {
+			return err
+		}
+		if note.table != nil {
+			if err := loadExportTable(note); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadExportTable reads a database's CSV, infers its schema and pairs each
+// row with the exported page of the same title, in the CSV's order.
+func loadExportTable(note *vaultNote) error {
+	records, err := readCSVFile(note.table.file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", note.key, err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	headers := records[0]
+	if len(headers) > 0 {
+		headers[0] = strings.TrimPrefix(headers[0], "\ufeff")
+	}
+	rows := records[1:]
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = exportCellLinkPattern.ReplaceAllString(cell, "")
+		}
+	}
+
+	table := note.table
+	table.headers = headers
+	table.columns = inferCSVSchema(headers, rows, exportListDelimiter)
+	for _, row := range rows {
+		for i := range row {
+			if i < len(table.columns) {
+				row[i] = csvCellValue(row[i], table.columns[i], exportListDelimiter)
+			}
+		}
+	}
+	table.rows = rows
+
+	pages := note.children
+	var entries []*vaultNote
+	for i, row := range rows {
+		title := ""
+		if len(row) > 0 {
+			title = strings.TrimSpace(row[0])
+		}
+		var entry *vaultNote
+		for j, page := range pages {
+			if page.title == title {
+				entry = page
+				pages = slices.Delete(pages, j, j+1)
+				break
+			}
+		}
+		if entry == nil {
+			entry = &vaultNote{key: fmt.Sprintf("%s#%d", note.key, i+1), title: title, parent: note}
+		}
+		entry.row = row
+		entries = append(entries, entry)
+	}
+	// Pages without a row keep only their title.
+	for _, page := range pages {
+		page.row = []string{page.title}
+		entries = append(entries, page)
+	}
+	note.children = entries
+	return nil
+}
+
+// properties returns the page properties of a database entry from its row.
+func (t *vaultTable) properties(note *vaultNote) map[string]interface{} {
+	props := buildPageProperties(note.row, columnMappings(t.columns))
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	if len(t.columns) > 0 {
+		if _, ok := props[t.columns[0].Name]; !ok {
+			props[t.columns[0].Name] = csvValueToProperty(note.title, "title")
+		}
+	}
+	return props
+}
+
+// createDatabase creates the database of a CSV with its inferred schema.
+func (v *vaultImport) createDatabase(ctx context.Context, note *vaultNote, parent map[string]interface{}) error {
+	pageID, ok := parent["page_id"].(string)
+	if !ok {
+		return clierrors.NewUserError(
+			fmt.Sprintf("cannot create database %q inside a database", note.title),
+			"Import the export under a page",
+		)
+	}
+	db, err := v.client.CreateDatabase(ctx, &notion.CreateDatabaseRequest{
+		Parent: map[string]interface{}{"type": "page_id", "page_id": pageID},
+		Title: []map[string]interface{}{
+			{"type": "text", "text": map[string]interface{}{"content": note.title}},
+		},
+		InitialDataSource: &notion.InitialDataSource{Properties: databaseProperties(note.table.columns)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create database for %s: %w", note.key, err)
+	}
+	note.id = db.ID
+	if len(db.DataSources) > 0 {
+		note.table.dataSourceID = db.DataSources[0].ID
+		return nil
+	}
+	note.table.dataSourceID, err = resolveDataSourceID(ctx, v.client, db.ID, "")
+	return err
+}
+
+// stripExportProperties drops the "Property: value" lines Notion writes at
+// the top of the pages of database entries.
+func stripExportProperties(body string, headers []string) string {
+	lines := strings.Split(strings.TrimLeft(body, "\n"), "\n")
+	n := 0
+	for ; n < len(lines); n++ {
+		key, _, ok := strings.Cut(lines[n], ":")
+		if !ok || !slices.Contains(headers, strings.TrimSpace(key)) {
+			break
+		}
+	}
+	if n == 0 {
+		return body
+	}
+	return strings.Join(lines[n:], "\n")
+}
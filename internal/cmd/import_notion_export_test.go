@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeZip writes an archive of files, in name order of the slice.
+func writeZip(t *testing.T, path string, files [][2]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := zw.Create(file[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(file[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportNotionExport(t *testing.T) {
+	server := newImportDirServer(t, nil)
+
+	const (
+		wikiID  = "0123456789abcdef0123456789abcdef"
+		tasksID = "11111111111111111111111111111111"
+		docsID  = "22222222222222222222222222222222"
+	)
+	dir := t.TempDir()
+	// Notion splits large exports into parts, zipped in the download.
+	part := filepath.Join(dir, "part.zip")
+	writeZip(t, part, [][2]string{
+		{"Team Wiki " + wikiID + ".md", "# Team Wiki\n\nSee [Tasks](Team%20Wiki%20" + wikiID + "/Tasks%20" + tasksID + ".csv).\n"},
+		{"Team Wiki " + wikiID + "/Tasks " + tasksID + ".csv", "\ufeffName,Status,Tags,Due,Done\n" +
+			"Write docs,Doing,\"docs, writing\",\"May 1, 2026\",Yes\n" +
+			"Ship,Done,release,\"May 2, 2026 → May 3, 2026\",No\n" +
+			"Review,Doing,docs,,No\n"},
+		{"Team Wiki " + wikiID + "/Tasks " + tasksID + "/Write docs " + docsID + ".md",
+			"# Write docs\n\nStatus: Doing\nTags: docs, writing\n\nDraft the guide.\n\n![chart.png](Write%20docs%20" + docsID + "/chart.png)\n"},
+		{"Team Wiki " + wikiID + "/Tasks " + tasksID + "/Write docs " + docsID + "/chart.png", "PNG"},
+	})
+	partData, err := os.ReadFile(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(dir, "Export.zip")
+	writeZip(t, archive, [][2]string{{"Export-Part-1.zip", string(partData)}})
+
+	parentID := "bbbbbbbb-0000-0000-0000-000000000000"
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"import", "notion-export", archive, "--parent", parentID})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("import notion-export failed: %v\nstderr=%s", err, errBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "Imported 4 pages and 1 databases") {
+		t.Errorf("summary = %q", errBuf.String())
+	}
+
+	wiki := server.idOf(t, "Team Wiki")
+	if len(server.databases) != 1 {
+		t.Fatalf("created %d databases, want 1", len(server.databases))
+	}
+	var dbID string
+	var db map[string]any
+	for id, req := range server.databases {
+		dbID, db = id, req
+	}
+	if got := db["parent"].(map[string]any)["page_id"]; got != wiki {
+		t.Errorf("database parent = %v, want %s", got, wiki)
+	}
+	if title, _ := json.Marshal(db["title"]); !strings.Contains(string(title), `"content":"Tasks"`) {
+		t.Errorf("database title = %s", title)
+	}
+	props := db["initial_data_source"].(map[string]any)["properties"].(map[string]any)
+	types := map[string]string{}
+	for name, prop := range props {
+		for propType := range prop.(map[string]any) {
+			types[name] = propType
+		}
+	}
+	wantTypes := map[string]string{"Name": "title", "Status": "select", "Tags": "multi_select", "Due": "date", "Done": "checkbox"}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Errorf("schema = %v, want %v", types, wantTypes)
+	}
+
+	// Rows are created in CSV order, the one with a page included.
+	var rows []map[string]any
+	for _, req := range server.created {
+		if req["parent"].(map[string]any)["data_source_id"] == "ds-"+dbID {
+			rows = append(rows, req["properties"].(map[string]any))
+		}
+	}
+	if len(rows) != 3 {
+		t.Fatalf("created %d rows, want 3", len(rows))
+	}
+	ship, _ := json.Marshal(rows[1])
+	for _, want := range []string{
+		`"Due":{"date":{"end":"2026-05-03","start":"2026-05-02"}}`,
+		`"Done":{"checkbox":false}`,
+		`"Status":{"select":{"name":"Done"}}`,
+	} {
+		if !strings.Contains(string(ship), want) {
+			t.Errorf("row properties missing %s:\n%s", want, ship)
+		}
+	}
+	docs, _ := json.Marshal(rows[0])
+	if !strings.Contains(string(docs), `"Tags":{"multi_select":[{"name":"docs"},{"name":"writing"}]}`) {
+		t.Errorf("row properties:\n%s", docs)
+	}
+
+	content, _ := json.Marshal(server.appended[server.idOf(t, "Write docs")])
+	if strings.Contains(string(content), "Status: Doing") || !strings.Contains(string(content), "Draft the guide.") {
+		t.Errorf("row content should drop the property lines:\n%s", content)
+	}
+	if !strings.Contains(string(content), `"file_upload":{"id":"upload-1"}`) {
+		t.Errorf("row content should upload the image:\n%s", content)
+	}
+	wikiContent, _ := json.Marshal(server.appended[wiki])
+	if !strings.Contains(string(wikiContent), `"database":{"id":"`+dbID+`"}`) {
+		t.Errorf("link to the CSV should mention the database:\n%s", wikiContent)
+	}
+}
+
+func TestExtractZip_RejectsUnsafePaths(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil.zip")
+	writeZip(t, archive, [][2]string{{"../escape.md", "x"}})
+	if err := extractZip(archive, filepath.Join(dir, "out")); err == nil {
+		t.Fatal("expected an error for an entry outside the archive")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.md")); err == nil {
+		t.Error("entry was written outside the archive")
+	}
+}
+
+func TestInferColumnType(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      []string
+		wantType    string
+		wantOptions []string
+	}{
+		{"empty", nil, "rich_text", nil},
+		{"checkbox", []string{"Yes", "no"}, "checkbox", nil},
+		{"number", []string{"1", "2.5", "-3", "0.25"}, "number", nil},
+		{"leading zero", []string{"02134", "10001"}, "rich_text", nil},
+		{"not decimal", []string{"NaN", "Inf", "0x1p-2", "1e5"}, "rich_text", nil},
+		{"date", []string{"2026-05-01", "May 2, 2026 3:04 PM (GMT+2)"}, "date", nil},
+		{"url", []string{"https://example.invalid/a"}, "url", nil},
+		{"email", []string{"ada@example.invalid"}, "email", nil},
+		{"select", []string{"Doing", "Done", "Doing"}, "select", []string{"Doing", "Done"}},
+		{"multi-select", []string{"a, b", "b"}, "multi_select", []string{"a", "b"}},
+		{"unique text", []string{"first note", "second note"}, "rich_text", nil},
+		{"option with comma", []string{"a,b", "a,b"}, "rich_text", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotOptions := inferColumnType(tt.values, ", ")
+			if gotType != tt.wantType || !reflect.DeepEqual(gotOptions, tt.wantOptions) {
+				t.Errorf("inferColumnType(%q) = %s %q, want %s %q", tt.values, gotType, gotOptions, tt.wantType, tt.wantOptions)
+			}
+		})
+	}
+}
+
+func TestParseCSVDate(t *testing.T) {
+	tests := map[string]string{
+		"2026-05-01":                  "2026-05-01",
+		"2026-05-01T10:00:00Z":        "2026-05-01T10:00:00Z",
+		"May 1, 2026":                 "2026-05-01",
+		"May 1, 2026 3:04 PM (GMT+2)": "2026-05-01T15:04:00",
+		"May 1, 2026 → May 3, 2026":   "2026-05-01→2026-05-03",
+		"2026-05-01 -> 2026-05-03":    "2026-05-01→2026-05-03",
+	}
+	for in, want := range tests {
+		if got, ok := parseCSVDate(in); !ok || got != want {
+			t.Errorf("parseCSVDate(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := parseCSVDate("soon"); ok {
+		t.Error("parseCSVDate(\"soon\") should fail")
+	}
+}
+
+func TestStripExportProperties(t *testing.T) {
+	body := "\nStatus: Doing\nTags: a, b\n\nNote: not a property\n"
+	got := stripExportProperties(body, []string{"Name", "Status", "Tags"})
+	if got != "\nNote: not a property\n" {
+		t.Errorf("stripExportProperties = %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -124,40 +125,32 @@ func TestParseMarkdownToBlocks(t *testing.T) {
 	}
 }
 
-func TestIsBlockStart(t *testing.T) {
+func TestParseMarkdownToBlocks_ParagraphInterruptions(t *testing.T) {
 	tests := []struct {
-		line     string
-		expected bool
+		input         string
+		expectedTypes []string
 	}{
-		{"# Heading", true},
-		{"## Heading", true},
-		{"### Heading", true},
-		{"---", true},
-		{"***", true},
-		{"___", true},
-		{"- Bullet", true},
-		{"* Bullet", true},
-		{"+ Bullet", true},
-		{"1. Numbered", true},
-		{"23. Numbered", true},
-		{"> Quote", true},
-		{"```", true},
-		{"```go", true},
-		{"- [ ] Todo", true},
-		{"- [x] Todo", true},
-		{"| A | B |", true},
-		{"| --- | --- |", true},
-		{"| single cell |", true},
-		{"Normal text", false},
-		{"", false},
-		{"    indented", false},
+		{"Text\n# Heading", []string{"paragraph", "heading_1"}},
+		{"Text\n---", []string{"heading_2"}},
+		{"Text\n***", []string{"paragraph", "divider"}},
+		{"Text\n- Bullet", []string{"paragraph", "bulleted_list_item"}},
+		{"Text\n1. Numbered", []string{"paragraph", "numbered_list_item"}},
+		{"Text\n> Quote", []string{"paragraph", "quote"}},
+		{"Text\n```\ncode\n```", []string{"paragraph", "code"}},
+		{"Text\n23. Not a list", []string{"paragraph"}},
+		{"Text\n|b|", []string{"paragraph"}},
+		{"Text\n    indented", []string{"paragraph"}},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.line, func(t *testing.T) {
-			result := isBlockStart(tt.line)
-			if result != tt.expected {
-				t.Errorf("isBlockStart(%q) = %v, expected %v", tt.line, result, tt.expected)
+		t.Run(tt.input, func(t *testing.T) {
+			blocks := parseMarkdownToBlocks(tt.input)
+			var types []string
+			for _, block := range blocks {
+				types = append(types, block["type"].(string))
+			}
+			if strings.Join(types, ",") != strings.Join(tt.expectedTypes, ",") {
+				t.Errorf("block types = %v, expected %v", types, tt.expectedTypes)
 			}
 		})
 	}
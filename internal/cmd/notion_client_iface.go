@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"io"
 	"net/http"
 
 	"github.com/salmonumbrella/notion-cli/internal/notion"
@@ -19,9 +20,9 @@ type pageSchemaGetter interface {
 	dataSourceGetter
 }
 
-// blockChildrenReader describes the block children retrieval used by export/duplicate helpers.
-type blockChildrenReader interface {
-	GetBlockChildren(ctx context.Context, blockID string, opts *notion.BlockChildrenOptions) (*notion.BlockList, error)
+// blockTreeReader describes the recursive block children retrieval used by export/duplicate helpers.
+type blockTreeReader interface {
+	GetBlockChildrenRecursive(ctx context.Context, blockID string, depth int, opts *notion.BlockChildrenOptions) ([]notion.Block, error)
 }
 
 // blockChildrenWriter describes the block children append operation used by duplicate helpers.
@@ -29,14 +30,23 @@ type blockChildrenWriter interface {
 	AppendBlockChildren(ctx context.Context, blockID string, req *notion.AppendBlockChildrenRequest) (*notion.BlockList, error)
 }
 
+// fileUploader describes the file upload operations used to attach local
+// files to imported blocks.
+type fileUploader interface {
+	CreateFileUpload(ctx context.Context, req *notion.CreateFileUploadRequest) (*notion.FileUpload, error)
+	SendFileUpload(ctx context.Context, uploadURL string, file io.Reader, filename, contentType string) (*notion.FileUpload, error)
+	UploadLargeFile(ctx context.Context, filename string, file io.Reader) (*notion.FileUpload, error)
+}
+
 // rawRequester describes the raw API request used by api request helpers.
 type rawRequester interface {
 	DoRawRequest(ctx context.Context, method, path string, body []byte, headers http.Header) (*notion.RawResponse, error)
 }
 
 var (
-	_ blockChildrenReader = (*notion.Client)(nil)
+	_ blockTreeReader     = (*notion.Client)(nil)
 	_ blockChildrenWriter = (*notion.Client)(nil)
+	_ fileUploader        = (*notion.Client)(nil)
 	_ rawRequester        = (*notion.Client)(nil)
 	_ pageSchemaGetter    = (*notion.Client)(nil)
 )
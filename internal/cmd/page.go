@@ -42,6 +42,7 @@ func newPageCmd() *cobra.Command {
 	cmd.AddCommand(newPageUpdateBatchCmd())
 	cmd.AddCommand(newPageDuplicateCmd())
 	cmd.AddCommand(newPageExportCmd())
+	cmd.AddCommand(newPageViewCmd())
 	cmd.AddCommand(newPagePropertyCmd())
 	cmd.AddCommand(newPageMoveCmd())
 	cmd.AddCommand(newPageDeleteCmd())
@@ -147,6 +148,7 @@ func newPageGetCmd() *cobra.Command {
 	var enrich bool
 	var includeChildren bool
 	var childrenDepth int
+	var concurrency int
 	var light bool
 
 	cmd := &cobra.Command{
@@ -169,6 +171,9 @@ Note: --enrich requires 1-2 extra API calls per page (1 for parent title, 1 for
 
 Use --include-children to include page body blocks in the response.
 Use --children-depth to control recursive block fetching depth (1 = direct children only).
+Use --concurrency to bound parallel block fetches when --children-depth > 1.
+In text mode the page is shown as a "Property: value" sheet in schema order,
+with people resolved to names and dates shown with a relative hint.
 Use --light (or --li) for compact lookup output (id, object, title, url).
 Use --light to emit compact JSON output for fast lookups.
 
@@ -225,6 +230,10 @@ Example:
 				return fmt.Errorf("--children-depth must be >= 1")
 			}
 
+			if textViewEnabled(ctx) && !enrich && !includeChildren {
+				return printPageSheet(ctx, client, page)
+			}
+
 			var result interface{} = page
 			if enrich {
 				result = enrichPage(ctx, client, page)
@@ -235,7 +244,7 @@ Example:
 				if childrenDepth == 1 {
 					children, err = fetchAllBlockChildren(ctx, client, pageID)
 				} else {
-					children, err = client.GetBlockChildrenRecursive(ctx, pageID, childrenDepth, nil)
+					children, err = client.GetBlockChildrenRecursive(ctx, pageID, childrenDepth, &notion.BlockChildrenOptions{Concurrency: concurrency})
 				}
 				if err != nil {
 					return wrapAPIError(err, "access block", "block", args[0])
@@ -257,6 +266,7 @@ Example:
 	cmd.Flags().BoolVar(&enrich, "enrich", false, "Include parent title and child count (extra API calls)")
 	cmd.Flags().BoolVar(&includeChildren, "include-children", false, "Include page body blocks in output")
 	cmd.Flags().IntVar(&childrenDepth, "children-depth", 1, "Depth for --include-children (1 = direct children only)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", notion.DefaultBlockFetchConcurrency, "Maximum parallel block fetches for --children-depth > 1")
 	cmd.Flags().BoolVar(&light, "light", false, "Return compact payload (id, object, title, url)")
 	flagAlias(cmd.Flags(), "light", "li")
 
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 
@@ -32,6 +33,7 @@ func newPageDuplicateCmd() *cobra.Command {
 	var dataSourceID string
 	var titleOverride string
 	var noChildren bool
+	var concurrency int
 
 	cmd := &cobra.Command{
 		Use:     "duplicate <page-id>",
@@ -113,7 +115,7 @@ Use --no-children to skip block duplication.`,
 			}
 
 			if !noChildren {
-				children, err := buildBlockTree(ctx, client, sourceID)
+				children, err := buildBlockTree(ctx, client, sourceID, concurrency)
 				if err != nil {
 					return err
 				}
@@ -132,6 +134,7 @@ Use --no-children to skip block duplication.`,
 	cmd.Flags().StringVar(&dataSourceID, "datasource", "", "Data source ID (optional, overrides --parent-type database)")
 	cmd.Flags().StringVar(&titleOverride, "title", "", "Override the duplicate page title")
 	cmd.Flags().BoolVar(&noChildren, "no-children", false, "Skip duplicating page content blocks")
+	cmd.Flags().IntVar(&concurrency, "concurrency", notion.DefaultBlockFetchConcurrency, "Maximum parallel block fetches")
 
 	// Flag aliases
 	flagAlias(cmd.Flags(), "parent", "pa")
@@ -195,50 +198,34 @@ func overrideTitleProperty(properties map[string]interface{}, title string) {
 	}
 }
 
-func buildBlockTree(ctx context.Context, client blockChildrenReader, blockID string) ([]map[string]interface{}, error) {
-	var blocks []notion.Block
-	cursor := ""
-
-	for {
-		opts := &notion.BlockChildrenOptions{StartCursor: cursor, PageSize: 100}
-		list, err := client.GetBlockChildren(ctx, blockID, opts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch block children: %w", err)
-		}
-		blocks = append(blocks, list.Results...)
-		if !list.HasMore || list.NextCursor == nil || *list.NextCursor == "" {
-			break
-		}
-		cursor = *list.NextCursor
+func buildBlockTree(ctx context.Context, client blockTreeReader, blockID string, concurrency int) ([]map[string]interface{}, error) {
+	blocks, err := client.GetBlockChildrenRecursive(ctx, blockID, fullBlockTreeDepth, &notion.BlockChildrenOptions{
+		PageSize:    100,
+		Concurrency: concurrency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block children: %w", err)
 	}
+	return blockPayloads(stderrFromContext(ctx), blocks), nil
+}
 
+// blockPayloads converts fetched blocks into append payloads, skipping block
+// types the API cannot create with a warning to stderr.
+func blockPayloads(stderr io.Writer, blocks []notion.Block) []map[string]interface{} {
 	var payloads []map[string]interface{}
 	for i := range blocks {
 		block := blocks[i]
 		if unsupportedBlockTypes[block.Type] {
-			_, _ = fmt.Fprintf(stderrFromContext(ctx), "warning: skipping unsupported block type %q (%s)\n", block.Type, block.ID)
+			_, _ = fmt.Fprintf(stderr, "warning: skipping unsupported block type %q (%s)\n", block.Type, block.ID)
 			continue
 		}
 
 		var children []map[string]interface{}
 		if block.HasChildren {
-			childPayloads, err := buildBlockTree(ctx, client, block.ID)
-			if err != nil {
-				return nil, err
-			}
-			children = childPayloads
-		}
-
-		content := make(map[string]interface{}, len(block.Content))
-		for k, v := range block.Content {
-			content[k] = v
+			children = blockPayloads(stderr, block.Children)
 		}
 
-		payload := map[string]interface{}{
-			"object":   "block",
-			"type":     block.Type,
-			block.Type: content,
-		}
+		payload := block.Payload()
 		if len(children) > 0 {
 			payload["children"] = children
 		}
@@ -246,7 +233,7 @@ func buildBlockTree(ctx context.Context, client blockChildrenReader, blockID str
 		payloads = append(payloads, payload)
 	}
 
-	return payloads, nil
+	return payloads
 }
 
 func appendChildrenInBatches(ctx context.Context, client blockChildrenWriter, parentID string, children []map[string]interface{}) error {
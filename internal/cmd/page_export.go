@@ -3,11 +3,14 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"html/template"
+	"math"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notion-cli/internal/cmdutil"
+	clierrors "github.com/salmonumbrella/notion-cli/internal/errors"
 	"github.com/salmonumbrella/notion-cli/internal/notion"
 	"github.com/salmonumbrella/notion-cli/internal/output"
 )
@@ -21,13 +24,37 @@ type exportBlock struct {
 
 func newPageExportCmd() *cobra.Command {
 	var format string
+	var fallback string
+	var concurrency int
+	var recursive bool
+	var dir string
 
 	cmd := &cobra.Command{
 		Use:     "export <page-id>",
 		Aliases: []string{"ex"},
 		Short:   "Export a page's content",
-		Long:    "Export a Notion page as Markdown or JSON block tree.",
-		Args:    cobra.ExactArgs(1),
+		Long: `Export a Notion page as Markdown, a JSON block tree, or a standalone HTML
+page (--format html) with a table of contents and highlighted code.
+
+Markdown covers every block type: toggles become <details> elements, files and
+bookmarks links, equations $$ blocks, child pages and mentions links to Notion,
+and a table of contents block a list of heading links. --fallback controls what
+Markdown cannot express (unsupported blocks, underline and colours):
+  comment  Keep them as HTML comments and inline HTML (default)
+  drop     Leave them out for plain Markdown
+
+--recursive --dir exports the page with all its child pages and child
+databases (one file per page and database row) into a folder tree that
+mirrors Notion: a page's children go in a folder named after it. Links and
+mentions between exported pages become relative file links, Notion-hosted
+images and files are downloaded next to the pages that use them, and
+manifest.json maps every page, database and file ID to its path.
+
+Example:
+  ntn page export 12345678-1234-1234-1234-123456789012 > page.md
+  ntn p ex 12345678-1234-1234-1234-123456789012 --fallback drop
+  ntn p ex 12345678-1234-1234-1234-123456789012 --recursive --dir out/`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			sf := SkillFileFromContext(ctx)
@@ -36,25 +63,49 @@ func newPageExportCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			mdFallback, err := parseMarkdownFallback(fallback)
+			if err != nil {
+				return err
+			}
+			exportFormat := strings.ToLower(strings.TrimSpace(format))
+			if recursive != (dir != "") {
+				return clierrors.NewUserError("--recursive and --dir must be used together", "Example: ntn p ex <page> --recursive --dir out/")
+			}
 
 			client, err := clientFromContext(ctx)
 			if err != nil {
 				return err
 			}
 
+			if recursive {
+				switch exportFormat {
+				case "markdown", "md":
+					exportFormat = "markdown"
+				case "json", "html":
+				default:
+					return fmt.Errorf("invalid --format %q (expected markdown, json or html)", format)
+				}
+				manifest, err := newTreeExporter(client, dir, exportFormat, mdFallback, concurrency).export(ctx, pageID)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(stderrFromContext(ctx), "Exported %d pages and %d files to %s\n", len(manifest.Pages), len(manifest.Files), dir)
+				return nil
+			}
+
 			page, err := client.GetPage(ctx, pageID)
 			if err != nil {
 				return fmt.Errorf("failed to fetch page: %w", err)
 			}
 
-			blocks, err := fetchExportBlocks(ctx, client, pageID)
+			blocks, err := fetchExportBlocks(ctx, client, pageID, concurrency)
 			if err != nil {
 				return err
 			}
 
-			switch strings.ToLower(strings.TrimSpace(format)) {
+			switch exportFormat {
 			case "markdown", "md":
-				markdown := renderMarkdown(blocks, 0)
+				markdown := newMarkdownRenderer(mdFallback).render(blocks, 0)
 				if title := pageTitleFromProperties(page.Properties); title != "" {
 					markdown = "# " + title + "\n\n" + markdown
 				}
@@ -66,34 +117,47 @@ func newPageExportCmd() *cobra.Command {
 					"page":   page,
 					"blocks": blocks,
 				})
+			case "html":
+				renderer := newHTMLRenderer()
+				body := renderer.render(blocks)
+				return writeHTMLPage(stdoutFromContext(ctx), htmlPage{
+					Title: extractPageTitleFromProperties(page.Properties),
+					Icon:  template.HTML(htmlIcon(page.Icon, "page-icon")),
+					Body:  template.HTML(body),
+					TOC:   template.HTML(renderer.toc()),
+				})
 			default:
-				return fmt.Errorf("invalid --format %q (expected markdown or json)", format)
+				return fmt.Errorf("invalid --format %q (expected markdown, json or html)", format)
 			}
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "markdown", "Export format (markdown or json)")
+	cmd.Flags().StringVar(&format, "format", "markdown", "Export format (markdown, json or html)")
+	cmd.Flags().StringVar(&fallback, "fallback", string(markdownFallbackComment), "Markdown for unsupported content: comment or drop")
+	cmd.Flags().IntVar(&concurrency, "concurrency", notion.DefaultBlockFetchConcurrency, "Maximum parallel block fetches")
+	cmd.Flags().BoolVar(&recursive, "recursive", false, "Also export child pages and databases (requires --dir)")
+	cmd.Flags().StringVar(&dir, "dir", "", "Output directory for --recursive")
 
 	return cmd
 }
 
-func fetchExportBlocks(ctx context.Context, client blockChildrenReader, blockID string) ([]exportBlock, error) {
-	var blocks []notion.Block
-	cursor := ""
+// fullBlockTreeDepth asks GetBlockChildrenRecursive for the entire block tree.
+const fullBlockTreeDepth = math.MaxInt32
 
-	for {
-		opts := &notion.BlockChildrenOptions{StartCursor: cursor, PageSize: 100}
-		list, err := client.GetBlockChildren(ctx, blockID, opts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch block children: %w", err)
-		}
-		blocks = append(blocks, list.Results...)
-		if !list.HasMore || list.NextCursor == nil || *list.NextCursor == "" {
-			break
-		}
-		cursor = *list.NextCursor
+// fetchExportBlocks fetches the full block tree under blockID, fanning nested
+// fetches out across up to concurrency workers (0 = client default).
+func fetchExportBlocks(ctx context.Context, client blockTreeReader, blockID string, concurrency int) ([]exportBlock, error) {
+	blocks, err := client.GetBlockChildrenRecursive(ctx, blockID, fullBlockTreeDepth, &notion.BlockChildrenOptions{
+		PageSize:    100,
+		Concurrency: concurrency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block children: %w", err)
 	}
+	return toExportBlocks(blocks), nil
+}
 
+func toExportBlocks(blocks []notion.Block) []exportBlock {
 	var nodes []exportBlock
 	for i := range blocks {
 		block := blocks[i]
@@ -103,294 +167,22 @@ func fetchExportBlocks(ctx context.Context, client blockChildrenReader, blockID
 			Content: block.Content,
 		}
 		if block.HasChildren {
-			children, err := fetchExportBlocks(ctx, client, block.ID)
-			if err != nil {
-				return nil, err
-			}
-			node.Children = children
+			node.Children = toExportBlocks(block.Children)
 		}
 		nodes = append(nodes, node)
 	}
-
-	return nodes, nil
-}
-
-func renderMarkdown(blocks []exportBlock, indent int) string {
-	return strings.TrimRight(strings.Join(renderMarkdownLines(blocks, indent), "\n"), "\n")
+	return nodes
 }
 
-func renderMarkdownLines(blocks []exportBlock, indent int) []string {
-	var lines []string
-	for i, block := range blocks {
-		if i > 0 {
-			lines = append(lines, "")
-		}
-		lines = append(lines, renderBlockMarkdown(block, indent)...)
-	}
-	return lines
-}
-
-func renderBlockMarkdown(block exportBlock, indent int) []string {
-	prefix := strings.Repeat("  ", indent)
-	switch block.Type {
-	case "paragraph":
-		return []string{prefix + richTextContentToMarkdown(block.Content, "rich_text")}
-	case "heading_1":
-		return []string{"# " + richTextContentToMarkdown(block.Content, "rich_text")}
-	case "heading_2":
-		return []string{"## " + richTextContentToMarkdown(block.Content, "rich_text")}
-	case "heading_3":
-		return []string{"### " + richTextContentToMarkdown(block.Content, "rich_text")}
-	case "bulleted_list_item":
-		lines := []string{prefix + "- " + richTextContentToMarkdown(block.Content, "rich_text")}
-		if len(block.Children) > 0 {
-			lines = append(lines, renderMarkdownLines(block.Children, indent+1)...)
-		}
-		return lines
-	case "numbered_list_item":
-		lines := []string{prefix + "1. " + richTextContentToMarkdown(block.Content, "rich_text")}
-		if len(block.Children) > 0 {
-			lines = append(lines, renderMarkdownLines(block.Children, indent+1)...)
-		}
-		return lines
-	case "to_do":
-		checked, _ := block.Content["checked"].(bool)
-		box := " "
-		if checked {
-			box = "x"
-		}
-		lines := []string{prefix + "- [" + box + "] " + richTextContentToMarkdown(block.Content, "rich_text")}
-		if len(block.Children) > 0 {
-			lines = append(lines, renderMarkdownLines(block.Children, indent+1)...)
-		}
-		return lines
-	case "toggle":
-		lines := []string{prefix + "- " + richTextContentToMarkdown(block.Content, "rich_text")}
-		if len(block.Children) > 0 {
-			lines = append(lines, renderMarkdownLines(block.Children, indent+1)...)
-		}
-		return lines
-	case "quote":
-		lines := []string{prefix + "> " + richTextContentToMarkdown(block.Content, "rich_text")}
-		if len(block.Children) > 0 {
-			for _, childLine := range renderMarkdownLines(block.Children, 0) {
-				lines = append(lines, prefix+"> "+childLine)
-			}
-		}
-		return lines
-	case "code":
-		language, _ := block.Content["language"].(string)
-		code := richTextFromContent(block.Content, "rich_text")
-		return []string{prefix + "```" + language, code, prefix + "```"}
-	case "callout":
-		return []string{prefix + "> " + richTextContentToMarkdown(block.Content, "rich_text")}
-	case "divider":
-		return []string{prefix + "---"}
-	case "image":
-		if url := extractFileURL(block.Content); url != "" {
-			return []string{prefix + "![](" + url + ")"}
-		}
-		return []string{prefix + "![](unsupported-image)"}
-	case "table":
-		return renderTableMarkdown(block, prefix)
-	case "table_row":
-		// table_row is handled by renderTableMarkdown; standalone rendering shouldn't happen
-		return nil
-	default:
-		if len(block.Children) > 0 {
-			lines := []string{prefix + "<!-- unsupported block type: " + block.Type + " -->"}
-			lines = append(lines, renderMarkdownLines(block.Children, indent+1)...)
-			return lines
-		}
-		return []string{prefix + "<!-- unsupported block type: " + block.Type + " -->"}
-	}
-}
-
-// renderTableMarkdown converts a table block with table_row children to markdown pipe table.
-func renderTableMarkdown(block exportBlock, prefix string) []string {
-	if len(block.Children) == 0 {
-		return nil
-	}
-
-	var lines []string
-	for i, row := range block.Children {
-		cells := extractTableCells(row.Content)
-		line := prefix + "| " + strings.Join(cells, " | ") + " |"
-		lines = append(lines, line)
-
-		// Add separator after first row (markdown table spec requires it)
-		if i == 0 {
-			var seps []string
-			for range cells {
-				seps = append(seps, "---")
-			}
-			lines = append(lines, prefix+"| "+strings.Join(seps, " | ")+" |")
-		}
-	}
-
-	return lines
-}
-
-// extractTableCells extracts cell text from a table_row's content.
-func extractTableCells(content map[string]interface{}) []string {
-	cellsRaw, ok := content["cells"].([]interface{})
-	if !ok {
-		return nil
-	}
-
-	cells := make([]string, len(cellsRaw))
-	for i, cell := range cellsRaw {
-		items, ok := cell.([]interface{})
-		if !ok {
-			cells[i] = ""
-			continue
-		}
-		cells[i] = richTextToMarkdown(items)
-	}
-	return cells
-}
-
-func richTextFromContent(content map[string]interface{}, key string) string {
-	value, ok := content[key]
-	if !ok {
-		return ""
-	}
-
-	items, ok := value.([]interface{})
-	if !ok {
-		return ""
+// typedContent decodes the block's content, falling back to an UnknownBlock
+// when it does not match its type's schema.
+func (b exportBlock) typedContent() notion.BlockContent {
+	block := notion.Block{Type: b.Type, Content: b.Content}
+	content, err := block.TypedContent()
+	if err != nil {
+		return notion.UnknownBlock(b.Content)
 	}
-
-	var parts []string
-	for _, item := range items {
-		entry, ok := item.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if plain, ok := entry["plain_text"].(string); ok {
-			parts = append(parts, plain)
-			continue
-		}
-		if text, ok := entry["text"].(map[string]interface{}); ok {
-			if content, ok := text["content"].(string); ok {
-				parts = append(parts, content)
-			}
-		}
-	}
-	return strings.Join(parts, "")
-}
-
-// richTextToMarkdown converts a Notion rich_text array ([]interface{}) to a markdown string,
-// preserving bold, italic, code, strikethrough, and links.
-func richTextToMarkdown(items []interface{}) string {
-	if len(items) == 0 {
-		return ""
-	}
-
-	var b strings.Builder
-	for _, item := range items {
-		entry, ok := item.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		text := ""
-		if plain, ok := entry["plain_text"].(string); ok {
-			text = plain
-		} else if textObj, ok := entry["text"].(map[string]interface{}); ok {
-			text, _ = textObj["content"].(string)
-		}
-		if text == "" {
-			continue
-		}
-
-		linkURL := ""
-		if href, ok := entry["href"].(string); ok && href != "" {
-			linkURL = href
-		} else if textObj, ok := entry["text"].(map[string]interface{}); ok {
-			if linkObj, ok := textObj["link"].(map[string]interface{}); ok {
-				linkURL, _ = linkObj["url"].(string)
-			}
-		}
-
-		var bold, italic, code, strikethrough bool
-		if ann, ok := entry["annotations"].(map[string]interface{}); ok {
-			bold, _ = ann["bold"].(bool)
-			italic, _ = ann["italic"].(bool)
-			code, _ = ann["code"].(bool)
-			strikethrough, _ = ann["strikethrough"].(bool)
-		}
-
-		segment := text
-		if linkURL != "" {
-			segment = "[" + segment + "](" + linkURL + ")"
-		}
-		if code {
-			segment = "`" + segment + "`"
-		}
-		if strikethrough {
-			segment = "~~" + segment + "~~"
-		}
-		if bold && italic {
-			segment = "***" + segment + "***"
-		} else if bold {
-			segment = "**" + segment + "**"
-		} else if italic {
-			segment = "*" + segment + "*"
-		}
-
-		b.WriteString(segment)
-	}
-
-	return b.String()
-}
-
-// richTextContentToMarkdown extracts a rich_text array from a block's content map
-// and converts it to markdown with formatting preserved.
-func richTextContentToMarkdown(content map[string]interface{}, key string) string {
-	value, ok := content[key]
-	if !ok {
-		return ""
-	}
-	items, ok := value.([]interface{})
-	if !ok {
-		return ""
-	}
-	return richTextToMarkdown(items)
-}
-
-func extractFileURL(content map[string]interface{}) string {
-	fileType, _ := content["type"].(string)
-	if fileType == "" {
-		if file, ok := content["file"].(map[string]interface{}); ok {
-			if url, ok := file["url"].(string); ok {
-				return url
-			}
-		}
-		if external, ok := content["external"].(map[string]interface{}); ok {
-			if url, ok := external["url"].(string); ok {
-				return url
-			}
-		}
-		return ""
-	}
-
-	switch fileType {
-	case "file":
-		if file, ok := content["file"].(map[string]interface{}); ok {
-			if url, ok := file["url"].(string); ok {
-				return url
-			}
-		}
-	case "external":
-		if external, ok := content["external"].(map[string]interface{}); ok {
-			if url, ok := external["url"].(string); ok {
-				return url
-			}
-		}
-	}
-
-	return ""
+	return content
 }
 
 func pageTitleFromProperties(properties map[string]interface{}) string {
@@ -399,8 +191,8 @@ func pageTitleFromProperties(properties map[string]interface{}) string {
 		if !ok {
 			continue
 		}
-		if _, ok := prop["title"]; ok {
-			return richTextContentToMarkdown(prop, "title")
+		if title, ok := prop["title"]; ok {
+			return richTextMarkdown(notion.DecodeRichText(title))
 		}
 	}
 	return ""
@@ -0,0 +1,586 @@
+package cmd
+
+import (
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// htmlRenderer renders exported blocks as HTML fragments. Pages are wrapped
+// into documents by writeHTMLPage.
+type htmlRenderer struct {
+	// pageLink maps a page or database ID to a local link target, as for
+	// markdownRenderer.
+	pageLink func(id string) string
+	// breadcrumb is the page's trail of ancestors, shown for breadcrumb
+	// blocks.
+	breadcrumb template.HTML
+
+	headings []htmlHeading
+	anchors  map[string]string
+}
+
+// htmlHeading is a heading of the rendered page, for tables of contents.
+type htmlHeading struct {
+	level  int
+	anchor string
+	text   string
+}
+
+func newHTMLRenderer() *htmlRenderer {
+	return &htmlRenderer{anchors: map[string]string{}}
+}
+
+// render renders blocks, collecting the page's headings first so that
+// table_of_contents blocks can list them.
+func (h *htmlRenderer) render(blocks []exportBlock) string {
+	h.collectHeadings(blocks, map[string]int{})
+	var b strings.Builder
+	h.blocks(&b, blocks)
+	return b.String()
+}
+
+func (h *htmlRenderer) collectHeadings(blocks []exportBlock, seen map[string]int) {
+	for _, block := range blocks {
+		if block.Type == "child_page" {
+			continue
+		}
+		if content, ok := block.typedContent().(*notion.HeadingBlock); ok && strings.HasPrefix(block.Type, "heading_") {
+			text := notion.PlainText(content.RichText)
+			anchor := headingAnchor(text)
+			if anchor == "" {
+				anchor = "section"
+			}
+			if n := seen[anchor]; n > 0 {
+				seen[anchor]++
+				anchor = anchor + "-" + strconv.Itoa(n)
+			} else {
+				seen[anchor] = 1
+			}
+			h.anchors[block.ID] = anchor
+			h.headings = append(h.headings, htmlHeading{level: int(block.Type[len(block.Type)-1] - '0'), anchor: anchor, text: text})
+		}
+		h.collectHeadings(block.Children, seen)
+	}
+}
+
+// toc lists the page's headings as links, or returns "" for a page
+// without headings.
+func (h *htmlRenderer) toc() string {
+	if len(h.headings) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("<ul>\n")
+	for _, heading := range h.headings {
+		b.WriteString(`<li class="toc-h` + strconv.Itoa(heading.level) + `"><a href="#` + html.EscapeString(heading.anchor) + `">` + html.EscapeString(heading.text) + "</a></li>\n")
+	}
+	b.WriteString("</ul>\n")
+	return b.String()
+}
+
+// blocks renders blocks in order, grouping runs of list items of the same
+// type into one list.
+func (h *htmlRenderer) blocks(b *strings.Builder, blocks []exportBlock) {
+	for i := 0; i < len(blocks); {
+		open, closing := htmlListTags(blocks[i].Type)
+		if open == "" {
+			h.block(b, blocks[i])
+			i++
+			continue
+		}
+		listType := blocks[i].Type
+		b.WriteString(open + "\n")
+		for ; i < len(blocks) && blocks[i].Type == listType; i++ {
+			h.listItem(b, blocks[i])
+		}
+		b.WriteString(closing + "\n")
+	}
+}
+
+func htmlListTags(blockType string) (string, string) {
+	switch blockType {
+	case "bulleted_list_item":
+		return "<ul>", "</ul>"
+	case "numbered_list_item":
+		return "<ol>", "</ol>"
+	case "to_do":
+		return `<ul class="todo">`, "</ul>"
+	}
+	return "", ""
+}
+
+func (h *htmlRenderer) listItem(b *strings.Builder, block exportBlock) {
+	switch content := block.typedContent().(type) {
+	case *notion.ListItemBlock:
+		b.WriteString("<li" + htmlColorClass(content.Color) + ">" + h.richText(content.RichText))
+	case *notion.ToDoBlock:
+		checked := ""
+		if content.Checked {
+			checked = " checked"
+		}
+		b.WriteString("<li" + htmlColorClass(content.Color) + `><input type="checkbox" disabled` + checked + "> " + h.richText(content.RichText))
+	default:
+		b.WriteString("<li>")
+	}
+	if len(block.Children) > 0 {
+		b.WriteString("\n")
+		h.blocks(b, block.Children)
+	}
+	b.WriteString("</li>\n")
+}
+
+// indented renders children of a block that has no container of its own.
+func (h *htmlRenderer) indented(b *strings.Builder, children []exportBlock) {
+	if len(children) == 0 {
+		return
+	}
+	b.WriteString(`<div class="indented">` + "\n")
+	h.blocks(b, children)
+	b.WriteString("</div>\n")
+}
+
+func (h *htmlRenderer) block(b *strings.Builder, block exportBlock) {
+	switch content := block.typedContent().(type) {
+	case *notion.ParagraphBlock:
+		b.WriteString("<p" + htmlColorClass(content.Color) + ">" + h.richText(content.RichText) + "</p>\n")
+		h.indented(b, block.Children)
+	case *notion.HeadingBlock:
+		level := block.Type[len(block.Type)-1:]
+		heading := "<h" + level + ` id="` + html.EscapeString(h.anchors[block.ID]) + `"` + htmlColorClass(content.Color) + ">" +
+			h.richText(content.RichText) + "</h" + level + ">"
+		if len(block.Children) == 0 {
+			b.WriteString(heading + "\n")
+			return
+		}
+		b.WriteString("<details open><summary>" + heading + "</summary>\n")
+		h.blocks(b, block.Children)
+		b.WriteString("</details>\n")
+	case *notion.ToggleBlock:
+		b.WriteString("<details" + htmlColorClass(content.Color) + "><summary>" + h.richText(content.RichText) + "</summary>\n")
+		h.blocks(b, block.Children)
+		b.WriteString("</details>\n")
+	case *notion.QuoteBlock:
+		b.WriteString("<blockquote" + htmlColorClass(content.Color) + "><p>" + h.richText(content.RichText) + "</p>\n")
+		h.blocks(b, block.Children)
+		b.WriteString("</blockquote>\n")
+	case *notion.CalloutBlock:
+		color := content.Color
+		if color == "" || color == "default" {
+			color = "gray_background"
+		}
+		b.WriteString(`<aside class="callout ` + htmlColorName(color) + `">` + htmlIcon(content.Icon, "callout-icon") + `<div class="callout-body"><p>` + h.richText(content.RichText) + "</p>\n")
+		h.blocks(b, block.Children)
+		b.WriteString("</div></aside>\n")
+	case *notion.CodeBlock:
+		b.WriteString(h.code(content))
+	case *notion.EquationBlock:
+		b.WriteString(`<div class="equation"><code>` + html.EscapeString(content.Expression) + "</code></div>\n")
+	case *notion.DividerBlock:
+		b.WriteString("<hr>\n")
+	case *notion.FileBlock:
+		b.WriteString(h.file(block, content))
+	case *notion.BookmarkBlock:
+		b.WriteString(`<p class="bookmark">` + htmlLink(content.URL, h.richTextOr(content.Caption, content.URL)) + "</p>\n")
+	case *notion.EmbedBlock:
+		b.WriteString(`<p class="bookmark">` + htmlLink(content.URL, h.richTextOr(content.Caption, content.URL)) + "</p>\n")
+	case *notion.LinkPreviewBlock:
+		b.WriteString(`<p class="bookmark">` + htmlLink(content.URL, html.EscapeString(content.URL)) + "</p>\n")
+	case *notion.TableBlock:
+		b.WriteString(h.table(block, content))
+	case *notion.TableRowBlock:
+		// table_row is rendered by its table.
+	case *notion.ChildPageBlock:
+		b.WriteString(`<p class="page-link">` + htmlLink(linkedPageURL(h.pageLink, block.ID, ""), "📄 "+html.EscapeString(content.Title)) + "</p>\n")
+	case *notion.ChildDatabaseBlock:
+		b.WriteString(`<p class="page-link">` + htmlLink(linkedPageURL(h.pageLink, block.ID, ""), "🗃️ "+html.EscapeString(content.Title)) + "</p>\n")
+	case *notion.LinkToPageBlock:
+		id := content.PageID
+		if id == "" {
+			id = content.DatabaseID
+		}
+		b.WriteString(`<p class="page-link">` + htmlLink(linkedPageURL(h.pageLink, id, ""), "↗ Linked page") + "</p>\n")
+	case *notion.SyncedBlock:
+		h.blocks(b, block.Children)
+	case *notion.ColumnListBlock:
+		b.WriteString(`<div class="columns">` + "\n")
+		h.blocks(b, block.Children)
+		b.WriteString("</div>\n")
+	case *notion.ColumnBlock:
+		style := ""
+		if content.WidthRatio != nil && *content.WidthRatio > 0 {
+			style = ` style="flex: ` + strconv.FormatFloat(*content.WidthRatio, 'f', -1, 64) + ` 1 0"`
+		}
+		b.WriteString(`<div class="column"` + style + ">\n")
+		h.blocks(b, block.Children)
+		b.WriteString("</div>\n")
+	case *notion.TableOfContentsBlock:
+		if toc := h.toc(); toc != "" {
+			b.WriteString(`<nav class="toc-block">` + "\n" + toc + "</nav>\n")
+		}
+	case *notion.BreadcrumbBlock:
+		if h.breadcrumb != "" {
+			b.WriteString(`<nav class="breadcrumb">` + string(h.breadcrumb) + "</nav>\n")
+		}
+	case *notion.TemplateBlock:
+		b.WriteString(htmlCommentText("template: " + notion.PlainText(content.RichText)))
+		h.blocks(b, block.Children)
+	default:
+		b.WriteString(htmlCommentText("unsupported block type: " + block.Type))
+		h.blocks(b, block.Children)
+	}
+}
+
+// code renders a code block with its tokens highlighted by lexCode.
+func (h *htmlRenderer) code(content *notion.CodeBlock) string {
+	source := notion.PlainText(content.RichText)
+	var code strings.Builder
+	ok := lexCode(source, content.Language, func(token, kind string) {
+		if kind == "" {
+			code.WriteString(html.EscapeString(token))
+		} else {
+			code.WriteString(`<span class="tok-` + kind + `">` + html.EscapeString(token) + "</span>")
+		}
+	})
+	if !ok {
+		code.WriteString(html.EscapeString(source))
+	}
+
+	var b strings.Builder
+	b.WriteString(`<figure class="code"><pre><code`)
+	if content.Language != "" && content.Language != "plain text" {
+		b.WriteString(` class="language-` + html.EscapeString(strings.ReplaceAll(content.Language, " ", "-")) + `"`)
+	}
+	b.WriteString(">" + code.String() + "</code></pre>")
+	if caption := h.richText(content.Caption); caption != "" {
+		b.WriteString("<figcaption>" + caption + "</figcaption>")
+	}
+	b.WriteString("</figure>\n")
+	return b.String()
+}
+
+// file renders images, and audio and video hosted by Notion, inline, and
+// other files as links.
+func (h *htmlRenderer) file(block exportBlock, content *notion.FileBlock) string {
+	url := content.URL()
+	if url == "" {
+		return htmlCommentText(block.Type + " without a URL")
+	}
+	caption := h.richText(content.Caption)
+	figcaption := ""
+	if caption != "" {
+		figcaption = "<figcaption>" + caption + "</figcaption>"
+	}
+	src := htmlURL(url)
+
+	switch {
+	case block.Type == "image":
+		alt := html.EscapeString(notion.PlainText(content.Caption))
+		return `<figure class="image"><img src="` + src + `" alt="` + alt + `" loading="lazy">` + figcaption + "</figure>\n"
+	case block.Type == "video" && content.Type == "file":
+		return `<figure class="video"><video controls src="` + src + `"></video>` + figcaption + "</figure>\n"
+	case block.Type == "audio" && content.Type == "file":
+		return `<figure class="audio"><audio controls src="` + src + `"></audio>` + figcaption + "</figure>\n"
+	}
+
+	label := caption
+	if label == "" {
+		label = html.EscapeString(content.Name)
+	}
+	if label == "" {
+		label = block.Type
+	}
+	return `<p class="file">` + htmlLink(url, "📎 "+label) + "</p>\n"
+}
+
+// table renders a table block with its table_row children, using header
+// cells for the column and row headers the table has.
+func (h *htmlRenderer) table(block exportBlock, content *notion.TableBlock) string {
+	if len(block.Children) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(`<div class="table-wrap"><table>` + "\n")
+	for i, row := range block.Children {
+		rowContent, ok := row.typedContent().(*notion.TableRowBlock)
+		if !ok {
+			continue
+		}
+		header := i == 0 && content.HasColumnHeader
+		if header {
+			b.WriteString("<thead>\n")
+		}
+		b.WriteString("<tr>")
+		for j, cell := range rowContent.Cells {
+			tag := "td"
+			if header || (j == 0 && content.HasRowHeader) {
+				tag = "th"
+			}
+			b.WriteString("<" + tag + ">" + h.richText(cell) + "</" + tag + ">")
+		}
+		b.WriteString("</tr>\n")
+		if header {
+			b.WriteString("</thead>\n")
+		}
+	}
+	b.WriteString("</table></div>\n")
+	return b.String()
+}
+
+// richTextOr renders items, or the escaped fallback when they are empty.
+func (h *htmlRenderer) richTextOr(items []notion.RichText, fallback string) string {
+	if text := h.richText(items); text != "" {
+		return text
+	}
+	return html.EscapeString(fallback)
+}
+
+// richText renders rich text as inline HTML with its annotations, colours,
+// links and mentions.
+func (h *htmlRenderer) richText(items []notion.RichText) string {
+	var b strings.Builder
+	for _, item := range items {
+		text := item.PlainText
+		if text == "" && item.Text != nil {
+			text = item.Text.Content
+		}
+		linkURL := item.Href
+		if linkURL == "" && item.Text != nil && item.Text.Link != nil {
+			linkURL = item.Text.Link.URL
+		}
+
+		var segment string
+		switch {
+		case item.Equation != nil:
+			segment = `<code class="equation">` + html.EscapeString(item.Equation.Expression) + "</code>"
+			linkURL = ""
+		case item.Mention != nil:
+			text, linkURL = mentionLink(item.Mention, text, linkURL, h.pageLink)
+			segment = html.EscapeString(text)
+		default:
+			linkURL = localNotionLink(h.pageLink, linkURL)
+			segment = html.EscapeString(text)
+		}
+		if segment == "" {
+			continue
+		}
+		segment = strings.ReplaceAll(segment, "\n", "<br>")
+
+		var ann notion.Annotations
+		if item.Annotations != nil {
+			ann = *item.Annotations
+		}
+		if ann.Code && item.Equation == nil {
+			segment = "<code>" + segment + "</code>"
+		}
+		if ann.Bold {
+			segment = "<strong>" + segment + "</strong>"
+		}
+		if ann.Italic {
+			segment = "<em>" + segment + "</em>"
+		}
+		if ann.Strikethrough {
+			segment = "<s>" + segment + "</s>"
+		}
+		if ann.Underline {
+			segment = "<u>" + segment + "</u>"
+		}
+		if class := htmlColorClass(ann.Color); class != "" {
+			segment = "<span" + class + ">" + segment + "</span>"
+		}
+		if linkURL != "" {
+			segment = htmlLink(linkURL, segment)
+		}
+		b.WriteString(segment)
+	}
+	return b.String()
+}
+
+// htmlLink links label, which must already be HTML, to url. Labels of
+// URLs that are not safe to link to are kept as text.
+func htmlLink(url, label string) string {
+	if !safeHTMLURL(url) {
+		return label
+	}
+	return `<a href="` + html.EscapeString(url) + `">` + label + "</a>"
+}
+
+// htmlURL escapes url for an attribute, replacing an unsafe URL with the
+// placeholder html/template uses.
+func htmlURL(url string) string {
+	if !safeHTMLURL(url) {
+		return "#ZgotmplZ"
+	}
+	return html.EscapeString(url)
+}
+
+// safeHTMLURL reports whether url may be linked to or loaded: relative URLs
+// and http, https and mailto ones. URLs come from page content and database
+// rows, so a javascript: URL must not become a live link.
+func safeHTMLURL(url string) bool {
+	if i := strings.IndexAny(url, ":/?#"); i >= 0 && url[i] == ':' {
+		switch strings.ToLower(url[:i]) {
+		case "http", "https", "mailto":
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// htmlColorClass returns the class attribute for a Notion colour, or ""
+// for the default colour.
+func htmlColorClass(color string) string {
+	if color == "" || color == "default" {
+		return ""
+	}
+	return ` class="` + htmlColorName(color) + `"`
+}
+
+// htmlColorName maps a Notion colour to its CSS class: "red" to "color-red"
+// and "red_background" to "bg-red".
+func htmlColorName(color string) string {
+	if name, ok := strings.CutSuffix(color, "_background"); ok {
+		return "bg-" + html.EscapeString(name)
+	}
+	return "color-" + html.EscapeString(color)
+}
+
+// htmlIcon renders a page or callout icon: an emoji, or an image for
+// uploaded and external icons.
+func htmlIcon(icon map[string]interface{}, class string) string {
+	if emoji, ok := icon["emoji"].(string); ok && emoji != "" {
+		return `<span class="` + class + `">` + html.EscapeString(emoji) + "</span>"
+	}
+	for _, key := range []string{"external", "file"} {
+		if location, ok := icon[key].(map[string]interface{}); ok {
+			if url, ok := location["url"].(string); ok && url != "" {
+				return `<img class="` + class + `" src="` + htmlURL(url) + `" alt="">`
+			}
+		}
+	}
+	return ""
+}
+
+// htmlCommentText keeps content HTML cannot show as a comment.
+func htmlCommentText(text string) string {
+	return "<!-- " + strings.ReplaceAll(text, "--", "- -") + " -->\n"
+}
+
+// htmlPage is a rendered page wrapped in a standalone HTML document. Nav,
+// when set, is the sidebar of a site; TOC lists the page's headings.
+type htmlPage struct {
+	Title      string
+	Icon       template.HTML
+	Body       template.HTML
+	TOC        template.HTML
+	Nav        template.HTML
+	Breadcrumb template.HTML
+}
+
+func writeHTMLPage(w io.Writer, page htmlPage) error {
+	return htmlPageTemplate.Execute(w, page)
+}
+
+var htmlPageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>` + htmlPageCSS + `</style>
+</head>
+<body>
+<div class="layout">
+{{- if .Nav}}
+<nav class="sidebar">
+{{.Nav}}</nav>
+{{- end}}
+<main>
+{{- if .Breadcrumb}}
+<nav class="breadcrumb">{{.Breadcrumb}}</nav>
+{{- end}}
+<article>
+<h1 class="page-title">{{.Icon}}{{.Title}}</h1>
+{{.Body}}</article>
+</main>
+{{- if .TOC}}
+<nav class="toc">
+<p class="toc-title">On this page</p>
+{{.TOC}}</nav>
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+// htmlPageCSS styles exported pages. It is inlined so every page is
+// self-contained.
+const htmlPageCSS = `
+:root { --text: #37352f; --muted: #787774; --border: #e9e9e7; --code: #f7f6f3; --link: #0b6e99; }
+* { box-sizing: border-box; }
+body { margin: 0; color: var(--text); font: 16px/1.6 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; }
+a { color: var(--link); }
+.layout { display: flex; align-items: flex-start; min-height: 100vh; }
+.sidebar { position: sticky; top: 0; flex: 0 0 260px; height: 100vh; overflow-y: auto; padding: 16px; background: #fbfbfa; border-right: 1px solid var(--border); font-size: 14px; }
+.sidebar ul { list-style: none; margin: 0; padding-left: 14px; }
+.sidebar > ul { padding-left: 0; }
+.sidebar a { display: block; padding: 2px 6px; border-radius: 4px; color: var(--text); text-decoration: none; }
+.sidebar a:hover { background: #efefed; }
+.sidebar a.current { background: #e8e7e4; font-weight: 600; }
+main { flex: 1 1 auto; min-width: 0; max-width: 900px; padding: 32px 48px 96px; }
+.toc { position: sticky; top: 0; flex: 0 0 220px; padding: 32px 16px; font-size: 13px; }
+.toc ul, .toc-block ul { list-style: none; margin: 0; padding: 0; }
+.toc a, .toc-block a { color: var(--muted); text-decoration: none; }
+.toc-title { margin: 0 0 8px; font-weight: 600; color: var(--muted); }
+.toc-h2 { padding-left: 12px; }
+.toc-h3 { padding-left: 24px; }
+.breadcrumb { font-size: 14px; color: var(--muted); margin-bottom: 16px; }
+.breadcrumb a { color: var(--muted); text-decoration: none; }
+.page-title { font-size: 2.4em; margin: 0 0 16px; }
+.page-icon { margin-right: 8px; }
+img.page-icon { width: 1em; height: 1em; vertical-align: -0.1em; }
+h1, h2, h3 { line-height: 1.3; margin: 1.6em 0 0.4em; }
+summary h1, summary h2, summary h3 { display: inline; }
+p { margin: 0.4em 0; }
+.indented { padding-left: 24px; }
+blockquote { margin: 0.6em 0; padding: 0 14px; border-left: 3px solid currentColor; }
+.callout { display: flex; gap: 10px; margin: 0.6em 0; padding: 14px 16px; border-radius: 4px; }
+.callout-icon { flex: 0 0 auto; }
+img.callout-icon { width: 1.4em; height: 1.4em; }
+.callout-body { flex: 1 1 auto; min-width: 0; }
+ul.todo { list-style: none; padding-left: 4px; }
+figure { margin: 0.8em 0; }
+figcaption { font-size: 14px; color: var(--muted); margin-top: 4px; }
+img, video { max-width: 100%; }
+code { background: var(--code); border-radius: 3px; padding: 0.15em 0.3em; font-size: 85%; font-family: SFMono-Regular, Menlo, Consolas, monospace; color: #eb5757; }
+pre { background: var(--code); border-radius: 4px; padding: 16px; overflow-x: auto; }
+pre code { background: none; padding: 0; color: var(--text); font-size: 14px; }
+.tok-keyword { color: #d73a49; }
+.tok-string { color: #22863a; }
+.tok-comment { color: #6a737d; font-style: italic; }
+.tok-number { color: #005cc5; }
+.equation { text-align: center; margin: 0.8em 0; }
+.equation code, code.equation { color: var(--text); }
+hr { border: 0; border-top: 1px solid var(--border); margin: 1.2em 0; }
+.table-wrap { overflow-x: auto; margin: 0.8em 0; }
+table { border-collapse: collapse; font-size: 14px; }
+th, td { border: 1px solid var(--border); padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: #f7f6f3; }
+.columns { display: flex; gap: 24px; }
+.column { flex: 1 1 0; min-width: 0; }
+details > summary { cursor: pointer; }
+.color-gray { color: #787774; } .color-brown { color: #9f6b53; } .color-orange { color: #d9730d; }
+.color-yellow { color: #cb912f; } .color-green { color: #448361; } .color-blue { color: #337ea9; }
+.color-purple { color: #9065b0; } .color-pink { color: #c14c8a; } .color-red { color: #d44c47; }
+.bg-gray { background: #f1f1ef; } .bg-brown { background: #f4eeee; } .bg-orange { background: #fbecdd; }
+.bg-yellow { background: #fbf3db; } .bg-green { background: #edf3ec; } .bg-blue { background: #e7f3f8; }
+.bg-purple { background: rgba(244, 240, 247, 0.8); } .bg-pink { background: rgba(249, 238, 243, 0.8); } .bg-red { background: #fdebec; }
+@media (max-width: 1100px) { .toc { display: none; } }
+@media (max-width: 760px) { .layout { display: block; } .sidebar { position: static; height: auto; border-right: 0; border-bottom: 1px solid var(--border); } main { padding: 24px 16px 64px; } }
+@media print { .sidebar, .toc { display: none; } }
+`
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+func TestHTMLRenderer_Blocks(t *testing.T) {
+	rt := func(text string) map[string]interface{} {
+		return map[string]interface{}{"rich_text": []interface{}{mdText(text, nil)}}
+	}
+	row := func(cells ...string) exportBlock {
+		var items []interface{}
+		for _, cell := range cells {
+			items = append(items, []interface{}{mdText(cell, nil)})
+		}
+		return exportBlock{Type: "table_row", Content: map[string]interface{}{"cells": items}}
+	}
+	blocks := []exportBlock{
+		{Type: "table_of_contents", Content: map[string]interface{}{}},
+		{ID: "h1", Type: "heading_1", Content: rt("Intro & Setup")},
+		{Type: "bulleted_list_item", Content: rt("one"), Children: []exportBlock{
+			{Type: "numbered_list_item", Content: rt("nested")},
+		}},
+		{Type: "bulleted_list_item", Content: rt("two")},
+		{Type: "to_do", Content: map[string]interface{}{"rich_text": []interface{}{mdText("done", nil)}, "checked": true}},
+		{Type: "callout", Content: map[string]interface{}{
+			"rich_text": []interface{}{mdText("Note", nil)},
+			"icon":      map[string]interface{}{"type": "emoji", "emoji": "💡"},
+			"color":     "blue_background",
+		}},
+		{Type: "code", Content: map[string]interface{}{
+			"rich_text": []interface{}{mdText(`return "<b>" // done`, nil)},
+			"language":  "go",
+		}},
+		{Type: "table", Content: map[string]interface{}{"table_width": 2, "has_column_header": true}, Children: []exportBlock{
+			row("Name", "Role"),
+			row("Ada", "Eng"),
+		}},
+		{ID: "h2", Type: "heading_2", Content: rt("Intro & Setup")},
+	}
+
+	h := newHTMLRenderer()
+	got := h.render(blocks)
+	want := strings.Join([]string{
+		`<nav class="toc-block">`,
+		`<ul>`,
+		`<li class="toc-h1"><a href="#intro-setup">Intro &amp; Setup</a></li>`,
+		`<li class="toc-h2"><a href="#intro-setup-1">Intro &amp; Setup</a></li>`,
+		`</ul>`,
+		`</nav>`,
+		`<h1 id="intro-setup">Intro &amp; Setup</h1>`,
+		`<ul>`,
+		`<li>one`,
+		`<ol>`,
+		`<li>nested</li>`,
+		`</ol>`,
+		`</li>`,
+		`<li>two</li>`,
+		`</ul>`,
+		`<ul class="todo">`,
+		`<li><input type="checkbox" disabled checked> done</li>`,
+		`</ul>`,
+		`<aside class="callout bg-blue"><span class="callout-icon">💡</span><div class="callout-body"><p>Note</p>`,
+		`</div></aside>`,
+		`<figure class="code"><pre><code class="language-go"><span class="tok-keyword">return</span> <span class="tok-string">&#34;&lt;b&gt;&#34;</span> <span class="tok-comment">// done</span></code></pre></figure>`,
+		`<div class="table-wrap"><table>`,
+		`<thead>`,
+		`<tr><th>Name</th><th>Role</th></tr>`,
+		`</thead>`,
+		`<tr><td>Ada</td><td>Eng</td></tr>`,
+		`</table></div>`,
+		`<h2 id="intro-setup-1">Intro &amp; Setup</h2>`,
+		``,
+	}, "\n")
+	if got != want {
+		t.Errorf("render:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHTMLRenderer_RichText(t *testing.T) {
+	items := notion.DecodeRichText([]interface{}{
+		mdText("bold", map[string]interface{}{"annotations": map[string]interface{}{"bold": true, "color": "red"}}),
+		mdText(" <tag> ", nil),
+		mdText("link", map[string]interface{}{"href": "https://example.invalid/?a=1&b=2"}),
+		map[string]interface{}{"type": "equation", "plain_text": "x", "equation": map[string]interface{}{"expression": "x<1"}},
+		map[string]interface{}{"type": "mention", "plain_text": "Roadmap", "mention": map[string]interface{}{"type": "page", "page": map[string]interface{}{"id": "abcd"}}},
+	})
+
+	h := newHTMLRenderer()
+	h.pageLink = func(id string) string {
+		if id == "abcd" {
+			return "Roadmap.html"
+		}
+		return ""
+	}
+	got := h.richText(items)
+	want := `<span class="color-red"><strong>bold</strong></span> &lt;tag&gt; ` +
+		`<a href="https://example.invalid/?a=1&amp;b=2">link</a>` +
+		`<code class="equation">x&lt;1</code>` +
+		`<a href="Roadmap.html">Roadmap</a>`
+	if got != want {
+		t.Errorf("richText:\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestHTMLRenderer_UnsafeURLs(t *testing.T) {
+	blocks := []exportBlock{
+		{Type: "paragraph", Content: map[string]interface{}{"rich_text": []interface{}{
+			mdText("bad", map[string]interface{}{"href": "javascript:alert(1)"}),
+			mdText(" ", nil),
+			mdText("mail", map[string]interface{}{"href": "mailto:ada@example.invalid"}),
+		}}},
+		{Type: "bookmark", Content: map[string]interface{}{"url": " JavaScript:alert(1)", "caption": []interface{}{}}},
+		{Type: "image", Content: map[string]interface{}{"type": "external", "external": map[string]interface{}{"url": "data:image/svg+xml,<svg/onload=alert(1)>"}, "caption": []interface{}{}}},
+	}
+	got := newHTMLRenderer().render(blocks)
+	want := strings.Join([]string{
+		`<p>bad <a href="mailto:ada@example.invalid">mail</a></p>`,
+		`<p class="bookmark"> JavaScript:alert(1)</p>`,
+		`<figure class="image"><img src="#ZgotmplZ" alt="" loading="lazy"></figure>`,
+		``,
+	}, "\n")
+	if got != want {
+		t.Errorf("render:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	icon := map[string]interface{}{"type": "external", "external": map[string]interface{}{"url": "javascript:alert(1)"}}
+	if got := htmlIcon(icon, "page-icon"); got != `<img class="page-icon" src="#ZgotmplZ" alt="">` {
+		t.Errorf("htmlIcon = %s", got)
+	}
+	for url, safe := range map[string]bool{
+		"https://example.invalid/a": true,
+		"HTTP://example.invalid":    true,
+		"../Other.html#top":         true,
+		"#anchor":                   true,
+		"javascript:alert(1)":       false,
+		"java\tscript:alert(1)":     false,
+		"vbscript:x":                false,
+	} {
+		if got := safeHTMLURL(url); got != safe {
+			t.Errorf("safeHTMLURL(%q) = %v, want %v", url, got, safe)
+		}
+	}
+}
+
+func TestSiteBuild(t *testing.T) {
+	tree := newPageTreeServer(t)
+
+	dir := t.TempDir()
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"site", "build", tree.rootID, "--out", dir})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("site build failed: %v\nstderr=%s", err, errBuf.String())
+	}
+
+	read := func(rel string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		return string(data)
+	}
+
+	if index := read("index.html"); !strings.Contains(index, `url=Handbook.html`) {
+		t.Errorf("index.html should open the root page:\n%s", index)
+	}
+
+	rootPage := read("Handbook.html")
+	for _, want := range []string{
+		`<title>Handbook</title>`,
+		`<nav class="sidebar">`,
+		`<li><a href="Handbook.html" class="current" aria-current="page">Handbook</a>`,
+		`<li><a href="Handbook/Tasks/Write%20docs.html">Write docs</a></li>`,
+		`<a href="Handbook/Setup%20-%20Install.html">Setup</a>`,
+		`<img src="Handbook/chart.png" alt="" loading="lazy">`,
+	} {
+		if !strings.Contains(rootPage, want) {
+			t.Errorf("Handbook.html missing %q:\n%s", want, rootPage)
+		}
+	}
+
+	child := read("Handbook/Setup - Install.html")
+	for _, want := range []string{
+		`<nav class="breadcrumb"><a href="../Handbook.html">Handbook</a> / <span>Setup / Install</span></nav>`,
+		`Back <a href="../Handbook.html">home</a>`,
+	} {
+		if !strings.Contains(child, want) {
+			t.Errorf("child page missing %q:\n%s", want, child)
+		}
+	}
+
+	database := read("Handbook/Tasks.html")
+	if !strings.Contains(database, `<td><a href="Tasks/Write%20docs.html">Write docs</a></td>`) {
+		t.Errorf("database table should link its rows:\n%s", database)
+	}
+	if !strings.Contains(errBuf.String(), "Built site with 4 pages and 1 files") {
+		t.Errorf("summary = %q", errBuf.String())
+	}
+}
@@ -0,0 +1,594 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// markdownFallback selects how the exporter renders what Markdown cannot
+// express: blocks without a Markdown form, and underline and colour
+// annotations.
+type markdownFallback string
+
+const (
+	// markdownFallbackComment keeps unsupported blocks as HTML comments and
+	// renders underline and colours as inline HTML.
+	markdownFallbackComment markdownFallback = "comment"
+	// markdownFallbackDrop leaves them out, producing plain Markdown.
+	markdownFallbackDrop markdownFallback = "drop"
+)
+
+func parseMarkdownFallback(value string) (markdownFallback, error) {
+	switch markdownFallback(strings.ToLower(strings.TrimSpace(value))) {
+	case markdownFallbackComment, "":
+		return markdownFallbackComment, nil
+	case markdownFallbackDrop:
+		return markdownFallbackDrop, nil
+	}
+	return "", fmt.Errorf("invalid --fallback %q (expected comment or drop)", value)
+}
+
+// notionPageURL is the web URL of a page or database, used for links to
+// child pages and mentions that carry no href.
+func notionPageURL(id string) string {
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
+
+// markdownRenderer renders exported blocks as Markdown.
+type markdownRenderer struct {
+	fallback markdownFallback
+	// headings are the page's headings, for table_of_contents blocks.
+	headings []exportBlock
+	// pageLink maps a page or database ID to a local link target when the
+	// page is exported alongside this one, returning "" otherwise.
+	pageLink func(id string) string
+}
+
+func newMarkdownRenderer(fallback markdownFallback) *markdownRenderer {
+	return &markdownRenderer{fallback: fallback}
+}
+
+// pageURL is the link target of a page or database: its exported file when
+// there is one, else its Notion URL.
+func (m *markdownRenderer) pageURL(id string) string {
+	return linkedPageURL(m.pageLink, id, "")
+}
+
+// renderMarkdown renders blocks with the default options.
+func renderMarkdown(blocks []exportBlock, indent int) string {
+	return newMarkdownRenderer(markdownFallbackComment).render(blocks, indent)
+}
+
+func renderBlockMarkdown(block exportBlock, indent int) []string {
+	return newMarkdownRenderer(markdownFallbackComment).block(block, indent)
+}
+
+// richTextMarkdown converts rich text to Markdown with the default options.
+func richTextMarkdown(items []notion.RichText) string {
+	return newMarkdownRenderer(markdownFallbackComment).richText(items)
+}
+
+// richTextToMarkdown converts a Notion rich_text array ([]interface{}) to a
+// Markdown string.
+func richTextToMarkdown(items []interface{}) string {
+	return richTextMarkdown(notion.DecodeRichText(items))
+}
+
+func (m *markdownRenderer) render(blocks []exportBlock, indent int) string {
+	m.headings = collectHeadings(blocks, m.headings[:0])
+	return strings.TrimRight(strings.Join(m.lines(blocks, indent), "\n"), "\n")
+}
+
+func collectHeadings(blocks []exportBlock, into []exportBlock) []exportBlock {
+	for _, block := range blocks {
+		if strings.HasPrefix(block.Type, "heading_") {
+			into = append(into, block)
+		}
+		into = collectHeadings(block.Children, into)
+	}
+	return into
+}
+
+func (m *markdownRenderer) lines(blocks []exportBlock, indent int) []string {
+	var lines []string
+	for _, block := range blocks {
+		rendered := m.block(block, indent)
+		if len(rendered) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, rendered...)
+	}
+	return lines
+}
+
+// unsupported renders a block without a Markdown form, keeping its children.
+func (m *markdownRenderer) unsupported(block exportBlock, indent int, what string) []string {
+	var lines []string
+	if m.fallback == markdownFallbackComment {
+		lines = append(lines, strings.Repeat("  ", indent)+"<!-- "+what+" -->")
+	}
+	if len(block.Children) > 0 {
+		lines = append(lines, m.lines(block.Children, indent+1)...)
+	}
+	return lines
+}
+
+// withChildren appends the children of block, indented one level. Nested
+// lists follow their parent directly; anything else is separated by a blank
+// line so it is not read as a continuation of the parent's text.
+func (m *markdownRenderer) withChildren(lines []string, block exportBlock, indent int) []string {
+	if len(block.Children) == 0 {
+		return lines
+	}
+	if block.Type == "paragraph" || !isListBlockType(block.Children[0].Type) {
+		lines = append(lines, "")
+	}
+	return append(lines, m.lines(block.Children, indent+1)...)
+}
+
+func isListBlockType(blockType string) bool {
+	return blockType == "bulleted_list_item" || blockType == "numbered_list_item" || blockType == "to_do"
+}
+
+func (m *markdownRenderer) block(block exportBlock, indent int) []string {
+	prefix := strings.Repeat("  ", indent)
+	switch content := block.typedContent().(type) {
+	case *notion.ParagraphBlock:
+		return m.withChildren(prefixed(prefix, prefix, m.richText(content.RichText)), block, indent)
+	case *notion.HeadingBlock:
+		level := strings.TrimPrefix(block.Type, "heading_")
+		hashes := map[string]string{"1": "# ", "2": "## ", "3": "### "}[level]
+		lines := []string{prefix + hashes + strings.ReplaceAll(m.richText(content.RichText), "\n", " ")}
+		if len(block.Children) > 0 {
+			lines = append(lines, "")
+			lines = append(lines, m.lines(block.Children, indent)...)
+		}
+		return lines
+	case *notion.ListItemBlock:
+		marker := "- "
+		if block.Type == "numbered_list_item" {
+			marker = "1. "
+		}
+		continuation := prefix + strings.Repeat(" ", len(marker))
+		return m.withChildren(prefixed(prefix+marker, continuation, m.richText(content.RichText)), block, indent)
+	case *notion.ToDoBlock:
+		box := " "
+		if content.Checked {
+			box = "x"
+		}
+		return m.withChildren(prefixed(prefix+"- ["+box+"] ", prefix+"      ", m.richText(content.RichText)), block, indent)
+	case *notion.ToggleBlock:
+		return m.toggle(block, m.richText(content.RichText), indent)
+	case *notion.QuoteBlock:
+		return m.quoted(prefix, m.richText(content.RichText), block)
+	case *notion.CalloutBlock:
+		text := m.richText(content.RichText)
+		if emoji, ok := content.Icon["emoji"].(string); ok && emoji != "" {
+			text = emoji + " " + text
+		}
+		return m.quoted(prefix, text, block)
+	case *notion.CodeBlock:
+		return m.code(content, prefix)
+	case *notion.EquationBlock:
+		return []string{prefix + "$$", prefix + content.Expression, prefix + "$$"}
+	case *notion.DividerBlock:
+		return []string{prefix + "---"}
+	case *notion.FileBlock:
+		return m.file(block, content, indent)
+	case *notion.BookmarkBlock:
+		return []string{prefix + markdownLink(m.richTextOr(content.Caption, content.URL), content.URL)}
+	case *notion.EmbedBlock:
+		return []string{prefix + markdownLink(m.richTextOr(content.Caption, content.URL), content.URL)}
+	case *notion.LinkPreviewBlock:
+		return []string{prefix + "<" + content.URL + ">"}
+	case *notion.TableBlock:
+		return m.table(block, content, prefix)
+	case *notion.TableRowBlock:
+		// table_row is rendered by its table; standalone rendering shouldn't happen
+		return nil
+	case *notion.ChildPageBlock:
+		return []string{prefix + markdownLink("📄 "+content.Title, m.pageURL(block.ID))}
+	case *notion.ChildDatabaseBlock:
+		return []string{prefix + markdownLink("🗃️ "+content.Title, m.pageURL(block.ID))}
+	case *notion.LinkToPageBlock:
+		id := content.PageID
+		if id == "" {
+			id = content.DatabaseID
+		}
+		return []string{prefix + markdownLink("↗ Linked page", m.pageURL(id))}
+	case *notion.SyncedBlock, *notion.ColumnListBlock, *notion.ColumnBlock:
+		// Containers: their content is the children, shown in place.
+		return m.lines(block.Children, indent)
+	case *notion.TemplateBlock:
+		return m.unsupported(block, indent, "template: "+notion.PlainText(content.RichText))
+	case *notion.TableOfContentsBlock:
+		return m.tableOfContents(prefix)
+	case *notion.BreadcrumbBlock:
+		return m.unsupported(block, indent, "breadcrumb")
+	}
+	return m.unsupported(block, indent, "unsupported block type: "+block.Type)
+}
+
+// toggle renders a toggle as an HTML <details> element, which keeps it
+// collapsible on GitHub. With the drop fallback it becomes a list item.
+func (m *markdownRenderer) toggle(block exportBlock, summary string, indent int) []string {
+	prefix := strings.Repeat("  ", indent)
+	if m.fallback == markdownFallbackDrop {
+		return m.withChildren(prefixed(prefix+"- ", prefix+"  ", summary), block, indent)
+	}
+	lines := []string{prefix + "<details>", prefix + "<summary>" + strings.ReplaceAll(summary, "\n", " ") + "</summary>"}
+	if len(block.Children) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, m.lines(block.Children, indent)...)
+		lines = append(lines, "")
+	}
+	return append(lines, prefix+"</details>")
+}
+
+func (m *markdownRenderer) quoted(prefix, text string, block exportBlock) []string {
+	lines := prefixed(prefix+"> ", prefix+"> ", text)
+	for _, childLine := range m.lines(block.Children, 0) {
+		lines = append(lines, strings.TrimRight(prefix+"> "+childLine, " "))
+	}
+	return lines
+}
+
+func (m *markdownRenderer) code(content *notion.CodeBlock, prefix string) []string {
+	source := notion.PlainText(content.RichText)
+	fence := "```"
+	for strings.Contains(source, fence) {
+		fence += "`"
+	}
+	language := content.Language
+	if language == "plain text" {
+		language = ""
+	}
+	lines := []string{prefix + fence + language}
+	lines = append(lines, prefixed(prefix, prefix, source)...)
+	lines = append(lines, prefix+fence)
+	if caption := m.richText(content.Caption); caption != "" {
+		lines = append(lines, prefix+"*"+caption+"*")
+	}
+	return lines
+}
+
+// file renders images inline and other files (pdf, video, audio, file) as
+// links. Uploaded files have no URL and fall back.
+func (m *markdownRenderer) file(block exportBlock, content *notion.FileBlock, indent int) []string {
+	prefix := strings.Repeat("  ", indent)
+	url := content.URL()
+	caption := m.richText(content.Caption)
+	if url == "" {
+		return m.unsupported(block, indent, block.Type+" without a URL")
+	}
+	if block.Type == "image" {
+		return []string{prefix + "![" + caption + "](" + url + ")"}
+	}
+	label := caption
+	if label == "" {
+		label = content.Name
+	}
+	if label == "" {
+		label = block.Type
+	}
+	return []string{prefix + markdownLink(label, url)}
+}
+
+// table renders a table block with its table_row children as a pipe table.
+// Markdown tables always have a header, so the first row is used as one.
+func (m *markdownRenderer) table(block exportBlock, _ *notion.TableBlock, prefix string) []string {
+	if len(block.Children) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for i, row := range block.Children {
+		var cells []string
+		if content, ok := row.typedContent().(*notion.TableRowBlock); ok {
+			cells = make([]string, len(content.Cells))
+			for j, cell := range content.Cells {
+				cells[j] = markdownCell(m.richText(cell))
+			}
+		}
+		lines = append(lines, prefix+"| "+strings.Join(cells, " | ")+" |")
+
+		// Add separator after first row (markdown table spec requires it)
+		if i == 0 {
+			seps := make([]string, len(cells))
+			for j := range seps {
+				seps[j] = "---"
+			}
+			lines = append(lines, prefix+"| "+strings.Join(seps, " | ")+" |")
+		}
+	}
+	return lines
+}
+
+// markdownCell escapes the pipes richText left unescaped, in code spans,
+// and turns line breaks into <br>.
+func markdownCell(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch ch := text[i]; {
+		case ch == '\\' && i+1 < len(text):
+			b.WriteString(text[i : i+2])
+			i++
+		case ch == '|':
+			b.WriteString(`\|`)
+		case ch == '\n':
+			b.WriteString("<br>")
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// tableOfContents renders the page headings as a nested list of anchor
+// links, using GitHub's heading anchors.
+func (m *markdownRenderer) tableOfContents(prefix string) []string {
+	var lines []string
+	seen := map[string]int{}
+	for _, heading := range m.headings {
+		content, ok := heading.typedContent().(*notion.HeadingBlock)
+		if !ok {
+			continue
+		}
+		text := notion.PlainText(content.RichText)
+		level := int(heading.Type[len(heading.Type)-1] - '1')
+		anchor := headingAnchor(text)
+		if n := seen[anchor]; n > 0 {
+			seen[anchor]++
+			anchor = fmt.Sprintf("%s-%d", anchor, n)
+		} else {
+			seen[anchor] = 1
+		}
+		lines = append(lines, prefix+strings.Repeat("  ", level)+"- ["+text+"](#"+anchor+")")
+	}
+	return lines
+}
+
+var anchorStrip = regexp.MustCompile(`[^\p{L}\p{N}\s_-]`)
+
+func headingAnchor(text string) string {
+	anchor := anchorStrip.ReplaceAllString(strings.ToLower(strings.TrimSpace(text)), "")
+	return strings.Join(strings.Fields(anchor), "-")
+}
+
+// prefixed splits text into lines, prefixing the first with first and the
+// rest with rest, so multi-line text stays inside its list item or quote.
+func prefixed(first, rest, text string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		p := rest
+		if i == 0 {
+			p = first
+		}
+		if line == "" {
+			lines[i] = strings.TrimRight(p, " ")
+		} else {
+			lines[i] = p + line
+		}
+	}
+	return lines
+}
+
+func markdownLink(text, url string) string {
+	if text == "" {
+		text = url
+	}
+	return "[" + text + "](" + url + ")"
+}
+
+// richTextOr renders items, or returns fallback when they are empty.
+func (m *markdownRenderer) richTextOr(items []notion.RichText, fallback string) string {
+	if text := m.richText(items); text != "" {
+		return text
+	}
+	return fallback
+}
+
+// markdownEscaper backslash-escapes the characters of plain text that
+// Markdown would read as formatting.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`,
+	"<", `\<`, "$", `\$`, "~", `\~`, "|", `\|`,
+)
+
+// lineStartMarker matches the start of a line that Markdown would read as
+// a heading, list item, quote, thematic break or setext underline.
+var lineStartMarker = regexp.MustCompile(`(?m)^[ \t]*(?:>|(?:#{1,6}|[-+]|\d{1,9}[.)])(?:[ \t]|$)|[-=]+[ \t]*$)`)
+
+// escapeLineStarts escapes the block markers that start lines of text:
+// "# x" becomes "\# x" and "1. x" becomes "1\. x". The start of text only
+// counts as a line start when atStart is set.
+func escapeLineStarts(text string, atStart bool) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range lineStartMarker.FindAllStringIndex(text, -1) {
+		if loc[0] == 0 && !atStart {
+			continue
+		}
+		match := text[loc[0]:loc[1]]
+		i := len(match) - len(strings.TrimLeft(match, " \t"))
+		if j := strings.IndexAny(match, ".)"); j >= 0 {
+			i = j
+		}
+		b.WriteString(text[last : loc[0]+i])
+		b.WriteByte('\\')
+		last = loc[0] + i
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// richText converts rich text to Markdown, preserving bold, italic, code,
+// strikethrough and links. Mentions of pages and databases become links,
+// date mentions their dates, and inline equations $...$. Underline and
+// colours are kept as inline HTML unless the fallback drops them. Plain
+// text is escaped so it reads back as the same text.
+func (m *markdownRenderer) richText(items []notion.RichText) string {
+	var b strings.Builder
+	for _, item := range items {
+		text := item.PlainText
+		if text == "" && item.Text != nil {
+			text = item.Text.Content
+		}
+
+		linkURL := item.Href
+		if linkURL == "" && item.Text != nil && item.Text.Link != nil {
+			linkURL = item.Text.Link.URL
+		}
+
+		switch {
+		case item.Equation != nil:
+			text = "$" + item.Equation.Expression + "$"
+		case item.Mention != nil:
+			text, linkURL = mentionLink(item.Mention, text, linkURL, m.pageLink)
+		case linkURL != "":
+			linkURL = localNotionLink(m.pageLink, linkURL)
+		}
+		if text == "" {
+			continue
+		}
+
+		var ann notion.Annotations
+		if item.Annotations != nil {
+			ann = *item.Annotations
+		}
+
+		// Markdown emphasis cannot start or end with whitespace, so keep
+		// surrounding spaces outside the markers.
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" {
+			b.WriteString(text)
+			continue
+		}
+		lead := text[:strings.Index(text, trimmed)]
+		trail := text[len(lead)+len(trimmed):]
+
+		segment := trimmed
+		if ann.Code {
+			segment = "`" + segment + "`"
+		} else if item.Equation == nil {
+			before := b.String() + lead
+			line := before[strings.LastIndex(before, "\n")+1:]
+			segment = escapeLineStarts(markdownEscaper.Replace(segment), strings.TrimSpace(line) == "")
+		}
+		if linkURL != "" && item.Equation == nil {
+			segment = "[" + segment + "](" + linkURL + ")"
+		}
+		if ann.Strikethrough {
+			segment = "~~" + segment + "~~"
+		}
+		if ann.Bold && ann.Italic {
+			segment = "***" + segment + "***"
+		} else if ann.Bold {
+			segment = "**" + segment + "**"
+		} else if ann.Italic {
+			segment = "*" + segment + "*"
+		}
+		if m.fallback == markdownFallbackComment {
+			if ann.Underline {
+				segment = "<u>" + segment + "</u>"
+			}
+			segment = colorSpan(segment, ann.Color)
+		}
+
+		b.WriteString(lead + segment + trail)
+	}
+
+	return b.String()
+}
+
+// mentionLink returns the text and link of a mention: pages and databases
+// link to their exported file (see pageLink) or Notion, dates show their
+// range, and users keep their @name.
+func mentionLink(mention *notion.Mention, text, href string, pageLink func(id string) string) (string, string) {
+	switch mention.Type {
+	case "page":
+		if mention.Page != nil {
+			href = linkedPageURL(pageLink, mention.Page.ID, href)
+		}
+		return text, href
+	case "database":
+		if mention.Database != nil {
+			href = linkedPageURL(pageLink, mention.Database.ID, href)
+		}
+		return text, href
+	case "date":
+		if mention.Date != nil && mention.Date.Start != "" {
+			text = mention.Date.Start
+			if mention.Date.End != nil && *mention.Date.End != "" {
+				text += " → " + *mention.Date.End
+			}
+		}
+		return text, ""
+	case "link_preview":
+		if mention.LinkPreview != nil {
+			return text, mention.LinkPreview.URL
+		}
+	case "user":
+		if !strings.HasPrefix(text, "@") {
+			text = "@" + text
+		}
+		return text, ""
+	}
+	return text, href
+}
+
+// linkedPageURL links to a page or database: its exported file when
+// pageLink knows one, else href, else its Notion URL.
+func linkedPageURL(pageLink func(id string) string, id, href string) string {
+	if pageLink != nil {
+		if target := pageLink(id); target != "" {
+			return target
+		}
+	}
+	if href == "" {
+		href = notionPageURL(id)
+	}
+	return href
+}
+
+// localNotionLink points a link to an exported Notion page at its file,
+// leaving other links alone.
+func localNotionLink(pageLink func(id string) string, url string) string {
+	if pageLink == nil {
+		return url
+	}
+	if id := notionLinkID(url); id != "" {
+		if target := pageLink(id); target != "" {
+			return target
+		}
+	}
+	return url
+}
+
+var notionLinkIDPattern = regexp.MustCompile(`(?i)^(?:https?://(?:www\.)?notion\.(?:so|site)/|https?://[a-z0-9-]+\.notion\.site/|/)(?:[^?#]*[/-])?([0-9a-f]{32})(?:[?#].*)?$`)
+
+// notionLinkID returns the page ID of a link to a Notion page, such as
+// the "/<id>" links the API gives for inline page links, or "".
+func notionLinkID(url string) string {
+	if match := notionLinkIDPattern.FindStringSubmatch(url); match != nil {
+		return strings.ToLower(match[1])
+	}
+	return ""
+}
+
+// colorSpan wraps text in a span carrying a Notion text or background colour.
+func colorSpan(text, color string) string {
+	if color == "" || color == "default" {
+		return text
+	}
+	if name, ok := strings.CutSuffix(color, "_background"); ok {
+		return `<span style="background-color: ` + name + `">` + text + "</span>"
+	}
+	return `<span style="color: ` + color + `">` + text + "</span>"
+}
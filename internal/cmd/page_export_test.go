@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
 )
 
 func TestRichTextToMarkdown(t *testing.T) {
@@ -435,3 +438,202 @@ func TestMarkdownRoundTrip_FormattingPreserved(t *testing.T) {
 		t.Errorf("block 2: expected paragraph, got %s", reparsed[2]["type"])
 	}
 }
+
+type fakeBlockTreeReader struct {
+	blocks   []notion.Block
+	gotDepth int
+	gotOpts  *notion.BlockChildrenOptions
+}
+
+func (f *fakeBlockTreeReader) GetBlockChildrenRecursive(_ context.Context, _ string, depth int, opts *notion.BlockChildrenOptions) ([]notion.Block, error) {
+	f.gotDepth = depth
+	f.gotOpts = opts
+	return f.blocks, nil
+}
+
+func TestFetchExportBlocks_UsesRecursiveFetch(t *testing.T) {
+	reader := &fakeBlockTreeReader{
+		blocks: []notion.Block{
+			{
+				ID:          "toggle-1",
+				Type:        "toggle",
+				HasChildren: true,
+				Content:     map[string]interface{}{"rich_text": []interface{}{}},
+				Children: []notion.Block{
+					{ID: "para-1", Type: "paragraph", Content: map[string]interface{}{"rich_text": []interface{}{}}},
+				},
+			},
+			{ID: "divider-1", Type: "divider"},
+		},
+	}
+
+	blocks, err := fetchExportBlocks(context.Background(), reader, "page-1", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reader.gotDepth != fullBlockTreeDepth {
+		t.Errorf("depth = %d, want full tree", reader.gotDepth)
+	}
+	if reader.gotOpts == nil || reader.gotOpts.Concurrency != 7 || reader.gotOpts.PageSize != 100 {
+		t.Errorf("unexpected options: %+v", reader.gotOpts)
+	}
+	if len(blocks) != 2 || blocks[0].ID != "toggle-1" || blocks[1].ID != "divider-1" {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+	if len(blocks[0].Children) != 1 || blocks[0].Children[0].ID != "para-1" {
+		t.Fatalf("unexpected children: %+v", blocks[0].Children)
+	}
+}
+
+func mdText(text string, extra map[string]interface{}) map[string]interface{} {
+	item := map[string]interface{}{"type": "text", "plain_text": text, "text": map[string]interface{}{"content": text}}
+	for k, v := range extra {
+		item[k] = v
+	}
+	return item
+}
+
+func TestRichTextMarkdown_AnnotationsAndMentions(t *testing.T) {
+	items := notion.DecodeRichText([]interface{}{
+		mdText("under", map[string]interface{}{"annotations": map[string]interface{}{"underline": true}}),
+		mdText(" ", nil),
+		mdText("red ", map[string]interface{}{"annotations": map[string]interface{}{"bold": true, "color": "red"}}),
+		map[string]interface{}{"type": "equation", "plain_text": "E=mc^2", "equation": map[string]interface{}{"expression": "E=mc^2"}},
+		mdText(" by ", nil),
+		map[string]interface{}{"type": "mention", "plain_text": "@Ada", "mention": map[string]interface{}{"type": "user", "user": map[string]interface{}{"id": "u1"}}},
+		mdText(" in ", nil),
+		map[string]interface{}{"type": "mention", "plain_text": "Roadmap", "mention": map[string]interface{}{"type": "page", "page": map[string]interface{}{"id": "1234-abcd"}}},
+		mdText(" on ", nil),
+		map[string]interface{}{"type": "mention", "plain_text": "January 15, 2024", "mention": map[string]interface{}{"type": "date", "date": map[string]interface{}{"start": "2024-01-15", "end": "2024-01-16"}}},
+	})
+
+	got := newMarkdownRenderer(markdownFallbackComment).richText(items)
+	want := `<u>under</u> <span style="color: red">**red**</span> $E=mc^2$ by @Ada in [Roadmap](` + notionPageURL("1234-abcd") + `) on 2024-01-15 → 2024-01-16`
+	if got != want {
+		t.Errorf("comment fallback:\ngot:  %s\nwant: %s", got, want)
+	}
+
+	got = newMarkdownRenderer(markdownFallbackDrop).richText(items)
+	want = `under **red** $E=mc^2$ by @Ada in [Roadmap](` + notionPageURL("1234-abcd") + `) on 2024-01-15 → 2024-01-16`
+	if got != want {
+		t.Errorf("drop fallback:\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestRenderMarkdown_AllBlockTypes(t *testing.T) {
+	rt := func(text string) map[string]interface{} {
+		return map[string]interface{}{"rich_text": []interface{}{mdText(text, nil)}}
+	}
+	blocks := []exportBlock{
+		{Type: "table_of_contents", Content: map[string]interface{}{}},
+		{Type: "heading_1", Content: rt("Intro")},
+		{Type: "bookmark", Content: map[string]interface{}{"url": "https://example.com"}},
+		{Type: "embed", Content: map[string]interface{}{"url": "https://example.com/e", "caption": []interface{}{mdText("Demo", nil)}}},
+		{Type: "equation", Content: map[string]interface{}{"expression": "a^2+b^2"}},
+		{Type: "pdf", Content: map[string]interface{}{"type": "external", "external": map[string]interface{}{"url": "https://example.com/a.pdf"}, "name": "a.pdf"}},
+		{Type: "video", Content: map[string]interface{}{"type": "file_upload", "file_upload": map[string]interface{}{"id": "fu"}}},
+		{Type: "column_list", Content: map[string]interface{}{}, Children: []exportBlock{
+			{Type: "column", Content: map[string]interface{}{}, Children: []exportBlock{{Type: "paragraph", Content: rt("Left")}}},
+			{Type: "column", Content: map[string]interface{}{}, Children: []exportBlock{{Type: "paragraph", Content: rt("Right")}}},
+		}},
+		{Type: "synced_block", Content: map[string]interface{}{"synced_from": nil}, Children: []exportBlock{{Type: "paragraph", Content: rt("Synced")}}},
+		{ID: "aaaa-bbbb", Type: "child_page", Content: map[string]interface{}{"title": "Sub"}},
+		{Type: "link_to_page", Content: map[string]interface{}{"type": "page_id", "page_id": "cccc-dddd"}},
+		{Type: "toggle", Content: rt("More"), Children: []exportBlock{{Type: "paragraph", Content: rt("Inside")}}},
+		{Type: "breadcrumb", Content: map[string]interface{}{}},
+		{Type: "heading_2", Content: rt("Intro")},
+	}
+
+	got := newMarkdownRenderer(markdownFallbackComment).render(blocks, 0)
+	want := strings.Join([]string{
+		"- [Intro](#intro)",
+		"  - [Intro](#intro-1)",
+		"",
+		"# Intro",
+		"",
+		"[https://example.com](https://example.com)",
+		"",
+		"[Demo](https://example.com/e)",
+		"",
+		"$$",
+		"a^2+b^2",
+		"$$",
+		"",
+		"[a.pdf](https://example.com/a.pdf)",
+		"",
+		"<!-- video without a URL -->",
+		"",
+		"Left",
+		"",
+		"Right",
+		"",
+		"Synced",
+		"",
+		"[📄 Sub](" + notionPageURL("aaaa-bbbb") + ")",
+		"",
+		"[↗ Linked page](" + notionPageURL("cccc-dddd") + ")",
+		"",
+		"<details>",
+		"<summary>More</summary>",
+		"",
+		"Inside",
+		"",
+		"</details>",
+		"",
+		"<!-- breadcrumb -->",
+		"",
+		"## Intro",
+	}, "\n")
+	if got != want {
+		t.Errorf("comment fallback:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	dropped := newMarkdownRenderer(markdownFallbackDrop).render(blocks, 0)
+	if strings.Contains(dropped, "<!--") || strings.Contains(dropped, "<details>") {
+		t.Errorf("drop fallback kept HTML:\n%s", dropped)
+	}
+	if !strings.Contains(dropped, "- More\n\n  Inside") {
+		t.Errorf("drop fallback should render toggles as list items:\n%s", dropped)
+	}
+}
+
+func TestParseMarkdownFallback(t *testing.T) {
+	if f, err := parseMarkdownFallback(""); err != nil || f != markdownFallbackComment {
+		t.Errorf("default = %q, %v", f, err)
+	}
+	if f, err := parseMarkdownFallback("DROP"); err != nil || f != markdownFallbackDrop {
+		t.Errorf("drop = %q, %v", f, err)
+	}
+	if _, err := parseMarkdownFallback("html"); err == nil {
+		t.Error("expected an error for an unknown fallback")
+	}
+}
+
+func TestRenderMarkdown_NestedHeading(t *testing.T) {
+	rt := func(text string) map[string]interface{} {
+		return map[string]interface{}{"rich_text": []interface{}{mdText(text, nil)}}
+	}
+	blocks := []exportBlock{
+		{Type: "bulleted_list_item", Content: rt("Item"), Children: []exportBlock{
+			{Type: "heading_2", Content: rt("Details")},
+			{Type: "paragraph", Content: rt("Body")},
+		}},
+		{Type: "paragraph", Content: rt("After")},
+	}
+
+	md := renderMarkdown(blocks, 0)
+	if !strings.Contains(md, "\n  ## Details\n") {
+		t.Errorf("heading should be indented under its list item:\n%s", md)
+	}
+
+	reparsed := parseMarkdownToBlocks(md)
+	if len(reparsed) != 2 {
+		t.Fatalf("expected 2 top-level blocks after re-parse, got %d:\n%s", len(reparsed), md)
+	}
+	item := reparsed[0]["bulleted_list_item"].(map[string]interface{})
+	children, _ := item["children"].([]map[string]interface{})
+	if len(children) != 2 || children[0]["type"] != "heading_2" {
+		t.Errorf("heading should stay a child of the list item: %v", item["children"])
+	}
+}
@@ -0,0 +1,500 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// pageTreeReader describes the reads a recursive page export needs.
+type pageTreeReader interface {
+	blockTreeReader
+	databaseGetter
+	GetPage(ctx context.Context, pageID string) (*notion.Page, error)
+	QueryDataSource(ctx context.Context, dataSourceID string, req *notion.QueryDataSourceRequest) (*notion.DataSourceQueryResult, error)
+}
+
+var _ pageTreeReader = (*notion.Client)(nil)
+
+// exportManifestName is the manifest file written at the top of a recursive
+// export.
+const exportManifestName = "manifest.json"
+
+// exportFileTimeout bounds each download of a Notion-hosted file.
+const exportFileTimeout = 2 * time.Minute
+
+// exportManifest maps every exported page, database and file to its path,
+// relative to the export directory.
+type exportManifest struct {
+	Root       string               `json:"root"`
+	Format     string               `json:"format"`
+	ExportedAt string               `json:"exported_at"`
+	Pages      []exportManifestPage `json:"pages"`
+	Files      []exportManifestFile `json:"files"`
+}
+
+type exportManifestPage struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Path     string `json:"path"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+type exportManifestFile struct {
+	BlockID string `json:"block_id"`
+	PageID  string `json:"page_id"`
+	Path    string `json:"path"`
+}
+
+// exportNode is a page or database in a recursive export.
+type exportNode struct {
+	id       string
+	kind     string // "page" or "database"
+	title    string
+	path     string // file path relative to the export directory, slash-separated
+	parentID string
+	page     *notion.Page
+	database *notion.Database
+	blocks   []exportBlock
+	rows     []*exportNode
+	// names are the file names already used in this node's folder.
+	names map[string]bool
+}
+
+// folder is the directory holding the node's child pages and files.
+func (n *exportNode) folder() string {
+	return strings.TrimSuffix(n.path, path.Ext(n.path))
+}
+
+// treeExporter writes a page and everything beneath it (child pages,
+// child databases and their rows, and Notion-hosted files) to a directory,
+// rewriting links between exported pages into relative file links.
+type treeExporter struct {
+	client      pageTreeReader
+	dir         string
+	format      string // "markdown", "json" or "html"
+	fallback    markdownFallback
+	concurrency int
+	httpClient  *http.Client
+
+	nodes []*exportNode
+	byID  map[string]*exportNode
+	// childrenOf lists the pages and databases under each node, in page
+	// order, for site navigation.
+	childrenOf map[string][]*exportNode
+	rootNames  map[string]bool
+	manifest   exportManifest
+}
+
+func newTreeExporter(client pageTreeReader, dir, format string, fallback markdownFallback, concurrency int) *treeExporter {
+	return &treeExporter{
+		client:      client,
+		dir:         dir,
+		format:      format,
+		fallback:    fallback,
+		concurrency: concurrency,
+		httpClient:  &http.Client{Timeout: exportFileTimeout},
+		byID:        map[string]*exportNode{},
+		rootNames:   map[string]bool{},
+	}
+}
+
+// export walks the tree under rootID, writes every page and the manifest,
+// and returns the manifest.
+func (e *treeExporter) export(ctx context.Context, rootID string) (*exportManifest, error) {
+	e.manifest = exportManifest{
+		Root:       rootID,
+		Format:     e.format,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Pages:      []exportManifestPage{},
+		Files:      []exportManifestFile{},
+	}
+
+	root := &exportNode{id: rootID, kind: "page"}
+	if err := e.visitPage(ctx, root, e.rootNames, nil); err != nil {
+		return nil, err
+	}
+
+	e.childrenOf = map[string][]*exportNode{}
+	for _, node := range e.nodes[1:] {
+		key := exportNodeKey(node.parentID)
+		e.childrenOf[key] = append(e.childrenOf[key], node)
+	}
+
+	// Paths are only all known once the walk is done, so links between
+	// pages are rewritten while writing.
+	for _, node := range e.nodes {
+		if err := e.write(ctx, node); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(e.manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(e.dir, exportManifestName), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return &e.manifest, nil
+}
+
+// visitPage fetches a page, downloads its files and visits the child pages
+// and databases found in its blocks. blocks holds the page's content when
+// the parent's block tree already included it.
+func (e *treeExporter) visitPage(ctx context.Context, node *exportNode, siblings map[string]bool, blocks []exportBlock) error {
+	if e.byID[exportNodeKey(node.id)] != nil {
+		return nil
+	}
+
+	page, err := e.client.GetPage(ctx, node.id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch page %s: %w", node.id, err)
+	}
+	node.page = page
+	if title := extractPageTitleFromProperties(page.Properties); title != "" {
+		node.title = title
+	}
+	if blocks == nil {
+		if blocks, err = fetchExportBlocks(ctx, e.client, node.id, e.concurrency); err != nil {
+			return err
+		}
+	}
+	node.blocks = blocks
+	e.add(node, siblings)
+
+	// Signed file URLs expire after an hour, so files are fetched as soon
+	// as the page is, not after the whole tree has been walked.
+	if err := e.downloadFiles(ctx, node, node.blocks); err != nil {
+		return err
+	}
+	return e.visitChildren(ctx, node, node.blocks)
+}
+
+// visitChildren visits the child pages and databases among blocks,
+// including those nested in toggles and columns.
+func (e *treeExporter) visitChildren(ctx context.Context, parent *exportNode, blocks []exportBlock) error {
+	for i := range blocks {
+		block := &blocks[i]
+		switch block.Type {
+		case "child_page":
+			title := ""
+			if content, ok := block.typedContent().(*notion.ChildPageBlock); ok {
+				title = content.Title
+			}
+			child := &exportNode{id: block.ID, kind: "page", title: title, parentID: parent.id}
+			// The parent's block tree already holds the child page's
+			// content; it is exported on its own, so drop it here.
+			children := block.Children
+			if children == nil {
+				children = []exportBlock{}
+			}
+			block.Children = nil
+			if err := e.visitPage(ctx, child, parent.names, children); err != nil {
+				return err
+			}
+		case "child_database":
+			title := ""
+			if content, ok := block.typedContent().(*notion.ChildDatabaseBlock); ok {
+				title = content.Title
+			}
+			child := &exportNode{id: block.ID, kind: "database", title: title, parentID: parent.id}
+			if err := e.visitDatabase(ctx, child, parent.names); err != nil {
+				return err
+			}
+		default:
+			if err := e.visitChildren(ctx, parent, block.Children); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// visitDatabase exports a database as an index of its rows, each row
+// exported as a page in the database's folder.
+func (e *treeExporter) visitDatabase(ctx context.Context, node *exportNode, siblings map[string]bool) error {
+	if e.byID[exportNodeKey(node.id)] != nil {
+		return nil
+	}
+
+	database, err := e.client.GetDatabase(ctx, node.id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch database %s: %w", node.id, err)
+	}
+	node.database = database
+	if title := extractTitlePlainText(toInterfaceSlice(database.Title)); title != "" {
+		node.title = title
+	}
+	e.add(node, siblings)
+
+	for _, ds := range database.DataSources {
+		req := &notion.QueryDataSourceRequest{PageSize: NotionMaxPageSize}
+		for {
+			result, err := e.client.QueryDataSource(ctx, ds.ID, req)
+			if err != nil {
+				return fmt.Errorf("failed to query database %s: %w", node.id, err)
+			}
+			for i := range result.Results {
+				row := &exportNode{id: result.Results[i].ID, kind: "page", parentID: node.id}
+				if err := e.visitPage(ctx, row, node.names, nil); err != nil {
+					return err
+				}
+				if e.byID[exportNodeKey(row.id)] == row {
+					node.rows = append(node.rows, row)
+				}
+			}
+			if !result.HasMore || result.NextCursor == nil || *result.NextCursor == "" {
+				break
+			}
+			req.StartCursor = *result.NextCursor
+		}
+	}
+	return nil
+}
+
+// add gives node a unique file name among its siblings and records it.
+func (e *treeExporter) add(node *exportNode, siblings map[string]bool) {
+	name := exportFileName(node.title)
+	if siblings[strings.ToLower(name)] {
+		name += " " + strings.ReplaceAll(node.id, "-", "")[:8]
+	}
+	siblings[strings.ToLower(name)] = true
+
+	ext := ".md"
+	switch e.format {
+	case "json":
+		ext = ".json"
+	case "html":
+		ext = ".html"
+	}
+	if parent := e.byID[exportNodeKey(node.parentID)]; parent != nil {
+		node.path = parent.folder() + "/" + name + ext
+	} else {
+		node.path = name + ext
+	}
+	node.names = map[string]bool{}
+
+	e.nodes = append(e.nodes, node)
+	e.byID[exportNodeKey(node.id)] = node
+	e.manifest.Pages = append(e.manifest.Pages, exportManifestPage{
+		ID:       node.id,
+		Type:     node.kind,
+		Title:    node.title,
+		Path:     node.path,
+		ParentID: node.parentID,
+	})
+}
+
+// downloadFiles saves the Notion-hosted files among blocks into the node's
+// folder and points the blocks at the local copies.
+func (e *treeExporter) downloadFiles(ctx context.Context, node *exportNode, blocks []exportBlock) error {
+	for _, block := range blocks {
+		// Child pages are exported, and their files downloaded, on their own.
+		if block.Type != "child_page" {
+			if err := e.downloadFiles(ctx, node, block.Children); err != nil {
+				return err
+			}
+		}
+		if block.Content["type"] != "file" {
+			continue
+		}
+		hosted, ok := block.Content["file"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fileURL, _ := hosted["url"].(string)
+		if fileURL == "" {
+			continue
+		}
+
+		name := exportAssetName(fileURL, block.Content["name"])
+		if node.names[strings.ToLower(name)] {
+			ext := path.Ext(name)
+			name = strings.TrimSuffix(name, ext) + " " + strings.ReplaceAll(block.ID, "-", "")[:8] + ext
+		}
+		node.names[strings.ToLower(name)] = true
+
+		rel := node.folder() + "/" + name
+		if err := e.download(ctx, fileURL, filepath.Join(e.dir, filepath.FromSlash(rel))); err != nil {
+			return fmt.Errorf("failed to download %s file in block %s: %w", block.Type, block.ID, err)
+		}
+		hosted["url"] = e.link(node.path, rel)
+		delete(hosted, "expiry_time")
+		e.manifest.Files = append(e.manifest.Files, exportManifestFile{BlockID: block.ID, PageID: node.id, Path: rel})
+	}
+	return nil
+}
+
+func (e *treeExporter) download(ctx context.Context, fileURL, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// write renders a node to its file.
+func (e *treeExporter) write(ctx context.Context, node *exportNode) error {
+	var data []byte
+	switch {
+	case e.format == "html":
+		data = e.htmlPage(ctx, node)
+	case e.format == "json":
+		payload := map[string]interface{}{"page": node.page, "blocks": node.blocks}
+		if node.kind == "database" {
+			payload = map[string]interface{}{"database": node.database, "rows": e.rowLinks(node)}
+		}
+		encoded, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", node.path, err)
+		}
+		data = append(encoded, '\n')
+	case node.kind == "database":
+		data = []byte(e.databaseMarkdown(node))
+	default:
+		renderer := newMarkdownRenderer(e.fallback)
+		renderer.pageLink = e.pageLinkFrom(node)
+		markdown := renderer.render(node.blocks, 0)
+		if title := pageTitleFromProperties(node.page.Properties); title != "" {
+			markdown = strings.TrimRight("# "+title+"\n\n"+markdown, "\n")
+		}
+		data = []byte(markdown + "\n")
+	}
+
+	dest := filepath.Join(e.dir, filepath.FromSlash(node.path))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", node.path, err)
+	}
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", node.path, err)
+	}
+	return nil
+}
+
+// pageLinkFrom links from the file of node to other exported pages.
+func (e *treeExporter) pageLinkFrom(node *exportNode) func(id string) string {
+	return func(id string) string {
+		if target := e.byID[exportNodeKey(id)]; target != nil {
+			return e.link(node.path, target.path)
+		}
+		return ""
+	}
+}
+
+// databaseMarkdown lists a database's rows as links to their files.
+func (e *treeExporter) databaseMarkdown(node *exportNode) string {
+	var b strings.Builder
+	b.WriteString("# " + node.title + "\n")
+	if len(node.rows) > 0 {
+		b.WriteString("\n")
+	}
+	for _, row := range node.rows {
+		b.WriteString("- " + markdownLink(row.title, e.link(node.path, row.path)) + "\n")
+	}
+	return b.String()
+}
+
+// rowLinks lists a database's rows for its JSON file.
+func (e *treeExporter) rowLinks(node *exportNode) []map[string]string {
+	rows := make([]map[string]string, 0, len(node.rows))
+	for _, row := range node.rows {
+		rows = append(rows, map[string]string{"id": row.id, "title": row.title, "path": e.link(node.path, row.path)})
+	}
+	return rows
+}
+
+// link is the relative link from the file at from to the file at to, both
+// relative to the export directory. Markdown links are escaped so that
+// spaces and brackets in titles do not end them.
+func (e *treeExporter) link(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		rel = to
+	}
+	rel = filepath.ToSlash(rel)
+	if e.format == "json" {
+		return rel
+	}
+	return markdownPathEscaper.Replace(rel)
+}
+
+var markdownPathEscaper = strings.NewReplacer(
+	"%", "%25", " ", "%20", "(", "%28", ")", "%29", "[", "%5B", "]", "%5D",
+	"<", "%3C", ">", "%3E", "#", "%23", "?", "%3F",
+)
+
+// exportNodeKey normalizes an ID for lookups, since the API returns IDs
+// with dashes and links carry them without.
+func exportNodeKey(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
+
+// maxExportNameRunes keeps file names well within filesystem limits.
+const maxExportNameRunes = 80
+
+// exportFileName turns a title into a file name, replacing characters
+// that are not allowed in file names on common filesystems.
+func exportFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r), unicode.IsControl(r):
+			return '-'
+		}
+		return r
+	}, strings.Join(strings.Fields(title), " "))
+	if runes := []rune(name); len(runes) > maxExportNameRunes {
+		name = string(runes[:maxExportNameRunes])
+	}
+	name = strings.Trim(name, " .")
+	if name == "" {
+		name = "Untitled"
+	}
+	return name
+}
+
+// exportAssetName picks a file name for a downloaded file: its name in
+// Notion when set, else the last element of its URL path.
+func exportAssetName(fileURL string, name interface{}) string {
+	if s, ok := name.(string); ok && s != "" {
+		return exportFileName(s)
+	}
+	if u, err := url.Parse(fileURL); err == nil {
+		if base := path.Base(u.Path); base != "." && base != "/" {
+			return exportFileName(base)
+		}
+	}
+	return "file"
+}
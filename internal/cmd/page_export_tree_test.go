@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testPageTree holds the IDs of the page tree served by newPageTreeServer.
+type testPageTree struct {
+	rootID, childID, dbID, dsID, rowID string
+}
+
+// newPageTreeServer serves a small page tree: a root page with a mention, a
+// child page linking back, a child database with one row, a Notion-hosted
+// image and a link_to_page block. NOTION_API_BASE_URL is pointed at it.
+func newPageTreeServer(t *testing.T) testPageTree {
+	t.Helper()
+	t.Setenv("NOTION_TOKEN", "test-token")
+
+	tree := testPageTree{
+		rootID:  "11111111-1111-1111-1111-111111111111",
+		childID: "22222222-2222-2222-2222-222222222222",
+		dbID:    "33333333-3333-3333-3333-333333333333",
+		dsID:    "44444444-4444-4444-4444-444444444444",
+		rowID:   "55555555-5555-5555-5555-555555555555",
+	}
+	rootID, childID, dbID, dsID, rowID := tree.rootID, tree.childID, tree.dbID, tree.dsID, tree.rowID
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	page := func(id, title string) map[string]any {
+		return map[string]any{
+			"object": "page",
+			"id":     id,
+			"properties": map[string]any{
+				"title": map[string]any{"type": "title", "title": []any{map[string]any{"plain_text": title}}},
+			},
+		}
+	}
+	text := func(content string) map[string]any {
+		return map[string]any{"type": "text", "plain_text": content, "text": map[string]any{"content": content}}
+	}
+	list := func(blocks ...map[string]any) map[string]any {
+		return map[string]any{"object": "list", "results": blocks, "has_more": false, "next_cursor": nil}
+	}
+
+	mux.HandleFunc("/pages/"+rootID, func(w http.ResponseWriter, r *http.Request) { writeJSON(w, page(rootID, "Handbook")) })
+	mux.HandleFunc("/pages/"+childID, func(w http.ResponseWriter, r *http.Request) { writeJSON(w, page(childID, "Setup / Install")) })
+	mux.HandleFunc("/pages/"+rowID, func(w http.ResponseWriter, r *http.Request) { writeJSON(w, page(rowID, "Write docs")) })
+	mux.HandleFunc("/blocks/"+rootID+"/children", func(w http.ResponseWriter, r *http.Request) {
+		mention := map[string]any{
+			"type":       "mention",
+			"plain_text": "Setup",
+			"href":       notionPageURL(childID),
+			"mention":    map[string]any{"type": "page", "page": map[string]any{"id": childID}},
+		}
+		writeJSON(w, list(
+			map[string]any{"id": "b1", "type": "paragraph", "paragraph": map[string]any{"rich_text": []any{text("See "), mention}}},
+			map[string]any{"id": childID, "type": "child_page", "has_children": true, "child_page": map[string]any{"title": "Setup / Install"}},
+			map[string]any{"id": dbID, "type": "child_database", "child_database": map[string]any{"title": "Tasks"}},
+			map[string]any{"id": "b2", "type": "image", "image": map[string]any{
+				"type": "file",
+				"file": map[string]any{"url": server.URL + "/files/chart.png?X-Amz-Signature=abc", "expiry_time": "2026-01-01T00:00:00.000Z"},
+			}},
+			map[string]any{"id": "b3", "type": "link_to_page", "link_to_page": map[string]any{"type": "database_id", "database_id": dbID}},
+		))
+	})
+	mux.HandleFunc("/blocks/"+childID+"/children", func(w http.ResponseWriter, r *http.Request) {
+		link := map[string]any{
+			"type":       "text",
+			"plain_text": "home",
+			"href":       "/" + strings.ReplaceAll(rootID, "-", ""),
+			"text":       map[string]any{"content": "home", "link": map[string]any{"url": "/" + strings.ReplaceAll(rootID, "-", "")}},
+		}
+		writeJSON(w, list(map[string]any{"id": "c1", "type": "paragraph", "paragraph": map[string]any{"rich_text": []any{text("Back "), link}}}))
+	})
+	mux.HandleFunc("/blocks/"+rowID+"/children", func(w http.ResponseWriter, r *http.Request) { writeJSON(w, list()) })
+	mux.HandleFunc("/databases/"+dbID, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"object":       "database",
+			"id":           dbID,
+			"title":        []map[string]any{{"plain_text": "Tasks"}},
+			"data_sources": []map[string]any{{"id": dsID, "name": "Tasks"}},
+		})
+	})
+	mux.HandleFunc("/data_sources/"+dsID+"/query", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"object": "list", "results": []any{page(rowID, "Write docs")}, "has_more": false})
+	})
+	mux.HandleFunc("/files/chart.png", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("PNG")) })
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+	return tree
+}
+
+func TestPageExport_Recursive(t *testing.T) {
+	tree := newPageTreeServer(t)
+	rootID, childID, dbID, rowID := tree.rootID, tree.childID, tree.dbID, tree.rowID
+
+	dir := t.TempDir()
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"page", "export", rootID, "--recursive", "--dir", dir})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("export failed: %v\nstderr=%s", err, errBuf.String())
+	}
+
+	read := func(rel string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		return string(data)
+	}
+
+	want := "# Handbook\n\n" +
+		"See [Setup](Handbook/Setup%20-%20Install.md)\n\n" +
+		"[📄 Setup / Install](Handbook/Setup%20-%20Install.md)\n\n" +
+		"[🗃️ Tasks](Handbook/Tasks.md)\n\n" +
+		"![](Handbook/chart.png)\n\n" +
+		"[↗ Linked page](Handbook/Tasks.md)\n"
+	if got := read("Handbook.md"); got != want {
+		t.Errorf("Handbook.md:\n%s\nwant:\n%s", got, want)
+	}
+	if got := read("Handbook/Setup - Install.md"); got != "# Setup / Install\n\nBack [home](../Handbook.md)\n" {
+		t.Errorf("child page = %q", got)
+	}
+	if got := read("Handbook/Tasks.md"); got != "# Tasks\n\n- [Write docs](Tasks/Write%20docs.md)\n" {
+		t.Errorf("database index = %q", got)
+	}
+	if got := read("Handbook/Tasks/Write docs.md"); got != "# Write docs\n" {
+		t.Errorf("row page = %q", got)
+	}
+	if got := read("Handbook/chart.png"); got != "PNG" {
+		t.Errorf("downloaded file = %q", got)
+	}
+
+	var manifest exportManifest
+	if err := json.Unmarshal([]byte(read(exportManifestName)), &manifest); err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	paths := map[string]string{}
+	for _, p := range manifest.Pages {
+		paths[p.ID] = p.Path
+	}
+	wantPaths := map[string]string{
+		rootID:  "Handbook.md",
+		childID: "Handbook/Setup - Install.md",
+		dbID:    "Handbook/Tasks.md",
+		rowID:   "Handbook/Tasks/Write docs.md",
+	}
+	for id, want := range wantPaths {
+		if paths[id] != want {
+			t.Errorf("manifest path of %s = %q, want %q", id, paths[id], want)
+		}
+	}
+	if len(manifest.Files) != 1 || manifest.Files[0].Path != "Handbook/chart.png" || manifest.Files[0].PageID != rootID {
+		t.Errorf("manifest files = %+v", manifest.Files)
+	}
+	if !strings.Contains(errBuf.String(), "Exported 4 pages and 1 files") {
+		t.Errorf("summary = %q", errBuf.String())
+	}
+}
+
+func TestPageExport_RecursiveRequiresDir(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"page", "export", "11111111-1111-1111-1111-111111111111", "--recursive"})
+	if err := root.ExecuteContext(context.Background()); err == nil || !strings.Contains(err.Error(), "--dir") {
+		t.Fatalf("err = %v, want a --dir error", err)
+	}
+}
+
+func TestNotionLinkID(t *testing.T) {
+	id := "0123456789abcdef0123456789abcdef"
+	tests := map[string]string{
+		"/" + id:                        id,
+		notionPageURL(id):               id,
+		notionPageURL(id) + "?pvs=4":    id,
+		"/" + id + "#heading":           id,
+		"https://example.invalid/" + id: "",
+		"/docs/readme":                  "",
+	}
+	for link, want := range tests {
+		if got := notionLinkID(link); got != want {
+			t.Errorf("notionLinkID(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestExportFileName(t *testing.T) {
+	tests := map[string]string{
+		"Notes: Q1/Q2":       "Notes- Q1-Q2",
+		"  spaced   title  ": "spaced title",
+		"...":                "Untitled",
+		"":                   "Untitled",
+	}
+	for title, want := range tests {
+		if got := exportFileName(title); got != want {
+			t.Errorf("exportFileName(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"sort"
 
+	"github.com/salmonumbrella/notion-cli/internal/notion"
 	"github.com/salmonumbrella/notion-cli/internal/richtext"
 	"github.com/salmonumbrella/notion-cli/internal/skill"
 )
@@ -151,28 +152,15 @@ func buildPropertiesFromFlags(sf *skill.SkillFile, properties map[string]interfa
 	}
 
 	if status != "" {
-		properties["Status"] = map[string]interface{}{
-			"status": map[string]interface{}{
-				"name": status,
-			},
-		}
+		properties["Status"], _ = notion.PropertyPayload("status", status)
 	}
 
 	if priority != "" {
-		properties["Priority"] = map[string]interface{}{
-			"select": map[string]interface{}{
-				"name": priority,
-			},
-		}
+		properties["Priority"], _ = notion.PropertyPayload("select", priority)
 	}
 
 	if assignee != "" {
-		resolvedID := resolveUserID(sf, assignee)
-		properties["Assignee"] = map[string]interface{}{
-			"people": []map[string]interface{}{
-				{"object": "user", "id": resolvedID},
-			},
-		}
+		properties["Assignee"], _ = notion.PropertyPayload("people", resolveUserID(sf, assignee))
 	}
 
 	return properties
@@ -225,14 +213,6 @@ func setTitleProperty(properties map[string]interface{}, propName, title string)
 	if properties == nil {
 		properties = make(map[string]interface{})
 	}
-	properties[propName] = map[string]interface{}{
-		"title": []map[string]interface{}{
-			{
-				"text": map[string]interface{}{
-					"content": title,
-				},
-			},
-		},
-	}
+	properties[propName], _ = notion.PropertyPayload("title", title)
 	return properties
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -128,6 +129,9 @@ func runSyncPush(ctx context.Context, client *notion.Client, stderr io.Writer, f
 			printer.Field("Action", "create new page and write notion-id to frontmatter")
 		}
 		printer.Field("Blocks to sync", fmt.Sprintf("%d", len(input.Blocks)))
+		if len(input.Images) > 0 {
+			printer.Field("Images to upload", fmt.Sprintf("%d", len(input.Images)))
+		}
 		if len(input.Blocks) > 0 {
 			printer.Section("Block types:")
 			typeCounts := countBlockTypes(input.Blocks)
@@ -139,6 +143,11 @@ func runSyncPush(ctx context.Context, client *notion.Client, stderr io.Writer, f
 		return nil
 	}
 
+	doc := &markdownDocument{Blocks: input.Blocks, Images: input.Images}
+	if err := doc.uploadImages(ctx, client); err != nil {
+		return err
+	}
+
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	if input.NotionID != "" {
@@ -162,6 +171,7 @@ type syncPushInput struct {
 	Frontmatter map[string]string
 	Body        string
 	Blocks      []map[string]interface{}
+	Images      []*markdownImage
 	NotionID    string
 }
 
@@ -172,10 +182,12 @@ func loadSyncPushInput(filePath string) (*syncPushInput, error) {
 	}
 
 	fm, body := parseFrontmatter(string(data))
+	doc := parseMarkdownDocument(body, filepath.Dir(filePath))
 	return &syncPushInput{
 		Frontmatter: fm,
 		Body:        body,
-		Blocks:      parseMarkdownToBlocks(body),
+		Blocks:      doc.Blocks,
+		Images:      doc.Images,
 		NotionID:    fm["notion-id"],
 	}, nil
 }
@@ -331,10 +343,11 @@ func runSyncPull(ctx context.Context, client *notion.Client, stderr io.Writer, p
 		return wrapAPIError(err, "get page", "page", pageID)
 	}
 
-	title := pageTitleFromProperties(page.Properties)
+	// The frontmatter keeps the title as plain text, which push sends back.
+	title := extractPageTitleFromProperties(page.Properties)
 
 	// Fetch blocks
-	blocks, err := fetchExportBlocks(ctx, client, normalizedID)
+	blocks, err := fetchExportBlocks(ctx, client, normalizedID, 0)
 	if err != nil {
 		return err
 	}
@@ -495,29 +508,15 @@ func extractTitleFromBody(body string) string {
 
 // fetchAllBlockChildren fetches all direct block children (non-recursive, handles pagination).
 func fetchAllBlockChildren(ctx context.Context, client *notion.Client, blockID string) ([]notion.Block, error) {
-	var allBlocks []notion.Block
-	cursor := ""
-
-	for {
-		opts := &notion.BlockChildrenOptions{StartCursor: cursor, PageSize: 100}
-		list, err := client.GetBlockChildren(ctx, blockID, opts)
-		if err != nil {
-			return nil, err
-		}
-		allBlocks = append(allBlocks, list.Results...)
-		if !list.HasMore || list.NextCursor == nil || *list.NextCursor == "" {
-			break
-		}
-		cursor = *list.NextCursor
-	}
-
-	return allBlocks, nil
+	return client.IterBlockChildren(ctx, blockID, iterOptions("", NotionMaxPageSize, 0)).Collect()
 }
 
 // appendBlocksInBatches appends blocks in batches of 100 (Notion API limit).
 func appendBlocksInBatches(ctx context.Context, client *notion.Client, pageID string, blocks []map[string]interface{}) error {
 	const batchSize = 100
 
+	detached := detachDeepChildren(blocks)
+	var created []notion.Block
 	for i := 0; i < len(blocks); i += batchSize {
 		end := i + batchSize
 		if end > len(blocks) {
@@ -527,12 +526,14 @@ func appendBlocksInBatches(ctx context.Context, client *notion.Client, pageID st
 		req := &notion.AppendBlockChildrenRequest{
 			Children: blocks[i:end],
 		}
-		if _, err := client.AppendBlockChildren(ctx, pageID, req); err != nil {
+		result, err := client.AppendBlockChildren(ctx, pageID, req)
+		if err != nil {
 			return err
 		}
+		created = append(created, result.Results...)
 	}
 
-	return nil
+	return appendDetachedChildren(ctx, client, created, detached)
 }
 
 // resolveParentForSync determines the parent map for creating a new page during sync.
@@ -0,0 +1,479 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rivo/uniseg"
+
+	"github.com/salmonumbrella/notion-cli/internal/errors"
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+	"github.com/salmonumbrella/notion-cli/internal/output"
+)
+
+// emptyPropertyText stands in for unset property values in the page sheet.
+const emptyPropertyText = "—"
+
+// compactSeparator separates properties in the one-line-per-row view.
+const compactSeparator = " · "
+
+type userGetter interface {
+	GetUser(ctx context.Context, userID string) (*notion.User, error)
+}
+
+// textViewEnabled reports whether a command may render its own text view:
+// text output without --query, --fields or --jsonpath, which all operate on
+// the JSON shape and are left to the printer.
+func textViewEnabled(ctx context.Context) bool {
+	return output.FormatFromContext(ctx) == output.FormatText &&
+		output.QueryFromContext(ctx) == "" &&
+		strings.TrimSpace(output.FieldsFromContext(ctx)) == "" &&
+		strings.TrimSpace(output.JSONPathFromContext(ctx)) == ""
+}
+
+// propertyRenderer turns page property values into short human-readable text.
+// Dates are shown with a relative hint, and people are shown by name,
+// looking up users the API returned without one.
+type propertyRenderer struct {
+	ctx   context.Context
+	users userGetter
+	now   time.Time
+	names map[string]string
+	// absolute drops the relative hints from dates, for output that is
+	// read after the day it was made, such as exported sites.
+	absolute bool
+}
+
+func newPropertyRenderer(ctx context.Context, users userGetter) *propertyRenderer {
+	return &propertyRenderer{ctx: ctx, users: users, now: time.Now(), names: map[string]string{}}
+}
+
+// propertyOrder returns the property names of props, in schema order when
+// order is known and otherwise title first and then alphabetically.
+// Properties missing from order are appended alphabetically.
+func propertyOrder(props map[string]interface{}, order []string) []string {
+	names := make([]string, 0, len(props))
+	seen := make(map[string]bool, len(props))
+	for _, name := range order {
+		if _, ok := props[name]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	var titles, rest []string
+	for name, raw := range props {
+		if seen[name] {
+			continue
+		}
+		if prop, _ := raw.(map[string]interface{}); prop["type"] == "title" {
+			titles = append(titles, name)
+		} else {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(titles)
+	sort.Strings(rest)
+	if len(order) == 0 {
+		return append(titles, rest...)
+	}
+	return append(append(names, titles...), rest...)
+}
+
+// schemaOrder fetches the property order of a data source, or nil when it
+// cannot be fetched: the text view then falls back to alphabetical order.
+func schemaOrder(ctx context.Context, client dataSourceGetter, dataSourceID string) []string {
+	if dataSourceID == "" {
+		return nil
+	}
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil || ds == nil {
+		return nil
+	}
+	return ds.PropertyOrder
+}
+
+// parentDataSourceID returns the data source a page belongs to, if any.
+func parentDataSourceID(page *notion.Page) string {
+	id, _ := page.Parent["data_source_id"].(string)
+	return id
+}
+
+// printPageSheet writes a page as a "Property: value" sheet: the title,
+// then every other property in schema order, then the page URL.
+func printPageSheet(ctx context.Context, client *notion.Client, page *notion.Page) error {
+	title, sheet := pageSheet(ctx, client, page)
+	_, err := io.WriteString(stdoutFromContext(ctx), title+"\n\n"+sheet)
+	return err
+}
+
+// pageSheet returns the page title and its properties as aligned
+// "Name: value" lines, with multi-line values indented under their value.
+func pageSheet(ctx context.Context, client *notion.Client, page *notion.Page) (string, string) {
+	r := newPropertyRenderer(ctx, client)
+	order := schemaOrder(ctx, client, parentDataSourceID(page))
+
+	title := r.title(page)
+	if title == "" {
+		title = "Untitled"
+	}
+
+	type line struct{ name, value string }
+	lines := []line{{"ID", page.ID}}
+	for _, name := range propertyOrder(page.Properties, order) {
+		pv, err := notion.DecodePropertyValue(page.Properties[name])
+		if err != nil || pv.Type == "title" {
+			continue
+		}
+		value := r.value(pv)
+		if value == "" {
+			value = emptyPropertyText
+		}
+		lines = append(lines, line{name, value})
+	}
+	if page.URL != "" {
+		lines = append(lines, line{"URL", page.URL})
+	}
+
+	width := 0
+	for _, l := range lines {
+		width = max(width, uniseg.StringWidth(l.name))
+	}
+
+	var b strings.Builder
+	indent := strings.Repeat(" ", width+2)
+	for _, l := range lines {
+		b.WriteString(l.name)
+		b.WriteString(":")
+		b.WriteString(strings.Repeat(" ", width-uniseg.StringWidth(l.name)+1))
+		b.WriteString(strings.ReplaceAll(l.value, "\n", "\n"+indent))
+		b.WriteString("\n")
+	}
+	return title, b.String()
+}
+
+// printPageRows writes query results one page per line: the title, then the
+// non-empty properties as "Name: value" in schema order. A footer gives the
+// cursor for the next page when there are more results.
+func printPageRows(ctx context.Context, client *notion.Client, dataSourceID string, pages []notion.Page, hasMore bool, nextCursor *string) error {
+	if limited, ok := output.ApplyAgentOptions(ctx, pages).([]notion.Page); ok {
+		pages = limited
+	}
+	if output.FailEmptyFromContext(ctx) && len(pages) == 0 {
+		return errors.NewUserError("no results", "Remove --fail-empty to allow empty output")
+	}
+
+	out := stdoutFromContext(ctx)
+	if len(pages) == 0 {
+		_, err := io.WriteString(out, "No results\n")
+		return err
+	}
+
+	r := newPropertyRenderer(ctx, client)
+	order := schemaOrder(ctx, client, dataSourceID)
+
+	var b strings.Builder
+	for i := range pages {
+		page := &pages[i]
+		title := r.title(page)
+		if title == "" {
+			title = "Untitled"
+		}
+		parts := []string{title}
+		for _, name := range propertyOrder(page.Properties, order) {
+			pv, err := notion.DecodePropertyValue(page.Properties[name])
+			if err != nil || pv.Type == "title" {
+				continue
+			}
+			if value := r.value(pv); value != "" {
+				parts = append(parts, name+": "+strings.ReplaceAll(value, "\n", " "))
+			}
+		}
+		b.WriteString(strings.Join(parts, compactSeparator))
+		b.WriteString("\n")
+	}
+	if hasMore && nextCursor != nil && *nextCursor != "" {
+		fmt.Fprintf(&b, "\nMore results: --start-cursor %s\n", *nextCursor)
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// title returns the plain text of the page's title property.
+func (r *propertyRenderer) title(page *notion.Page) string {
+	for _, raw := range page.Properties {
+		pv, err := notion.DecodePropertyValue(raw)
+		if err == nil && pv.Type == "title" {
+			return notion.PlainText(pv.Title)
+		}
+	}
+	return ""
+}
+
+// value renders a property value, or "" when it is empty.
+func (r *propertyRenderer) value(pv *notion.PropertyValue) string {
+	switch pv.Type {
+	case "title":
+		return notion.PlainText(pv.Title)
+	case "rich_text":
+		return notion.PlainText(pv.RichText)
+	case "number":
+		return formatNumber(pv.Number)
+	case "select":
+		if pv.Select != nil {
+			return pv.Select.Name
+		}
+	case "status":
+		if pv.Status != nil {
+			return pv.Status.Name
+		}
+	case "multi_select":
+		names := make([]string, 0, len(pv.MultiSelect))
+		for _, opt := range pv.MultiSelect {
+			names = append(names, opt.Name)
+		}
+		return strings.Join(names, ", ")
+	case "date":
+		return r.date(pv.Date)
+	case "people":
+		names := make([]string, 0, len(pv.People))
+		for i := range pv.People {
+			names = append(names, r.userName(&pv.People[i]))
+		}
+		return strings.Join(names, ", ")
+	case "files":
+		names := make([]string, 0, len(pv.Files))
+		for _, f := range pv.Files {
+			if f.Name != "" {
+				names = append(names, f.Name)
+			} else if u := f.URL(); u != "" {
+				names = append(names, u)
+			}
+		}
+		return strings.Join(names, ", ")
+	case "checkbox":
+		if pv.Checkbox != nil {
+			if *pv.Checkbox {
+				return "yes"
+			}
+			return "no"
+		}
+	case "url":
+		return derefString(pv.URL)
+	case "email":
+		return derefString(pv.Email)
+	case "phone_number":
+		return derefString(pv.PhoneNumber)
+	case "relation":
+		ids := make([]string, 0, len(pv.Relation))
+		for _, rel := range pv.Relation {
+			ids = append(ids, rel.ID)
+		}
+		text := strings.Join(ids, ", ")
+		if pv.HasMore {
+			text += ", …"
+		}
+		return text
+	case "formula":
+		return r.formula(pv.Formula)
+	case "rollup":
+		return r.rollup(pv.Rollup)
+	case "created_time":
+		return r.timestamp(derefString(pv.CreatedTime))
+	case "last_edited_time":
+		return r.timestamp(derefString(pv.LastEditedTime))
+	case "created_by":
+		if pv.CreatedBy != nil {
+			return r.userName(pv.CreatedBy)
+		}
+	case "last_edited_by":
+		if pv.LastEditedBy != nil {
+			return r.userName(pv.LastEditedBy)
+		}
+	case "unique_id":
+		if pv.UniqueID != nil {
+			return pv.UniqueID.String()
+		}
+	case "verification":
+		if pv.Verification != nil && pv.Verification.State != "" {
+			text := pv.Verification.State
+			if pv.Verification.VerifiedBy != nil {
+				text += " by " + r.userName(pv.Verification.VerifiedBy)
+			}
+			return text
+		}
+	}
+	return ""
+}
+
+func (r *propertyRenderer) formula(f *notion.FormulaValue) string {
+	if f == nil {
+		return ""
+	}
+	switch f.Type {
+	case "string":
+		return derefString(f.String)
+	case "number":
+		return formatNumber(f.Number)
+	case "boolean":
+		if f.Boolean != nil {
+			if *f.Boolean {
+				return "yes"
+			}
+			return "no"
+		}
+	case "date":
+		return r.date(f.Date)
+	}
+	return ""
+}
+
+// rollup renders a rollup result. Array items are property values of the
+// related pages and are rendered like the properties themselves.
+func (r *propertyRenderer) rollup(v *notion.RollupValue) string {
+	if v == nil {
+		return ""
+	}
+	switch v.Type {
+	case "number":
+		return formatNumber(v.Number)
+	case "date":
+		return r.date(v.Date)
+	case "array":
+		parts := make([]string, 0, len(v.Array))
+		for _, item := range v.Array {
+			pv, err := notion.DecodePropertyValue(item)
+			if err != nil {
+				continue
+			}
+			if text := r.value(pv); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// userName returns the user's name, fetching users the API returned as bare
+// references. Lookups are cached per render; the ID is shown when the user
+// cannot be fetched.
+func (r *propertyRenderer) userName(u *notion.User) string {
+	if u.Name != "" {
+		return u.Name
+	}
+	if u.ID == "" {
+		return ""
+	}
+	if name, ok := r.names[u.ID]; ok {
+		return name
+	}
+	name := u.ID
+	if r.users != nil {
+		if user, err := r.users.GetUser(r.ctx, u.ID); err == nil && user != nil && user.Name != "" {
+			name = user.Name
+		}
+	}
+	r.names[u.ID] = name
+	return name
+}
+
+// date renders a date or date range with a hint relative to today, e.g.
+// "2024-01-15 (in 3 days)" or "2024-01-15 → 2024-01-19 (yesterday)".
+func (r *propertyRenderer) date(d *notion.Date) string {
+	if d == nil || d.Start == "" {
+		return ""
+	}
+	start, startTime, ok := r.parseDate(d.Start)
+	text := start
+	if d.End != nil && *d.End != "" {
+		end, _, _ := r.parseDate(*d.End)
+		text += " → " + end
+	}
+	if ok && !r.absolute {
+		text += " (" + relativeDay(startTime, r.now) + ")"
+	}
+	return text
+}
+
+// timestamp renders a created or last edited time with a relative hint.
+func (r *propertyRenderer) timestamp(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	return r.date(&notion.Date{Start: raw})
+}
+
+// parseDate formats a Notion date for display: dates as 2006-01-02, and date
+// times as 2006-01-02 15:04 in the local time zone.
+func (r *propertyRenderer) parseDate(raw string) (string, time.Time, bool) {
+	if t, err := time.ParseInLocation("2006-01-02", raw, r.now.Location()); err == nil {
+		return raw, t, true
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000Z07:00"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			t = t.In(r.now.Location())
+			return t.Format("2006-01-02 15:04"), t, true
+		}
+	}
+	return raw, time.Time{}, false
+}
+
+// relativeDay describes t relative to now by calendar day: "today",
+// "in 3 days", "2 months ago" and so on.
+func relativeDay(t, now time.Time) string {
+	day := func(x time.Time) time.Time {
+		y, m, d := x.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	days := int(math.Round(day(t).Sub(day(now)).Hours() / 24))
+
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	case -1:
+		return "yesterday"
+	}
+
+	n, unit := days, "day"
+	abs := max(days, -days)
+	switch {
+	case abs >= 365:
+		n, unit = days/365, "year"
+	case abs >= 60:
+		n, unit = days/30, "month"
+	case abs >= 14:
+		n, unit = days/7, "week"
+	}
+	count := max(n, -n)
+	label := strconv.Itoa(count) + " " + unit
+	if count != 1 {
+		label += "s"
+	}
+	if n > 0 {
+		return "in " + label
+	}
+	return label + " ago"
+}
+
+func formatNumber(n *float64) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*n, 'f', -1, 64)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
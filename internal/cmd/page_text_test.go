@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+type fakeUserGetter map[string]string
+
+func (f fakeUserGetter) GetUser(_ context.Context, id string) (*notion.User, error) {
+	return &notion.User{ID: id, Name: f[id]}, nil
+}
+
+func TestRelativeDay(t *testing.T) {
+	now := time.Date(2024, 1, 12, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now.Add(-6 * time.Hour), "today"},
+		{time.Date(2024, 1, 13, 1, 0, 0, 0, time.UTC), "tomorrow"},
+		{time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC), "yesterday"},
+		{time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), "in 3 days"},
+		{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "10 days ago"},
+		{time.Date(2024, 1, 26, 0, 0, 0, 0, time.UTC), "in 2 weeks"},
+		{time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), "3 months ago"},
+		{time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), "in 1 year"},
+	}
+	for _, tt := range tests {
+		if got := relativeDay(tt.t, now); got != tt.want {
+			t.Errorf("relativeDay(%s) = %q, want %q", tt.t.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
+
+func TestPropertyRendererValue(t *testing.T) {
+	r := newPropertyRenderer(context.Background(), fakeUserGetter{"u2": "Grace"})
+	r.now = time.Date(2024, 1, 12, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"people", `{"type":"people","people":[{"object":"user","id":"u1","name":"Ada"},{"object":"user","id":"u2"}]}`, "Ada, Grace"},
+		{"date", `{"type":"date","date":{"start":"2024-01-15"}}`, "2024-01-15 (in 3 days)"},
+		{"date range", `{"type":"date","date":{"start":"2024-01-11","end":"2024-01-14"}}`, "2024-01-11 → 2024-01-14 (yesterday)"},
+		{"relation", `{"type":"relation","relation":[{"id":"r1"},{"id":"r2"}],"has_more":true}`, "r1, r2, …"},
+		{"rollup array", `{"type":"rollup","rollup":{"type":"array","function":"show_original","array":[{"type":"title","title":[{"plain_text":"One"}]},{"type":"number","number":2}]}}`, "One, 2"},
+		{"rollup number", `{"type":"rollup","rollup":{"type":"number","function":"sum","number":4.5}}`, "4.5"},
+		{"formula", `{"type":"formula","formula":{"type":"boolean","boolean":true}}`, "yes"},
+		{"multi_select", `{"type":"multi_select","multi_select":[{"name":"a"},{"name":"b"}]}`, "a, b"},
+		{"unique_id", `{"type":"unique_id","unique_id":{"prefix":"TASK","number":7}}`, "TASK-7"},
+		{"empty select", `{"type":"select","select":null}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw interface{}
+			if err := json.Unmarshal([]byte(tt.raw), &raw); err != nil {
+				t.Fatal(err)
+			}
+			pv, err := notion.DecodePropertyValue(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.value(pv); got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropertyOrder(t *testing.T) {
+	props := map[string]interface{}{
+		"Zeta":  map[string]interface{}{"type": "rich_text"},
+		"Alpha": map[string]interface{}{"type": "number"},
+		"Name":  map[string]interface{}{"type": "title"},
+		"Due":   map[string]interface{}{"type": "date"},
+	}
+	if got := strings.Join(propertyOrder(props, nil), ","); got != "Name,Alpha,Due,Zeta" {
+		t.Errorf("fallback order = %s", got)
+	}
+	if got := strings.Join(propertyOrder(props, []string{"Name", "Zeta", "Gone", "Due"}), ","); got != "Name,Zeta,Due,Alpha" {
+		t.Errorf("schema order = %s", got)
+	}
+}
+
+func textViewServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	const dsID = "22222222-2222-2222-2222-222222222222"
+	page := func(id, title, status string) map[string]interface{} {
+		return map[string]interface{}{
+			"object": "page",
+			"id":     id,
+			"url":    "https://example.invalid/" + id,
+			"parent": map[string]interface{}{"type": "data_source_id", "data_source_id": dsID},
+			"properties": map[string]interface{}{
+				"Name":     map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": title}}},
+				"Status":   map[string]interface{}{"type": "status", "status": map[string]interface{}{"name": status}},
+				"Owner":    map[string]interface{}{"type": "people", "people": []interface{}{map[string]interface{}{"object": "user", "id": "u1"}}},
+				"Estimate": map[string]interface{}{"type": "number", "number": nil},
+			},
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pages/11111111-1111-1111-1111-111111111111", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(page("11111111-1111-1111-1111-111111111111", "Launch", "Doing"))
+	})
+	mux.HandleFunc("/data_sources/"+dsID, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"object":"data_source","id":"` + dsID + `","properties":{"Name":{"type":"title"},"Status":{"type":"status"},"Owner":{"type":"people"},"Estimate":{"type":"number"}}}`))
+	})
+	mux.HandleFunc("/data_sources/"+dsID+"/query", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object":      "list",
+			"results":     []interface{}{page("p1", "Launch", "Doing"), page("p2", "Retro", "Done")},
+			"has_more":    true,
+			"next_cursor": "cursor-2",
+		})
+	})
+	mux.HandleFunc("/databases/33333333-3333-3333-3333-333333333333", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object":       "database",
+			"id":           "33333333-3333-3333-3333-333333333333",
+			"data_sources": []interface{}{map[string]interface{}{"id": dsID, "name": "Tasks"}},
+		})
+	})
+	mux.HandleFunc("/users/u1", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "user", "id": "u1", "name": "Ada"})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+	return server
+}
+
+func TestPageGet_TextSheet(t *testing.T) {
+	textViewServer(t)
+
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"page", "get", "11111111-1111-1111-1111-111111111111", "-o", "text"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("page get failed: %v\nstderr=%s", err, errBuf.String())
+	}
+
+	want := "Launch\n\n" +
+		"ID:       11111111-1111-1111-1111-111111111111\n" +
+		"Status:   Doing\n" +
+		"Owner:    Ada\n" +
+		"Estimate: —\n" +
+		"URL:      https://example.invalid/11111111-1111-1111-1111-111111111111\n"
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestDBQuery_CompactText(t *testing.T) {
+	textViewServer(t)
+
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"db", "query", "33333333-3333-3333-3333-333333333333", "-o", "text"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("db query failed: %v\nstderr=%s", err, errBuf.String())
+	}
+
+	want := "Launch · Status: Doing · Owner: Ada\n" +
+		"Retro · Status: Done · Owner: Ada\n" +
+		"\nMore results: --start-cursor cursor-2\n"
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/muesli/termenv"
+	"github.com/rivo/uniseg"
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+	"github.com/salmonumbrella/notion-cli/internal/output"
+	"github.com/salmonumbrella/notion-cli/internal/richtext"
+)
+
+// defaultViewWidth is used for rules when the terminal width is unknown.
+const defaultViewWidth = 80
+
+func newPageViewCmd() *cobra.Command {
+	var noPager bool
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:     "view <page-id-or-name>",
+		Aliases: []string{"v"},
+		Short:   "Read a page in the terminal",
+		Long: `Render a page in the terminal: its properties, then its content with
+headings, text formatting and colours, to-do checkboxes, nested toggles,
+tables, callouts and syntax-highlighted code blocks.
+
+On a terminal the page is shown through $PAGER (default: less); use --no-pager
+to print it directly. Colour follows the color setting and NO_COLOR, so piped
+output is plain text.
+
+Example:
+  ntn page view 12345678-1234-1234-1234-123456789012
+  ntn p v "Meeting Notes" --no-pager`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			sf := SkillFileFromContext(ctx)
+
+			client, err := clientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			pageID, err := resolveAndNormalizePageID(ctx, client, sf, args[0])
+			if err != nil {
+				return err
+			}
+
+			page, err := client.GetPage(ctx, pageID)
+			if err != nil {
+				return wrapAPIError(err, "get page", "page", args[0])
+			}
+
+			blocks, err := fetchExportBlocks(ctx, client, pageID, concurrency)
+			if err != nil {
+				return err
+			}
+
+			v := newPageViewer(ctx)
+			title, sheet := pageSheet(ctx, client, page)
+			var b strings.Builder
+			b.WriteString(v.style(title).Bold().String())
+			b.WriteString("\n\n")
+			b.WriteString(sheet)
+			if len(blocks) > 0 {
+				b.WriteString("\n")
+				b.WriteString(v.style(strings.Repeat("─", v.width)).Faint().String())
+				b.WriteString("\n\n")
+				b.WriteString(strings.Join(v.render(blocks, ""), "\n"))
+				b.WriteString("\n")
+			}
+
+			return writePaged(ctx, b.String(), noPager)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noPager, "no-pager", false, "Print directly instead of through $PAGER")
+	cmd.Flags().IntVar(&concurrency, "concurrency", notion.DefaultBlockFetchConcurrency, "Maximum parallel block fetches")
+
+	return cmd
+}
+
+// pageViewer renders a block tree for the terminal. Without colour the
+// output is plain text with the same layout.
+type pageViewer struct {
+	profile termenv.Profile
+	width   int
+}
+
+func newPageViewer(ctx context.Context) *pageViewer {
+	v := &pageViewer{profile: termenv.Ascii, width: defaultViewWidth}
+	if term, ok := output.TerminalFromContext(ctx); ok {
+		if term.Color {
+			v.profile = termenv.ANSI256
+		}
+		if term.Width > 0 {
+			v.width = min(term.Width, defaultViewWidth)
+		}
+	}
+	return v
+}
+
+func (v *pageViewer) style(text string) termenv.Style {
+	return v.profile.String(text)
+}
+
+func (v *pageViewer) text(items []notion.RichText) string {
+	return richtext.ANSI(items, v.profile)
+}
+
+// render returns the lines of blocks, each starting with prefix. Sibling
+// blocks are separated by a blank line, except runs of list items.
+func (v *pageViewer) render(blocks []exportBlock, prefix string) []string {
+	var lines []string
+	number := 0
+	for i, block := range blocks {
+		if block.Type == "numbered_list_item" {
+			number++
+		} else {
+			number = 0
+		}
+		if i > 0 && !(isViewListItem(block.Type) && isViewListItem(blocks[i-1].Type)) {
+			lines = append(lines, strings.TrimRight(prefix, " "))
+		}
+		lines = append(lines, v.block(block, prefix, number)...)
+	}
+	return lines
+}
+
+func isViewListItem(blockType string) bool {
+	switch blockType {
+	case "bulleted_list_item", "numbered_list_item", "to_do":
+		return true
+	}
+	return false
+}
+
+// children renders the children of block below its first line.
+func (v *pageViewer) children(block exportBlock, prefix string) []string {
+	if len(block.Children) == 0 {
+		return nil
+	}
+	return v.render(block.Children, prefix)
+}
+
+func (v *pageViewer) block(block exportBlock, prefix string, number int) []string {
+	nested := prefix + "  "
+	switch content := block.typedContent().(type) {
+	case *notion.ParagraphBlock:
+		lines := prefixLines(prefix, v.text(annotate(content.RichText, content.Color, nil)))
+		return append(lines, v.children(block, nested)...)
+	case *notion.HeadingBlock:
+		heading := v.text(annotate(content.RichText, content.Color, func(a *notion.Annotations) {
+			a.Bold = true
+			a.Underline = a.Underline || block.Type == "heading_1"
+			a.Italic = a.Italic || block.Type == "heading_3"
+		}))
+		if content.IsToggleable {
+			heading = "▾ " + heading
+		}
+		lines := prefixLines(prefix, heading)
+		if block.Type == "heading_1" {
+			rule := strings.Repeat("═", max(uniseg.StringWidth(notion.PlainText(content.RichText)), 3))
+			lines = append(lines, prefix+v.style(rule).Faint().String())
+		}
+		return append(lines, v.children(block, nested)...)
+	case *notion.ListItemBlock:
+		marker := "• "
+		if block.Type == "numbered_list_item" {
+			marker = strconv.Itoa(number) + ". "
+		}
+		lines := prefixLines(prefix, v.style(marker).Faint().String()+v.text(annotate(content.RichText, content.Color, nil)))
+		return append(lines, v.children(block, nested)...)
+	case *notion.ToDoBlock:
+		box, text := "☐ ", v.text(annotate(content.RichText, content.Color, nil))
+		if content.Checked {
+			box = "☑ "
+			text = v.style(notion.PlainText(content.RichText)).Faint().CrossOut().String()
+		}
+		lines := prefixLines(prefix, box+text)
+		return append(lines, v.children(block, nested)...)
+	case *notion.ToggleBlock:
+		lines := prefixLines(prefix, "▾ "+v.text(annotate(content.RichText, content.Color, nil)))
+		return append(lines, v.children(block, nested)...)
+	case *notion.QuoteBlock:
+		bar := prefix + v.style("│ ").Faint().String()
+		lines := prefixLines(bar, v.text(annotate(content.RichText, content.Color, func(a *notion.Annotations) { a.Italic = true })))
+		return append(lines, v.children(block, bar)...)
+	case *notion.CalloutBlock:
+		bar := prefix + richtext.Colorize("│ ", strings.TrimSuffix(content.Color, "_background"), v.profile)
+		lines := prefixLines(bar, calloutEmoji(content.Icon)+" "+v.text(annotate(content.RichText, content.Color, nil)))
+		return append(lines, v.children(block, bar)...)
+	case *notion.CodeBlock:
+		return v.code(content, prefix)
+	case *notion.DividerBlock:
+		return []string{prefix + v.style(strings.Repeat("─", max(v.width-uniseg.StringWidth(stripANSI(prefix)), 3))).Faint().String()}
+	case *notion.EquationBlock:
+		return []string{prefix + v.style(content.Expression).Italic().String()}
+	case *notion.FileBlock:
+		label := content.Name
+		if caption := notion.PlainText(content.Caption); caption != "" {
+			label = caption
+		}
+		line := v.style("[" + block.Type + "]").Faint().String()
+		if label != "" {
+			line += " " + label
+		}
+		if url := content.URL(); url != "" {
+			line += " " + v.style(url).Underline().String()
+		}
+		return []string{prefix + line}
+	case *notion.BookmarkBlock:
+		return []string{prefix + "🔗 " + v.style(content.URL).Underline().String()}
+	case *notion.EmbedBlock:
+		return []string{prefix + "🔗 " + v.style(content.URL).Underline().String()}
+	case *notion.LinkPreviewBlock:
+		return []string{prefix + "🔗 " + v.style(content.URL).Underline().String()}
+	case *notion.ChildPageBlock:
+		return []string{prefix + "📄 " + v.style(content.Title).Bold().String()}
+	case *notion.ChildDatabaseBlock:
+		return []string{prefix + "🗃 " + v.style(content.Title).Bold().String()}
+	case *notion.LinkToPageBlock:
+		id := content.PageID
+		if id == "" {
+			id = content.DatabaseID
+		}
+		return []string{prefix + "→ " + id}
+	case *notion.TableBlock:
+		return v.table(block, content, prefix)
+	case *notion.ColumnListBlock, *notion.ColumnBlock, *notion.SyncedBlock, *notion.TemplateBlock:
+		return v.children(block, prefix)
+	case *notion.TableOfContentsBlock, *notion.BreadcrumbBlock:
+		return nil
+	}
+
+	lines := []string{prefix + v.style("[unsupported block: "+block.Type+"]").Faint().String()}
+	return append(lines, v.children(block, nested)...)
+}
+
+// code renders a code block under a language label, highlighted when
+// colour is on.
+func (v *pageViewer) code(content *notion.CodeBlock, prefix string) []string {
+	bar := prefix + v.style("│ ").Faint().String()
+	lines := []string{prefix + v.style("╭─ "+content.Language).Faint().String()}
+	source := highlightCode(notion.PlainText(content.RichText), content.Language, v.profile)
+	lines = append(lines, prefixLines(bar, source)...)
+	lines = append(lines, prefix+v.style("╰─").Faint().String())
+	if caption := notion.PlainText(content.Caption); caption != "" {
+		lines = append(lines, prefix+v.style(caption).Faint().String())
+	}
+	return lines
+}
+
+// table renders a table block and its table_row children with box-drawing
+// borders, separating the header row when the table has one.
+func (v *pageViewer) table(block exportBlock, content *notion.TableBlock, prefix string) []string {
+	var rows [][]string
+	var widths []int
+	var cellWidths [][]int
+	for _, child := range block.Children {
+		row, ok := child.typedContent().(*notion.TableRowBlock)
+		if !ok {
+			continue
+		}
+		cells := make([]string, len(row.Cells))
+		sizes := make([]int, len(row.Cells))
+		for i, cell := range row.Cells {
+			cells[i] = strings.ReplaceAll(v.text(cell), "\n", " ")
+			// Measured as rendered, with the URLs links are followed by.
+			sizes[i] = uniseg.StringWidth(stripANSI(cells[i]))
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], sizes[i])
+		}
+		rows = append(rows, cells)
+		cellWidths = append(cellWidths, sizes)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	border := func(left, mid, right string) string {
+		parts := make([]string, len(widths))
+		for i, w := range widths {
+			parts[i] = strings.Repeat("─", w+2)
+		}
+		return prefix + v.style(left+strings.Join(parts, mid)+right).Faint().String()
+	}
+	sep := v.style("│").Faint().String()
+
+	lines := []string{border("┌", "┬", "┐")}
+	for r, cells := range rows {
+		var b strings.Builder
+		b.WriteString(prefix + sep)
+		for i, w := range widths {
+			cell, size := "", 0
+			if i < len(cells) {
+				cell, size = cells[i], cellWidths[r][i]
+			}
+			if (r == 0 && content.HasColumnHeader) || (i == 0 && content.HasRowHeader) {
+				cell = v.style(stripANSI(cell)).Bold().String()
+			}
+			b.WriteString(" " + cell + strings.Repeat(" ", w-size+1) + sep)
+		}
+		lines = append(lines, b.String())
+		if r == 0 && content.HasColumnHeader && len(rows) > 1 {
+			lines = append(lines, border("├", "┼", "┤"))
+		}
+	}
+	return append(lines, border("└", "┴", "┘"))
+}
+
+// stripANSI removes SGR escape sequences from s.
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			i = j
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// annotate returns a copy of items with the block colour applied to text
+// without a colour of its own, and with style applied to every annotation.
+func annotate(items []notion.RichText, color string, style func(*notion.Annotations)) []notion.RichText {
+	out := make([]notion.RichText, len(items))
+	for i, item := range items {
+		var ann notion.Annotations
+		if item.Annotations != nil {
+			ann = *item.Annotations
+		}
+		if ann.Color == "" || ann.Color == "default" {
+			ann.Color = color
+		}
+		if style != nil {
+			style(&ann)
+		}
+		item.Annotations = &ann
+		out[i] = item
+	}
+	return out
+}
+
+// calloutEmoji returns the callout's emoji icon, or a light bulb for
+// custom and external icons.
+func calloutEmoji(icon map[string]interface{}) string {
+	if emoji, ok := icon["emoji"].(string); ok && emoji != "" {
+		return emoji
+	}
+	return "💡"
+}
+
+// prefixLines splits text into lines and prefixes each one.
+func prefixLines(prefix, text string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return lines
+}
+
+// writePaged writes content to stdout, through $PAGER when stdout is a
+// terminal. A pager that cannot be started is skipped rather than failing
+// the command.
+func writePaged(ctx context.Context, content string, noPager bool) error {
+	out := stdoutFromContext(ctx)
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less"}
+	}
+	if noPager || !isTerminal(out) || pager[0] == "cat" {
+		_, err := io.WriteString(out, content)
+		return err
+	}
+
+	c := exec.CommandContext(ctx, pager[0], pager[1:]...)
+	c.Stdin = strings.NewReader(content)
+	c.Stdout = out
+	c.Stderr = stderrFromContext(ctx)
+	c.Env = os.Environ()
+	if os.Getenv("LESS") == "" {
+		// Keep colours, and exit at once when the page fits on screen.
+		c.Env = append(c.Env, "LESS=FRX")
+	}
+	if err := c.Start(); err != nil {
+		_, err := io.WriteString(out, content)
+		return err
+	}
+	if err := c.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Quitting the pager early is not an error.
+			return nil
+		}
+		return fmt.Errorf("pager failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/muesli/termenv"
+)
+
+// codeSyntax describes just enough of a language to colour keywords,
+// strings, comments and numbers. It is a lexer for display, not a parser:
+// anything it does not recognise is printed as is.
+type codeSyntax struct {
+	keywords     map[string]bool
+	lineComments []string
+	blockComment [2]string
+	quotes       string
+}
+
+// Colours of highlighted tokens (ANSI 256).
+const (
+	codeKeywordColor = "170"
+	codeStringColor  = "35"
+	codeCommentColor = "245"
+	codeNumberColor  = "208"
+)
+
+func keywordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	cLikeSyntax = codeSyntax{
+		keywords:     keywordSet("if else for while do switch case default break continue return goto struct union enum typedef const static void int char float double long short unsigned signed sizeof class public private protected new delete this try catch throw namespace using template virtual bool true false null nullptr include define"),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+	}
+	goSyntax = codeSyntax{
+		keywords:     keywordSet("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var true false nil iota"),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}
+	jsSyntax = codeSyntax{
+		keywords:     keywordSet("async await break case catch class const continue debugger default delete do else export extends finally for from function if import in instanceof let new of return static super switch this throw try typeof var void while yield true false null undefined interface type implements enum readonly as"),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}
+	pythonSyntax = codeSyntax{
+		keywords:     keywordSet("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield True False None self"),
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+	rubySyntax = codeSyntax{
+		keywords:     keywordSet("alias and begin break case class def defined do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield require"),
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+	rustSyntax = codeSyntax{
+		keywords:     keywordSet("as async await break const continue crate dyn else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while"),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"`,
+	}
+	shellSyntax = codeSyntax{
+		keywords:     keywordSet("if then else elif fi for in do done while until case esac function return local export echo exit set unset source"),
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+	sqlSyntax = codeSyntax{
+		keywords:     keywordSet("select from where and or not insert into values update set delete create table alter drop index join left right inner outer on group by order having limit offset as distinct union all null is in like between case when then else end primary key foreign references SELECT FROM WHERE AND OR NOT INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE ALTER DROP INDEX JOIN LEFT RIGHT INNER OUTER ON GROUP BY ORDER HAVING LIMIT OFFSET AS DISTINCT UNION ALL NULL IS IN LIKE BETWEEN CASE WHEN THEN ELSE END PRIMARY KEY FOREIGN REFERENCES"),
+		lineComments: []string{"--"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `'"`,
+	}
+	dataSyntax = codeSyntax{
+		keywords:     keywordSet("true false null yes no"),
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+)
+
+// codeSyntaxes maps Notion code block languages to their syntax.
+var codeSyntaxes = map[string]*codeSyntax{
+	"go":          &goSyntax,
+	"c":           &cLikeSyntax,
+	"c++":         &cLikeSyntax,
+	"c#":          &cLikeSyntax,
+	"java":        &cLikeSyntax,
+	"kotlin":      &cLikeSyntax,
+	"swift":       &cLikeSyntax,
+	"javascript":  &jsSyntax,
+	"typescript":  &jsSyntax,
+	"python":      &pythonSyntax,
+	"ruby":        &rubySyntax,
+	"rust":        &rustSyntax,
+	"shell":       &shellSyntax,
+	"bash":        &shellSyntax,
+	"powershell":  &shellSyntax,
+	"sql":         &sqlSyntax,
+	"json":        &dataSyntax,
+	"yaml":        &dataSyntax,
+	"toml":        &dataSyntax,
+	"docker":      &shellSyntax,
+	"makefile":    &shellSyntax,
+	"objective-c": &cLikeSyntax,
+}
+
+// Kinds of tokens found by lexCode. Plain text has the empty kind.
+const (
+	codeTokenKeyword = "keyword"
+	codeTokenString  = "string"
+	codeTokenComment = "comment"
+	codeTokenNumber  = "number"
+)
+
+// codeTokenColors are the terminal colours of each token kind.
+var codeTokenColors = map[string]string{
+	codeTokenKeyword: codeKeywordColor,
+	codeTokenString:  codeStringColor,
+	codeTokenComment: codeCommentColor,
+	codeTokenNumber:  codeNumberColor,
+}
+
+// highlightCode colours source in the given Notion code language. Unknown
+// languages, and any profile without colour, return the code unchanged.
+func highlightCode(source, language string, profile termenv.Profile) string {
+	if profile == termenv.Ascii {
+		return source
+	}
+
+	paint := func(text, color string) string {
+		// Colour each line on its own so the pager and the indentation
+		// added by the caller never cut through an escape sequence.
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = profile.String(line).Foreground(profile.Color(color)).String()
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	var b strings.Builder
+	ok := lexCode(source, language, func(token, kind string) {
+		if kind == "" {
+			b.WriteString(token)
+		} else {
+			b.WriteString(paint(token, codeTokenColors[kind]))
+		}
+	})
+	if !ok {
+		return source
+	}
+	return b.String()
+}
+
+// lexCode splits source into tokens of the given Notion code language,
+// calling emit with each token and its kind. It reports false, emitting
+// nothing, for languages it has no syntax for.
+func lexCode(source, language string, emit func(token, kind string)) bool {
+	syntax, ok := codeSyntaxes[strings.ToLower(language)]
+	if !ok {
+		return false
+	}
+
+	rest := source
+	for rest != "" {
+		if token, ok := syntax.comment(rest); ok {
+			emit(token, codeTokenComment)
+			rest = rest[len(token):]
+			continue
+		}
+		if strings.ContainsRune(syntax.quotes, rune(rest[0])) {
+			token := quotedToken(rest)
+			emit(token, codeTokenString)
+			rest = rest[len(token):]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(rest)
+		if unicode.IsLetter(r) || r == '_' {
+			end := strings.IndexFunc(rest, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+			})
+			if end < 0 {
+				end = len(rest)
+			}
+			word := rest[:end]
+			if syntax.keywords[word] {
+				emit(word, codeTokenKeyword)
+			} else {
+				emit(word, "")
+			}
+			rest = rest[end:]
+			continue
+		}
+		if unicode.IsDigit(r) {
+			end := strings.IndexFunc(rest, func(r rune) bool {
+				return !unicode.IsDigit(r) && !unicode.IsLetter(r) && r != '.' && r != '_'
+			})
+			if end < 0 {
+				end = len(rest)
+			}
+			emit(rest[:end], codeTokenNumber)
+			rest = rest[end:]
+			continue
+		}
+		emit(rest[:size], "")
+		rest = rest[size:]
+	}
+	return true
+}
+
+// comment returns the comment starting at s, if any.
+func (c *codeSyntax) comment(s string) (string, bool) {
+	if open := c.blockComment[0]; open != "" && strings.HasPrefix(s, open) {
+		end := strings.Index(s[len(open):], c.blockComment[1])
+		if end < 0 {
+			return s, true
+		}
+		return s[:len(open)+end+len(c.blockComment[1])], true
+	}
+	for _, marker := range c.lineComments {
+		if strings.HasPrefix(s, marker) {
+			if end := strings.IndexByte(s, '\n'); end >= 0 {
+				return s[:end], true
+			}
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// quotedToken returns the string literal at the start of s, honouring
+// backslash escapes. An unterminated literal runs to the end of the line.
+func quotedToken(s string) string {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return s[:i+1]
+		case '\n':
+			if quote != '`' {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
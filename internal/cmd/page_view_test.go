@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func viewText(content ...interface{}) map[string]interface{} {
+	var items []interface{}
+	for _, c := range content {
+		switch v := c.(type) {
+		case string:
+			items = append(items, map[string]interface{}{"type": "text", "plain_text": v, "text": map[string]interface{}{"content": v}})
+		case map[string]interface{}:
+			items = append(items, v)
+		}
+	}
+	return map[string]interface{}{"rich_text": items}
+}
+
+func TestPageViewerRender(t *testing.T) {
+	blocks := []exportBlock{
+		{Type: "heading_1", Content: viewText("Plan")},
+		{Type: "paragraph", Content: viewText("Intro ", map[string]interface{}{
+			"type": "text", "plain_text": "bold", "annotations": map[string]interface{}{"bold": true},
+		})},
+		{Type: "numbered_list_item", Content: viewText("First")},
+		{Type: "numbered_list_item", Content: viewText("Second"), Children: []exportBlock{
+			{Type: "bulleted_list_item", Content: viewText("Nested")},
+		}},
+		{Type: "to_do", Content: map[string]interface{}{"rich_text": viewText("Ship")["rich_text"], "checked": true}},
+		{Type: "toggle", Content: viewText("More"), Children: []exportBlock{
+			{Type: "paragraph", Content: viewText("Hidden")},
+		}},
+		{Type: "callout", Content: map[string]interface{}{"rich_text": viewText("Heads up")["rich_text"], "icon": map[string]interface{}{"type": "emoji", "emoji": "⚠️"}}},
+		{Type: "code", Content: map[string]interface{}{"rich_text": viewText("x := 1")["rich_text"], "language": "go"}},
+		{Type: "table", Content: map[string]interface{}{"table_width": 2, "has_column_header": true}, Children: []exportBlock{
+			{Type: "table_row", Content: map[string]interface{}{"cells": []interface{}{viewText("Name")["rich_text"], viewText("Qty")["rich_text"]}}},
+			{Type: "table_row", Content: map[string]interface{}{"cells": []interface{}{viewText("Apples")["rich_text"], viewText(map[string]interface{}{
+				"type": "text", "plain_text": "3", "href": "https://example.invalid/q",
+			})["rich_text"]}}},
+		}},
+		{Type: "divider", Content: map[string]interface{}{}},
+	}
+
+	v := &pageViewer{profile: termenv.Ascii, width: 10}
+	got := strings.Join(v.render(blocks, ""), "\n")
+	want := strings.Join([]string{
+		"Plan",
+		"════",
+		"",
+		"Intro bold",
+		"",
+		"1. First",
+		"2. Second",
+		"  • Nested",
+		"☑ Ship",
+		"",
+		"▾ More",
+		"  Hidden",
+		"",
+		"│ ⚠️ Heads up",
+		"",
+		"╭─ go",
+		"│ x := 1",
+		"╰─",
+		"",
+		"┌────────┬───────────────────────────────┐",
+		"│ Name   │ Qty                           │",
+		"├────────┼───────────────────────────────┤",
+		"│ Apples │ 3 (https://example.invalid/q) │",
+		"└────────┴───────────────────────────────┘",
+		"",
+		"──────────",
+	}, "\n")
+	if got != want {
+		t.Errorf("render:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHighlightCode(t *testing.T) {
+	src := "func main() { // hi\n\treturn \"x\" + 42\n}"
+	if got := highlightCode(src, "go", termenv.Ascii); got != src {
+		t.Errorf("Ascii highlight changed the source: %q", got)
+	}
+	if got := highlightCode(src, "brainfuck", termenv.ANSI256); got != src {
+		t.Errorf("unknown language changed the source: %q", got)
+	}
+
+	got := highlightCode(src, "go", termenv.ANSI256)
+	for _, want := range []string{
+		"\x1b[38;5;170mfunc\x1b[0m",
+		"\x1b[38;5;245m// hi\x1b[0m",
+		"\x1b[38;5;35m\"x\"\x1b[0m",
+		"\x1b[38;5;208m42\x1b[0m",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("highlight = %q, missing %q", got, want)
+		}
+	}
+	if stripANSI(got) != src {
+		t.Errorf("highlight altered text: %q", stripANSI(got))
+	}
+}
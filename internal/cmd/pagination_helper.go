@@ -1,39 +1,106 @@
 package cmd
 
-import "context"
+import (
+	"context"
 
-// pageFetcher fetches a single page of results.
-type pageFetcher[T any] func(ctx context.Context, cursor string, pageSize int) (results []T, nextCursor *string, hasMore bool, err error)
+	"github.com/spf13/cobra"
 
-// fetchAllPages iterates over a paginated endpoint and returns aggregated results.
-func fetchAllPages[T any](ctx context.Context, startCursor string, pageSize int, limit int, fetch pageFetcher[T]) ([]T, *string, bool, error) {
-	cursor := startCursor
-	var allResults []T
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+	"github.com/salmonumbrella/notion-cli/internal/output"
+)
+
+// collectPages drains it and returns the items together with the cursor and
+// has_more flag that list commands report. The iterator stops as soon as its
+// Limit is reached, so --limit never fetches more pages than it needs.
+func collectPages[T any](it *notion.Iterator[T]) ([]T, *string, bool, error) {
+	items, err := it.Collect()
+	if err != nil {
+		return nil, nil, false, err
+	}
 	var nextCursor *string
-	hasMore := false
+	if c := it.NextCursor(); c != "" {
+		nextCursor = &c
+	}
+	return items, nextCursor, it.HasMore(), nil
+}
+
+// iterOptions builds iterator options from the common pagination flags.
+func iterOptions(startCursor string, pageSize, limit int) notion.IterOptions {
+	return notion.IterOptions{StartCursor: startCursor, PageSize: pageSize, Limit: limit}
+}
 
-	for {
-		results, next, more, err := fetch(ctx, cursor, pageSize)
+// addResumeFlag registers --resume on a command that takes --all.
+func addResumeFlag(cmd *cobra.Command, target *string) {
+	cmd.Flags().StringVar(target, "resume", "", "Checkpoint progress to this file and continue from it on rerun (implies --all, requires -o ndjson)")
+}
+
+// streamAllPages streams the results of the iterator newIter builds, as
+// streamPages does. With a --resume path it continues from the checkpoint
+// saved under key: from its cursor, and with only what is left of --limit
+// after the results earlier runs wrote.
+func streamAllPages[T any](ctx context.Context, resumePath, key string, opts notion.IterOptions, newIter func(notion.IterOptions) *notion.Iterator[T], transform func(T) interface{}, wrapErr func(error) error) error {
+	var state *resumeState
+	if resumePath != "" {
+		var err error
+		state, err = loadResumeState(ctx, resumePath, key)
 		if err != nil {
-			return nil, nextCursor, hasMore, err
+			return err
+		}
+		opts.StartCursor = state.startCursor(opts.StartCursor)
+		if opts.Limit > 0 {
+			opts.Limit -= state.Emitted
+			if opts.Limit <= 0 {
+				// Earlier runs already wrote --limit results.
+				return state.finish()
+			}
 		}
+	}
+	return streamPages(ctx, newIter(opts), transform, state, wrapErr)
+}
 
-		allResults = append(allResults, results...)
-		nextCursor = next
-		hasMore = more
+// streamPages writes the items of it to stdout as NDJSON as each page
+// arrives, for --all with -o ndjson. transform maps an item to its output
+// form; returning nil drops it. When state is set, a checkpoint is saved
+// after every page and removed once the last one is written. Fetch errors
+// are passed through wrapErr after the trailing cursor record is written, so
+// an interrupted run can resume with --start-cursor or --resume.
+func streamPages[T any](ctx context.Context, it *notion.Iterator[T], transform func(T) interface{}, state *resumeState, wrapErr func(error) error) error {
+	stream, err := output.NewStream(ctx, stdoutFromContext(ctx))
+	if err != nil {
+		return err
+	}
 
-		if limit > 0 && len(allResults) >= limit {
-			allResults = allResults[:limit]
+	pending := 0
+	for item, err := range it.All() {
+		if err != nil {
 			break
 		}
-
-		if !more || next == nil || *next == "" {
-			break
+		pending++
+		var out interface{} = item
+		if transform != nil {
+			out = transform(item)
+		}
+		if out != nil {
+			if err := stream.Write(out); err != nil {
+				return err
+			}
+		}
+		if state != nil && it.Buffered() == 0 {
+			if err := state.save(it.NextCursor(), pending); err != nil {
+				return err
+			}
+			pending = 0
 		}
-		cursor = *next
 	}
 
-	return allResults, nextCursor, hasMore, nil
+	closeErr := stream.Close(it.NextCursor(), it.HasMore())
+	if err := it.Err(); err != nil {
+		return wrapErr(err)
+	}
+	if state != nil && !it.HasMore() {
+		if err := state.finish(); err != nil {
+			return err
+		}
+	}
+	return closeErr
 }
-
-var _ = fetchAllPages[struct{}]
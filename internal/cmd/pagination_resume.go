@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/salmonumbrella/notion-cli/internal/errors"
+	"github.com/salmonumbrella/notion-cli/internal/output"
+)
+
+// resumeState is the checkpoint written by --resume after every page, so a
+// long --all run that dies halfway can continue from where it stopped.
+type resumeState struct {
+	path string
+
+	// Key identifies the query the checkpoint belongs to.
+	Key string `json:"key"`
+	// NextCursor is the cursor after the last page fully written.
+	NextCursor string `json:"next_cursor"`
+	// Emitted counts results processed across all runs so far, which a
+	// resumed run takes off --limit.
+	Emitted   int    `json:"emitted"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// resumeKey derives a checkpoint key from the command and the request that
+// selects its results, so a state file is never applied to a different query.
+func resumeKey(command string, req interface{}) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return command + ":" + hex.EncodeToString(sum[:8])
+}
+
+// loadResumeState opens the checkpoint at path, or starts a new one when the
+// file does not exist yet. Results are only written as they arrive with
+// streamed NDJSON, so other formats are rejected: a checkpoint would
+// otherwise skip results that were fetched but never printed.
+func loadResumeState(ctx context.Context, path, key string) (*resumeState, error) {
+	if !output.CanStream(ctx) {
+		return nil, errors.NewUserError(
+			"--resume requires streamed NDJSON output",
+			"Use -o ndjson without --sort-by so results are written as each page arrives",
+		)
+	}
+
+	state := &resumeState{path: path, Key: key}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resume state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.NewUserError(
+			fmt.Sprintf("invalid resume state file %s: %v", path, err),
+			"Delete the file to start over",
+		)
+	}
+	if state.Key != key {
+		return nil, errors.NewUserError(
+			fmt.Sprintf("resume state %s belongs to a different query", path),
+			"Use another --resume file, or delete this one to start over",
+		)
+	}
+	return state, nil
+}
+
+// startCursor returns the cursor to continue from, falling back to the
+// --start-cursor flag for a fresh checkpoint.
+func (s *resumeState) startCursor(flag string) string {
+	if s.NextCursor != "" {
+		return s.NextCursor
+	}
+	return flag
+}
+
+// save records that n more results were written and the next page starts
+// at cursor.
+func (s *resumeState) save(cursor string, n int) error {
+	s.NextCursor = cursor
+	s.Emitted += n
+	s.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode resume state: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write resume state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write resume state: %w", err)
+	}
+	return nil
+}
+
+// finish removes the checkpoint once every page has been written.
+func (s *resumeState) finish() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove resume state: %w", err)
+	}
+	return nil
+}
@@ -1,7 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/salmonumbrella/notion-cli/internal/output"
@@ -168,3 +177,257 @@ func TestCapPageSize_WithContextLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestUserListAll_LimitStopsFetching(t *testing.T) {
+	var pageSizes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := r.URL.Query().Get("page_size")
+		pageSizes = append(pageSizes, size)
+		n, _ := strconv.Atoi(size)
+		results := make([]map[string]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			results = append(results, map[string]interface{}{"object": "user", "id": fmt.Sprintf("u%d-%d", len(pageSizes), i)})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object":      "list",
+			"results":     results,
+			"has_more":    true,
+			"next_cursor": fmt.Sprintf("cursor-%d", len(pageSizes)),
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"user", "list", "--all", "--page-size", "2", "--limit", "3", "-o", "json"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("user list failed: %v\nstderr=%s", err, errBuf.String())
+	}
+
+	if fmt.Sprint(pageSizes) != "[2 1]" {
+		t.Fatalf("page sizes requested = %v, want [2 1]", pageSizes)
+	}
+
+	var got struct {
+		Results    []map[string]interface{} `json:"results"`
+		HasMore    bool                     `json:"has_more"`
+		NextCursor string                   `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out.String())
+	}
+	if len(got.Results) != 3 || !got.HasMore || got.NextCursor != "cursor-2" {
+		t.Errorf("results=%d has_more=%v next_cursor=%q", len(got.Results), got.HasMore, got.NextCursor)
+	}
+}
+
+func TestSearchAll_StreamsNDJSONWithResumeCursor(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		cursor, _ := req["start_cursor"].(string)
+		cursors = append(cursors, cursor)
+		if len(cursors) == 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "error", "status": 400, "code": "validation_error", "message": "boom"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": "list",
+			"results": []map[string]interface{}{
+				{"object": "page", "id": fmt.Sprintf("p%d-a", len(cursors))},
+				{"object": "page", "id": fmt.Sprintf("p%d-b", len(cursors))},
+			},
+			"has_more":    true,
+			"next_cursor": fmt.Sprintf("cursor-%d", len(cursors)),
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"search", "--all", "--jq", ".id", "-o", "ndjson"})
+	if err := root.ExecuteContext(context.Background()); err == nil {
+		t.Fatal("expected the failed third page to be reported")
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 4 results and a trailer:\n%s", len(lines), out.String())
+	}
+	if string(lines[0]) != `"p1-a"` || string(lines[3]) != `"p2-b"` {
+		t.Errorf("unexpected results:\n%s", out.String())
+	}
+
+	var trailer struct {
+		Meta struct {
+			FetchedCount int    `json:"fetched_count"`
+			HasMore      bool   `json:"has_more"`
+			NextCursor   string `json:"next_cursor"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(lines[4], &trailer); err != nil {
+		t.Fatalf("decode trailer: %v", err)
+	}
+	if trailer.Meta.FetchedCount != 4 || !trailer.Meta.HasMore || trailer.Meta.NextCursor != "cursor-2" {
+		t.Errorf("trailer = %+v, want 4 results resuming at cursor-2", trailer.Meta)
+	}
+}
+
+func TestSearchResume_ContinuesFromCheckpoint(t *testing.T) {
+	failAt := 3
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		cursor, _ := req["start_cursor"].(string)
+		cursors = append(cursors, cursor)
+		if len(cursors) == failAt {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "error", "status": 400, "code": "validation_error", "message": "boom"})
+			return
+		}
+		page := 1
+		if cursor != "" {
+			page, _ = strconv.Atoi(cursor[len("cursor-"):])
+			page++
+		}
+		resp := map[string]interface{}{
+			"object":   "list",
+			"results":  []map[string]interface{}{{"object": "page", "id": fmt.Sprintf("p%d", page)}},
+			"has_more": page < 4,
+		}
+		if page < 4 {
+			resp["next_cursor"] = fmt.Sprintf("cursor-%d", page)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+	statePath := filepath.Join(t.TempDir(), "search.state")
+
+	run := func() (string, error) {
+		var out, errBuf bytes.Buffer
+		app := &App{Stdout: &out, Stderr: &errBuf}
+		root := app.RootCommand()
+		root.SetArgs([]string{"search", "q", "--resume", statePath, "--results-only", "--jq", ".id", "-o", "ndjson"})
+		err := root.ExecuteContext(context.Background())
+		return out.String(), err
+	}
+
+	out, err := run()
+	if err == nil {
+		t.Fatal("expected first run to fail on the third page")
+	}
+	if out != "\"p1\"\n\"p2\"\n" {
+		t.Fatalf("first run output = %q", out)
+	}
+	var state resumeState
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	if state.NextCursor != "cursor-2" || state.Emitted != 2 {
+		t.Fatalf("state = %+v, want cursor-2 after 2 results", state)
+	}
+
+	failAt = 0
+	out, err = run()
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if out != "\"p3\"\n\"p4\"\n" {
+		t.Errorf("second run output = %q, want p3 and p4", out)
+	}
+	if cursors[len(cursors)-2] != "cursor-2" {
+		t.Errorf("second run started at %q, want cursor-2", cursors[len(cursors)-2])
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Error("state file should be removed after a complete run")
+	}
+}
+
+func TestUserListResume_KeepsLimitAcrossRuns(t *testing.T) {
+	failAt := 3
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == failAt {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "error", "status": 400, "code": "validation_error", "message": "boom"})
+			return
+		}
+		page := 1
+		if cursor := r.URL.Query().Get("start_cursor"); cursor != "" {
+			page, _ = strconv.Atoi(cursor[len("cursor-"):])
+			page++
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object":      "list",
+			"results":     []map[string]interface{}{{"object": "user", "id": fmt.Sprintf("u%d", page)}},
+			"has_more":    true,
+			"next_cursor": fmt.Sprintf("cursor-%d", page),
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("NOTION_API_BASE_URL", server.URL)
+	statePath := filepath.Join(t.TempDir(), "users.state")
+
+	run := func() (string, error) {
+		var out, errBuf bytes.Buffer
+		app := &App{Stdout: &out, Stderr: &errBuf}
+		root := app.RootCommand()
+		root.SetArgs([]string{"user", "list", "--resume", statePath, "--limit", "3", "--results-only", "--jq", ".id", "-o", "ndjson"})
+		err := root.ExecuteContext(context.Background())
+		return out.String(), err
+	}
+
+	if out, err := run(); err == nil || out != "\"u1\"\n\"u2\"\n" {
+		t.Fatalf("first run = %q, %v; want u1 and u2, then an error", out, err)
+	}
+	failAt = 0
+	if out, err := run(); err != nil || out != "\"u3\"\n" {
+		t.Fatalf("second run = %q, %v; want only u3 to reach --limit 3", out, err)
+	}
+	if out, err := run(); err != nil || out != "" {
+		t.Fatalf("third run = %q, %v; want nothing once --limit is reached", out, err)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Error("state file should be removed once --limit is reached")
+	}
+}
+
+func TestResume_RequiresNDJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "test-token")
+	t.Setenv("NOTION_API_BASE_URL", "http://127.0.0.1:0")
+
+	var out, errBuf bytes.Buffer
+	app := &App{Stdout: &out, Stderr: &errBuf}
+	root := app.RootCommand()
+	root.SetArgs([]string{"search", "q", "--resume", filepath.Join(t.TempDir(), "s.state"), "-o", "json"})
+	err := root.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "NDJSON") {
+		t.Fatalf("err = %v, want NDJSON requirement", err)
+	}
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -113,19 +112,8 @@ Examples:
 			var hasMore bool
 
 			if all {
-				allResults, nc, hm, err := fetchAllPages(ctx, startCursor, pageSize, limit, func(ctx context.Context, cursor string, pageSize int) ([]map[string]interface{}, *string, bool, error) {
-					req := &notion.SearchRequest{
-						Query:       query,
-						Filter:      filter,
-						StartCursor: cursor,
-						PageSize:    pageSize,
-					}
-					res, err := client.Search(ctx, req)
-					if err != nil {
-						return nil, nil, false, err
-					}
-					return res.Results, res.NextCursor, res.HasMore, nil
-				})
+				req := &notion.SearchRequest{Query: query, Filter: filter}
+				allResults, nc, hm, err := collectPages(client.IterSearch(ctx, req, iterOptions(startCursor, pageSize, limit)))
 				if err != nil {
 					return fmt.Errorf("failed to search: %w", err)
 				}
@@ -9,8 +9,10 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
 
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/salmonumbrella/notion-cli/internal/errors"
 	"github.com/salmonumbrella/notion-cli/internal/logging"
 	"github.com/salmonumbrella/notion-cli/internal/notion"
+	"github.com/salmonumbrella/notion-cli/internal/output"
 	"github.com/salmonumbrella/notion-cli/internal/skill"
 	"github.com/salmonumbrella/notion-cli/internal/ui"
 )
@@ -39,12 +42,21 @@ func newRootCmd(app *App) *cobra.Command {
 		pickFlag      string
 		jsonPathFlag  string
 		queryFile     string
+		templateFlag  string
+		templateFile  string
+		wrapFlag      bool
 		errorFormat   string
 		quietFlag     bool
 		failEmptyFlag bool
 		compactJSON   bool
 		latestFlag    bool
 		recentFlag    int
+		rateFlag      float64
+		sharedRate    bool
+		cacheFlag     bool
+		noCacheFlag   bool
+		recordDir     string
+		replayDir     string
 
 		// Agent-friendly flags
 		yesFlag         bool
@@ -90,6 +102,9 @@ func newRootCmd(app *App) *cobra.Command {
 				fieldsFlag:      fieldsFlag,
 				pickFlag:        pickFlag,
 				jsonPathFlag:    jsonPathFlag,
+				templateFlag:    templateFlag,
+				templateFile:    templateFile,
+				wrapFlag:        wrapFlag,
 				quietFlag:       quietFlag,
 				failEmptyFlag:   failEmptyFlag,
 				compactJSON:     compactJSON,
@@ -101,6 +116,12 @@ func newRootCmd(app *App) *cobra.Command {
 				descFlag:        descFlag,
 				resultsOnlyFlag: resultsOnlyFlag,
 				errorFormat:     errorFormat,
+				rateFlag:        rateFlag,
+				sharedRateFlag:  sharedRate,
+				cacheFlag:       cacheFlag,
+				noCacheFlag:     noCacheFlag,
+				recordDir:       recordDir,
+				replayDir:       replayDir,
 			})
 			if err != nil {
 				return err
@@ -111,6 +132,10 @@ func newRootCmd(app *App) *cobra.Command {
 
 			// Inject parsed global options into context so subcommands can access them.
 			ctx := buildRootContext(cmd.Context(), app, cfg, debugMode, opts)
+			ctx, err = withCassette(ctx, opts)
+			if err != nil {
+				return err
+			}
 			if opts.queryNormalized && !opts.quiet {
 				ui.FromContext(ctx).Warning("Normalized --query by removing \\! (shell escape); use ! without backslash.")
 			}
@@ -145,7 +170,7 @@ func newRootCmd(app *App) *cobra.Command {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("ntn %s (commit: %s, built: %s)\n", app.Version, app.Commit, app.BuildTime))
 
 	// Global flags
-	rootCmd.PersistentFlags().StringP("output", "o", "text", "Output format: text|json|ndjson|jsonl|table|yaml")
+	rootCmd.PersistentFlags().StringP("output", "o", "text", "Output format: text|json|ndjson|jsonl|table|yaml|csv|tsv|markdown|template")
 	// Alias --format to --output for agent discoverability
 	rootCmd.PersistentFlags().String("format", "text", "Alias for --output")
 	_ = rootCmd.PersistentFlags().MarkHidden("format")
@@ -161,7 +186,16 @@ func newRootCmd(app *App) *cobra.Command {
 	_ = rootCmd.PersistentFlags().MarkHidden("pick")
 	rootCmd.PersistentFlags().StringVar(&jsonPathFlag, "jsonpath", "", "Extract a value using JSONPath (e.g. $.results[0].id)")
 	rootCmd.PersistentFlags().StringVar(&queryFile, "query-file", "", "Read JQ expression from file ('-' for stdin)")
+	rootCmd.PersistentFlags().StringVar(&templateFlag, "template", "", "Go template for -o template (implies -o template)")
+	rootCmd.PersistentFlags().StringVar(&templateFile, "template-file", "", "Read Go template from file ('-' for stdin)")
+	rootCmd.PersistentFlags().BoolVar(&wrapFlag, "wrap", false, "Wrap long cells in -o table output instead of truncating them")
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Enable debug output (shows HTTP requests/responses)")
+	rootCmd.PersistentFlags().Float64Var(&rateFlag, "rate", notion.DefaultRateLimit, "Max API requests per second, paced client-side (0 disables pacing)")
+	rootCmd.PersistentFlags().BoolVar(&sharedRate, "shared-rate", false, "Share the --rate budget with other ntn processes using the same workspace (not synchronised on Windows)")
+	rootCmd.PersistentFlags().BoolVar(&cacheFlag, "cache", false, "Serve page/database/data source/user/block-children reads from the on-disk cache")
+	rootCmd.PersistentFlags().BoolVar(&noCacheFlag, "no-cache", false, "Bypass the on-disk response cache (overrides cache in config)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Record API requests/responses to a cassette directory (tokens redacted)")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Serve API responses from a recorded cassette directory without network access")
 	rootCmd.PersistentFlags().StringVarP(&workspaceName, "workspace", "w", "", "Workspace to use (overrides NOTION_WORKSPACE env var)")
 	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "auto", "Error output format (auto|text|json)")
 	rootCmd.PersistentFlags().BoolVar(&quietFlag, "quiet", false, "Suppress non-essential output")
@@ -215,11 +249,13 @@ func newRootCmd(app *App) *cobra.Command {
 	rootCmd.AddCommand(newWebhookCmd())
 	rootCmd.AddCommand(newCompletionCmd())
 	rootCmd.AddCommand(newAPICmd())
+	rootCmd.AddCommand(newCacheCmd())
 	rootCmd.AddCommand(newImportCmd())
 	rootCmd.AddCommand(newMCPCmd())
 	rootCmd.AddCommand(newWorkersCmd())
 	rootCmd.AddCommand(newBulkCmd())
 	rootCmd.AddCommand(newSkillCmd())
+	rootCmd.AddCommand(newSiteCmd())
 
 	// Top-level convenience commands (desire-path aliases)
 	var loginNoBrowser bool
@@ -554,6 +590,8 @@ func checkTokenAgeAndWarn(ctx context.Context, quiet bool) {
 func NewNotionClient(ctx context.Context, token string) *notion.Client {
 	client := notion.NewClient(token)
 
+	cfg := ConfigFromContext(ctx)
+
 	// Allows tests and proxies to override the Notion API base URL.
 	// Precedence:
 	// 1) NOTION_API_BASE_URL env var
@@ -561,7 +599,6 @@ func NewNotionClient(ctx context.Context, token string) *notion.Client {
 	if baseURL := strings.TrimSpace(os.Getenv("NOTION_API_BASE_URL")); baseURL != "" {
 		client.WithBaseURL(baseURL)
 	} else {
-		cfg := ConfigFromContext(ctx)
 		if cfg == nil {
 			// Backward compatibility for tests/direct calls that bypass root pre-run.
 			cfg, _ = config.Load()
@@ -579,12 +616,43 @@ func NewNotionClient(ctx context.Context, token string) *notion.Client {
 			}
 		}
 	}
+	if rate, ok := RateLimitFromContext(ctx); ok {
+		client.WithRateLimit(rate)
+		if rate > 0 && SharedRateFromContext(ctx) {
+			if path, err := config.SharedRateLimitPath(stateWorkspace(ctx, cfg)); err == nil {
+				client.WithRateLimiter(notion.NewSharedTokenBucket(path, rate, notion.DefaultRateBurst))
+			}
+		}
+	}
+	if replayer := ReplayerFromContext(ctx); replayer != nil {
+		// Replays never reach the API, so there is nothing to pace.
+		client.WithTransport(replayer).WithRateLimiter(nil)
+	} else if recorder := RecorderFromContext(ctx); recorder != nil {
+		client.WithTransport(recorder.Wrap(client.Transport()))
+	}
+	if ResponseCacheFromContext(ctx) {
+		if dir, err := config.ResponseCacheDir(stateWorkspace(ctx, cfg)); err == nil {
+			client.WithCache(notion.NewResponseCache(dir, ResponseCacheTTLFromContext(ctx)))
+		}
+	}
 	if debug.IsDebug(ctx) {
 		client.WithDebugOutput(stderrFromContext(ctx))
 	}
 	return client
 }
 
+// stateWorkspace returns the workspace name that keys per-workspace state
+// such as the shared request budget and the response cache.
+func stateWorkspace(ctx context.Context, cfg *config.Config) string {
+	if ws := WorkspaceFromContext(ctx); ws != "" {
+		return ws
+	}
+	if cfg != nil {
+		return cfg.DefaultWorkspace
+	}
+	return ""
+}
+
 // GetTokenFromContext retrieves the token based on workspace context.
 // If a workspace is specified in context, it gets the workspace-specific token.
 // Otherwise, falls back to the default token retrieval.
@@ -606,6 +674,36 @@ func isTerminal(w io.Writer) bool {
 	return term.IsTerminal(int(f.Fd()))
 }
 
+// terminalTableOptions decides how -o table renders to w. On a terminal the
+// table is fitted to its width and coloured per the color setting; elsewhere
+// the plain layout is kept unless colour is forced or --wrap is given.
+func terminalTableOptions(w io.Writer, colorSetting string, wrap bool) (output.TerminalOptions, bool) {
+	mode := parseColorMode(colorSetting)
+	if os.Getenv("NO_COLOR") != "" {
+		mode = ui.ColorNever
+	}
+	tty := isTerminal(w)
+	if !tty && mode != ui.ColorAlways && !wrap {
+		return output.TerminalOptions{}, false
+	}
+
+	opts := output.TerminalOptions{Color: mode == ui.ColorAlways, Wrap: wrap}
+	if f, ok := w.(*os.File); ok && tty {
+		if mode == ui.ColorAuto {
+			opts.Color = termenv.NewOutput(f).ColorProfile() != termenv.Ascii
+		}
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil {
+			opts.Width = width
+		}
+	}
+	if opts.Width == 0 {
+		if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+			opts.Width = n
+		}
+	}
+	return opts, true
+}
+
 func parseColorMode(value string) ui.ColorMode {
 	switch strings.ToLower(strings.TrimSpace(value)) {
 	case "always":
@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
 
+	"github.com/salmonumbrella/notion-cli/internal/notion"
 	"github.com/salmonumbrella/notion-cli/internal/output"
 )
 
@@ -114,3 +118,87 @@ func TestRootCmd_ContextFallback(t *testing.T) {
 }
 
 // Backwards compatibility test removed: output format is now context-driven only.
+
+func TestRootCmd_RateLimitPrecedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		configYML string
+		args      []string
+		want      float64
+	}{
+		{name: "default", want: notion.DefaultRateLimit},
+		{name: "config", configYML: "rate: 1.5\n", want: 1.5},
+		{name: "flag overrides config", configYML: "rate: 1.5\n", args: []string{"--rate", "0"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if tt.configYML != "" {
+				path := filepath.Join(home, ".config", "notion-cli", "config.yaml")
+				if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.configYML), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got float64
+			var ok bool
+			app := &App{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+			root := app.RootCommand()
+			root.AddCommand(&cobra.Command{
+				Use: "probe",
+				RunE: func(cmd *cobra.Command, args []string) error {
+					got, ok = RateLimitFromContext(cmd.Context())
+					return nil
+				},
+			})
+			root.SetArgs(append([]string{"probe"}, tt.args...))
+			if err := root.ExecuteContext(context.Background()); err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if !ok || got != tt.want {
+				t.Fatalf("rate = %v (set=%v), want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootCmd_RateLimitRejectsNegative(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	app := &App{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	root := app.RootCommand()
+	root.AddCommand(&cobra.Command{Use: "probe", RunE: func(*cobra.Command, []string) error { return nil }})
+	root.SetArgs([]string{"probe", "--rate", "-1"})
+	if err := root.ExecuteContext(context.Background()); err == nil {
+		t.Fatal("expected error for negative --rate")
+	}
+}
+
+func TestTerminalTableOptions(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("COLUMNS", "")
+
+	if _, ok := terminalTableOptions(&bytes.Buffer{}, "auto", false); ok {
+		t.Error("piped output should keep the plain table")
+	}
+
+	opts, ok := terminalTableOptions(&bytes.Buffer{}, "always", false)
+	if !ok || !opts.Color || opts.Width != 0 {
+		t.Errorf("color=always: opts = %+v, ok = %v", opts, ok)
+	}
+
+	t.Setenv("COLUMNS", "72")
+	opts, ok = terminalTableOptions(&bytes.Buffer{}, "never", true)
+	if !ok || opts.Color || !opts.Wrap || opts.Width != 72 {
+		t.Errorf("--wrap with color=never: opts = %+v, ok = %v", opts, ok)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if opts, _ := terminalTableOptions(&bytes.Buffer{}, "always", true); opts.Color {
+		t.Error("NO_COLOR should disable colour")
+	}
+}
@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -129,3 +132,30 @@ func TestRootFlagParity_NonInteractiveTrio(t *testing.T) {
 		t.Fatal("--no-input should set --yes")
 	}
 }
+
+func TestRootTemplateFlags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmplFile := filepath.Join(t.TempDir(), "out.tmpl")
+	if err := os.WriteFile(tmplFile, []byte("{{.}}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "template with other format", args: []string{"config", "path", "--template", "{{.}}", "-o", "json"}, wantErr: "require -o template"},
+		{name: "template and template-file", args: []string{"config", "path", "--template", "{{.}}", "--template-file", tmplFile}, wantErr: "--template-file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newFlagParityTestRoot(t).RootCommand()
+			root.SetArgs(tt.args)
+			err := root.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
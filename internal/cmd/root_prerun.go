@@ -6,9 +6,11 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/salmonumbrella/notion-cli/internal/cassette"
 	"github.com/salmonumbrella/notion-cli/internal/cmdutil"
 	"github.com/salmonumbrella/notion-cli/internal/config"
 	"github.com/salmonumbrella/notion-cli/internal/debug"
@@ -24,6 +26,9 @@ type globalFlagInput struct {
 	fieldsFlag      string
 	pickFlag        string
 	jsonPathFlag    string
+	templateFlag    string
+	templateFile    string
+	wrapFlag        bool
 	quietFlag       bool
 	failEmptyFlag   bool
 	compactJSON     bool
@@ -35,6 +40,12 @@ type globalFlagInput struct {
 	descFlag        bool
 	resultsOnlyFlag bool
 	errorFormat     string
+	rateFlag        float64
+	sharedRateFlag  bool
+	cacheFlag       bool
+	noCacheFlag     bool
+	recordDir       string
+	replayDir       string
 }
 
 type globalOptions struct {
@@ -44,6 +55,8 @@ type globalOptions struct {
 	queryNormalized bool
 	fieldsRaw       string
 	jsonPathRaw     string
+	template        string
+	wrap            bool
 	quiet           bool
 	failEmpty       bool
 	compactJSON     bool
@@ -54,10 +67,19 @@ type globalOptions struct {
 	desc            bool
 	resultsOnly     bool
 	errorFormat     string
+	rate            float64
+	sharedRate      bool
+	cache           bool
+	noCache         bool
+	cacheTTL        time.Duration
+	recordDir       string
+	replayDir       string
 
 	queryFlagSet     bool
 	jqFlagSet        bool
 	queryFileFlagSet bool
+	templateFlagSet  bool
+	templateFileSet  bool
 	fieldsFlagSet    bool
 	pickFlagSet      bool
 	recentFlagSet    bool
@@ -84,6 +106,13 @@ func parseGlobalOptions(cmd *cobra.Command, cfg *config.Config, stdout io.Writer
 		desc:        flags.descFlag,
 		resultsOnly: flags.resultsOnlyFlag,
 		errorFormat: flags.errorFormat,
+		rate:        flags.rateFlag,
+		sharedRate:  flags.sharedRateFlag,
+		cache:       flags.cacheFlag,
+		noCache:     flags.noCacheFlag,
+		cacheTTL:    cfg.GetCacheTTL(),
+		recordDir:   strings.TrimSpace(flags.recordDir),
+		replayDir:   strings.TrimSpace(flags.replayDir),
 
 		queryFlagSet:   strings.TrimSpace(flags.queryFlag) != "",
 		jqFlagSet:      strings.TrimSpace(flags.jqFlag) != "",
@@ -101,6 +130,18 @@ func parseGlobalOptions(cmd *cobra.Command, cfg *config.Config, stdout io.Writer
 		recentFlag:     flags.recentFlag,
 	}
 
+	if !commandFlagChanged(cmd, "rate") {
+		if rate, ok := cfg.GetRate(); ok {
+			opts.rate = rate
+		}
+	}
+	if !commandFlagChanged(cmd, "shared-rate") {
+		opts.sharedRate = cfg.GetSharedRate()
+	}
+	if !commandFlagChanged(cmd, "cache") && !opts.noCache {
+		opts.cache = cfg.GetCache()
+	}
+
 	lightValue, hasLightFlag := commandBoolFlagValue(cmd, "light")
 	opts.light = hasLightFlag && lightValue
 
@@ -137,7 +178,7 @@ func parseGlobalOptions(cmd *cobra.Command, cfg *config.Config, stdout io.Writer
 
 	if !cmd.Flags().Changed("quiet") && !isTerminal(stdout) {
 		switch opts.format {
-		case output.FormatJSON, output.FormatNDJSON, output.FormatYAML:
+		case output.FormatJSON, output.FormatNDJSON, output.FormatYAML, output.FormatCSV, output.FormatTSV:
 			opts.quiet = true
 		}
 	}
@@ -165,6 +206,21 @@ func parseGlobalOptions(cmd *cobra.Command, cfg *config.Config, stdout io.Writer
 	}
 	opts.jsonPathRaw = strings.TrimSpace(flags.jsonPathFlag)
 
+	opts.wrap = flags.wrapFlag
+	opts.template = flags.templateFlag
+	opts.templateFlagSet = flags.templateFlag != ""
+	opts.templateFileSet = strings.TrimSpace(flags.templateFile) != ""
+	if opts.templateFileSet {
+		loaded, err := cmdutil.ReadInputSource(flags.templateFile)
+		if err != nil {
+			return globalOptions{}, err
+		}
+		opts.template = loaded
+	}
+	if (opts.templateFlagSet || opts.templateFileSet) && !opts.outputFlagSet && !opts.formatFlagSet && !opts.jsonFlagSet {
+		opts.format = output.FormatTemplate
+	}
+
 	return opts, nil
 }
 
@@ -189,6 +245,12 @@ func validateGlobalOptions(opts *globalOptions) error {
 	if opts.fieldsRaw != "" && opts.jsonPathRaw != "" {
 		return errOnlyOne("--fields/--pick", "--jsonpath")
 	}
+	if opts.templateFlagSet && opts.templateFileSet {
+		return errOnlyOne("--template", "--template-file")
+	}
+	if (opts.templateFlagSet || opts.templateFileSet) && opts.format != output.FormatTemplate {
+		return fmt.Errorf("--template/--template-file require -o template (got -o %s)", opts.format)
+	}
 	if opts.recentFlagSet && opts.recentFlag <= 0 {
 		return fmt.Errorf("--recent must be >= 1")
 	}
@@ -206,6 +268,15 @@ func validateGlobalOptions(opts *globalOptions) error {
 		opts.sortBy = "created_time"
 		opts.desc = true
 	}
+	if opts.rate < 0 {
+		return fmt.Errorf("--rate must be >= 0")
+	}
+	if opts.cache && opts.noCache {
+		return errOnlyOne("--cache", "--no-cache")
+	}
+	if opts.recordDir != "" && opts.replayDir != "" {
+		return errOnlyOne("--record", "--replay")
+	}
 	if err := validateErrorFormat(opts.errorFormat); err != nil {
 		return err
 	}
@@ -226,15 +297,38 @@ func buildRootContext(ctx context.Context, app *App, cfg *config.Config, debugMo
 	ctx = output.WithQuiet(ctx, opts.quiet)
 	ctx = output.WithFields(ctx, opts.fieldsRaw)
 	ctx = output.WithJSONPath(ctx, opts.jsonPathRaw)
+	ctx = output.WithTemplate(ctx, opts.template)
 	ctx = output.WithFailEmpty(ctx, opts.failEmpty)
 	ctx = output.WithResultsOnly(ctx, opts.resultsOnly)
 	ctx = output.WithLight(ctx, opts.light)
 	ctx = output.WithCompactJSON(ctx, opts.compactJSON)
 	ctx = WithErrorFormat(ctx, opts.errorFormat)
+	ctx = WithRateLimit(ctx, opts.rate)
+	ctx = WithSharedRate(ctx, opts.sharedRate)
+	ctx = WithResponseCache(ctx, opts.cache)
+	ctx = WithResponseCacheTTL(ctx, opts.cacheTTL)
 	ctx = ui.WithUI(ctx, ui.New(parseColorMode(cfg.GetColor())))
+	if term, ok := terminalTableOptions(app.Stdout, cfg.GetColor(), opts.wrap); ok {
+		ctx = output.WithTerminal(ctx, term)
+	}
 	return ctx
 }
 
+// withCassette prepares --record/--replay for every client created by the command.
+func withCassette(ctx context.Context, opts globalOptions) (context.Context, error) {
+	if opts.replayDir != "" {
+		replayer, err := cassette.Load(opts.replayDir)
+		if err != nil {
+			return ctx, err
+		}
+		return WithReplayer(ctx, replayer), nil
+	}
+	if opts.recordDir != "" {
+		return WithRecorder(ctx, cassette.NewRecorder(opts.recordDir)), nil
+	}
+	return ctx, nil
+}
+
 func errOnlyOne(left, right string) error {
 	return fmt.Errorf("use only one of %s or %s", left, right)
 }
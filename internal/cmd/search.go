@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -19,6 +18,7 @@ func newSearchCmd() *cobra.Command {
 	var all bool
 	var textQuery string
 	var light bool
+	var resumePath string
 
 	cmd := &cobra.Command{
 		Use:     "search [query]",
@@ -32,6 +32,8 @@ Use --sort to specify sort order (JSON object with "direction" and "timestamp" k
 Use --page-size to control the number of results per page (max 100).
 Use --start-cursor for pagination.
 Use --all to fetch all pages of results automatically.
+Use --resume <state-file> (with -o ndjson) to checkpoint progress after every
+page and continue from it when the command is rerun.
 Use --light (or --li) for compact output (id, object, title, url).
 Use global --results-only to output just the results array (useful for piping to jq).
 
@@ -121,23 +123,28 @@ Example - Fetch all results:
 			}
 
 			// If --all flag is set, fetch all pages
-			if all {
-				allResults, nextCursor, hasMore, err := fetchAllPages(ctx, startCursor, pageSize, limit, func(ctx context.Context, cursor string, pageSize int) ([]map[string]interface{}, *string, bool, error) {
-					req := &notion.SearchRequest{
-						Query:       query,
-						Sort:        sort,
-						Filter:      filter,
-						StartCursor: cursor,
-						PageSize:    pageSize,
-					}
-
-					result, err := client.Search(ctx, req)
-					if err != nil {
-						return nil, nil, false, err
+			if all || resumePath != "" {
+				req := &notion.SearchRequest{Query: query, Sort: sort, Filter: filter}
+				opts := iterOptions(startCursor, pageSize, limit)
+				newIter := func(opts notion.IterOptions) *notion.Iterator[map[string]interface{}] {
+					return client.IterSearch(ctx, req, opts)
+				}
+				if output.CanStream(ctx) || resumePath != "" {
+					var transform func(map[string]interface{}) interface{}
+					if light {
+						transform = func(r map[string]interface{}) interface{} {
+							if l := toLightSearchResults([]map[string]interface{}{r}); len(l) > 0 {
+								return l[0]
+							}
+							return nil
+						}
 					}
+					return streamAllPages(ctx, resumePath, resumeKey("search", req), opts, newIter, transform, func(err error) error {
+						return fmt.Errorf("failed to search: %w", err)
+					})
+				}
 
-					return result.Results, result.NextCursor, result.HasMore, nil
-				})
+				allResults, nextCursor, hasMore, err := collectPages(newIter(opts))
 				if err != nil {
 					return fmt.Errorf("failed to search: %w", err)
 				}
@@ -188,6 +195,7 @@ Example - Fetch all results:
 	cmd.Flags().StringVar(&startCursor, "start-cursor", "", "Pagination cursor")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Number of results per page (max 100)")
 	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of results (may be slow for large datasets)")
+	addResumeFlag(cmd, &resumePath)
 	cmd.Flags().StringVarP(&textQuery, "text", "t", "", "Search text (alternative to positional argument)")
 	cmd.Flags().BoolVar(&light, "light", false, "Return compact payload (id, object, title, url)")
 
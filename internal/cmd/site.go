@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notion-cli/internal/cmdutil"
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+func newSiteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "site",
+		Short: "Build static HTML sites from page trees",
+		Long: `Build static HTML sites from Notion page trees.
+
+Sites are plain HTML files that can be served from any web server or
+opened from disk, so docs can be published without Notion's public sharing.`,
+	}
+
+	cmd.AddCommand(newSiteBuildCmd())
+	return cmd
+}
+
+func newSiteBuildCmd() *cobra.Command {
+	var outDir string
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "build <root-page>",
+		Short: "Render a page tree to a static HTML site",
+		Long: `Render a page and all its child pages and databases to a static HTML site.
+
+Every page becomes a self-contained HTML file with a navigation sidebar built
+from the page hierarchy, a table of contents and highlighted code. Databases
+become HTML tables of their rows, each row linking to its own page. Images
+and files hosted by Notion are downloaded next to the pages that use them,
+and index.html opens the root page. manifest.json maps IDs to paths as for
+ntn page export --recursive.
+
+Example:
+  ntn site build 12345678-1234-1234-1234-123456789012 --out site/`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			sf := SkillFileFromContext(ctx)
+
+			pageID, err := cmdutil.NormalizeNotionID(resolveID(sf, args[0]))
+			if err != nil {
+				return err
+			}
+
+			client, err := clientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			exporter := newTreeExporter(client, outDir, "html", markdownFallbackComment, concurrency)
+			manifest, err := exporter.export(ctx, pageID)
+			if err != nil {
+				return err
+			}
+			if err := exporter.writeSiteIndex(); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(stderrFromContext(ctx), "Built site with %d pages and %d files in %s\n", len(manifest.Pages), len(manifest.Files), outDir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&outDir, "out", "site", "Output directory")
+	cmd.Flags().IntVar(&concurrency, "concurrency", notion.DefaultBlockFetchConcurrency, "Maximum parallel block fetches")
+
+	return cmd
+}
+
+// writeSiteIndex writes index.html, sending visitors to the root page.
+func (e *treeExporter) writeSiteIndex() error {
+	root := e.nodes[0]
+	if root.path == "index.html" {
+		return nil
+	}
+	target := html.EscapeString(e.link("index.html", root.path))
+	page := `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="0; url=` + target + `">
+<title>` + html.EscapeString(exportTitle(root)) + `</title>
+</head>
+<body>
+<p><a href="` + target + `">` + html.EscapeString(exportTitle(root)) + `</a></p>
+</body>
+</html>
+`
+	if err := os.WriteFile(filepath.Join(e.dir, "index.html"), []byte(page), 0o644); err != nil {
+		return fmt.Errorf("failed to write index.html: %w", err)
+	}
+	return nil
+}
+
+// htmlPage renders a page or database of the tree as a site page.
+func (e *treeExporter) htmlPage(ctx context.Context, node *exportNode) []byte {
+	renderer := newHTMLRenderer()
+	renderer.pageLink = e.pageLinkFrom(node)
+	renderer.breadcrumb = e.breadcrumb(node)
+
+	var body string
+	var icon map[string]interface{}
+	if node.kind == "database" {
+		body = e.databaseHTML(ctx, node)
+		icon = node.database.Icon
+	} else {
+		body = renderer.render(node.blocks)
+		icon = node.page.Icon
+	}
+
+	var buf bytes.Buffer
+	// The template only fails on a write error, which a bytes.Buffer never
+	// returns.
+	_ = writeHTMLPage(&buf, htmlPage{
+		Title:      exportTitle(node),
+		Icon:       template.HTML(htmlIcon(icon, "page-icon")),
+		Body:       template.HTML(body),
+		TOC:        template.HTML(renderer.toc()),
+		Nav:        e.nav(node),
+		Breadcrumb: renderer.breadcrumb,
+	})
+	return buf.Bytes()
+}
+
+func exportTitle(node *exportNode) string {
+	if node.title == "" {
+		return "Untitled"
+	}
+	return node.title
+}
+
+// nav renders the site's page hierarchy, marking the current page.
+func (e *treeExporter) nav(current *exportNode) template.HTML {
+	var b strings.Builder
+	e.navList(&b, e.nodes[:1], current)
+	return template.HTML(b.String())
+}
+
+func (e *treeExporter) navList(b *strings.Builder, nodes []*exportNode, current *exportNode) {
+	b.WriteString("<ul>\n")
+	for _, node := range nodes {
+		attrs := ""
+		if node == current {
+			attrs = ` class="current" aria-current="page"`
+		}
+		b.WriteString(`<li><a href="` + html.EscapeString(e.link(current.path, node.path)) + `"` + attrs + ">" + html.EscapeString(exportTitle(node)) + "</a>")
+		if children := e.childrenOf[exportNodeKey(node.id)]; len(children) > 0 {
+			b.WriteString("\n")
+			e.navList(b, children, current)
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n")
+}
+
+// breadcrumb links the ancestors of node, or returns "" for the root.
+func (e *treeExporter) breadcrumb(node *exportNode) template.HTML {
+	var trail []*exportNode
+	for parent := e.byID[exportNodeKey(node.parentID)]; parent != nil; parent = e.byID[exportNodeKey(parent.parentID)] {
+		trail = append([]*exportNode{parent}, trail...)
+	}
+	if len(trail) == 0 {
+		return ""
+	}
+	var parts []string
+	for _, ancestor := range trail {
+		parts = append(parts, htmlLink(e.link(node.path, ancestor.path), html.EscapeString(exportTitle(ancestor))))
+	}
+	parts = append(parts, "<span>"+html.EscapeString(exportTitle(node))+"</span>")
+	return template.HTML(strings.Join(parts, " / "))
+}
+
+// databaseHTML renders a database's rows as a table of their properties,
+// the title of each row linking to its page.
+func (e *treeExporter) databaseHTML(ctx context.Context, node *exportNode) string {
+	if len(node.rows) == 0 {
+		return `<p class="empty">No entries</p>` + "\n"
+	}
+
+	users, _ := e.client.(userGetter)
+	r := newPropertyRenderer(ctx, users)
+	r.absolute = true
+
+	columns := map[string]interface{}{}
+	for _, row := range node.rows {
+		for name, value := range row.page.Properties {
+			if _, ok := columns[name]; !ok {
+				columns[name] = value
+			}
+		}
+	}
+	names := propertyOrder(columns, nil)
+
+	var b strings.Builder
+	b.WriteString(`<div class="table-wrap"><table class="database">` + "\n<thead>\n<tr>")
+	for _, name := range names {
+		b.WriteString("<th>" + html.EscapeString(name) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for _, row := range node.rows {
+		b.WriteString("<tr>")
+		for _, name := range names {
+			b.WriteString("<td>" + e.propertyHTML(node, row, r, row.page.Properties[name]) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table></div>\n")
+	return b.String()
+}
+
+// propertyHTML renders one cell of a database table. Titles link to the
+// row's page, relations to the related pages when they were exported, and
+// URLs to their targets.
+func (e *treeExporter) propertyHTML(from, row *exportNode, r *propertyRenderer, raw interface{}) string {
+	pv, err := notion.DecodePropertyValue(raw)
+	if err != nil {
+		return ""
+	}
+	switch pv.Type {
+	case "title":
+		return htmlLink(e.link(from.path, row.path), html.EscapeString(exportTitle(row)))
+	case "relation":
+		var links []string
+		for _, rel := range pv.Relation {
+			if target := e.byID[exportNodeKey(rel.ID)]; target != nil {
+				links = append(links, htmlLink(e.link(from.path, target.path), html.EscapeString(exportTitle(target))))
+			} else {
+				links = append(links, html.EscapeString(rel.ID))
+			}
+		}
+		return strings.Join(links, ", ")
+	case "url":
+		if url := derefString(pv.URL); url != "" {
+			return htmlLink(url, html.EscapeString(url))
+		}
+		return ""
+	}
+	return strings.ReplaceAll(html.EscapeString(r.value(pv)), "\n", "<br>")
+}
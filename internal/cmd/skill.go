@@ -177,64 +177,40 @@ func scanWorkspace(ctx context.Context, client *notion.Client) (*workspaceData,
 	data.CurrentUser = me
 
 	// Get all users with pagination
-	var allUsers []*notion.User
-	var userCursor string
-	for {
-		opts := &notion.ListUsersOptions{
-			StartCursor: userCursor,
-			PageSize:    100,
-		}
-		users, err := client.ListUsers(ctx, opts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list users: %w", err)
-		}
-		allUsers = append(allUsers, users.Results...)
-		if !users.HasMore || users.NextCursor == nil {
-			break
-		}
-		userCursor = *users.NextCursor
+	allUsers, err := client.IterUsers(ctx, iterOptions("", NotionMaxPageSize, 0)).Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
 	data.Users = allUsers
 
 	// Search for data sources with pagination (API 2025-09-03+ uses data_source instead of database)
 	// Track unique databases by ID (to avoid duplicates from multiple data sources)
 	seenDBs := make(map[string]bool)
-	var searchCursor string
-
-	for {
-		searchResp, err := client.Search(ctx, &notion.SearchRequest{
-			Filter: map[string]interface{}{
-				"property": "object",
-				"value":    "data_source",
-			},
-			StartCursor: searchCursor,
-			PageSize:    100,
-		})
+	searchReq := &notion.SearchRequest{
+		Filter: map[string]interface{}{
+			"property": "object",
+			"value":    "data_source",
+		},
+	}
+	for result, err := range client.IterSearch(ctx, searchReq, iterOptions("", NotionMaxPageSize, 0)).All() {
 		if err != nil {
 			return nil, fmt.Errorf("failed to search databases: %w", err)
 		}
 
 		// Convert search results to DataSource structs and extract parent database info
-		for _, result := range searchResp.Results {
-			if result["object"] == "data_source" {
-				ds := parseDataSourceResult(result)
-				data.DataSources = append(data.DataSources, ds)
-
-				// Extract parent database info if available
-				if parent, ok := result["parent"].(map[string]interface{}); ok {
-					if dbID, ok := parent["database_id"].(string); ok && !seenDBs[dbID] {
-						seenDBs[dbID] = true
-						db := buildDatabaseFromDataSource(dbID, ds, result)
-						data.Databases = append(data.Databases, db)
-					}
+		if result["object"] == "data_source" {
+			ds := parseDataSourceResult(result)
+			data.DataSources = append(data.DataSources, ds)
+
+			// Extract parent database info if available
+			if parent, ok := result["parent"].(map[string]interface{}); ok {
+				if dbID, ok := parent["database_id"].(string); ok && !seenDBs[dbID] {
+					seenDBs[dbID] = true
+					db := buildDatabaseFromDataSource(dbID, ds, result)
+					data.Databases = append(data.Databases, db)
 				}
 			}
 		}
-
-		if !searchResp.HasMore || searchResp.NextCursor == nil {
-			break
-		}
-		searchCursor = *searchResp.NextCursor
 	}
 
 	return data, nil
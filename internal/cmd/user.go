@@ -72,6 +72,7 @@ func newUserListCmd() *cobra.Command {
 	var startCursor string
 	var pageSize int
 	var all bool
+	var resumePath string
 	var light bool
 
 	cmd := &cobra.Command{
@@ -82,6 +83,8 @@ func newUserListCmd() *cobra.Command {
 
 Supports pagination with --start-cursor and --page-size flags.
 Use --all to fetch all pages of results automatically.
+Use --resume <state-file> (with -o ndjson) to checkpoint progress after every
+page and continue from it when the command is rerun.
 Use --light (or --li) for compact lookup output (id, name, email, type).
 Use global --results-only to output just the results array (useful for piping to jq).
 
@@ -109,36 +112,29 @@ Example:
 			}
 
 			// If --all flag is set, fetch all pages
-			if all {
-				var allUsers []*notion.User
-				cursor := startCursor
-				hasMore := false
-				var nextCursor *string
-
-				for {
-					opts := &notion.ListUsersOptions{
-						StartCursor: cursor,
-						PageSize:    pageSize,
+			if all || resumePath != "" {
+				opts := iterOptions(startCursor, pageSize, limit)
+				newIter := func(opts notion.IterOptions) *notion.Iterator[*notion.User] {
+					return client.IterUsers(ctx, opts)
+				}
+				if output.CanStream(ctx) || resumePath != "" {
+					var transform func(*notion.User) interface{}
+					if light {
+						transform = func(u *notion.User) interface{} {
+							if l := toLightUsers([]*notion.User{u}); len(l) > 0 {
+								return l[0]
+							}
+							return nil
+						}
 					}
-
-					userList, err := client.ListUsers(ctx, opts)
-					if err != nil {
+					return streamAllPages(ctx, resumePath, resumeKey("user list", nil), opts, newIter, transform, func(err error) error {
 						return fmt.Errorf("failed to list users: %w", err)
-					}
-
-					allUsers = append(allUsers, userList.Results...)
-					hasMore = userList.HasMore
-					nextCursor = userList.NextCursor
-
-					if limit > 0 && len(allUsers) >= limit {
-						allUsers = allUsers[:limit]
-						break
-					}
+					})
+				}
 
-					if !userList.HasMore || userList.NextCursor == nil || *userList.NextCursor == "" {
-						break
-					}
-					cursor = *userList.NextCursor
+				allUsers, nextCursor, hasMore, err := collectPages(newIter(opts))
+				if err != nil {
+					return fmt.Errorf("failed to list users: %w", err)
 				}
 
 				// Print all results
@@ -191,6 +187,7 @@ Example:
 	cmd.Flags().StringVar(&startCursor, "start-cursor", "", "Pagination cursor from previous response")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Number of items per page (max 100)")
 	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of results (may be slow for large datasets)")
+	addResumeFlag(cmd, &resumePath)
 	cmd.Flags().BoolVar(&light, "light", false, "Return compact user payload (id, name, email, type)")
 	flagAlias(cmd.Flags(), "light", "li")
 
@@ -436,7 +436,7 @@ func consumeWorkersCommonFlag(ctx context.Context, args []string, i *int) (conte
 		if *i+1 >= len(args) {
 			return ctx, false, false, clierrors.NewUserError(
 				fmt.Sprintf("%s requires a value", arg),
-				"Use one of: text, json, ndjson, jsonl, table, yaml",
+				"Use one of: text, json, ndjson, jsonl, table, yaml, csv, tsv",
 			)
 		}
 		*i = *i + 1
@@ -669,7 +669,7 @@ func extractWorkersSubcommandArgs(args []string, subcommand string) ([]string, b
 			if i+1 >= len(args) {
 				return nil, false, clierrors.NewUserError(
 					fmt.Sprintf("%s requires a value", args[i]),
-					"Use one of: text, json, ndjson, jsonl, table, yaml",
+					"Use one of: text, json, ndjson, jsonl, table, yaml, csv, tsv",
 				)
 			}
 			prefix = append(prefix, args[i], args[i+1])
@@ -5,18 +5,33 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the CLI configuration
 type Config struct {
-	// Default output format (text, json, table, yaml)
+	// Default output format (text, json, ndjson, table, yaml, csv, tsv)
 	Output string `yaml:"output,omitempty"`
 
 	// Default color mode (auto, always, never)
 	Color string `yaml:"color,omitempty"`
 
+	// Client-side request rate limit in requests per second (0 disables pacing).
+	// Unset uses the client default.
+	Rate *float64 `yaml:"rate,omitempty"`
+
+	// Share the request rate budget across all ntn processes using the same workspace
+	SharedRate bool `yaml:"shared_rate,omitempty"`
+
+	// Serve repeated reads from the on-disk response cache
+	Cache bool `yaml:"cache,omitempty"`
+
+	// How long cached responses stay fresh (Go duration, e.g. 10m). Empty uses the default.
+	CacheTTL string `yaml:"cache_ttl,omitempty"`
+
 	// Default workspace name (for multi-workspace support)
 	DefaultWorkspace string `yaml:"default_workspace,omitempty"`
 
@@ -65,6 +80,41 @@ func DefaultConfigPath() (string, error) {
 	return configPathFunc()
 }
 
+// SharedRateLimitPath returns the state file for the cross-process request
+// budget of a workspace: ~/.config/notion-cli/ratelimit/<workspace>.json
+func SharedRateLimitPath(workspace string) (string, error) {
+	path, err := DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "ratelimit", stateFileName(workspace)+".json"), nil
+}
+
+// ResponseCacheDir returns the response cache directory of a workspace:
+// ~/.config/notion-cli/cache/<workspace>
+func ResponseCacheDir(workspace string) (string, error) {
+	path, err := DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "cache", stateFileName(workspace)), nil
+}
+
+// stateFileName makes a workspace name safe to use as a file name.
+func stateFileName(workspace string) string {
+	if workspace == "" {
+		return "default"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, workspace)
+}
+
 // Load loads config from the default path, returns empty config if not found
 func Load() (*Config, error) {
 	path, err := DefaultConfigPath()
@@ -125,6 +175,33 @@ func (c *Config) GetColor() string {
 	return c.Color
 }
 
+// GetRate returns the configured request rate and whether one is set
+func (c *Config) GetRate() (float64, bool) {
+	if c.Rate == nil {
+		return 0, false
+	}
+	return *c.Rate, true
+}
+
+// GetSharedRate reports whether the request budget is shared across processes
+func (c *Config) GetSharedRate() bool {
+	return c.SharedRate
+}
+
+// GetCache reports whether the response cache is enabled by default
+func (c *Config) GetCache() bool {
+	return c.Cache
+}
+
+// GetCacheTTL returns the configured cache TTL, or 0 if unset or invalid
+func (c *Config) GetCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(c.CacheTTL)
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
 // GetWorkspace returns the workspace configuration by name
 func (c *Config) GetWorkspace(name string) (*WorkspaceConfig, error) {
 	if c.Workspaces == nil {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadFromPath(t *testing.T) {
@@ -178,6 +179,32 @@ func TestDefaultConfigPath(t *testing.T) {
 	}
 }
 
+func TestSharedRateLimitPath(t *testing.T) {
+	dir := t.TempDir()
+	orig := SetConfigPathFunc(func() (string, error) {
+		return filepath.Join(dir, "config.yaml"), nil
+	})
+	t.Cleanup(func() { SetConfigPathFunc(orig) })
+
+	tests := []struct {
+		workspace string
+		want      string
+	}{
+		{workspace: "", want: "default.json"},
+		{workspace: "personal", want: "personal.json"},
+		{workspace: "team/a b", want: "team_a_b.json"},
+	}
+	for _, tt := range tests {
+		got, err := SharedRateLimitPath(tt.workspace)
+		if err != nil {
+			t.Fatalf("SharedRateLimitPath(%q) error = %v", tt.workspace, err)
+		}
+		if want := filepath.Join(dir, "ratelimit", tt.want); got != want {
+			t.Errorf("SharedRateLimitPath(%q) = %v, want %v", tt.workspace, got, want)
+		}
+	}
+}
+
 func TestWorkspaceConfig(t *testing.T) {
 	content := `workspaces:
   personal:
@@ -712,3 +739,21 @@ workspaces:
 		t.Errorf("test.APIURL = %v, want https://test.notion.com/v1", test.APIURL)
 	}
 }
+
+func TestGetCacheTTL(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "30m", want: 30 * time.Minute},
+		{value: "soon", want: 0},
+		{value: "-1m", want: 0},
+	}
+	for _, tt := range tests {
+		cfg := &Config{CacheTTL: tt.value}
+		if got := cfg.GetCacheTTL(); got != tt.want {
+			t.Errorf("GetCacheTTL() with %q = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BlockContent is the typed payload stored under a block's type key, e.g.
+// the "paragraph" object of a paragraph block. Use Block.TypedContent to
+// decode one and Block.SetContent to store one.
+type BlockContent interface {
+	isBlockContent()
+}
+
+// ParagraphBlock is the content of a paragraph block.
+type ParagraphBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Color    string     `json:"color,omitempty"`
+}
+
+// HeadingBlock is the content of heading_1, heading_2 and heading_3 blocks.
+type HeadingBlock struct {
+	RichText     []RichText `json:"rich_text"`
+	Color        string     `json:"color,omitempty"`
+	IsToggleable bool       `json:"is_toggleable,omitempty"`
+}
+
+// ListItemBlock is the content of bulleted_list_item and numbered_list_item blocks.
+type ListItemBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Color    string     `json:"color,omitempty"`
+}
+
+// ToDoBlock is the content of a to_do block.
+type ToDoBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Checked  bool       `json:"checked"`
+	Color    string     `json:"color,omitempty"`
+}
+
+// ToggleBlock is the content of a toggle block.
+type ToggleBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Color    string     `json:"color,omitempty"`
+}
+
+// QuoteBlock is the content of a quote block.
+type QuoteBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Color    string     `json:"color,omitempty"`
+}
+
+// CalloutBlock is the content of a callout block.
+type CalloutBlock struct {
+	RichText []RichText             `json:"rich_text"`
+	Icon     map[string]interface{} `json:"icon,omitempty"`
+	Color    string                 `json:"color,omitempty"`
+}
+
+// CodeBlock is the content of a code block.
+type CodeBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Caption  []RichText `json:"caption,omitempty"`
+	Language string     `json:"language"`
+}
+
+// TemplateBlock is the content of a template block.
+type TemplateBlock struct {
+	RichText []RichText `json:"rich_text"`
+}
+
+// FileBlock is the content of image, file, pdf, video and audio blocks.
+type FileBlock struct {
+	// Type is "file" (Notion-hosted), "external" or "file_upload".
+	Type       string         `json:"type"`
+	File       *FileLocation  `json:"file,omitempty"`
+	External   *FileLocation  `json:"external,omitempty"`
+	FileUpload *FileUploadRef `json:"file_upload,omitempty"`
+	Caption    []RichText     `json:"caption,omitempty"`
+	Name       string         `json:"name,omitempty"`
+}
+
+// FileLocation is the URL of a Notion-hosted or external file.
+type FileLocation struct {
+	URL        string `json:"url"`
+	ExpiryTime string `json:"expiry_time,omitempty"`
+}
+
+// FileUploadRef references a completed file upload.
+type FileUploadRef struct {
+	ID string `json:"id"`
+}
+
+// URL returns the file's URL, or "" for file uploads and empty content.
+func (f *FileBlock) URL() string {
+	switch {
+	case f.File != nil && (f.Type == "file" || f.Type == ""):
+		return f.File.URL
+	case f.External != nil && (f.Type == "external" || f.Type == ""):
+		return f.External.URL
+	}
+	return ""
+}
+
+// BookmarkBlock is the content of a bookmark block.
+type BookmarkBlock struct {
+	URL     string     `json:"url"`
+	Caption []RichText `json:"caption,omitempty"`
+}
+
+// EmbedBlock is the content of an embed block.
+type EmbedBlock struct {
+	URL     string     `json:"url"`
+	Caption []RichText `json:"caption,omitempty"`
+}
+
+// LinkPreviewBlock is the content of a link_preview block.
+type LinkPreviewBlock struct {
+	URL string `json:"url"`
+}
+
+// EquationBlock is the content of an equation block.
+type EquationBlock struct {
+	Expression string `json:"expression"`
+}
+
+// TableBlock is the content of a table block. Its rows are table_row children.
+type TableBlock struct {
+	TableWidth      int  `json:"table_width"`
+	HasColumnHeader bool `json:"has_column_header"`
+	HasRowHeader    bool `json:"has_row_header"`
+}
+
+// TableRowBlock is the content of a table_row block.
+type TableRowBlock struct {
+	Cells [][]RichText `json:"cells"`
+}
+
+// SyncedBlock is the content of a synced_block block. SyncedFrom is nil for
+// the original block and references the original for duplicates.
+type SyncedBlock struct {
+	SyncedFrom *SyncedFrom `json:"synced_from"`
+}
+
+// SyncedFrom references the original of a duplicate synced block.
+type SyncedFrom struct {
+	Type    string `json:"type"`
+	BlockID string `json:"block_id"`
+}
+
+// ColumnListBlock is the content of a column_list block. Its columns are children.
+type ColumnListBlock struct{}
+
+// ColumnBlock is the content of a column block.
+type ColumnBlock struct {
+	WidthRatio *float64 `json:"width_ratio,omitempty"`
+}
+
+// LinkToPageBlock is the content of a link_to_page block.
+type LinkToPageBlock struct {
+	Type       string `json:"type"`
+	PageID     string `json:"page_id,omitempty"`
+	DatabaseID string `json:"database_id,omitempty"`
+}
+
+// ChildPageBlock is the content of a child_page block.
+type ChildPageBlock struct {
+	Title string `json:"title"`
+}
+
+// ChildDatabaseBlock is the content of a child_database block.
+type ChildDatabaseBlock struct {
+	Title string `json:"title"`
+}
+
+// DividerBlock is the content of a divider block.
+type DividerBlock struct{}
+
+// BreadcrumbBlock is the content of a breadcrumb block.
+type BreadcrumbBlock struct{}
+
+// TableOfContentsBlock is the content of a table_of_contents block.
+type TableOfContentsBlock struct {
+	Color string `json:"color,omitempty"`
+}
+
+// UnknownBlock holds the content of block types without a typed struct,
+// such as those added to the API after this client.
+type UnknownBlock map[string]interface{}
+
+func (*ParagraphBlock) isBlockContent()       {}
+func (*HeadingBlock) isBlockContent()         {}
+func (*ListItemBlock) isBlockContent()        {}
+func (*ToDoBlock) isBlockContent()            {}
+func (*ToggleBlock) isBlockContent()          {}
+func (*QuoteBlock) isBlockContent()           {}
+func (*CalloutBlock) isBlockContent()         {}
+func (*CodeBlock) isBlockContent()            {}
+func (*TemplateBlock) isBlockContent()        {}
+func (*FileBlock) isBlockContent()            {}
+func (*BookmarkBlock) isBlockContent()        {}
+func (*EmbedBlock) isBlockContent()           {}
+func (*LinkPreviewBlock) isBlockContent()     {}
+func (*EquationBlock) isBlockContent()        {}
+func (*TableBlock) isBlockContent()           {}
+func (*TableRowBlock) isBlockContent()        {}
+func (*SyncedBlock) isBlockContent()          {}
+func (*ColumnListBlock) isBlockContent()      {}
+func (*ColumnBlock) isBlockContent()          {}
+func (*LinkToPageBlock) isBlockContent()      {}
+func (*ChildPageBlock) isBlockContent()       {}
+func (*ChildDatabaseBlock) isBlockContent()   {}
+func (*DividerBlock) isBlockContent()         {}
+func (*BreadcrumbBlock) isBlockContent()      {}
+func (*TableOfContentsBlock) isBlockContent() {}
+func (UnknownBlock) isBlockContent()          {}
+
+// blockContentTypes maps each block type to a constructor for its content.
+var blockContentTypes = map[string]func() BlockContent{
+	"paragraph":          func() BlockContent { return &ParagraphBlock{} },
+	"heading_1":          func() BlockContent { return &HeadingBlock{} },
+	"heading_2":          func() BlockContent { return &HeadingBlock{} },
+	"heading_3":          func() BlockContent { return &HeadingBlock{} },
+	"bulleted_list_item": func() BlockContent { return &ListItemBlock{} },
+	"numbered_list_item": func() BlockContent { return &ListItemBlock{} },
+	"to_do":              func() BlockContent { return &ToDoBlock{} },
+	"toggle":             func() BlockContent { return &ToggleBlock{} },
+	"quote":              func() BlockContent { return &QuoteBlock{} },
+	"callout":            func() BlockContent { return &CalloutBlock{} },
+	"code":               func() BlockContent { return &CodeBlock{} },
+	"template":           func() BlockContent { return &TemplateBlock{} },
+	"image":              func() BlockContent { return &FileBlock{} },
+	"file":               func() BlockContent { return &FileBlock{} },
+	"pdf":                func() BlockContent { return &FileBlock{} },
+	"video":              func() BlockContent { return &FileBlock{} },
+	"audio":              func() BlockContent { return &FileBlock{} },
+	"bookmark":           func() BlockContent { return &BookmarkBlock{} },
+	"embed":              func() BlockContent { return &EmbedBlock{} },
+	"link_preview":       func() BlockContent { return &LinkPreviewBlock{} },
+	"equation":           func() BlockContent { return &EquationBlock{} },
+	"table":              func() BlockContent { return &TableBlock{} },
+	"table_row":          func() BlockContent { return &TableRowBlock{} },
+	"synced_block":       func() BlockContent { return &SyncedBlock{} },
+	"column_list":        func() BlockContent { return &ColumnListBlock{} },
+	"column":             func() BlockContent { return &ColumnBlock{} },
+	"link_to_page":       func() BlockContent { return &LinkToPageBlock{} },
+	"child_page":         func() BlockContent { return &ChildPageBlock{} },
+	"child_database":     func() BlockContent { return &ChildDatabaseBlock{} },
+	"divider":            func() BlockContent { return &DividerBlock{} },
+	"breadcrumb":         func() BlockContent { return &BreadcrumbBlock{} },
+	"table_of_contents":  func() BlockContent { return &TableOfContentsBlock{} },
+}
+
+// NewBlockContent returns an empty content struct for a block type, or an
+// UnknownBlock for types without one.
+func NewBlockContent(blockType string) BlockContent {
+	if newContent, ok := blockContentTypes[blockType]; ok {
+		return newContent()
+	}
+	return UnknownBlock{}
+}
+
+// TypedContent decodes the block's content into the struct for its type.
+// Block types without a typed struct decode to an UnknownBlock.
+func (b *Block) TypedContent() (BlockContent, error) {
+	content := NewBlockContent(b.Type)
+	if unknown, ok := content.(UnknownBlock); ok {
+		for k, v := range b.Content {
+			unknown[k] = v
+		}
+		return unknown, nil
+	}
+	if len(b.Content) == 0 {
+		return content, nil
+	}
+
+	data, err := json.Marshal(b.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s content: %w", b.Type, err)
+	}
+	if err := json.Unmarshal(data, content); err != nil {
+		return nil, fmt.Errorf("failed to decode %s content: %w", b.Type, err)
+	}
+	return content, nil
+}
+
+// SetContent stores typed content as the block's content.
+func (b *Block) SetContent(content BlockContent) error {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s content: %w", b.Type, err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("failed to encode %s content: %w", b.Type, err)
+	}
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	b.Content = m
+	return nil
+}
+
+// RichText returns the rich text of text-bearing blocks (paragraphs,
+// headings, list items, to-dos, toggles, quotes, callouts, code, templates).
+// Other blocks and undecodable content return nil.
+func (b *Block) RichText() []RichText {
+	content, err := b.TypedContent()
+	if err != nil {
+		return nil
+	}
+	switch c := content.(type) {
+	case *ParagraphBlock:
+		return c.RichText
+	case *HeadingBlock:
+		return c.RichText
+	case *ListItemBlock:
+		return c.RichText
+	case *ToDoBlock:
+		return c.RichText
+	case *ToggleBlock:
+		return c.RichText
+	case *QuoteBlock:
+		return c.RichText
+	case *CalloutBlock:
+		return c.RichText
+	case *CodeBlock:
+		return c.RichText
+	case *TemplateBlock:
+		return c.RichText
+	}
+	return nil
+}
+
+// Caption returns the caption of media, bookmark, embed and code blocks.
+func (b *Block) Caption() []RichText {
+	content, err := b.TypedContent()
+	if err != nil {
+		return nil
+	}
+	switch c := content.(type) {
+	case *FileBlock:
+		return c.Caption
+	case *BookmarkBlock:
+		return c.Caption
+	case *EmbedBlock:
+		return c.Caption
+	case *CodeBlock:
+		return c.Caption
+	}
+	return nil
+}
+
+// PlainText returns the most useful text of a block: the title of child
+// pages and databases, otherwise its rich text, otherwise its caption.
+func (b *Block) PlainText() string {
+	content, err := b.TypedContent()
+	if err != nil {
+		return ""
+	}
+	switch c := content.(type) {
+	case *ChildPageBlock:
+		if strings.TrimSpace(c.Title) != "" {
+			return c.Title
+		}
+	case *ChildDatabaseBlock:
+		if strings.TrimSpace(c.Title) != "" {
+			return c.Title
+		}
+	}
+	if txt := PlainText(b.RichText()); strings.TrimSpace(txt) != "" {
+		return txt
+	}
+	if txt := PlainText(b.Caption()); strings.TrimSpace(txt) != "" {
+		return txt
+	}
+	return ""
+}
+
+// Payload returns the block in the shape accepted by the append children
+// endpoint, without children. The content map is copied.
+func (b *Block) Payload() map[string]interface{} {
+	content := make(map[string]interface{}, len(b.Content))
+	for k, v := range b.Content {
+		content[k] = v
+	}
+	return map[string]interface{}{
+		"object": "block",
+		"type":   b.Type,
+		b.Type:   content,
+	}
+}
+
+// PlainText concatenates the plain text of rich text items, falling back to
+// the text content for items built locally without plain_text.
+func PlainText(items []RichText) string {
+	var sb strings.Builder
+	for _, item := range items {
+		switch {
+		case item.PlainText != "":
+			sb.WriteString(item.PlainText)
+		case item.Text != nil:
+			sb.WriteString(item.Text.Content)
+		case item.Equation != nil:
+			sb.WriteString(item.Equation.Expression)
+		}
+	}
+	return sb.String()
+}
+
+// DecodeRichText converts a rich_text array decoded as generic JSON
+// ([]interface{}) into typed rich text. Malformed input yields nil.
+func DecodeRichText(v interface{}) []RichText {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var items []RichText
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil
+	}
+	return items
+}
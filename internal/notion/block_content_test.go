@@ -0,0 +1,167 @@
+package notion
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testRichText = `[{"type":"text","text":{"content":"Hi","link":{"url":"https://example.invalid"}},"annotations":{"bold":true,"italic":false,"strikethrough":false,"underline":false,"code":false,"color":"default"},"plain_text":"Hi","href":"https://example.invalid"}]`
+
+func TestBlockContent_RoundTrip(t *testing.T) {
+	tests := []struct {
+		blockType string
+		content   string
+		want      BlockContent
+	}{
+		{"paragraph", `{"rich_text":` + testRichText + `,"color":"blue"}`, &ParagraphBlock{}},
+		{"heading_2", `{"rich_text":` + testRichText + `,"color":"default","is_toggleable":true}`, &HeadingBlock{}},
+		{"numbered_list_item", `{"rich_text":` + testRichText + `,"color":"default"}`, &ListItemBlock{}},
+		{"to_do", `{"rich_text":` + testRichText + `,"checked":true,"color":"default"}`, &ToDoBlock{}},
+		{"toggle", `{"rich_text":[],"color":"default"}`, &ToggleBlock{}},
+		{"quote", `{"rich_text":` + testRichText + `,"color":"default"}`, &QuoteBlock{}},
+		{"callout", `{"rich_text":` + testRichText + `,"icon":{"type":"emoji","emoji":"💡"},"color":"gray_background"}`, &CalloutBlock{}},
+		{"code", `{"rich_text":` + testRichText + `,"caption":` + testRichText + `,"language":"go"}`, &CodeBlock{}},
+		{"image", `{"type":"external","external":{"url":"https://example.invalid/a.png"},"caption":` + testRichText + `}`, &FileBlock{}},
+		{"pdf", `{"type":"file","file":{"url":"https://example.invalid/a.pdf","expiry_time":"2026-01-01T00:00:00.000Z"},"name":"a.pdf"}`, &FileBlock{}},
+		{"video", `{"type":"file_upload","file_upload":{"id":"up-1"}}`, &FileBlock{}},
+		{"bookmark", `{"url":"https://example.invalid","caption":` + testRichText + `}`, &BookmarkBlock{}},
+		{"embed", `{"url":"https://example.invalid/embed"}`, &EmbedBlock{}},
+		{"link_preview", `{"url":"https://example.invalid/pr/1"}`, &LinkPreviewBlock{}},
+		{"equation", `{"expression":"e=mc^2"}`, &EquationBlock{}},
+		{"table", `{"table_width":2,"has_column_header":true,"has_row_header":false}`, &TableBlock{}},
+		{"table_row", `{"cells":[` + testRichText + `,[]]}`, &TableRowBlock{}},
+		{"synced_block", `{"synced_from":null}`, &SyncedBlock{}},
+		{"synced_block", `{"synced_from":{"type":"block_id","block_id":"b1"}}`, &SyncedBlock{}},
+		{"column_list", `{}`, &ColumnListBlock{}},
+		{"column", `{"width_ratio":0.5}`, &ColumnBlock{}},
+		{"link_to_page", `{"type":"page_id","page_id":"p1"}`, &LinkToPageBlock{}},
+		{"child_page", `{"title":"Child"}`, &ChildPageBlock{}},
+		{"child_database", `{"title":"Tasks"}`, &ChildDatabaseBlock{}},
+		{"divider", `{}`, &DividerBlock{}},
+		{"breadcrumb", `{}`, &BreadcrumbBlock{}},
+		{"table_of_contents", `{"color":"default"}`, &TableOfContentsBlock{}},
+		{"template", `{"rich_text":` + testRichText + `}`, &TemplateBlock{}},
+		{"meeting_notes", `{"title":"Standup","status":"done"}`, UnknownBlock{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.blockType, func(t *testing.T) {
+			raw := `{"object":"block","id":"b1","type":"` + tt.blockType + `","` + tt.blockType + `":` + tt.content + `}`
+			var block Block
+			if err := json.Unmarshal([]byte(raw), &block); err != nil {
+				t.Fatalf("unmarshal block: %v", err)
+			}
+
+			content, err := block.TypedContent()
+			if err != nil {
+				t.Fatalf("TypedContent: %v", err)
+			}
+			if reflect.TypeOf(content) != reflect.TypeOf(tt.want) {
+				t.Fatalf("TypedContent type = %T, want %T", content, tt.want)
+			}
+
+			got, err := json.Marshal(content)
+			if err != nil {
+				t.Fatalf("marshal content: %v", err)
+			}
+			assertJSONEqual(t, got, []byte(tt.content))
+
+			var copied Block
+			copied.Type = block.Type
+			if err := copied.SetContent(content); err != nil {
+				t.Fatalf("SetContent: %v", err)
+			}
+			if !reflect.DeepEqual(copied.Content, block.Content) {
+				t.Errorf("SetContent = %v, want %v", copied.Content, block.Content)
+			}
+		})
+	}
+}
+
+func TestBlock_JSONRoundTrip(t *testing.T) {
+	raw := `{"object":"block","id":"b1","type":"toggle","created_time":"","last_edited_time":"","has_children":true,"archived":false,
+		"toggle":{"rich_text":` + testRichText + `,"color":"default"},
+		"children":[{"object":"block","id":"b2","type":"paragraph","created_time":"","last_edited_time":"","has_children":false,"archived":false,"paragraph":{"rich_text":[],"color":"default"},"children":[]}]}`
+
+	var block Block
+	if err := json.Unmarshal([]byte(raw), &block); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(block.Children) != 1 || block.Children[0].Type != "paragraph" {
+		t.Fatalf("children = %+v", block.Children)
+	}
+
+	out, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	assertJSONEqual(t, out, []byte(raw))
+}
+
+func TestBlock_TextHelpers(t *testing.T) {
+	tests := []struct {
+		name  string
+		block Block
+		text  string
+	}{
+		{
+			name:  "rich text",
+			block: Block{Type: "paragraph", Content: map[string]interface{}{"rich_text": []interface{}{map[string]interface{}{"type": "text", "plain_text": "Hello"}}}},
+			text:  "Hello",
+		},
+		{
+			name:  "child page title",
+			block: Block{Type: "child_page", Content: map[string]interface{}{"title": "Child"}},
+			text:  "Child",
+		},
+		{
+			name:  "caption fallback",
+			block: Block{Type: "image", Content: map[string]interface{}{"type": "external", "caption": []interface{}{map[string]interface{}{"type": "text", "text": map[string]interface{}{"content": "Logo"}}}}},
+			text:  "Logo",
+		},
+		{
+			name:  "no text",
+			block: Block{Type: "divider", Content: map[string]interface{}{}},
+			text:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.block.PlainText(); got != tt.text {
+				t.Errorf("PlainText() = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestFileBlock_URL(t *testing.T) {
+	hosted := &FileBlock{Type: "file", File: &FileLocation{URL: "https://example.invalid/hosted"}}
+	external := &FileBlock{Type: "external", External: &FileLocation{URL: "https://example.invalid/ext"}}
+	upload := &FileBlock{Type: "file_upload", FileUpload: &FileUploadRef{ID: "up-1"}}
+
+	if got := hosted.URL(); got != "https://example.invalid/hosted" {
+		t.Errorf("hosted URL = %q", got)
+	}
+	if got := external.URL(); got != "https://example.invalid/ext" {
+		t.Errorf("external URL = %q", got)
+	}
+	if got := upload.URL(); got != "" {
+		t.Errorf("upload URL = %q, want empty", got)
+	}
+}
+
+func assertJSONEqual(t *testing.T, got, want []byte) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("decode got: %v\n%s", err, got)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("decode want: %v\n%s", err, want)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("JSON mismatch\ngot:  %s\nwant: %s", got, want)
+	}
+}
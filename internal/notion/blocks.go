@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"sync"
 )
 
 // Block represents a Notion block.
@@ -71,6 +72,32 @@ func (b Block) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// UnmarshalJSON implements custom JSON unmarshaling that mirrors MarshalJSON,
+// storing the type-specific content in Content.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parsed, err := parseBlock(raw)
+	if err != nil {
+		return err
+	}
+
+	var nested struct {
+		Children []Block `json:"children"`
+	}
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return err
+	}
+	if len(nested.Children) > 0 {
+		parsed.Children = nested.Children
+	}
+
+	*b = *parsed
+	return nil
+}
+
 // BlockList represents a paginated list of blocks.
 type BlockList struct {
 	Object     string   `json:"object"`
@@ -94,10 +121,18 @@ type UpdateBlockRequest struct {
 	Archived *bool                  `json:"archived,omitempty"`
 }
 
+// DefaultBlockFetchConcurrency is the number of block children requests
+// GetBlockChildrenRecursive keeps in flight when no concurrency is given.
+// It is kept low so a single traversal stays near Notion's ~3 req/s average.
+const DefaultBlockFetchConcurrency = 4
+
 // BlockChildrenOptions holds pagination options for getting block children.
 type BlockChildrenOptions struct {
 	StartCursor string
 	PageSize    int
+	// Concurrency bounds parallel child fetches in GetBlockChildrenRecursive.
+	// Zero uses DefaultBlockFetchConcurrency; 1 fetches sequentially.
+	Concurrency int
 }
 
 // GetBlock retrieves a block by ID.
@@ -222,59 +257,127 @@ func (c *Client) UpdateBlock(ctx context.Context, blockID string, req *UpdateBlo
 
 // GetBlockChildrenRecursive retrieves children of a block recursively up to the specified depth.
 // depth=0 returns no blocks, depth=1 returns direct children only, depth=2 includes grandchildren, etc.
+//
+// Nested children are fetched by a bounded pool of workers (see
+// BlockChildrenOptions.Concurrency). The result order always matches the
+// order of blocks on the page. Workers hold off while a Retry-After pause is
+// in effect and stop as soon as ctx is cancelled or any fetch fails.
 func (c *Client) GetBlockChildrenRecursive(ctx context.Context, blockID string, depth int, opts *BlockChildrenOptions) ([]Block, error) {
 	if depth <= 0 {
 		return []Block{}, nil
 	}
 
-	// Fetch direct children
-	var allBlocks []Block
 	cursor := ""
+	pageSize := 0
+	concurrency := DefaultBlockFetchConcurrency
 	if opts != nil {
 		cursor = opts.StartCursor
+		pageSize = opts.PageSize
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
 	}
 
-	for {
-		fetchOpts := &BlockChildrenOptions{
-			StartCursor: cursor,
-		}
-		if opts != nil && opts.PageSize > 0 {
-			fetchOpts.PageSize = opts.PageSize
-		}
+	// Fetch direct children
+	blocks, err := c.getAllBlockChildren(ctx, blockID, cursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
 
-		blockList, err := c.GetBlockChildren(ctx, blockID, fetchOpts)
-		if err != nil {
+	// If depth > 1, fetch children for blocks that have children.
+	// Child fetches never reuse the parent's StartCursor.
+	if depth > 1 {
+		w := newBlockTreeWalker(ctx, c, concurrency, pageSize)
+		w.expand(blocks, depth-1)
+		if err := w.wait(); err != nil {
 			return nil, err
 		}
+	}
 
-		allBlocks = append(allBlocks, blockList.Results...)
+	return blocks, nil
+}
 
-		if !blockList.HasMore || blockList.NextCursor == nil || *blockList.NextCursor == "" {
-			break
-		}
-		cursor = *blockList.NextCursor
+// getAllBlockChildren fetches every page of direct children for a block.
+func (c *Client) getAllBlockChildren(ctx context.Context, blockID, cursor string, pageSize int) ([]Block, error) {
+	return c.IterBlockChildren(ctx, blockID, IterOptions{StartCursor: cursor, PageSize: pageSize}).Collect()
+}
+
+// blockTreeWalker fans out block children fetches across a bounded number of
+// workers. Each fetched child list is written into its parent's slot, so the
+// tree keeps page order regardless of completion order.
+type blockTreeWalker struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	client   *Client
+	pageSize int
+	sem      chan struct{}
+	wg       sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+func newBlockTreeWalker(ctx context.Context, client *Client, concurrency, pageSize int) *blockTreeWalker {
+	ctx, cancel := context.WithCancel(ctx)
+	return &blockTreeWalker{
+		ctx:      ctx,
+		cancel:   cancel,
+		client:   client,
+		pageSize: pageSize,
+		sem:      make(chan struct{}, concurrency),
 	}
+}
 
-	// If depth > 1, recursively fetch children for blocks that have children
-	if depth > 1 {
-		// Create new options for child blocks - don't pass parent's StartCursor
-		childOpts := &BlockChildrenOptions{}
-		if opts != nil {
-			childOpts.PageSize = opts.PageSize
+// expand schedules child fetches for every block with children, descending
+// up to depth more levels.
+func (w *blockTreeWalker) expand(blocks []Block, depth int) {
+	for i := range blocks {
+		if !blocks[i].HasChildren {
+			continue
 		}
+		w.wg.Add(1)
+		go w.fetch(&blocks[i], depth)
+	}
+}
 
-		for i := range allBlocks {
-			if allBlocks[i].HasChildren {
-				children, err := c.GetBlockChildrenRecursive(ctx, allBlocks[i].ID, depth-1, childOpts)
-				if err != nil {
-					return nil, fmt.Errorf("failed to get children of block %s: %w", allBlocks[i].ID, err)
-				}
-				allBlocks[i].Children = children
-			}
-		}
+func (w *blockTreeWalker) fetch(block *Block, depth int) {
+	defer w.wg.Done()
+
+	// Acquire a worker slot only for the request itself so that scheduling
+	// grandchildren never blocks on slots held by their ancestors.
+	select {
+	case w.sem <- struct{}{}:
+	case <-w.ctx.Done():
+		w.fail(w.ctx.Err())
+		return
+	}
+	children, err := w.client.getAllBlockChildren(w.ctx, block.ID, "", w.pageSize)
+	<-w.sem
+
+	if err != nil {
+		w.fail(fmt.Errorf("failed to get children of block %s: %w", block.ID, err))
+		return
 	}
 
-	return allBlocks, nil
+	block.Children = children
+	if depth > 1 {
+		w.expand(children, depth-1)
+	}
+}
+
+// fail records the first error and cancels outstanding fetches.
+func (w *blockTreeWalker) fail(err error) {
+	w.errOnce.Do(func() {
+		w.err = err
+		w.cancel()
+	})
+}
+
+// wait blocks until every scheduled fetch has finished and returns the first error.
+func (w *blockTreeWalker) wait() error {
+	w.wg.Wait()
+	w.cancel()
+	return w.err
 }
 
 // DeleteBlock deletes (archives) a block.
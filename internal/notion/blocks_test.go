@@ -3,9 +3,14 @@ package notion
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestGetBlock_Success(t *testing.T) {
@@ -535,6 +540,181 @@ func TestGetBlockChildrenRecursive_Depth0_ReturnsEmpty(t *testing.T) {
 	}
 }
 
+// newBlockTreeServer serves a synthetic tree where every block listed in
+// tree has the given children. Responses are delayed so that concurrent
+// fetches overlap and complete out of order.
+func newBlockTreeServer(t *testing.T, tree map[string][]string, inFlight, maxInFlight *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for {
+			cur := atomic.LoadInt32(maxInFlight)
+			if n <= cur || atomic.CompareAndSwapInt32(maxInFlight, cur, n) {
+				break
+			}
+		}
+
+		parent := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+		children, ok := tree[parent]
+		if !ok {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		// Later siblings answer faster, so completion order differs from page order.
+		time.Sleep(time.Duration(10-len(parent)%10) * time.Millisecond)
+
+		results := make([]interface{}, 0, len(children))
+		for _, id := range children {
+			_, hasChildren := tree[id]
+			results = append(results, map[string]interface{}{
+				"object":       "block",
+				"id":           id,
+				"type":         "toggle",
+				"has_children": hasChildren,
+				"toggle":       map[string]interface{}{"rich_text": []interface{}{}},
+			})
+		}
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object":      "list",
+			"results":     results,
+			"has_more":    false,
+			"next_cursor": nil,
+		})
+	}))
+}
+
+func TestGetBlockChildrenRecursive_ConcurrentKeepsOrder(t *testing.T) {
+	tree := map[string][]string{"root": {}}
+	for i := 0; i < 8; i++ {
+		child := fmt.Sprintf("c%d", i)
+		tree["root"] = append(tree["root"], child)
+		tree[child] = []string{child + "-a", child + "-b"}
+		tree[child+"-a"] = []string{child + "-a-x"}
+	}
+
+	var inFlight, maxInFlight int32
+	server := newBlockTreeServer(t, tree, &inFlight, &maxInFlight)
+	defer server.Close()
+
+	// Disable pacing so the worker pool bound is what limits parallelism.
+	client := NewClient("test-token").WithBaseURL(server.URL).WithRateLimiter(nil)
+	blocks, err := client.GetBlockChildrenRecursive(context.Background(), "root", 3, &BlockChildrenOptions{Concurrency: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(blocks) != 8 {
+		t.Fatalf("expected 8 blocks, got %d", len(blocks))
+	}
+	for i, block := range blocks {
+		want := fmt.Sprintf("c%d", i)
+		if block.ID != want {
+			t.Fatalf("blocks[%d].ID = %q, want %q", i, block.ID, want)
+		}
+		if len(block.Children) != 2 || block.Children[0].ID != want+"-a" || block.Children[1].ID != want+"-b" {
+			t.Fatalf("unexpected children for %s: %+v", want, block.Children)
+		}
+		grandchildren := block.Children[0].Children
+		if len(grandchildren) != 1 || grandchildren[0].ID != want+"-a-x" {
+			t.Fatalf("unexpected grandchildren for %s-a: %+v", want, grandchildren)
+		}
+		// depth 3 stops before fetching great-grandchildren
+		if grandchildren[0].Children != nil {
+			t.Fatalf("expected no children beyond depth 3, got %+v", grandchildren[0].Children)
+		}
+	}
+
+	// The top-level fetch runs alone; nested fetches must respect the bound.
+	if got := atomic.LoadInt32(&maxInFlight); got > 3 {
+		t.Errorf("max in-flight requests = %d, want <= 3", got)
+	}
+}
+
+func TestGetBlockChildrenRecursive_ErrorStopsTraversal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blocks/root/children":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "list",
+				"results": []interface{}{
+					map[string]interface{}{"object": "block", "id": "ok", "type": "toggle", "has_children": true, "toggle": map[string]interface{}{}},
+					map[string]interface{}{"object": "block", "id": "missing", "type": "toggle", "has_children": true, "toggle": map[string]interface{}{}},
+				},
+				"has_more": false,
+			})
+		case "/blocks/ok/children":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "results": []interface{}{}, "has_more": false})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "error", "status": 404, "code": "object_not_found", "message": "not found"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token").WithBaseURL(server.URL)
+	_, err := client.GetBlockChildrenRecursive(context.Background(), "root", 2, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "failed to get children of block missing") {
+		t.Errorf("error = %q, want it to name the failing block", err.Error())
+	}
+}
+
+func TestGetBlockChildrenRecursive_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blocks/root/children" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "list",
+				"results": []interface{}{
+					map[string]interface{}{"object": "block", "id": "slow", "type": "toggle", "has_children": true, "toggle": map[string]interface{}{}},
+				},
+				"has_more": false,
+			})
+			return
+		}
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token").WithBaseURL(server.URL)
+	_, err := client.GetBlockChildrenRecursive(ctx, "root", 2, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGetBlockChildrenRecursive_HonoursRetryAfter(t *testing.T) {
+	var calls int32
+	var firstAt, retryAt time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			firstAt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "error", "status": 429, "code": "rate_limited", "message": "slow down"})
+			return
+		}
+		retryAt = time.Now()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "results": []interface{}{}, "has_more": false})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token").WithBaseURL(server.URL)
+	if _, err := client.GetBlockChildrenRecursive(context.Background(), "root", 2, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gap := retryAt.Sub(firstAt); gap < 900*time.Millisecond {
+		t.Errorf("retry happened after %v, want >= Retry-After (1s)", gap)
+	}
+	if until := client.rateLimiter.PausedUntil(); !until.IsZero() {
+		t.Errorf("expected pause to have elapsed, still paused until %v", until)
+	}
+}
+
 func TestBlock_MarshalJSON_IncludesChildren(t *testing.T) {
 	block := Block{
 		Object:      "block",
@@ -0,0 +1,347 @@
+package notion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached responses are served without asking the API.
+const DefaultCacheTTL = 10 * time.Minute
+
+// cacheableEndpoints lists the GET endpoints whose responses may be cached,
+// keyed by the ID captured in the path.
+var cacheableEndpoints = []struct {
+	kind string
+	re   *regexp.Regexp
+}{
+	{kind: "page", re: regexp.MustCompile(`^/pages/([^/?]+)$`)},
+	{kind: "database", re: regexp.MustCompile(`^/databases/([^/?]+)$`)},
+	{kind: "data_source", re: regexp.MustCompile(`^/data_sources/([^/?]+)$`)},
+	{kind: "user", re: regexp.MustCompile(`^/users/([^/?]+)$`)},
+	{kind: "block_children", re: regexp.MustCompile(`^/blocks/([^/?]+)/children(?:\?.*)?$`)},
+}
+
+// pathIDPattern matches Notion IDs (dashed or not) anywhere in a request path.
+var pathIDPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}`)
+
+// ResponseCache is an on-disk cache of read responses. Entries are grouped
+// by object ID so that a write to, or a newer last_edited_time for, an
+// object drops everything cached for it.
+type ResponseCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// cacheEntry is the on-disk representation of a cached response.
+type cacheEntry struct {
+	Key      string    `json:"key"`
+	Kind     string    `json:"kind"`
+	StoredAt time.Time `json:"stored_at"`
+	// LastEdited is the last_edited_time of the cached object itself.
+	// Listings such as block children have none.
+	LastEdited string          `json:"last_edited_time,omitempty"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// CacheStats summarises the contents of a ResponseCache.
+type CacheStats struct {
+	Dir       string         `json:"dir"`
+	Entries   int            `json:"entries"`
+	SizeBytes int64          `json:"size_bytes"`
+	Expired   int            `json:"expired"`
+	TTL       string         `json:"ttl"`
+	ByKind    map[string]int `json:"by_kind"`
+}
+
+// NewResponseCache creates a cache rooted at dir. A ttl <= 0 uses DefaultCacheTTL.
+func NewResponseCache(dir string, ttl time.Duration) *ResponseCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &ResponseCache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// Dir returns the directory holding the cache.
+func (rc *ResponseCache) Dir() string {
+	return rc.dir
+}
+
+// TTL returns how long entries stay fresh.
+func (rc *ResponseCache) TTL() time.Duration {
+	return rc.ttl
+}
+
+// Get returns the cached body for a request path (including its query), if fresh.
+func (rc *ResponseCache) Get(path string) ([]byte, bool) {
+	_, id, ok := cacheableRequest(path)
+	if !ok {
+		return nil, false
+	}
+	file := rc.entryPath(id, path)
+	entry, err := readCacheEntry(file)
+	if err != nil || entry.Key != path {
+		return nil, false
+	}
+	if rc.now().Sub(entry.StoredAt) > rc.ttl {
+		_ = os.Remove(file)
+		return nil, false
+	}
+	return entry.Body, true
+}
+
+// Put stores the response body for a request path. Paths that are not
+// cacheable are ignored.
+func (rc *ResponseCache) Put(path string, body []byte) error {
+	kind, id, ok := cacheableRequest(path)
+	if !ok || !json.Valid(body) {
+		return nil
+	}
+
+	entry := cacheEntry{
+		Key:      path,
+		Kind:     kind,
+		StoredAt: rc.now(),
+		Body:     json.RawMessage(body),
+	}
+	if kind != "block_children" {
+		var obj cachedObject
+		if err := json.Unmarshal(body, &obj); err == nil {
+			entry.LastEdited = obj.LastEditedTime
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	dir := filepath.Join(rc.dir, id)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), rc.entryPath(id, path)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Invalidate drops every entry cached for an object ID.
+func (rc *ResponseCache) Invalidate(id string) {
+	if id = normalizeCacheID(id); id != "" {
+		_ = os.RemoveAll(filepath.Join(rc.dir, id))
+	}
+}
+
+// Observe inspects a response body for objects carrying a last_edited_time,
+// at the top level or in a results list, and drops cached entries that are
+// older than what the API just reported.
+func (rc *ResponseCache) Observe(body []byte) {
+	var resp struct {
+		cachedObject
+		Results []cachedObject `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return
+	}
+	rc.observeObject(resp.cachedObject)
+	for _, obj := range resp.Results {
+		rc.observeObject(obj)
+	}
+}
+
+// Record updates the cache after a successful request: GET responses are
+// stored, and writes invalidate the objects they touched and their parents.
+// Queries and searches are POSTs that only read, so they invalidate nothing.
+func (rc *ResponseCache) Record(method, path string, body []byte) {
+	rc.Observe(body)
+	if method == http.MethodGet {
+		_ = rc.Put(path, body)
+		return
+	}
+	if isReadOnlyPost(path) {
+		return
+	}
+
+	for _, id := range pathIDPattern.FindAllString(path, -1) {
+		rc.Invalidate(id)
+	}
+	var resp struct {
+		ID     string `json:"id"`
+		Parent struct {
+			PageID       string `json:"page_id"`
+			BlockID      string `json:"block_id"`
+			DatabaseID   string `json:"database_id"`
+			DataSourceID string `json:"data_source_id"`
+		} `json:"parent"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return
+	}
+	for _, id := range []string{resp.ID, resp.Parent.PageID, resp.Parent.BlockID, resp.Parent.DatabaseID, resp.Parent.DataSourceID} {
+		rc.Invalidate(id)
+	}
+}
+
+// isReadOnlyPost reports whether path is a POST endpoint that reads:
+// database and data source queries, and search.
+func isReadOnlyPost(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	return path == "/search" || strings.HasSuffix(path, "/query")
+}
+
+// Stats walks the cache and reports its size.
+func (rc *ResponseCache) Stats() (*CacheStats, error) {
+	stats := &CacheStats{Dir: rc.dir, TTL: rc.ttl.String(), ByKind: map[string]int{}}
+	now := rc.now()
+	err := filepath.WalkDir(rc.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		entry, err := readCacheEntry(path)
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			stats.SizeBytes += info.Size()
+		}
+		stats.Entries++
+		stats.ByKind[entry.Kind]++
+		if now.Sub(entry.StoredAt) > rc.ttl {
+			stats.Expired++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	return stats, nil
+}
+
+// Clear removes every cached entry and returns how many were dropped.
+func (rc *ResponseCache) Clear() (int, error) {
+	stats, err := rc.Stats()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(rc.dir); err != nil {
+		return 0, fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return stats.Entries, nil
+}
+
+// cachedObject holds the fields Observe needs from any Notion object.
+type cachedObject struct {
+	ID             string `json:"id"`
+	LastEditedTime string `json:"last_edited_time"`
+}
+
+func (rc *ResponseCache) observeObject(obj cachedObject) {
+	id := normalizeCacheID(obj.ID)
+	if id == "" || obj.LastEditedTime == "" {
+		return
+	}
+	edited, err := time.Parse(time.RFC3339, obj.LastEditedTime)
+	if err != nil {
+		return
+	}
+
+	dir := filepath.Join(rc.dir, id)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		file := filepath.Join(dir, f.Name())
+		entry, err := readCacheEntry(file)
+		if err != nil {
+			continue
+		}
+		stale := false
+		if entry.LastEdited != "" {
+			stale = entry.LastEdited != obj.LastEditedTime
+		} else {
+			// last_edited_time has minute precision, so an edit within the
+			// minute the entry was stored cannot be told apart from one after it.
+			stale = !edited.Before(entry.StoredAt.Truncate(time.Minute))
+		}
+		if stale {
+			_ = os.Remove(file)
+		}
+	}
+}
+
+func (rc *ResponseCache) entryPath(id, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(rc.dir, id, hex.EncodeToString(sum[:])+".json")
+}
+
+func readCacheEntry(path string) (*cacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// cacheableRequest reports whether a GET path may be cached, returning the
+// kind of response and the normalised ID it belongs to.
+func cacheableRequest(path string) (kind, id string, ok bool) {
+	for _, ep := range cacheableEndpoints {
+		m := ep.re.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		if id = normalizeCacheID(m[1]); id == "" {
+			return "", "", false
+		}
+		return ep.kind, id, true
+	}
+	return "", "", false
+}
+
+// normalizeCacheID returns the undashed, lower-case form of a Notion ID, or
+// "" for anything else (such as "me").
+func normalizeCacheID(id string) string {
+	id = strings.ToLower(strings.ReplaceAll(id, "-", ""))
+	if len(id) != 32 {
+		return ""
+	}
+	for _, r := range id {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return ""
+		}
+	}
+	return id
+}
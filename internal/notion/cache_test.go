@@ -0,0 +1,196 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	cachePageID  = "11111111-2222-3333-4444-555555555555"
+	cacheChildID = "66666666-7777-8888-9999-000000000000"
+)
+
+func newTestCache(t *testing.T, clock *fakeClock) *ResponseCache {
+	t.Helper()
+	cache := NewResponseCache(t.TempDir(), time.Minute)
+	cache.now = clock.now
+	return cache
+}
+
+func TestResponseCache_PutGetAndExpire(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 2, 10, 0, 30, 0, time.UTC)}
+	cache := newTestCache(t, clock)
+	path := "/pages/" + cachePageID
+	body := []byte(`{"object":"page","id":"` + cachePageID + `","last_edited_time":"2026-01-02T09:00:00.000Z"}`)
+
+	if err := cache.Put(path, body); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got, ok := cache.Get(path); !ok || string(got) != string(body) {
+		t.Fatalf("Get = %s, %v; want cached body", got, ok)
+	}
+	if _, ok := cache.Get("/pages/" + cacheChildID); ok {
+		t.Fatal("Get for another page should miss")
+	}
+
+	clock.t = clock.t.Add(2 * time.Minute)
+	if _, ok := cache.Get(path); ok {
+		t.Fatal("Get after TTL should miss")
+	}
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Entries != 0 {
+		t.Errorf("expired entry should be removed on Get, stats = %+v", stats)
+	}
+}
+
+func TestResponseCache_IgnoresUncacheablePaths(t *testing.T) {
+	cache := newTestCache(t, &fakeClock{t: time.Now()})
+	for _, path := range []string{"/users/me", "/data_sources/templates", "/search", "/comments?block_id=" + cachePageID} {
+		if err := cache.Put(path, []byte(`{}`)); err != nil {
+			t.Fatalf("Put(%q): %v", path, err)
+		}
+		if _, ok := cache.Get(path); ok {
+			t.Errorf("Get(%q) hit; path should not be cacheable", path)
+		}
+	}
+}
+
+func TestResponseCache_ObserveNewerEdit(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 2, 10, 0, 30, 0, time.UTC)}
+	cache := newTestCache(t, clock)
+	pagePath := "/pages/" + cachePageID
+	childrenPath := "/blocks/" + cachePageID + "/children?page_size=100"
+	_ = cache.Put(pagePath, []byte(`{"object":"page","id":"`+cachePageID+`","last_edited_time":"2026-01-02T09:00:00.000Z"}`))
+	_ = cache.Put(childrenPath, []byte(`{"object":"list","results":[]}`))
+
+	// Seeing the edit time the page was cached with keeps both entries.
+	cache.Observe([]byte(`{"object":"list","results":[{"id":"` + cachePageID + `","last_edited_time":"2026-01-02T09:00:00.000Z"}]}`))
+	if _, ok := cache.Get(pagePath); !ok {
+		t.Fatal("page with unchanged last_edited_time should stay cached")
+	}
+	if _, ok := cache.Get(childrenPath); !ok {
+		t.Fatal("children listing stored after the last edit should stay cached")
+	}
+
+	cache.Observe([]byte(`{"object":"list","results":[{"id":"` + cachePageID + `","last_edited_time":"2026-01-02T10:00:00.000Z"}]}`))
+	if _, ok := cache.Get(pagePath); ok {
+		t.Error("page should be invalidated by a newer last_edited_time")
+	}
+	if _, ok := cache.Get(childrenPath); ok {
+		t.Error("children listing should be invalidated by an edit in the minute it was stored")
+	}
+}
+
+func TestResponseCache_RecordWriteInvalidatesParent(t *testing.T) {
+	cache := newTestCache(t, &fakeClock{t: time.Now()})
+	childrenPath := "/blocks/" + cachePageID + "/children"
+	_ = cache.Put(childrenPath, []byte(`{"object":"list","results":[]}`))
+
+	cache.Record(http.MethodDelete, "/blocks/"+cacheChildID, []byte(`{"object":"block","id":"`+cacheChildID+`","parent":{"type":"page_id","page_id":"`+cachePageID+`"}}`))
+	if _, ok := cache.Get(childrenPath); ok {
+		t.Error("deleting a child block should invalidate the parent's children listing")
+	}
+}
+
+func TestResponseCache_RecordQueryKeepsSchema(t *testing.T) {
+	cache := newTestCache(t, &fakeClock{t: time.Now()})
+	schemaPath := "/data_sources/" + cachePageID
+	_ = cache.Put(schemaPath, []byte(`{"object":"data_source","id":"`+cachePageID+`","properties":{}}`))
+
+	results := []byte(`{"object":"list","results":[{"object":"page","id":"` + cacheChildID + `"}]}`)
+	cache.Record(http.MethodPost, "/data_sources/"+cachePageID+"/query", results)
+	cache.Record(http.MethodPost, "/databases/"+cachePageID+"/query?filter_properties=title", results)
+	cache.Record(http.MethodPost, "/search", results)
+	if _, ok := cache.Get(schemaPath); !ok {
+		t.Error("querying a data source should keep its cached schema")
+	}
+
+	cache.Record(http.MethodPatch, "/data_sources/"+cachePageID, []byte(`{"object":"data_source","id":"`+cachePageID+`"}`))
+	if _, ok := cache.Get(schemaPath); ok {
+		t.Error("updating the data source should invalidate its cached schema")
+	}
+}
+
+func TestResponseCache_StatsAndClear(t *testing.T) {
+	cache := newTestCache(t, &fakeClock{t: time.Now()})
+	_ = cache.Put("/pages/"+cachePageID, []byte(`{"id":"`+cachePageID+`"}`))
+	_ = cache.Put("/users/"+cacheChildID, []byte(`{"id":"`+cacheChildID+`"}`))
+	_ = cache.Put("/blocks/"+cachePageID+"/children", []byte(`{"results":[]}`))
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Entries != 3 || stats.ByKind["page"] != 1 || stats.ByKind["user"] != 1 || stats.ByKind["block_children"] != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if stats.SizeBytes == 0 {
+		t.Error("expected non-zero cache size")
+	}
+
+	removed, err := cache.Clear()
+	if err != nil || removed != 3 {
+		t.Fatalf("Clear = %d, %v; want 3", removed, err)
+	}
+	if _, ok := cache.Get("/pages/" + cachePageID); ok {
+		t.Error("Get after Clear should miss")
+	}
+}
+
+func TestClient_CacheServesRepeatedReads(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object":           "page",
+				"id":               cachePageID,
+				"last_edited_time": "2026-01-02T09:00:00.000Z",
+			})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object":           "page",
+				"id":               cachePageID,
+				"last_edited_time": "2026-01-02T10:00:00.000Z",
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token").WithBaseURL(server.URL).WithRateLimiter(nil).
+		WithCache(NewResponseCache(t.TempDir(), time.Minute))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		page, err := client.GetPage(ctx, cachePageID)
+		if err != nil {
+			t.Fatalf("GetPage: %v", err)
+		}
+		if page.ID != cachePageID {
+			t.Fatalf("page id = %q", page.ID)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("API hits after repeated reads = %d, want 1", got)
+	}
+
+	if _, err := client.UpdatePage(ctx, cachePageID, &UpdatePageRequest{}); err != nil {
+		t.Fatalf("UpdatePage: %v", err)
+	}
+	if _, err := client.GetPage(ctx, cachePageID); err != nil {
+		t.Fatalf("GetPage after update: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("API hits after update and read = %d, want 3", got)
+	}
+}
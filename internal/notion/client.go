@@ -123,11 +123,13 @@ type Client struct {
 	maxRetries     int
 	circuitBreaker *circuitBreaker
 	rateLimiter    *RateLimitTracker
+	limiter        Limiter
+	cache          *ResponseCache
 }
 
 // NewClient creates a new Notion API client with the given token
 func NewClient(token string) *Client {
-	return &Client{
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
@@ -143,6 +145,8 @@ func NewClient(token string) *Client {
 		},
 		rateLimiter: NewRateLimitTracker(),
 	}
+	c.WithRateLimiter(NewTokenBucket(DefaultRateLimit, DefaultRateBurst))
+	return c
 }
 
 // WithHTTPClient sets a custom HTTP client
@@ -151,6 +155,20 @@ func (c *Client) WithHTTPClient(client *http.Client) *Client {
 	return c
 }
 
+// WithTransport replaces the HTTP transport, e.g. to record or replay traffic.
+func (c *Client) WithTransport(transport http.RoundTripper) *Client {
+	c.httpClient.Transport = transport
+	return c
+}
+
+// Transport returns the HTTP transport in use.
+func (c *Client) Transport() http.RoundTripper {
+	if c.httpClient.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.httpClient.Transport
+}
+
 // WithBaseURL sets a custom base URL (useful for testing)
 func (c *Client) WithBaseURL(baseURL string) *Client {
 	c.baseURL = baseURL
@@ -183,6 +201,42 @@ func (c *Client) EnableCircuitBreaker() *Client {
 	return c
 }
 
+// WithRateLimiter replaces the limiter that paces every request attempt.
+// Pass nil to disable client-side pacing. Limiters implementing
+// RateLimitObserver are also fed the API's X-RateLimit-* headers.
+func (c *Client) WithRateLimiter(limiter Limiter) *Client {
+	c.limiter = limiter
+	observer, _ := limiter.(RateLimitObserver)
+	c.rateLimiter.SetObserver(observer)
+	return c
+}
+
+// Limiter returns the limiter pacing requests, or nil when pacing is disabled.
+func (c *Client) Limiter() Limiter {
+	return c.limiter
+}
+
+// WithRateLimit paces requests to perSecond on average with the default burst.
+// A rate <= 0 disables client-side pacing.
+func (c *Client) WithRateLimit(perSecond float64) *Client {
+	if perSecond <= 0 {
+		return c.WithRateLimiter(nil)
+	}
+	return c.WithRateLimiter(NewTokenBucket(perSecond, DefaultRateBurst))
+}
+
+// WithCache serves repeated reads from an on-disk response cache.
+// Pass nil to disable caching.
+func (c *Client) WithCache(cache *ResponseCache) *Client {
+	c.cache = cache
+	return c
+}
+
+// Cache returns the response cache, or nil when caching is disabled.
+func (c *Client) Cache() *ResponseCache {
+	return c.cache
+}
+
 // WithDebug enables debug mode for HTTP request/response logging
 func (c *Client) WithDebug() *Client {
 	return c.WithDebugOutput(os.Stderr)
@@ -239,6 +293,10 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 			}
 		}
 
+		if err := c.waitForLimiter(ctx); err != nil {
+			return nil, ctxerrors.WrapContext(method, url, 0, err)
+		}
+
 		resp, err := c.doRequestOnce(ctx, method, path, body)
 		if err != nil {
 			lastErr = err
@@ -314,6 +372,7 @@ func (c *Client) doRequestOnceWithReader(ctx context.Context, method, requestURL
 	// Check for error responses
 	if resp.StatusCode >= 400 {
 		defer func() { _ = resp.Body.Close() }()
+		retryAfter := c.recordRetryAfter(resp)
 		var errResp ErrorResponse
 		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
 			return nil, &APIError{StatusCode: resp.StatusCode}
@@ -321,7 +380,7 @@ func (c *Client) doRequestOnceWithReader(ctx context.Context, method, requestURL
 		return nil, &APIError{
 			StatusCode: resp.StatusCode,
 			Response:   &errResp,
-			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			RetryAfter: retryAfter,
 		}
 	}
 
@@ -369,6 +428,10 @@ func (c *Client) doMultipartRequest(ctx context.Context, url string, fieldName s
 			}
 		}
 
+		if err := c.waitForLimiter(ctx); err != nil {
+			return ctxerrors.WrapContext(http.MethodPost, url, 0, err)
+		}
+
 		err := c.doMultipartRequestOnce(ctx, url, fieldName, bytes.NewReader(fileData), filename, contentType, result)
 		if err != nil {
 			lastErr = err
@@ -438,6 +501,24 @@ func (c *Client) doMultipartRequestOnce(ctx context.Context, url string, fieldNa
 	return nil
 }
 
+// waitForLimiter blocks until the client's limiter admits another request.
+func (c *Client) waitForLimiter(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.Wait(ctx)
+}
+
+// recordRetryAfter parses the Retry-After header of an error response and, for
+// 429s, pauses the rate limit tracker so concurrent callers back off as well.
+func (c *Client) recordRetryAfter(resp *http.Response) time.Duration {
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if resp.StatusCode == http.StatusTooManyRequests {
+		c.rateLimiter.Pause(retryAfter)
+	}
+	return retryAfter
+}
+
 // calculateRetryDelay calculates the delay before the next retry attempt
 func (c *Client) calculateRetryDelay(attempt int, lastErr error) time.Duration {
 	// Check if the error has a Retry-After header
@@ -497,69 +578,64 @@ func (c *Client) doGet(ctx context.Context, path string, query url.Values, resul
 		path = path + "?" + query.Encode()
 	}
 
-	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+	if c.cache != nil {
+		if data, ok := c.cache.Get(path); ok {
+			slog.Debug("cache hit", "path", path)
+			return decodeResult(data, result)
 		}
 	}
 
-	return nil
+	return c.doJSON(ctx, http.MethodGet, path, nil, result)
 }
 
 // doPost performs a POST request
 func (c *Client) doPost(ctx context.Context, path string, body, result interface{}) error {
-	resp, err := c.doRequest(ctx, http.MethodPost, path, body)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
-		}
-	}
-
-	return nil
+	return c.doJSON(ctx, http.MethodPost, path, body, result)
 }
 
 // doPatch performs a PATCH request
 func (c *Client) doPatch(ctx context.Context, path string, body, result interface{}) error {
-	resp, err := c.doRequest(ctx, http.MethodPatch, path, body)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
-		}
-	}
-
-	return nil
+	return c.doJSON(ctx, http.MethodPatch, path, body, result)
 }
 
 // doDelete performs a DELETE request
 func (c *Client) doDelete(ctx context.Context, path string, result interface{}) error {
-	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	return c.doJSON(ctx, http.MethodDelete, path, nil, result)
+}
+
+// doJSON performs a request and decodes the JSON response into result,
+// keeping the response cache up to date when one is configured.
+func (c *Client) doJSON(ctx context.Context, method, path string, body, result interface{}) error {
+	resp, err := c.doRequest(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+	if c.cache == nil {
+		if result != nil {
+			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
 		}
+		return nil
 	}
 
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	c.cache.Record(method, path, data)
+	return decodeResult(data, result)
+}
+
+func decodeResult(data []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
 	return nil
 }
 
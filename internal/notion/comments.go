@@ -31,6 +31,7 @@ type RichText struct {
 	Type        string       `json:"type"`
 	Text        *TextContent `json:"text,omitempty"`
 	Mention     *Mention     `json:"mention,omitempty"`
+	Equation    *Equation    `json:"equation,omitempty"`
 	Annotations *Annotations `json:"annotations,omitempty"`
 	PlainText   string       `json:"plain_text,omitempty"`
 	Href        string       `json:"href,omitempty"`
@@ -47,11 +48,19 @@ type Link struct {
 	URL string `json:"url"`
 }
 
+// Equation represents an inline equation in rich text.
+type Equation struct {
+	Expression string `json:"expression"`
+}
+
 // Mention represents a mention in rich text.
 type Mention struct {
-	Type string       `json:"type"`
-	User *UserMention `json:"user,omitempty"`
-	Page *PageMention `json:"page,omitempty"`
+	Type        string       `json:"type"`
+	User        *UserMention `json:"user,omitempty"`
+	Page        *PageMention `json:"page,omitempty"`
+	Database    *PageMention `json:"database,omitempty"`
+	Date        *Date        `json:"date,omitempty"`
+	LinkPreview *Link        `json:"link_preview,omitempty"`
 }
 
 // UserMention represents a user mention.
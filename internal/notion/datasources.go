@@ -1,7 +1,9 @@
 package notion
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 )
@@ -16,6 +18,50 @@ type DataSource struct {
 	Title          []RichText             `json:"title"`
 	Properties     map[string]interface{} `json:"properties"`
 	Parent         map[string]interface{} `json:"parent"`
+
+	// PropertyOrder lists the property names in the order the API returned
+	// them, which is the schema order shown in Notion. Properties is a map
+	// and loses it.
+	PropertyOrder []string `json:"-"`
+}
+
+// UnmarshalJSON decodes a data source and records its property order.
+func (d *DataSource) UnmarshalJSON(data []byte) error {
+	type dataSource DataSource
+	if err := json.Unmarshal(data, (*dataSource)(d)); err != nil {
+		return err
+	}
+	var raw struct {
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	d.PropertyOrder = objectKeys(raw.Properties)
+	return nil
+}
+
+// objectKeys returns the keys of a JSON object in document order, or nil if
+// data is not an object.
+func objectKeys(data json.RawMessage) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		key, _ := tok.(string)
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil
+		}
+		keys = append(keys, key)
+	}
+	return keys
 }
 
 // DataSourceList represents a paginated list of data sources.
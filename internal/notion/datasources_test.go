@@ -454,3 +454,24 @@ func TestListDataSourceTemplates_Empty(t *testing.T) {
 		t.Errorf("expected 0 templates, got %d", len(list.Results))
 	}
 }
+
+func TestDataSource_PropertyOrder(t *testing.T) {
+	data := []byte(`{"object":"data_source","id":"ds-1","properties":{"Name":{"type":"title"},"Status":{"type":"status"},"Due":{"type":"date"},"Assignee":{"type":"people"}}}`)
+
+	var ds DataSource
+	if err := json.Unmarshal(data, &ds); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := []string{"Name", "Status", "Due", "Assignee"}
+	if len(ds.PropertyOrder) != len(want) {
+		t.Fatalf("PropertyOrder = %v, want %v", ds.PropertyOrder, want)
+	}
+	for i := range want {
+		if ds.PropertyOrder[i] != want[i] {
+			t.Fatalf("PropertyOrder = %v, want %v", ds.PropertyOrder, want)
+		}
+	}
+	if ds.ID != "ds-1" || len(ds.Properties) != 4 {
+		t.Fatalf("unexpected data source: %+v", ds)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package notion
+
+import "os"
+
+// lockFile is a no-op on platforms without flock; the shared budget is then
+// best-effort across processes.
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without flock.
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,261 @@
+package notion
+
+import (
+	"context"
+	"iter"
+)
+
+// MaxPageSize is the largest page_size the API accepts.
+const MaxPageSize = 100
+
+// PageFunc fetches one page of a paginated endpoint starting at cursor.
+type PageFunc[T any] func(ctx context.Context, cursor string, pageSize int) (results []T, nextCursor *string, hasMore bool, err error)
+
+// IterOptions controls where an iterator starts and how much it fetches.
+type IterOptions struct {
+	// StartCursor resumes from a cursor returned by an earlier call.
+	StartCursor string
+	// PageSize is the page_size of each request. Zero leaves it to the API,
+	// which returns up to MaxPageSize items.
+	PageSize int
+	// Limit stops the iterator after this many items. Zero means no limit.
+	// The last request asks only for the items still needed, so the cursor
+	// left behind points exactly past the last item yielded.
+	Limit int
+}
+
+// Iterator lazily walks a paginated endpoint, fetching the next page only
+// once the previous one has been consumed.
+//
+// Ranging over All yields items in order. An error is yielded once, with a
+// zero item, and ends the iteration. Breaking out of the loop stops fetching;
+// items left on the current page are kept, so a later All resumes with them.
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch PageFunc[T]
+	opts  IterOptions
+
+	buf        []T
+	cursor     string
+	nextCursor string
+	hasMore    bool
+	started    bool
+	count      int
+	pages      int
+	err        error
+}
+
+// NewIterator returns an iterator over the pages produced by fetch.
+func NewIterator[T any](ctx context.Context, fetch PageFunc[T], opts IterOptions) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:    ctx,
+		fetch:  fetch,
+		opts:   opts,
+		cursor: opts.StartCursor,
+	}
+}
+
+// All returns a sequence over the remaining items.
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			if it.err != nil || it.limitReached() {
+				return
+			}
+			if len(it.buf) == 0 {
+				if it.started && !it.hasMore {
+					return
+				}
+				if err := it.fetchPage(); err != nil {
+					var zero T
+					yield(zero, err)
+					return
+				}
+				continue
+			}
+
+			item := it.buf[0]
+			it.buf = it.buf[1:]
+			it.count++
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (it *Iterator[T]) fetchPage() error {
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return err
+	}
+
+	pageSize := it.opts.PageSize
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	if it.opts.Limit > 0 {
+		remaining := it.opts.Limit - it.count
+		if (pageSize <= 0 && remaining < MaxPageSize) || (pageSize > 0 && remaining < pageSize) {
+			pageSize = remaining
+		}
+	}
+
+	results, next, more, err := it.fetch(it.ctx, it.cursor, pageSize)
+	if err != nil {
+		it.err = err
+		return err
+	}
+
+	it.started = true
+	it.pages++
+	it.buf = results
+	it.nextCursor = ""
+	if next != nil {
+		it.nextCursor = *next
+	}
+	it.hasMore = more && it.nextCursor != ""
+	it.cursor = it.nextCursor
+	return nil
+}
+
+func (it *Iterator[T]) limitReached() bool {
+	return it.opts.Limit > 0 && it.count >= it.opts.Limit
+}
+
+// Collect drains the iterator into a slice.
+func (it *Iterator[T]) Collect() ([]T, error) {
+	var out []T
+	for item, err := range it.All() {
+		if err != nil {
+			return out, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// NextCursor returns the cursor after the last page fetched, or "" when the
+// endpoint is exhausted. It is exact when iteration stopped at a page
+// boundary, which is always the case when it ran to completion, to Limit, or
+// to a failed fetch. Before the first page it is the start cursor.
+func (it *Iterator[T]) NextCursor() string {
+	if !it.started {
+		return it.opts.StartCursor
+	}
+	if !it.hasMore {
+		return ""
+	}
+	return it.nextCursor
+}
+
+// HasMore reports whether more items remain, either buffered or on the server.
+func (it *Iterator[T]) HasMore() bool {
+	return len(it.buf) > 0 || it.hasMore || !it.started
+}
+
+// Buffered returns the number of items fetched but not yet yielded. It is
+// zero at every page boundary.
+func (it *Iterator[T]) Buffered() int {
+	return len(it.buf)
+}
+
+// Count returns the number of items yielded so far.
+func (it *Iterator[T]) Count() int {
+	return it.count
+}
+
+// Pages returns the number of pages fetched so far.
+func (it *Iterator[T]) Pages() int {
+	return it.pages
+}
+
+// Err returns the error that ended iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// IterSearch iterates over search results. req's StartCursor and PageSize
+// are ignored in favour of opts.
+func (c *Client) IterSearch(ctx context.Context, req *SearchRequest, opts IterOptions) *Iterator[map[string]interface{}] {
+	base := SearchRequest{}
+	if req != nil {
+		base = *req
+	}
+	return NewIterator(ctx, func(ctx context.Context, cursor string, pageSize int) ([]map[string]interface{}, *string, bool, error) {
+		r := base
+		r.StartCursor = cursor
+		r.PageSize = pageSize
+		result, err := c.Search(ctx, &r)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return result.Results, result.NextCursor, result.HasMore, nil
+	}, opts)
+}
+
+// IterQueryDataSource iterates over the pages matching a data source query.
+// req's StartCursor and PageSize are ignored in favour of opts.
+func (c *Client) IterQueryDataSource(ctx context.Context, dataSourceID string, req *QueryDataSourceRequest, opts IterOptions) *Iterator[Page] {
+	base := QueryDataSourceRequest{}
+	if req != nil {
+		base = *req
+	}
+	return NewIterator(ctx, func(ctx context.Context, cursor string, pageSize int) ([]Page, *string, bool, error) {
+		r := base
+		r.StartCursor = cursor
+		r.PageSize = pageSize
+		result, err := c.QueryDataSource(ctx, dataSourceID, &r)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return result.Results, result.NextCursor, result.HasMore, nil
+	}, opts)
+}
+
+// IterBlockChildren iterates over the direct children of a block.
+func (c *Client) IterBlockChildren(ctx context.Context, blockID string, opts IterOptions) *Iterator[Block] {
+	return NewIterator(ctx, func(ctx context.Context, cursor string, pageSize int) ([]Block, *string, bool, error) {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return nil, nil, false, err
+		}
+		result, err := c.GetBlockChildren(ctx, blockID, &BlockChildrenOptions{StartCursor: cursor, PageSize: pageSize})
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return result.Results, result.NextCursor, result.HasMore, nil
+	}, opts)
+}
+
+// IterUsers iterates over the users in the workspace.
+func (c *Client) IterUsers(ctx context.Context, opts IterOptions) *Iterator[*User] {
+	return NewIterator(ctx, func(ctx context.Context, cursor string, pageSize int) ([]*User, *string, bool, error) {
+		result, err := c.ListUsers(ctx, &ListUsersOptions{StartCursor: cursor, PageSize: pageSize})
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return result.Results, result.NextCursor, result.HasMore, nil
+	}, opts)
+}
+
+// IterComments iterates over the un-resolved comments on a page or block.
+func (c *Client) IterComments(ctx context.Context, blockID string, opts IterOptions) *Iterator[*Comment] {
+	return NewIterator(ctx, func(ctx context.Context, cursor string, pageSize int) ([]*Comment, *string, bool, error) {
+		result, err := c.ListComments(ctx, blockID, &ListCommentsOptions{StartCursor: cursor, PageSize: pageSize})
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return result.Results, result.NextCursor, result.HasMore, nil
+	}, opts)
+}
+
+// IterFileUploads iterates over the integration's file uploads.
+func (c *Client) IterFileUploads(ctx context.Context, opts IterOptions) *Iterator[*FileUpload] {
+	return NewIterator(ctx, func(ctx context.Context, cursor string, pageSize int) ([]*FileUpload, *string, bool, error) {
+		result, err := c.ListFileUploads(ctx, &ListFileUploadsOptions{StartCursor: cursor, PageSize: pageSize})
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return result.Results, result.NextCursor, result.HasMore, nil
+	}, opts)
+}
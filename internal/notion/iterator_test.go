@@ -0,0 +1,214 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// fakePages serves the integers [0, total) in pages, using the offset as cursor.
+type fakePages struct {
+	total     int
+	pageSizes []int
+}
+
+func (f *fakePages) fetch(_ context.Context, cursor string, pageSize int) ([]int, *string, bool, error) {
+	f.pageSizes = append(f.pageSizes, pageSize)
+	start := 0
+	if cursor != "" {
+		start, _ = strconv.Atoi(cursor)
+	}
+	size := pageSize
+	if size <= 0 {
+		size = 3
+	}
+	end := start + size
+	if end > f.total {
+		end = f.total
+	}
+	var items []int
+	for i := start; i < end; i++ {
+		items = append(items, i)
+	}
+	if end >= f.total {
+		return items, nil, false, nil
+	}
+	next := strconv.Itoa(end)
+	return items, &next, true, nil
+}
+
+func TestIterator_AllPages(t *testing.T) {
+	f := &fakePages{total: 7}
+	it := NewIterator(context.Background(), f.fetch, IterOptions{})
+
+	got, err := it.Collect()
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(got) != 7 || got[6] != 6 {
+		t.Fatalf("got %v", got)
+	}
+	if it.Pages() != 3 {
+		t.Errorf("pages = %d, want 3", it.Pages())
+	}
+	if it.HasMore() || it.NextCursor() != "" {
+		t.Errorf("exhausted iterator reports has_more=%v cursor=%q", it.HasMore(), it.NextCursor())
+	}
+}
+
+func TestIterator_LimitStopsEarlyWithExactCursor(t *testing.T) {
+	f := &fakePages{total: 100}
+	it := NewIterator(context.Background(), f.fetch, IterOptions{PageSize: 4, Limit: 10})
+
+	got, err := it.Collect()
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(got) != 10 {
+		t.Fatalf("got %d items, want 10", len(got))
+	}
+	if want := []int{4, 4, 2}; fmt.Sprint(f.pageSizes) != fmt.Sprint(want) {
+		t.Errorf("page sizes = %v, want %v", f.pageSizes, want)
+	}
+	if it.NextCursor() != "10" || !it.HasMore() {
+		t.Errorf("cursor = %q has_more = %v, want 10/true", it.NextCursor(), it.HasMore())
+	}
+
+	// Resuming from the cursor continues exactly after the last item.
+	rest, err := NewIterator(context.Background(), f.fetch, IterOptions{StartCursor: it.NextCursor(), Limit: 1}).Collect()
+	if err != nil || len(rest) != 1 || rest[0] != 10 {
+		t.Fatalf("resume = %v, %v", rest, err)
+	}
+}
+
+func TestIterator_BreakKeepsBufferedItems(t *testing.T) {
+	f := &fakePages{total: 5}
+	it := NewIterator(context.Background(), f.fetch, IterOptions{})
+
+	for item, err := range it.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item == 1 {
+			break
+		}
+	}
+	if len(f.pageSizes) != 1 {
+		t.Fatalf("fetched %d pages before break, want 1", len(f.pageSizes))
+	}
+
+	rest, err := it.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(rest) != "[2 3 4]" {
+		t.Errorf("rest = %v, want [2 3 4]", rest)
+	}
+}
+
+func TestIterator_ErrorEndsIteration(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	it := NewIterator(context.Background(), func(context.Context, string, int) ([]int, *string, bool, error) {
+		calls++
+		if calls == 2 {
+			return nil, nil, false, boom
+		}
+		next := "c"
+		return []int{1}, &next, true, nil
+	}, IterOptions{})
+
+	var seen []int
+	var gotErr error
+	for item, err := range it.All() {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		seen = append(seen, item)
+	}
+	if !errors.Is(gotErr, boom) || !errors.Is(it.Err(), boom) {
+		t.Fatalf("error = %v / %v, want boom", gotErr, it.Err())
+	}
+	if len(seen) != 1 || calls != 2 {
+		t.Errorf("seen = %v calls = %d", seen, calls)
+	}
+}
+
+func TestIterator_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f := &fakePages{total: 5}
+	if _, err := NewIterator(ctx, f.fetch, IterOptions{}).Collect(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(f.pageSizes) != 0 {
+		t.Error("no page should be fetched after cancellation")
+	}
+}
+
+func TestClient_IterSearch(t *testing.T) {
+	var requests []SearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SearchRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+
+		result := SearchResult{Object: "list"}
+		for i := 0; i < req.PageSize; i++ {
+			result.Results = append(result.Results, map[string]interface{}{"id": fmt.Sprintf("%s-%d", req.StartCursor, i)})
+		}
+		next := "c" + strconv.Itoa(len(requests))
+		result.NextCursor = &next
+		result.HasMore = true
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token").WithBaseURL(server.URL)
+	it := client.IterSearch(context.Background(), &SearchRequest{Query: "q", PageSize: 7}, IterOptions{PageSize: 2, Limit: 3})
+	results, err := it.Collect()
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if len(requests) != 2 {
+		t.Fatalf("made %d requests, want 2", len(requests))
+	}
+	if requests[0].Query != "q" || requests[0].PageSize != 2 || requests[1].PageSize != 1 || requests[1].StartCursor != "c1" {
+		t.Errorf("unexpected requests %+v", requests)
+	}
+	if it.NextCursor() != "c2" {
+		t.Errorf("next cursor = %q, want c2", it.NextCursor())
+	}
+}
+
+func TestIterator_CursorAfterFailedFetch(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	it := NewIterator(context.Background(), func(_ context.Context, cursor string, _ int) ([]int, *string, bool, error) {
+		calls++
+		if calls == 2 {
+			return nil, nil, false, boom
+		}
+		next := "c" + strconv.Itoa(calls)
+		return []int{calls}, &next, true, nil
+	}, IterOptions{StartCursor: "c0"})
+
+	if it.NextCursor() != "c0" || !it.HasMore() {
+		t.Fatalf("before first page: cursor = %q has_more = %v", it.NextCursor(), it.HasMore())
+	}
+	if _, err := it.Collect(); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if it.NextCursor() != "c1" || !it.HasMore() {
+		t.Errorf("after failure: cursor = %q has_more = %v, want c1/true", it.NextCursor(), it.HasMore())
+	}
+}
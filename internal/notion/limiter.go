@@ -0,0 +1,136 @@
+package notion
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRateLimit is Notion's documented average request rate (requests per second).
+	DefaultRateLimit = 3.0
+	// DefaultRateBurst is the number of requests allowed back-to-back before pacing kicks in.
+	DefaultRateBurst = 10
+)
+
+// Limiter paces outgoing requests. Every request attempt made by Client
+// calls Wait first; returning an error aborts the request.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RateLimitObserver is implemented by limiters that adapt to the
+// X-RateLimit-* headers reported by the API.
+type RateLimitObserver interface {
+	ObserveRateLimit(info RateLimitInfo)
+}
+
+// TokenBucket is a Limiter that allows bursts of up to burst requests and
+// refills at rate requests per second.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	// last is the time tokens was last refilled. It may lie in the future
+	// when the API reported an exhausted window that has not reset yet.
+	last time.Time
+	now  func() time.Time
+}
+
+// NewTokenBucket creates a token bucket that starts full.
+// A rate <= 0 disables pacing; a burst < 1 is treated as 1.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// Rate returns the configured refill rate in requests per second.
+func (b *TokenBucket) Rate() float64 {
+	return b.rate
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return ctx.Err()
+	}
+
+	delay := b.reserve()
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.release()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ObserveRateLimit holds back all tokens until the reported reset time when
+// the API says the current window is exhausted.
+func (b *TokenBucket) ObserveRateLimit(info RateLimitInfo) {
+	if info.Limit <= 0 || info.Remaining > 0 || info.ResetAt.IsZero() {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	if info.ResetAt.After(b.last) {
+		b.last = info.ResetAt
+	}
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it.
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens--
+
+	readyAt := b.last
+	if b.tokens < 0 {
+		readyAt = readyAt.Add(time.Duration(-b.tokens / b.rate * float64(time.Second)))
+	}
+	return readyAt.Sub(b.now())
+}
+
+// release returns a reserved token that was never used.
+func (b *TokenBucket) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func (b *TokenBucket) refill() {
+	now := b.now()
+	if !now.After(b.last) {
+		return
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
@@ -0,0 +1,189 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for TokenBucket tests.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestBucket(rate float64, burst int, clock *fakeClock) *TokenBucket {
+	b := NewTokenBucket(rate, burst)
+	b.now = clock.now
+	b.last = clock.t
+	return b
+}
+
+func TestTokenBucket_BurstThenPace(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestBucket(2, 3, clock)
+
+	for i := 0; i < 3; i++ {
+		if d := b.reserve(); d > 0 {
+			t.Fatalf("reserve %d within burst returned delay %v", i, d)
+		}
+	}
+
+	if d := b.reserve(); d != 500*time.Millisecond {
+		t.Errorf("4th reserve delay = %v, want 500ms", d)
+	}
+	if d := b.reserve(); d != time.Second {
+		t.Errorf("5th reserve delay = %v, want 1s", d)
+	}
+
+	// After enough time passes the bucket refills but never beyond burst.
+	clock.t = clock.t.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if d := b.reserve(); d > 0 {
+			t.Fatalf("reserve %d after refill returned delay %v", i, d)
+		}
+	}
+	if d := b.reserve(); d <= 0 {
+		t.Error("expected delay once refilled burst is spent")
+	}
+}
+
+func TestTokenBucket_ObserveExhaustedWindow(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestBucket(1, 5, clock)
+
+	b.ObserveRateLimit(RateLimitInfo{Limit: 100, Remaining: 0, ResetAt: clock.t.Add(10 * time.Second)})
+
+	if d := b.reserve(); d != 11*time.Second {
+		t.Errorf("reserve after exhausted window = %v, want 11s", d)
+	}
+}
+
+func TestTokenBucket_ObserveIgnoresHealthyWindow(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestBucket(1, 5, clock)
+
+	b.ObserveRateLimit(RateLimitInfo{Limit: 100, Remaining: 50, ResetAt: clock.t.Add(10 * time.Second)})
+	b.ObserveRateLimit(RateLimitInfo{Remaining: 0})
+
+	if d := b.reserve(); d > 0 {
+		t.Errorf("reserve delay = %v, want none", d)
+	}
+}
+
+func TestTokenBucket_WaitCancelledReturnsToken(t *testing.T) {
+	b := NewTokenBucket(0.001, 1)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait(): %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v, want context.DeadlineExceeded", err)
+	}
+
+	b.mu.Lock()
+	tokens := b.tokens
+	b.mu.Unlock()
+	if tokens < -0.01 {
+		t.Errorf("tokens = %v, cancelled wait should not keep its reservation", tokens)
+	}
+}
+
+func TestTokenBucket_ZeroRateUnlimited(t *testing.T) {
+	b := NewTokenBucket(0, 1)
+	for i := 0; i < 100; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait(): %v", err)
+		}
+	}
+}
+
+type countingLimiter struct {
+	calls    int32
+	observed int32
+	err      error
+}
+
+func (l *countingLimiter) Wait(context.Context) error {
+	atomic.AddInt32(&l.calls, 1)
+	return l.err
+}
+
+func (l *countingLimiter) ObserveRateLimit(RateLimitInfo) {
+	atomic.AddInt32(&l.observed, 1)
+}
+
+func TestClient_AllRequestPathsUseLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "user", "id": "u1"})
+	}))
+	defer server.Close()
+
+	limiter := &countingLimiter{}
+	client := NewClient("test-token").WithBaseURL(server.URL).WithRateLimiter(limiter)
+	ctx := context.Background()
+
+	if _, err := client.GetSelf(ctx); err != nil {
+		t.Fatalf("GetSelf: %v", err)
+	}
+	if _, err := client.DoRawRequest(ctx, http.MethodGet, "/users/me", nil, nil); err != nil {
+		t.Fatalf("DoRawRequest: %v", err)
+	}
+	if err := client.doMultipartRequest(ctx, server.URL+"/file_uploads/f1/send", "file", strings.NewReader("data"), "a.txt", "text/plain", nil); err != nil {
+		t.Fatalf("doMultipartRequest: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&limiter.calls); got != 3 {
+		t.Errorf("limiter Wait calls = %d, want 3", got)
+	}
+	if got := atomic.LoadInt32(&limiter.observed); got != 3 {
+		t.Errorf("limiter observed %d header updates, want 3", got)
+	}
+}
+
+func TestClient_LimiterErrorAbortsRequest(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+
+	limiter := &countingLimiter{err: context.Canceled}
+	client := NewClient("test-token").WithBaseURL(server.URL).WithRateLimiter(limiter)
+
+	_, err := client.GetSelf(context.Background())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetSelf() = %v, want context.Canceled", err)
+	}
+	if atomic.LoadInt32(&hits) != 0 {
+		t.Error("request should not reach the server when the limiter fails")
+	}
+}
+
+func TestClient_WithRateLimit(t *testing.T) {
+	client := NewClient("test-token")
+	if bucket, ok := client.limiter.(*TokenBucket); !ok || bucket.Rate() != DefaultRateLimit {
+		t.Fatalf("default limiter = %#v, want TokenBucket at %v req/s", client.limiter, DefaultRateLimit)
+	}
+
+	client.WithRateLimit(10)
+	if bucket, ok := client.limiter.(*TokenBucket); !ok || bucket.Rate() != 10 {
+		t.Fatalf("limiter = %#v, want TokenBucket at 10 req/s", client.limiter)
+	}
+
+	client.WithRateLimit(0)
+	if client.limiter != nil {
+		t.Fatalf("limiter = %#v, want nil after WithRateLimit(0)", client.limiter)
+	}
+}
@@ -1,5 +1,7 @@
 package notion
 
+import "strconv"
+
 // FormulaProperty represents a formula property value.
 // Formula values are read-only and computed by Notion.
 type FormulaProperty struct {
@@ -64,32 +66,37 @@ const (
 	RollupFunctionPercentUnchecked = "percent_unchecked"
 )
 
-// PropertyValue is a generic property value that can hold any property type.
+// PropertyValue is a page property value. It is a tagged union: Type names the
+// property type and only the matching field is meaningful. Use
+// DecodePropertyValue to read one from a page and Encode to build the write
+// payload for it.
 type PropertyValue struct {
-	ID      string        `json:"id,omitempty"`
-	Type    string        `json:"type"`
-	Formula *FormulaValue `json:"formula,omitempty"`
-	Rollup  *RollupValue  `json:"rollup,omitempty"`
-	// Other common property types
-	Title          []RichText               `json:"title"`
-	RichText       []RichText               `json:"rich_text"`
-	Number         *float64                 `json:"number,omitempty"`
-	Select         *SelectOption            `json:"select,omitempty"`
-	MultiSelect    []SelectOption           `json:"multi_select"`
-	Date           *Date                    `json:"date,omitempty"`
-	Checkbox       *bool                    `json:"checkbox,omitempty"`
-	URL            *string                  `json:"url,omitempty"`
-	Email          *string                  `json:"email,omitempty"`
-	PhoneNumber    *string                  `json:"phone_number,omitempty"`
-	Files          []FileReference          `json:"files"`
-	Relation       []RelationReference      `json:"relation"`
-	People         []map[string]interface{} `json:"people"`
-	CreatedTime    *string                  `json:"created_time,omitempty"`
-	LastEditedTime *string                  `json:"last_edited_time,omitempty"`
-	CreatedBy      map[string]interface{}   `json:"created_by,omitempty"`
-	LastEditedBy   map[string]interface{}   `json:"last_edited_by,omitempty"`
-	Status         *StatusOption            `json:"status,omitempty"`
-	UniqueID       *UniqueID                `json:"unique_id,omitempty"`
+	ID             string                 `json:"id,omitempty"`
+	Type           string                 `json:"type"`
+	Title          []RichText             `json:"title,omitempty"`
+	RichText       []RichText             `json:"rich_text,omitempty"`
+	Number         *float64               `json:"number,omitempty"`
+	Select         *SelectOption          `json:"select,omitempty"`
+	MultiSelect    []SelectOption         `json:"multi_select,omitempty"`
+	Status         *StatusOption          `json:"status,omitempty"`
+	Date           *Date                  `json:"date,omitempty"`
+	People         []User                 `json:"people,omitempty"`
+	Files          []FileReference        `json:"files,omitempty"`
+	Checkbox       *bool                  `json:"checkbox,omitempty"`
+	URL            *string                `json:"url,omitempty"`
+	Email          *string                `json:"email,omitempty"`
+	PhoneNumber    *string                `json:"phone_number,omitempty"`
+	Relation       []RelationReference    `json:"relation,omitempty"`
+	HasMore        bool                   `json:"has_more,omitempty"`
+	Formula        *FormulaValue          `json:"formula,omitempty"`
+	Rollup         *RollupValue           `json:"rollup,omitempty"`
+	CreatedTime    *string                `json:"created_time,omitempty"`
+	CreatedBy      *User                  `json:"created_by,omitempty"`
+	LastEditedTime *string                `json:"last_edited_time,omitempty"`
+	LastEditedBy   *User                  `json:"last_edited_by,omitempty"`
+	UniqueID       *UniqueID              `json:"unique_id,omitempty"`
+	Verification   *Verification          `json:"verification,omitempty"`
+	Button         map[string]interface{} `json:"button,omitempty"`
 }
 
 // SelectOption represents a select or multi-select option.
@@ -109,10 +116,23 @@ type StatusOption struct {
 
 // FileReference represents a file in a files property.
 type FileReference struct {
-	Name     string                 `json:"name"`
-	Type     string                 `json:"type"` // "file" or "external"
-	File     map[string]interface{} `json:"file,omitempty"`
-	External map[string]interface{} `json:"external,omitempty"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"` // "file", "external" or "file_upload"
+	File       *FileLocation  `json:"file,omitempty"`
+	External   *FileLocation  `json:"external,omitempty"`
+	FileUpload *FileUploadRef `json:"file_upload,omitempty"`
+}
+
+// URL returns the file's URL, or "" for file uploads.
+func (f FileReference) URL() string {
+	switch {
+	case f.File != nil:
+		return f.File.URL
+	case f.External != nil:
+		return f.External.URL
+	default:
+		return ""
+	}
 }
 
 // RelationReference represents a relation to another page.
@@ -126,6 +146,22 @@ type UniqueID struct {
 	Number int     `json:"number"`
 }
 
+// String returns the ID with its prefix, e.g. "TASK-42".
+func (u UniqueID) String() string {
+	n := strconv.Itoa(u.Number)
+	if u.Prefix != nil && *u.Prefix != "" {
+		return *u.Prefix + "-" + n
+	}
+	return n
+}
+
+// Verification represents the verification state of a wiki page.
+type Verification struct {
+	State      string `json:"state"` // "verified", "expired" or "unverified"
+	VerifiedBy *User  `json:"verified_by,omitempty"`
+	Date       *Date  `json:"date,omitempty"`
+}
+
 // GetFormulaString returns the string value of a formula, or empty string if not a string formula.
 func (f *FormulaValue) GetFormulaString() string {
 	if f == nil {
@@ -0,0 +1,237 @@
+package notion
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxRichTextContent is the API limit on the content of one text object.
+const maxRichTextContent = 2000
+
+// dateRangeSeparators split "start → end" style date ranges.
+var dateRangeSeparators = []string{"→", "->", ".."}
+
+// ParsePropertyValue converts a user-supplied string into a typed value for a
+// property of the given schema type. It is the single place where flags, CSV
+// cells and --set clauses are interpreted:
+//
+//   - title, rich_text: plain text
+//   - number: a decimal number
+//   - select, status: the option name
+//   - multi_select: option names separated by ";"
+//   - date: an ISO date or date-time, or a range "start/end", "start..end" or "start → end"
+//   - checkbox: true/false, yes/no, 1/0, x
+//   - people, relation: IDs or Notion URLs separated by "," or ";"
+//   - files: URLs separated by "," or ";"
+//   - url, email, phone_number: the value as-is
+//
+// An empty string clears the property. Read-only types are rejected.
+func ParsePropertyValue(propType, value string) (*PropertyValue, error) {
+	if IsReadOnlyPropertyType(propType) {
+		return nil, fmt.Errorf("property type %q is read-only", propType)
+	}
+
+	value = strings.TrimSpace(value)
+	pv := &PropertyValue{Type: propType}
+
+	switch propType {
+	case "title":
+		pv.Title = TextRichText(value)
+	case "rich_text":
+		pv.RichText = TextRichText(value)
+	case "number":
+		if value == "" {
+			break
+		}
+		n, err := strconv.ParseFloat(strings.ReplaceAll(value, "_", ""), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as a number", value)
+		}
+		pv.Number = &n
+	case "select":
+		if value != "" {
+			pv.Select = &SelectOption{Name: value}
+		}
+	case "status":
+		if value != "" {
+			pv.Status = &StatusOption{Name: value}
+		}
+	case "multi_select":
+		pv.MultiSelect = []SelectOption{}
+		for _, name := range splitList(value, ";") {
+			pv.MultiSelect = append(pv.MultiSelect, SelectOption{Name: name})
+		}
+	case "date":
+		if value == "" {
+			break
+		}
+		d, err := ParseDateRange(value)
+		if err != nil {
+			return nil, err
+		}
+		pv.Date = d
+	case "checkbox":
+		b, err := parseCheckbox(value)
+		if err != nil {
+			return nil, err
+		}
+		pv.Checkbox = &b
+	case "url":
+		pv.URL = optionalString(value)
+	case "email":
+		pv.Email = optionalString(value)
+	case "phone_number":
+		pv.PhoneNumber = optionalString(value)
+	case "people":
+		pv.People = []User{}
+		for _, id := range splitList(value, ",;") {
+			pv.People = append(pv.People, User{Object: "user", ID: notionID(id)})
+		}
+	case "relation":
+		pv.Relation = []RelationReference{}
+		for _, id := range splitList(value, ",;") {
+			pv.Relation = append(pv.Relation, RelationReference{ID: notionID(id)})
+		}
+	case "files":
+		pv.Files = []FileReference{}
+		for _, raw := range splitList(value, ",;") {
+			f, err := externalFile(raw)
+			if err != nil {
+				return nil, err
+			}
+			pv.Files = append(pv.Files, f)
+		}
+	case "":
+		return nil, fmt.Errorf("property type is empty")
+	default:
+		return nil, fmt.Errorf("unsupported property type %q", propType)
+	}
+
+	return pv, nil
+}
+
+// PropertyPayload parses value for a property of the given type and returns
+// the request payload, e.g. {"status": {"name": "Done"}}.
+func PropertyPayload(propType, value string) (map[string]interface{}, error) {
+	pv, err := ParsePropertyValue(propType, value)
+	if err != nil {
+		return nil, err
+	}
+	return pv.Encode()
+}
+
+// TextRichText builds plain rich text for s, split into chunks that fit the
+// API's per-object content limit.
+func TextRichText(s string) []RichText {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return []RichText{{Type: "text", Text: &TextContent{Content: ""}}}
+	}
+	var out []RichText
+	for len(runes) > 0 {
+		n := len(runes)
+		if n > maxRichTextContent {
+			n = maxRichTextContent
+		}
+		out = append(out, RichText{Type: "text", Text: &TextContent{Content: string(runes[:n])}})
+		runes = runes[n:]
+	}
+	return out
+}
+
+// ParseDateRange parses a date or date range. Ranges may be written as an ISO
+// 8601 interval ("start/end") or with "..", "->" or "→" between the ends.
+func ParseDateRange(value string) (*Date, error) {
+	value = strings.TrimSpace(value)
+	start, end := value, ""
+	for _, sep := range dateRangeSeparators {
+		if i := strings.Index(value, sep); i >= 0 {
+			start, end = value[:i], value[i+len(sep):]
+			break
+		}
+	}
+	if end == "" {
+		if i := strings.Index(value, "/"); i >= 0 && looksLikeISODate(value[:i]) && looksLikeISODate(value[i+1:]) {
+			start, end = value[:i], value[i+1:]
+		}
+	}
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if start == "" {
+		return nil, fmt.Errorf("date range %q has no start", value)
+	}
+
+	d := &Date{Start: start}
+	if end != "" {
+		d.End = &end
+	}
+	return d, nil
+}
+
+// looksLikeISODate reports whether s starts with a YYYY-MM-DD date.
+func looksLikeISODate(s string) bool {
+	s = strings.TrimSpace(s)
+	if len(s) < 10 || s[4] != '-' || s[7] != '-' {
+		return false
+	}
+	_, err := strconv.Atoi(s[:4])
+	return err == nil
+}
+
+// notionID accepts a bare ID or a Notion URL and returns the ID.
+func notionID(s string) string {
+	if id, err := ExtractIDFromNotionURL(s); err == nil {
+		return id
+	}
+	return s
+}
+
+func parseCheckbox(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "1", "x", "checked", "on":
+		return true, nil
+	case "false", "no", "n", "0", "", "unchecked", "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("cannot parse %q as a checkbox (use true or false)", value)
+	}
+}
+
+func externalFile(raw string) (FileReference, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return FileReference{}, fmt.Errorf("file %q is not a URL", raw)
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" || name == "" {
+		name = u.Host
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	if r := []rune(name); len(r) > 100 {
+		name = string(r[:100])
+	}
+	return FileReference{Name: name, Type: "external", External: &FileLocation{URL: raw}}, nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// splitList splits s on any of seps, trimming items and dropping empties.
+func splitList(s, seps string) []string {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return strings.ContainsRune(seps, r) })
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
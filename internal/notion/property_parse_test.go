@@ -0,0 +1,86 @@
+package notion
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPropertyPayload(t *testing.T) {
+	tests := []struct {
+		propType string
+		value    string
+		want     string
+	}{
+		{"title", "Hello", `{"title":[{"text":{"content":"Hello"}}]}`},
+		{"rich_text", "", `{"rich_text":[{"text":{"content":""}}]}`},
+		{"number", "1_000.5", `{"number":1000.5}`},
+		{"number", "", `{"number":null}`},
+		{"select", "High", `{"select":{"name":"High"}}`},
+		{"select", "", `{"select":null}`},
+		{"status", "In progress", `{"status":{"name":"In progress"}}`},
+		{"multi_select", "a; b ;;c", `{"multi_select":[{"name":"a"},{"name":"b"},{"name":"c"}]}`},
+		{"date", "2024-01-15", `{"date":{"start":"2024-01-15"}}`},
+		{"date", "2024-01-15/2024-01-20", `{"date":{"start":"2024-01-15","end":"2024-01-20"}}`},
+		{"date", "2024-01-15T09:00:00Z .. 2024-01-15T10:00:00Z", `{"date":{"start":"2024-01-15T09:00:00Z","end":"2024-01-15T10:00:00Z"}}`},
+		{"date", "2024-01-15 → 2024-01-20", `{"date":{"start":"2024-01-15","end":"2024-01-20"}}`},
+		{"date", "01/15/2024", `{"date":{"start":"01/15/2024"}}`},
+		{"date", "", `{"date":null}`},
+		{"checkbox", "Yes", `{"checkbox":true}`},
+		{"checkbox", "0", `{"checkbox":false}`},
+		{"url", "https://example.invalid", `{"url":"https://example.invalid"}`},
+		{"email", "", `{"email":null}`},
+		{"phone_number", "+1 555", `{"phone_number":"+1 555"}`},
+		{"people", "u1, u2", `{"people":[{"object":"user","id":"u1"},{"object":"user","id":"u2"}]}`},
+		{"relation", "https://example.invalid/Page-0123456789ABCDEF0123456789ABCDEF;p2", `{"relation":[{"id":"0123456789abcdef0123456789abcdef"},{"id":"p2"}]}`},
+		{"relation", "", `{"relation":[]}`},
+		{"files", "https://example.invalid/a%20b.pdf", `{"files":[{"name":"a b.pdf","type":"external","external":{"url":"https://example.invalid/a%20b.pdf"}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.propType+"/"+tt.value, func(t *testing.T) {
+			payload, err := PropertyPayload(tt.propType, tt.value)
+			if err != nil {
+				t.Fatalf("PropertyPayload: %v", err)
+			}
+			got, _ := json.Marshal(payload)
+			assertJSONEqual(t, got, []byte(tt.want))
+		})
+	}
+}
+
+func TestPropertyPayload_Errors(t *testing.T) {
+	tests := []struct {
+		propType string
+		value    string
+		wantErr  string
+	}{
+		{"number", "abc", "cannot parse"},
+		{"checkbox", "maybe", "checkbox"},
+		{"files", "not-a-url", "not a URL"},
+		{"date", "..2024-01-01", "no start"},
+		{"formula", "1", "read-only"},
+		{"unique_id", "TASK-1", "read-only"},
+		{"verification", "verified", "read-only"},
+		{"place", "x", "unsupported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.propType, func(t *testing.T) {
+			_, err := PropertyPayload(tt.propType, tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTextRichText_SplitsLongText(t *testing.T) {
+	rt := TextRichText(strings.Repeat("é", maxRichTextContent+5))
+	if len(rt) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(rt))
+	}
+	if n := len([]rune(rt[1].Text.Content)); n != 5 {
+		t.Errorf("second chunk has %d runes, want 5", n)
+	}
+}
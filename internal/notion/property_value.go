@@ -0,0 +1,332 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// readOnlyPropertyTypes are computed by Notion and cannot be written.
+var readOnlyPropertyTypes = map[string]bool{
+	"formula":          true,
+	"rollup":           true,
+	"created_time":     true,
+	"created_by":       true,
+	"last_edited_time": true,
+	"last_edited_by":   true,
+	"unique_id":        true,
+	"verification":     true,
+	"button":           true,
+}
+
+// IsReadOnlyPropertyType reports whether values of the given property type are
+// computed by Notion and rejected on create or update.
+func IsReadOnlyPropertyType(propType string) bool {
+	return readOnlyPropertyTypes[propType]
+}
+
+// DecodePropertyValue decodes a raw property value as found in Page.Properties.
+func DecodePropertyValue(raw interface{}) (*PropertyValue, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode property value: %w", err)
+	}
+	var pv PropertyValue
+	if err := json.Unmarshal(data, &pv); err != nil {
+		return nil, fmt.Errorf("failed to decode property value: %w", err)
+	}
+	if pv.Type == "" {
+		return nil, fmt.Errorf("property value has no type")
+	}
+	return &pv, nil
+}
+
+// DecodeProperties decodes every property in a page's property map.
+func DecodeProperties(properties map[string]interface{}) (map[string]*PropertyValue, error) {
+	out := make(map[string]*PropertyValue, len(properties))
+	for name, raw := range properties {
+		pv, err := DecodePropertyValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", name, err)
+		}
+		out[name] = pv
+	}
+	return out, nil
+}
+
+// PropertyValues decodes the page's properties into typed values.
+func (p *Page) PropertyValues() (map[string]*PropertyValue, error) {
+	return DecodeProperties(p.Properties)
+}
+
+// MarshalJSON emits only the field selected by Type, so empty values are
+// written as null or [] the way the API returns them.
+func (pv PropertyValue) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{"type": pv.Type}
+	if pv.ID != "" {
+		out["id"] = pv.ID
+	}
+	if pv.Type != "" {
+		out[pv.Type] = pv.value()
+	}
+	if pv.Type == "relation" && pv.HasMore {
+		out["has_more"] = true
+	}
+	return json.Marshal(out)
+}
+
+// value returns the field selected by Type, normalising nil slices to empty.
+func (pv *PropertyValue) value() interface{} {
+	switch pv.Type {
+	case "title":
+		return nonNilSlice(pv.Title)
+	case "rich_text":
+		return nonNilSlice(pv.RichText)
+	case "number":
+		return pv.Number
+	case "select":
+		return pv.Select
+	case "multi_select":
+		return nonNilSlice(pv.MultiSelect)
+	case "status":
+		return pv.Status
+	case "date":
+		return pv.Date
+	case "people":
+		return nonNilSlice(pv.People)
+	case "files":
+		return nonNilSlice(pv.Files)
+	case "checkbox":
+		return pv.Checkbox != nil && *pv.Checkbox
+	case "url":
+		return pv.URL
+	case "email":
+		return pv.Email
+	case "phone_number":
+		return pv.PhoneNumber
+	case "relation":
+		return nonNilSlice(pv.Relation)
+	case "formula":
+		return pv.Formula
+	case "rollup":
+		return pv.Rollup
+	case "created_time":
+		return pv.CreatedTime
+	case "created_by":
+		return pv.CreatedBy
+	case "last_edited_time":
+		return pv.LastEditedTime
+	case "last_edited_by":
+		return pv.LastEditedBy
+	case "unique_id":
+		return pv.UniqueID
+	case "verification":
+		return pv.Verification
+	case "button":
+		if pv.Button == nil {
+			return map[string]interface{}{}
+		}
+		return pv.Button
+	default:
+		return nil
+	}
+}
+
+func nonNilSlice[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// Encode builds the create/update payload for the value, e.g.
+// {"select": {"name": "Done"}}. Read-only types return an error.
+func (pv *PropertyValue) Encode() (map[string]interface{}, error) {
+	if IsReadOnlyPropertyType(pv.Type) {
+		return nil, fmt.Errorf("property type %q is read-only", pv.Type)
+	}
+
+	var v interface{}
+	switch pv.Type {
+	case "title":
+		v = EncodeRichText(pv.Title)
+	case "rich_text":
+		v = EncodeRichText(pv.RichText)
+	case "number":
+		if pv.Number != nil {
+			v = *pv.Number
+		}
+	case "select":
+		if pv.Select != nil {
+			v = encodeOption(pv.Select.ID, pv.Select.Name)
+		}
+	case "multi_select":
+		opts := make([]map[string]interface{}, 0, len(pv.MultiSelect))
+		for _, o := range pv.MultiSelect {
+			opts = append(opts, encodeOption(o.ID, o.Name))
+		}
+		v = opts
+	case "status":
+		if pv.Status != nil {
+			v = encodeOption(pv.Status.ID, pv.Status.Name)
+		}
+	case "date":
+		if pv.Date != nil {
+			v = encodeDate(pv.Date)
+		}
+	case "people":
+		people := make([]map[string]interface{}, 0, len(pv.People))
+		for _, u := range pv.People {
+			people = append(people, map[string]interface{}{"object": "user", "id": u.ID})
+		}
+		v = people
+	case "files":
+		files := make([]map[string]interface{}, 0, len(pv.Files))
+		for _, f := range pv.Files {
+			files = append(files, encodeFile(f))
+		}
+		v = files
+	case "checkbox":
+		v = pv.Checkbox != nil && *pv.Checkbox
+	case "url":
+		if pv.URL != nil {
+			v = *pv.URL
+		}
+	case "email":
+		if pv.Email != nil {
+			v = *pv.Email
+		}
+	case "phone_number":
+		if pv.PhoneNumber != nil {
+			v = *pv.PhoneNumber
+		}
+	case "relation":
+		rel := make([]map[string]interface{}, 0, len(pv.Relation))
+		for _, r := range pv.Relation {
+			rel = append(rel, map[string]interface{}{"id": r.ID})
+		}
+		v = rel
+	case "":
+		return nil, fmt.Errorf("property value has no type")
+	default:
+		return nil, fmt.Errorf("unsupported property type %q", pv.Type)
+	}
+
+	return map[string]interface{}{pv.Type: v}, nil
+}
+
+func encodeOption(id, name string) map[string]interface{} {
+	if name == "" && id != "" {
+		return map[string]interface{}{"id": id}
+	}
+	return map[string]interface{}{"name": name}
+}
+
+func encodeDate(d *Date) map[string]interface{} {
+	m := map[string]interface{}{"start": d.Start}
+	if d.End != nil && *d.End != "" {
+		m["end"] = *d.End
+	}
+	if d.TimeZone != nil && *d.TimeZone != "" {
+		m["time_zone"] = *d.TimeZone
+	}
+	return m
+}
+
+func encodeFile(f FileReference) map[string]interface{} {
+	m := map[string]interface{}{"name": f.Name}
+	switch {
+	case f.FileUpload != nil:
+		m["type"] = "file_upload"
+		m["file_upload"] = map[string]interface{}{"id": f.FileUpload.ID}
+	case f.File != nil:
+		m["type"] = "file"
+		m["file"] = map[string]interface{}{"url": f.File.URL}
+	case f.External != nil:
+		m["type"] = "external"
+		m["external"] = map[string]interface{}{"url": f.External.URL}
+	}
+	return m
+}
+
+// EncodeRichText converts rich text to the request shape, dropping the
+// read-only plain_text and href fields and default annotations.
+func EncodeRichText(rt []RichText) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(rt))
+	for _, r := range rt {
+		m := map[string]interface{}{}
+		switch {
+		case r.Mention != nil:
+			m["mention"] = encodeMention(r.Mention)
+		case r.Equation != nil:
+			m["equation"] = map[string]interface{}{"expression": r.Equation.Expression}
+		default:
+			text := map[string]interface{}{}
+			if r.Text != nil {
+				text["content"] = r.Text.Content
+				if r.Text.Link != nil && r.Text.Link.URL != "" {
+					text["link"] = map[string]interface{}{"url": r.Text.Link.URL}
+				}
+			} else {
+				text["content"] = r.PlainText
+			}
+			m["text"] = text
+		}
+		if a := r.Annotations; a != nil && (*a != Annotations{} && *a != Annotations{Color: "default"}) {
+			m["annotations"] = map[string]interface{}{
+				"bold":          a.Bold,
+				"italic":        a.Italic,
+				"strikethrough": a.Strikethrough,
+				"underline":     a.Underline,
+				"code":          a.Code,
+				"color":         a.Color,
+			}
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func encodeMention(mention *Mention) map[string]interface{} {
+	m := map[string]interface{}{}
+	switch {
+	case mention.User != nil:
+		m["user"] = map[string]interface{}{"id": mention.User.ID}
+	case mention.Page != nil:
+		m["page"] = map[string]interface{}{"id": mention.Page.ID}
+	case mention.Database != nil:
+		m["database"] = map[string]interface{}{"id": mention.Database.ID}
+	case mention.Date != nil:
+		m["date"] = encodeDate(mention.Date)
+	case mention.LinkPreview != nil:
+		m["link_preview"] = map[string]interface{}{"url": mention.LinkPreview.URL}
+	}
+	if mention.Type != "" {
+		m["type"] = mention.Type
+	}
+	return m
+}
+
+// EncodeProperties encodes a set of typed values into a request properties
+// map, skipping read-only values.
+func EncodeProperties(values map[string]*PropertyValue) (map[string]interface{}, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make(map[string]interface{}, len(values))
+	for _, name := range names {
+		pv := values[name]
+		if pv == nil || IsReadOnlyPropertyType(pv.Type) {
+			continue
+		}
+		payload, err := pv.Encode()
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", name, err)
+		}
+		out[name] = payload
+	}
+	return out, nil
+}
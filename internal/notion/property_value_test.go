@@ -0,0 +1,152 @@
+package notion
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodePropertyValue_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"title", `{"id":"title","type":"title","title":[{"type":"text","text":{"content":"Hi"},"plain_text":"Hi"}]}`},
+		{"empty rich_text", `{"id":"a","type":"rich_text","rich_text":[]}`},
+		{"number", `{"id":"b","type":"number","number":3.5}`},
+		{"null number", `{"id":"b","type":"number","number":null}`},
+		{"select", `{"id":"c","type":"select","select":{"id":"o1","name":"High","color":"red"}}`},
+		{"null select", `{"id":"c","type":"select","select":null}`},
+		{"multi_select", `{"id":"d","type":"multi_select","multi_select":[{"name":"a"},{"name":"b"}]}`},
+		{"status", `{"id":"e","type":"status","status":{"id":"s1","name":"Done","color":"green"}}`},
+		{"date range", `{"id":"f","type":"date","date":{"start":"2024-01-01","end":"2024-01-05"}}`},
+		{"people", `{"id":"g","type":"people","people":[{"object":"user","id":"u1","name":"Ada"}]}`},
+		{"files", `{"id":"h","type":"files","files":[{"name":"a.pdf","type":"external","external":{"url":"https://example.invalid/a.pdf"}}]}`},
+		{"checkbox false", `{"id":"i","type":"checkbox","checkbox":false}`},
+		{"null url", `{"id":"j","type":"url","url":null}`},
+		{"relation", `{"id":"k","type":"relation","relation":[{"id":"p1"}],"has_more":true}`},
+		{"formula", `{"id":"l","type":"formula","formula":{"type":"number","number":2}}`},
+		{"rollup", `{"id":"m","type":"rollup","rollup":{"type":"number","function":"sum","array":null,"number":4}}`},
+		{"created_by", `{"id":"n","type":"created_by","created_by":{"object":"user","id":"u1"}}`},
+		{"unique_id", `{"id":"o","type":"unique_id","unique_id":{"prefix":"TASK","number":42}}`},
+		{"verification", `{"id":"p","type":"verification","verification":{"state":"verified","verified_by":{"object":"user","id":"u1"},"date":{"start":"2024-01-01"}}}`},
+		{"button", `{"id":"q","type":"button","button":{}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.raw), &raw); err != nil {
+				t.Fatalf("bad fixture: %v", err)
+			}
+			pv, err := DecodePropertyValue(raw)
+			if err != nil {
+				t.Fatalf("DecodePropertyValue: %v", err)
+			}
+			out, err := json.Marshal(pv)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			assertJSONEqual(t, out, []byte(tt.raw))
+		})
+	}
+}
+
+func TestDecodePropertyValue_MissingType(t *testing.T) {
+	if _, err := DecodePropertyValue(map[string]interface{}{"id": "x"}); err == nil {
+		t.Fatal("expected error for value without type")
+	}
+}
+
+func TestPage_PropertyValues(t *testing.T) {
+	page := &Page{Properties: map[string]interface{}{
+		"Name": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Task"}}},
+		"ID":   map[string]interface{}{"type": "unique_id", "unique_id": map[string]interface{}{"prefix": "TASK", "number": 7.0}},
+	}}
+
+	values, err := page.PropertyValues()
+	if err != nil {
+		t.Fatalf("PropertyValues: %v", err)
+	}
+	if got := PlainText(values["Name"].Title); got != "Task" {
+		t.Errorf("title = %q, want Task", got)
+	}
+	if got := values["ID"].UniqueID.String(); got != "TASK-7" {
+		t.Errorf("unique id = %q, want TASK-7", got)
+	}
+}
+
+func TestPropertyValue_Encode(t *testing.T) {
+	num := 1.5
+	done := true
+	tests := []struct {
+		name string
+		pv   PropertyValue
+		want string
+	}{
+		{
+			name: "title drops read-only fields",
+			pv:   PropertyValue{Type: "title", Title: []RichText{{Type: "text", Text: &TextContent{Content: "Hi"}, PlainText: "Hi", Annotations: &Annotations{Color: "default"}}}},
+			want: `{"title":[{"text":{"content":"Hi"}}]}`,
+		},
+		{
+			name: "rich_text keeps annotations and mentions",
+			pv: PropertyValue{Type: "rich_text", RichText: []RichText{
+				{Type: "text", Text: &TextContent{Content: "bold"}, Annotations: &Annotations{Bold: true, Color: "default"}},
+				{Type: "mention", Mention: &Mention{Type: "user", User: &UserMention{ID: "u1"}}},
+			}},
+			want: `{"rich_text":[{"annotations":{"bold":true,"code":false,"color":"default","italic":false,"strikethrough":false,"underline":false},"text":{"content":"bold"}},{"mention":{"type":"user","user":{"id":"u1"}}}]}`,
+		},
+		{name: "number", pv: PropertyValue{Type: "number", Number: &num}, want: `{"number":1.5}`},
+		{name: "cleared number", pv: PropertyValue{Type: "number"}, want: `{"number":null}`},
+		{name: "select", pv: PropertyValue{Type: "select", Select: &SelectOption{ID: "o1", Name: "High", Color: "red"}}, want: `{"select":{"name":"High"}}`},
+		{name: "cleared select", pv: PropertyValue{Type: "select"}, want: `{"select":null}`},
+		{name: "checkbox", pv: PropertyValue{Type: "checkbox", Checkbox: &done}, want: `{"checkbox":true}`},
+		{name: "people", pv: PropertyValue{Type: "people", People: []User{{ID: "u1", Name: "Ada"}}}, want: `{"people":[{"id":"u1","object":"user"}]}`},
+		{
+			name: "files",
+			pv: PropertyValue{Type: "files", Files: []FileReference{
+				{Name: "a.pdf", Type: "file", File: &FileLocation{URL: "https://example.invalid/a.pdf", ExpiryTime: "2026-01-01T00:00:00Z"}},
+				{Name: "b.png", Type: "file_upload", FileUpload: &FileUploadRef{ID: "up1"}},
+			}},
+			want: `{"files":[{"file":{"url":"https://example.invalid/a.pdf"},"name":"a.pdf","type":"file"},{"file_upload":{"id":"up1"},"name":"b.png","type":"file_upload"}]}`,
+		},
+		{name: "empty relation", pv: PropertyValue{Type: "relation"}, want: `{"relation":[]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := tt.pv.Encode()
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			got, _ := json.Marshal(payload)
+			assertJSONEqual(t, got, []byte(tt.want))
+		})
+	}
+}
+
+func TestPropertyValue_EncodeReadOnly(t *testing.T) {
+	for _, typ := range []string{"formula", "rollup", "unique_id", "verification", "created_time"} {
+		pv := PropertyValue{Type: typ}
+		if _, err := pv.Encode(); err == nil {
+			t.Errorf("Encode(%s) expected read-only error", typ)
+		}
+	}
+}
+
+func TestEncodeProperties_SkipsReadOnly(t *testing.T) {
+	num := 2.0
+	got, err := EncodeProperties(map[string]*PropertyValue{
+		"Score":   {Type: "number", Number: &num},
+		"Formula": {Type: "formula", Formula: &FormulaValue{Type: "number", Number: &num}},
+	})
+	if err != nil {
+		t.Fatalf("EncodeProperties: %v", err)
+	}
+	if _, ok := got["Formula"]; ok {
+		t.Error("read-only property should be skipped")
+	}
+	if _, ok := got["Score"]; !ok {
+		t.Error("Score missing from payload")
+	}
+}
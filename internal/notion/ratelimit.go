@@ -1,6 +1,7 @@
 package notion
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"sync"
@@ -25,6 +26,11 @@ type RateLimitInfo struct {
 type RateLimitTracker struct {
 	mu   sync.RWMutex
 	info *RateLimitInfo
+	// pausedUntil is set from Retry-After on 429 responses so concurrent
+	// callers sharing the client back off together.
+	pausedUntil time.Time
+	// observer, when set, is told about every header update.
+	observer RateLimitObserver
 }
 
 // NewRateLimitTracker creates a new rate limit tracker
@@ -44,7 +50,6 @@ func (t *RateLimitTracker) Update(resp *http.Response) {
 	}
 
 	t.mu.Lock()
-	defer t.mu.Unlock()
 
 	info := &RateLimitInfo{
 		RequestID: resp.Header.Get("X-Request-Id"),
@@ -66,6 +71,20 @@ func (t *RateLimitTracker) Update(resp *http.Response) {
 	}
 
 	t.info = info
+	observer := t.observer
+	t.mu.Unlock()
+
+	if observer != nil {
+		observer.ObserveRateLimit(*info)
+	}
+}
+
+// SetObserver registers a RateLimitObserver (typically the client's Limiter)
+// that is fed every X-RateLimit-* update. Pass nil to remove it.
+func (t *RateLimitTracker) SetObserver(observer RateLimitObserver) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.observer = observer
 }
 
 // Get returns the current rate limit info (may be nil if no requests made)
@@ -89,3 +108,47 @@ func (t *RateLimitTracker) IsLow() bool {
 	}
 	return float64(t.info.Remaining)/float64(t.info.Limit) < 0.1
 }
+
+// Pause asks callers of Wait to hold off for the given duration.
+// A shorter pause never shortens one that is already in effect.
+func (t *RateLimitTracker) Pause(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	until := time.Now().Add(d)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// PausedUntil returns the time until which requests should be held back,
+// or the zero time if no pause is in effect.
+func (t *RateLimitTracker) PausedUntil() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if time.Now().After(t.pausedUntil) {
+		return time.Time{}
+	}
+	return t.pausedUntil
+}
+
+// Wait blocks until any pause requested via Pause has elapsed or ctx is done.
+func (t *RateLimitTracker) Wait(ctx context.Context) error {
+	for {
+		until := t.PausedUntil()
+		if until.IsZero() {
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(time.Until(until))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package notion
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"sync"
 	"testing"
@@ -305,6 +307,44 @@ func TestRateLimitTracker_MultipleUpdates(t *testing.T) {
 	}
 }
 
+func TestRateLimitTracker_PauseAndWait(t *testing.T) {
+	tracker := NewRateLimitTracker()
+
+	if !tracker.PausedUntil().IsZero() {
+		t.Fatal("new tracker should not be paused")
+	}
+	if err := tracker.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() without pause: %v", err)
+	}
+
+	tracker.Pause(50 * time.Millisecond)
+	// A shorter pause must not shorten the existing one.
+	tracker.Pause(time.Millisecond)
+
+	start := time.Now()
+	if err := tracker.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait(): %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Wait() returned after %v, want >= ~50ms", elapsed)
+	}
+	if !tracker.PausedUntil().IsZero() {
+		t.Error("pause should have elapsed")
+	}
+}
+
+func TestRateLimitTracker_WaitCancelled(t *testing.T) {
+	tracker := NewRateLimitTracker()
+	tracker.Pause(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := tracker.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v, want context.DeadlineExceeded", err)
+	}
+}
+
 // formatInt converts an int to a string for header values
 func formatInt(n int) string {
 	if n == 0 {
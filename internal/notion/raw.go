@@ -46,6 +46,10 @@ func (c *Client) DoRawRequest(ctx context.Context, method, path string, body []b
 			}
 		}
 
+		if err := c.waitForLimiter(ctx); err != nil {
+			return nil, ctxerrors.WrapContext(method, url, 0, err)
+		}
+
 		resp, err := c.doRawRequestOnce(ctx, method, url, body, headers)
 		if err != nil {
 			lastErr = err
@@ -58,6 +62,11 @@ func (c *Client) DoRawRequest(ctx context.Context, method, path string, body []b
 		}
 
 		c.circuitBreaker.recordSuccess()
+		if c.cache != nil && strings.HasPrefix(url, c.baseURL) {
+			// Raw requests are never served from the cache, but writes made
+			// through them must still invalidate what they touched.
+			c.cache.Record(method, strings.TrimPrefix(url, c.baseURL), resp.Body)
+		}
 		return resp, nil
 	}
 
@@ -117,6 +126,7 @@ func (c *Client) doRawRequestOnce(ctx context.Context, method, url string, body
 	}
 
 	if resp.StatusCode >= 400 {
+		retryAfter := c.recordRetryAfter(resp)
 		var errResp ErrorResponse
 		if err := json.Unmarshal(data, &errResp); err != nil {
 			return nil, &APIError{StatusCode: resp.StatusCode}
@@ -124,7 +134,7 @@ func (c *Client) doRawRequestOnce(ctx context.Context, method, url string, body
 		return nil, &APIError{
 			StatusCode: resp.StatusCode,
 			Response:   &errResp,
-			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			RetryAfter: retryAfter,
 		}
 	}
 
@@ -0,0 +1,233 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SharedTokenBucket is a Limiter whose state lives in a file, so every
+// process pointing at the same path draws from one request budget.
+// Access to the file is serialised with an advisory file lock.
+type SharedTokenBucket struct {
+	path  string
+	rate  float64
+	burst float64
+	now   func() time.Time
+}
+
+// sharedBucketState is the on-disk representation of a SharedTokenBucket.
+type sharedBucketState struct {
+	Tokens float64   `json:"tokens"`
+	Last   time.Time `json:"last"`
+	// Reservations holds the times at which queued callers may proceed.
+	// Entries in the past are pruned on every access, so a crashed process
+	// never leaves a stale queue behind.
+	Reservations []time.Time `json:"reservations,omitempty"`
+}
+
+// SharedBudgetStatus describes the current state of a shared request budget.
+type SharedBudgetStatus struct {
+	Path       string    `json:"path"`
+	Rate       float64   `json:"rate"`
+	Burst      int       `json:"burst"`
+	Available  float64   `json:"available"`
+	QueueDepth int       `json:"queue_depth"`
+	NextSlotAt time.Time `json:"next_slot_at,omitempty"`
+}
+
+// NewSharedTokenBucket creates a token bucket backed by the state file at path.
+// A rate <= 0 disables pacing; a burst < 1 is treated as 1.
+func NewSharedTokenBucket(path string, rate float64, burst int) *SharedTokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &SharedTokenBucket{
+		path:  path,
+		rate:  rate,
+		burst: float64(burst),
+		now:   time.Now,
+	}
+}
+
+// Path returns the state file backing the bucket.
+func (b *SharedTokenBucket) Path() string {
+	return b.path
+}
+
+// Wait blocks until the shared budget admits another request or ctx is done.
+// If the state file cannot be used, Wait does not pace the request.
+func (b *SharedTokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return ctx.Err()
+	}
+
+	var readyAt time.Time
+	err := b.update(func(state *sharedBucketState, now time.Time) {
+		state.Tokens--
+		readyAt = state.Last
+		if state.Tokens < 0 {
+			readyAt = readyAt.Add(time.Duration(-state.Tokens / b.rate * float64(time.Second)))
+		}
+		if readyAt.After(now) {
+			state.Reservations = append(state.Reservations, readyAt)
+		}
+	})
+	if err != nil {
+		// An unusable state file must not block API access; fall back to unpaced.
+		slog.Debug("shared rate limit unavailable", "path", b.path, "error", err)
+		return ctx.Err()
+	}
+
+	delay := readyAt.Sub(b.now())
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.release(readyAt)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ObserveRateLimit holds back the shared budget until the reported reset
+// time when the API says the current window is exhausted.
+func (b *SharedTokenBucket) ObserveRateLimit(info RateLimitInfo) {
+	if info.Limit <= 0 || info.Remaining > 0 || info.ResetAt.IsZero() {
+		return
+	}
+
+	_ = b.update(func(state *sharedBucketState, _ time.Time) {
+		if info.ResetAt.After(state.Last) {
+			state.Last = info.ResetAt
+		}
+		if state.Tokens > 0 {
+			state.Tokens = 0
+		}
+	})
+}
+
+// Status reports the shared budget without consuming from it. The state is
+// read and refilled in memory only: Status neither takes the lock nor
+// writes the file, so polling it does not hold up other processes.
+func (b *SharedTokenBucket) Status() (*SharedBudgetStatus, error) {
+	if _, err := os.Stat(b.path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read rate limit state: %w", err)
+	}
+
+	now := b.now()
+	state := b.load(now)
+	b.refill(state, now)
+
+	status := &SharedBudgetStatus{
+		Path:       b.path,
+		Rate:       b.rate,
+		Burst:      int(b.burst),
+		Available:  max(state.Tokens, 0),
+		QueueDepth: len(state.Reservations),
+	}
+	if state.Tokens < 1 && b.rate > 0 {
+		next := state.Last.Add(time.Duration((1 - state.Tokens) / b.rate * float64(time.Second)))
+		if next.After(now) {
+			status.NextSlotAt = next
+		}
+	}
+	return status, nil
+}
+
+// release returns a reservation that was abandoned before it was used.
+func (b *SharedTokenBucket) release(readyAt time.Time) {
+	_ = b.update(func(state *sharedBucketState, _ time.Time) {
+		state.Tokens++
+		if state.Tokens > b.burst {
+			state.Tokens = b.burst
+		}
+		for i, r := range state.Reservations {
+			if r.Equal(readyAt) {
+				state.Reservations = append(state.Reservations[:i], state.Reservations[i+1:]...)
+				break
+			}
+		}
+	})
+}
+
+// update loads the state under the file lock, refills it, applies fn and
+// writes the result back.
+func (b *SharedTokenBucket) update(fn func(state *sharedBucketState, now time.Time)) error {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create rate limit directory: %w", err)
+	}
+
+	lock, err := os.OpenFile(b.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open rate limit lock: %w", err)
+	}
+	defer func() { _ = lock.Close() }()
+
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock rate limit state: %w", err)
+	}
+	defer func() { _ = unlockFile(lock) }()
+
+	now := b.now()
+	state := b.load(now)
+	b.refill(state, now)
+	fn(state, now)
+	return b.save(state)
+}
+
+// load reads the state file, starting from a full bucket if it is missing or unreadable.
+func (b *SharedTokenBucket) load(now time.Time) *sharedBucketState {
+	state := &sharedBucketState{Tokens: b.burst, Last: now}
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return state
+	}
+	var loaded sharedBucketState
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Last.IsZero() {
+		return state
+	}
+	return &loaded
+}
+
+func (b *SharedTokenBucket) save(state *sharedBucketState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode rate limit state: %w", err)
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write rate limit state: %w", err)
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		return fmt.Errorf("failed to write rate limit state: %w", err)
+	}
+	return nil
+}
+
+func (b *SharedTokenBucket) refill(state *sharedBucketState, now time.Time) {
+	if now.After(state.Last) {
+		state.Tokens += now.Sub(state.Last).Seconds() * b.rate
+		if state.Tokens > b.burst {
+			state.Tokens = b.burst
+		}
+		state.Last = now
+	}
+
+	pending := state.Reservations[:0]
+	for _, r := range state.Reservations {
+		if r.After(now) {
+			pending = append(pending, r)
+		}
+	}
+	state.Reservations = pending
+}
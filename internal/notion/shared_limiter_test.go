@@ -0,0 +1,171 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestSharedBucket(t *testing.T, path string, rate float64, burst int, clock *fakeClock) *SharedTokenBucket {
+	t.Helper()
+	b := NewSharedTokenBucket(path, rate, burst)
+	b.now = clock.now
+	return b
+}
+
+func TestSharedTokenBucket_SharesBudgetAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit", "default.json")
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+
+	// Two instances stand in for two separate ntn processes.
+	a := newTestSharedBucket(t, path, 1, 2, clock)
+	b := newTestSharedBucket(t, path, 1, 2, clock)
+
+	ctx := context.Background()
+	if err := a.Wait(ctx); err != nil {
+		t.Fatalf("a.Wait(): %v", err)
+	}
+	if err := b.Wait(ctx); err != nil {
+		t.Fatalf("b.Wait(): %v", err)
+	}
+
+	status, err := a.Status()
+	if err != nil {
+		t.Fatalf("Status(): %v", err)
+	}
+	if status.Available != 0 {
+		t.Errorf("Available = %v, want 0 after both instances spent the burst", status.Available)
+	}
+	if want := clock.t.Add(time.Second); !status.NextSlotAt.Equal(want) {
+		t.Errorf("NextSlotAt = %v, want %v", status.NextSlotAt, want)
+	}
+
+	// A third request through either instance must now queue.
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("b.Wait() = %v, want context.DeadlineExceeded while budget is empty", err)
+	}
+
+	// The cancelled reservation is returned and does not linger in the queue.
+	status, err = a.Status()
+	if err != nil {
+		t.Fatalf("Status(): %v", err)
+	}
+	if status.QueueDepth != 0 {
+		t.Errorf("QueueDepth = %d, want 0 after cancellation", status.QueueDepth)
+	}
+}
+
+func TestSharedTokenBucket_QueueDepth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "default.json")
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestSharedBucket(t, path, 1, 1, clock)
+
+	var readyTimes []time.Time
+	for i := 0; i < 3; i++ {
+		if err := b.update(func(state *sharedBucketState, now time.Time) {
+			state.Tokens--
+			readyAt := state.Last
+			if state.Tokens < 0 {
+				readyAt = readyAt.Add(time.Duration(-state.Tokens * float64(time.Second)))
+			}
+			if readyAt.After(now) {
+				state.Reservations = append(state.Reservations, readyAt)
+			}
+			readyTimes = append(readyTimes, readyAt)
+		}); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+
+	status, err := b.Status()
+	if err != nil {
+		t.Fatalf("Status(): %v", err)
+	}
+	if status.QueueDepth != 2 {
+		t.Fatalf("QueueDepth = %d, want 2", status.QueueDepth)
+	}
+
+	// Reservations in the past are pruned, even if their owner never returned.
+	clock.t = readyTimes[2].Add(time.Millisecond)
+	status, err = b.Status()
+	if err != nil {
+		t.Fatalf("Status(): %v", err)
+	}
+	if status.QueueDepth != 0 {
+		t.Errorf("QueueDepth = %d, want 0 once reservations elapsed", status.QueueDepth)
+	}
+}
+
+func TestSharedTokenBucket_StatusDoesNotWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "default.json")
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestSharedBucket(t, path, 1, 3, clock)
+
+	if _, err := b.Status(); err != nil {
+		t.Fatalf("Status(): %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Status() created the state file: %v", err)
+	}
+
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait(): %v", err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.t = clock.t.Add(time.Second)
+	status, err := b.Status()
+	if err != nil {
+		t.Fatalf("Status(): %v", err)
+	}
+	if status.Available != 3 {
+		t.Errorf("Available = %v, want the refilled 3", status.Available)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Errorf("Status() rewrote the state file:\n%s\nwas:\n%s", after, before)
+	}
+}
+
+func TestSharedTokenBucket_ObserveExhaustedWindow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "default.json")
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestSharedBucket(t, path, 1, 5, clock)
+
+	b.ObserveRateLimit(RateLimitInfo{Limit: 100, Remaining: 0, ResetAt: clock.t.Add(10 * time.Second)})
+
+	status, err := b.Status()
+	if err != nil {
+		t.Fatalf("Status(): %v", err)
+	}
+	if status.Available != 0 {
+		t.Errorf("Available = %v, want 0", status.Available)
+	}
+	if want := clock.t.Add(11 * time.Second); !status.NextSlotAt.Equal(want) {
+		t.Errorf("NextSlotAt = %v, want %v", status.NextSlotAt, want)
+	}
+}
+
+func TestSharedTokenBucket_UnusableStateDoesNotBlock(t *testing.T) {
+	// A state path beneath a regular file can never be created.
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewSharedTokenBucket(filepath.Join(blocker, "default.json"), 1, 1)
+	for i := 0; i < 3; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() = %v, want nil when state is unusable", err)
+		}
+	}
+	if _, err := b.Status(); err == nil {
+		t.Error("Status() should report an unusable state file")
+	}
+}
@@ -51,6 +51,7 @@ type (
 	resultsOnlyKey struct{}
 	lightKey       struct{}
 	compactJSONKey struct{}
+	templateKey    struct{}
 )
 
 // WithYes sets the --yes flag in context.
@@ -187,3 +188,16 @@ func CompactJSONFromContext(ctx context.Context) bool {
 	}
 	return false
 }
+
+// WithTemplate sets the Go template used by -o template.
+func WithTemplate(ctx context.Context, text string) context.Context {
+	return context.WithValue(ctx, templateKey{}, text)
+}
+
+// TemplateFromContext retrieves the -o template text from context.
+func TemplateFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(templateKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
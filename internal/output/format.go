@@ -29,6 +29,14 @@ const (
 	FormatTable Format = "table"
 	// FormatYAML is YAML format.
 	FormatYAML Format = "yaml"
+	// FormatCSV is comma-separated values with flattened property columns.
+	FormatCSV Format = "csv"
+	// FormatTSV is tab-separated values with flattened property columns.
+	FormatTSV Format = "tsv"
+	// FormatTemplate renders a Go template given with --template.
+	FormatTemplate Format = "template"
+	// FormatMarkdown is a GitHub-flavoured Markdown table.
+	FormatMarkdown Format = "markdown"
 )
 
 // ParseFormat converts a string to a Format type.
@@ -46,8 +54,16 @@ func ParseFormat(s string) (Format, error) {
 		return FormatTable, nil
 	case FormatYAML:
 		return FormatYAML, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatTSV:
+		return FormatTSV, nil
+	case FormatTemplate:
+		return FormatTemplate, nil
+	case FormatMarkdown, "md":
+		return FormatMarkdown, nil
 	default:
-		return "", errors.New("invalid --output format (expected text|json|ndjson|jsonl|table|yaml)")
+		return "", errors.New("invalid --output format (expected text|json|ndjson|jsonl|table|yaml|csv|tsv|markdown|template)")
 	}
 }
 
@@ -97,7 +113,16 @@ func (p *Printer) Print(ctx context.Context, data interface{}) error {
 	case FormatYAML:
 		return p.printYAML(data)
 	case FormatTable:
-		return p.printTable(data)
+		if term, ok := TerminalFromContext(ctx); ok {
+			return p.printTerminalTable(ctx, data, term)
+		}
+		return p.printTable(ctx, data)
+	case FormatMarkdown:
+		return p.printMarkdown(ctx, data)
+	case FormatCSV, FormatTSV:
+		return p.printDelimited(ctx, data)
+	case FormatTemplate:
+		return p.printTemplate(ctx, data)
 	case FormatText:
 		return p.printText(ctx, data)
 	default:
@@ -897,7 +922,7 @@ func (p *Printer) formatSlice(v reflect.Value) string {
 
 // printTable outputs data in tabular format using text/tabwriter.
 // Only works with slices of maps or structs.
-func (p *Printer) printTable(data interface{}) error {
+func (p *Printer) printTable(ctx context.Context, data interface{}) error {
 	switch v := data.(type) {
 	case Table:
 		return p.printTableFromTable(v)
@@ -932,7 +957,7 @@ func (p *Printer) printTable(data interface{}) error {
 
 	// Get first element to determine columns
 	first := v.Index(0)
-	for first.Kind() == reflect.Ptr {
+	for first.Kind() == reflect.Ptr || first.Kind() == reflect.Interface {
 		if first.IsNil() {
 			return errors.New("table format cannot handle nil elements")
 		}
@@ -941,7 +966,7 @@ func (p *Printer) printTable(data interface{}) error {
 
 	switch first.Kind() {
 	case reflect.Map:
-		return p.printTableFromMaps(v)
+		return p.printTableFromMaps(ctx, v)
 	case reflect.Struct:
 		return p.printTableFromStructs(v)
 	default:
@@ -980,8 +1005,9 @@ func (p *Printer) printTableFromTable(t Table) error {
 	return tw.Flush()
 }
 
-// printTableFromMaps outputs a table from a slice of maps.
-func (p *Printer) printTableFromMaps(v reflect.Value) error {
+// printTableFromMaps outputs a table from a slice of maps. The columns are
+// the --fields keys in order, else every key sorted.
+func (p *Printer) printTableFromMaps(ctx context.Context, v reflect.Value) error {
 	if v.Len() == 0 {
 		return nil
 	}
@@ -990,13 +1016,13 @@ func (p *Printer) printTableFromMaps(v reflect.Value) error {
 	keysMap := make(map[string]bool)
 	for i := 0; i < v.Len(); i++ {
 		m := v.Index(i)
-		for m.Kind() == reflect.Ptr {
+		for m.Kind() == reflect.Ptr || m.Kind() == reflect.Interface {
 			if m.IsNil() {
 				break
 			}
 			m = m.Elem()
 		}
-		if m.Kind() == reflect.Ptr {
+		if m.Kind() != reflect.Map {
 			continue
 		}
 		iter := m.MapRange()
@@ -1012,6 +1038,12 @@ func (p *Printer) printTableFromMaps(v reflect.Value) error {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
+	if specs, err := parseFieldSpecs(FieldsFromContext(ctx)); err == nil {
+		keys = keys[:0]
+		for _, spec := range specs {
+			keys = append(keys, spec.Key)
+		}
+	}
 
 	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
 	defer func() { _ = tw.Flush() }()
@@ -1028,13 +1060,13 @@ func (p *Printer) printTableFromMaps(v reflect.Value) error {
 	// Print rows
 	for i := 0; i < v.Len(); i++ {
 		m := v.Index(i)
-		for m.Kind() == reflect.Ptr {
+		for m.Kind() == reflect.Ptr || m.Kind() == reflect.Interface {
 			if m.IsNil() {
 				break
 			}
 			m = m.Elem()
 		}
-		if m.Kind() == reflect.Ptr {
+		if m.Kind() != reflect.Map {
 			continue
 		}
 
@@ -0,0 +1,330 @@
+package output
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// csvListSeparator joins multi-valued cells (multi_select, people, relation,
+// files). It matches the separator the CSV importer splits on, so exported
+// files can be imported again.
+const csvListSeparator = "; "
+
+// leadingColumns and trailingColumns frame page property columns in CSV
+// output when no --fields are given. Trailing columns appear only when some
+// row has them.
+var (
+	leadingColumns  = []string{"id"}
+	trailingColumns = []string{"url", "created_time", "last_edited_time"}
+)
+
+// printDelimited writes data as CSV or TSV with a header row. List envelopes
+// are reduced to their results, and each result becomes one row:
+//
+//   - with --fields, columns are the field keys in the order given;
+//   - for pages, columns are id, then one per property (title first), then
+//     url and timestamps;
+//   - otherwise columns are the union of top-level keys, id and object first.
+//
+// Cells are flattened to text: rich text to plain text, select and status to
+// the option name, people to names, dates to "start → end", formula and
+// rollup to their result, and lists joined with "; ".
+func (p *Printer) printDelimited(ctx context.Context, data interface{}) error {
+	columns, rows, err := flattenRows(ctx, data)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+
+	w := newDelimitedWriter(p.w, p.format == FormatTSV)
+	if err := w.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			record[i] = cellText(row[col])
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// flattenRows applies --query and returns the columns and rows shared by the
+// row formats (csv, tsv, markdown and the terminal table). Row values are
+// left as decoded JSON so renderers can inspect them, e.g. for option colours.
+func flattenRows(ctx context.Context, data interface{}) ([]string, []map[string]interface{}, error) {
+	if query := QueryFromContext(ctx); query != "" {
+		results, err := runQueryRaw(query, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = results
+		if len(results) == 1 {
+			data = results[0]
+		}
+	}
+
+	normalized, err := normalizeToInterface(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []map[string]interface{}
+	switch v := normalized.(type) {
+	case []interface{}:
+		for _, item := range v {
+			rows = append(rows, csvRow(item))
+		}
+	case nil:
+	default:
+		rows = append(rows, csvRow(v))
+	}
+
+	if fields := strings.TrimSpace(FieldsFromContext(ctx)); fields != "" {
+		specs, err := parseFieldSpecs(fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		columns := make([]string, 0, len(specs))
+		for _, spec := range specs {
+			columns = append(columns, spec.Key)
+		}
+		return columns, rows, nil
+	}
+	return csvColumns(normalized, rows), rows, nil
+}
+
+// csvRow turns one result into a column map. Page properties are lifted to
+// top-level columns named after the property.
+func csvRow(item interface{}) map[string]interface{} {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{"value": item}
+	}
+	props, ok := m["properties"].(map[string]interface{})
+	if !ok || m["object"] != "page" {
+		return m
+	}
+
+	row := make(map[string]interface{}, len(m)+len(props))
+	for k, v := range m {
+		if k != "properties" {
+			row[k] = v
+		}
+	}
+	for name, v := range props {
+		row[name] = v
+	}
+	return row
+}
+
+// csvColumns picks the default column order for rows.
+func csvColumns(normalized interface{}, rows []map[string]interface{}) []string {
+	items, _ := normalized.([]interface{})
+	if items == nil && normalized != nil {
+		items = []interface{}{normalized}
+	}
+
+	pages := len(items) > 0
+	var titles, props []string
+	seenProp := map[string]bool{}
+	for _, item := range items {
+		m, _ := item.(map[string]interface{})
+		p, ok := m["properties"].(map[string]interface{})
+		if !ok || m["object"] != "page" {
+			pages = false
+			break
+		}
+		for name, v := range p {
+			if seenProp[name] {
+				continue
+			}
+			seenProp[name] = true
+			if pv, _ := v.(map[string]interface{}); pv["type"] == "title" {
+				titles = append(titles, name)
+			} else {
+				props = append(props, name)
+			}
+		}
+	}
+
+	if pages {
+		sort.Strings(titles)
+		sort.Strings(props)
+		var trailing []string
+		for _, col := range trailingColumns {
+			if slices.ContainsFunc(rows, func(row map[string]interface{}) bool { _, ok := row[col]; return ok }) {
+				trailing = append(trailing, col)
+			}
+		}
+		var columns []string
+		for _, group := range [][]string{leadingColumns, titles, props, trailing} {
+			for _, col := range group {
+				// A property may share its name with a page field such as
+				// "url"; the property wins and appears once.
+				if !slices.Contains(columns, col) {
+					columns = append(columns, col)
+				}
+			}
+		}
+		return columns
+	}
+
+	present := map[string]bool{}
+	var keys []string
+	for _, row := range rows {
+		for k := range row {
+			if !present[k] {
+				present[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	var columns []string
+	for _, first := range []string{"id", "object"} {
+		if present[first] {
+			columns = append(columns, first)
+		}
+	}
+	for _, k := range keys {
+		if k != "id" && k != "object" {
+			columns = append(columns, k)
+		}
+	}
+	return columns
+}
+
+// cellText flattens a JSON value, usually a Notion property value, to text.
+func cellText(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		if text, ok := richTextPlain(val); ok {
+			return text
+		}
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			if s := cellText(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, csvListSeparator)
+	case map[string]interface{}:
+		return mapCellText(val)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
+
+func mapCellText(m map[string]interface{}) string {
+	if pt, ok := m["plain_text"].(string); ok {
+		return pt
+	}
+	if m["object"] == "user" {
+		if name, ok := m["name"].(string); ok && name != "" {
+			return name
+		}
+		return cellText(m["id"])
+	}
+	// Property values, formula/rollup results and files carry their payload
+	// under the key named by "type".
+	if t, ok := m["type"].(string); ok {
+		if inner, ok := m[t]; ok {
+			return cellText(inner)
+		}
+	}
+	if start, ok := m["start"].(string); ok {
+		if end, ok := m["end"].(string); ok && end != "" {
+			return start + " → " + end
+		}
+		return start
+	}
+	if number, ok := m["number"].(float64); ok {
+		prefix, _ := m["prefix"].(string)
+		if prefix != "" {
+			return prefix + "-" + strconv.FormatFloat(number, 'f', -1, 64)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	for _, key := range []string{"name", "email", "url", "id"} {
+		if s, ok := m[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	data, _ := json.Marshal(m)
+	return string(data)
+}
+
+// richTextPlain concatenates plain_text when items is a rich text array.
+func richTextPlain(items []interface{}) (string, bool) {
+	if len(items) == 0 {
+		return "", false
+	}
+	var b strings.Builder
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		pt, ok := m["plain_text"].(string)
+		if !ok {
+			return "", false
+		}
+		b.WriteString(pt)
+	}
+	return b.String(), true
+}
+
+// delimitedWriter writes CSV via encoding/csv, or TSV with tabs and line
+// breaks inside cells replaced by spaces, since TSV has no quoting.
+type delimitedWriter struct {
+	csv *csv.Writer
+	tsv io.Writer
+}
+
+func newDelimitedWriter(w io.Writer, tsv bool) *delimitedWriter {
+	if tsv {
+		return &delimitedWriter{tsv: w}
+	}
+	return &delimitedWriter{csv: csv.NewWriter(w)}
+}
+
+var tsvCellReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+func (d *delimitedWriter) Write(record []string) error {
+	if d.csv != nil {
+		return d.csv.Write(record)
+	}
+	cells := make([]string, len(record))
+	for i, cell := range record {
+		cells[i] = tsvCellReplacer.Replace(cell)
+	}
+	_, err := io.WriteString(d.tsv, strings.Join(cells, "\t")+"\n")
+	return err
+}
+
+func (d *delimitedWriter) Flush() error {
+	if d.csv != nil {
+		d.csv.Flush()
+		return d.csv.Error()
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+const csvTestPages = `{"object":"list","has_more":false,"results":[
+ {"object":"page","id":"p1","url":"https://example.invalid/p1","created_time":"2024-01-01T00:00:00.000Z","last_edited_time":"2024-01-02T00:00:00.000Z",
+  "properties":{
+   "Name":{"type":"title","title":[{"plain_text":"Launch, v2"}]},
+   "Status":{"type":"status","status":{"name":"Done"}},
+   "Tags":{"type":"multi_select","multi_select":[{"name":"a"},{"name":"b"}]},
+   "Owner":{"type":"people","people":[{"object":"user","id":"u1","name":"Ada"},{"object":"user","id":"u2"}]},
+   "Due":{"type":"date","date":{"start":"2024-01-15","end":"2024-01-20"}},
+   "Blocks":{"type":"relation","relation":[{"id":"r1"},{"id":"r2"}]},
+   "Score":{"type":"formula","formula":{"type":"number","number":2.5}},
+   "Total":{"type":"rollup","rollup":{"type":"array","array":[{"type":"number","number":1},{"type":"number","number":2}]}},
+   "Key":{"type":"unique_id","unique_id":{"prefix":"TASK","number":7}},
+   "Done":{"type":"checkbox","checkbox":true},
+   "Notes":{"type":"rich_text","rich_text":[]}
+  }}
+]}`
+
+func printDelimitedForTest(t *testing.T, ctx context.Context, format Format, raw string) string {
+	t.Helper()
+	var data interface{}
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	var buf bytes.Buffer
+	ctx = WithFormat(ctx, format)
+	if err := NewPrinter(&buf, format).Print(ctx, data); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	return buf.String()
+}
+
+func TestPrintCSV_FlattensPageProperties(t *testing.T) {
+	got := printDelimitedForTest(t, context.Background(), FormatCSV, csvTestPages)
+	want := "id,Name,Blocks,Done,Due,Key,Notes,Owner,Score,Status,Tags,Total,url,created_time,last_edited_time\n" +
+		"p1,\"Launch, v2\",r1; r2,true,2024-01-15 → 2024-01-20,TASK-7,,Ada; u2,2.5,Done,a; b,1; 2,https://example.invalid/p1,2024-01-01T00:00:00.000Z,2024-01-02T00:00:00.000Z\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrintCSV_FieldsChooseColumns(t *testing.T) {
+	ctx := WithFields(context.Background(), "name=properties.Name,id,status=properties.Status")
+	got := printDelimitedForTest(t, ctx, FormatCSV, csvTestPages)
+	want := "name,id,status\n\"Launch, v2\",p1,Done\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrintTSV_Users(t *testing.T) {
+	raw := `{"object":"list","results":[
+	 {"object":"user","id":"u1","type":"person","name":"Ada\tL","person":{"email":"ada@example.invalid"}},
+	 {"object":"user","id":"u2","type":"bot","name":"Bot","bot":{}}
+	]}`
+	got := printDelimitedForTest(t, context.Background(), FormatTSV, raw)
+	want := "id\tobject\tbot\tname\tperson\ttype\n" +
+		"u1\tuser\t\tAda L\tada@example.invalid\tperson\n" +
+		"u2\tuser\t{}\tBot\t\tbot\n"
+	if got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
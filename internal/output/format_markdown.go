@@ -0,0 +1,46 @@
+package output
+
+import (
+	"context"
+	"strings"
+)
+
+var markdownCellReplacer = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// printMarkdown writes data as a GitHub-flavoured Markdown table, with the
+// same columns and cell text as CSV output.
+func (p *Printer) printMarkdown(ctx context.Context, data interface{}) error {
+	columns, rows, err := flattenRows(ctx, data)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+
+	var b strings.Builder
+	writeMarkdownRow(&b, columns)
+	b.WriteString("|")
+	for range columns {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+
+	cells := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			cells[i] = cellText(row[col])
+		}
+		writeMarkdownRow(&b, cells)
+	}
+
+	_, err = p.w.Write([]byte(b.String()))
+	return err
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, cell := range cells {
+		b.WriteString(" ")
+		b.WriteString(markdownCellReplacer.Replace(cell))
+		b.WriteString(" |")
+	}
+	b.WriteString("\n")
+}
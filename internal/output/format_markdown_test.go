@@ -0,0 +1,24 @@
+package output
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPrintMarkdown_Table(t *testing.T) {
+	raw := `{"object":"list","results":[
+	 {"object":"page","id":"p1","url":"u1","properties":{"Name":{"type":"title","title":[{"plain_text":"a|b"}]},"Notes":{"type":"rich_text","rich_text":[{"plain_text":"line1\nline2"}]}}}
+	]}`
+	ctx := WithFields(context.Background(), "name=properties.Name,notes=properties.Notes")
+	got := printDelimitedForTest(t, ctx, FormatMarkdown, raw)
+	want := "| name | notes |\n| --- | --- |\n| a\\|b | line1<br>line2 |\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseFormat_MarkdownAlias(t *testing.T) {
+	if f, err := ParseFormat("md"); err != nil || f != FormatMarkdown {
+		t.Fatalf("ParseFormat(md) = %q, %v", f, err)
+	}
+}
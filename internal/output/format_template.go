@@ -0,0 +1,154 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"text/template"
+	"time"
+
+	clierrors "github.com/salmonumbrella/notion-cli/internal/errors"
+)
+
+// dateLayouts are the timestamp shapes the API returns, tried in order by the
+// template date helper.
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.000Z07:00", "2006-01-02T15:04", "2006-01-02"}
+
+// printTemplate renders data with the Go template from --template or
+// --template-file. A --query filter runs first, so the template sees its
+// result. Data is passed as plain maps and slices, so fields are addressed by
+// their JSON names: {{range .results}}{{.id}}{{end}}.
+func (p *Printer) printTemplate(ctx context.Context, data interface{}) error {
+	text := TemplateFromContext(ctx)
+	if strings.TrimSpace(text) == "" {
+		return clierrors.NewUserError(
+			"-o template requires a template",
+			"Pass --template '{{range .results}}{{.id}}{{\"\\n\"}}{{end}}' or --template-file",
+		)
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs()).Parse(text)
+	if err != nil {
+		return clierrors.WrapUserError(err, "invalid --template", "Templates use Go text/template syntax, e.g. {{range .results}}{{.id}}{{end}}")
+	}
+
+	if query := QueryFromContext(ctx); query != "" {
+		results, err := runQueryRaw(query, data)
+		if err != nil {
+			return err
+		}
+		data = results
+		if len(results) == 1 {
+			data = results[0]
+		}
+	}
+
+	normalized, err := normalizeToInterface(data)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(p.w, normalized)
+}
+
+// templateFuncs returns the helpers available to -o template:
+//
+//   - title: the title of a page, database or data source
+//   - prop NAME: a page property flattened to text, as in CSV output
+//   - plain: rich text (or any property value) as plain text
+//   - date LAYOUT: a timestamp, date object or date property in a Go layout
+//   - url: the url of an object, or the text of a url/files property
+//   - json: the value as compact JSON
+//   - join SEP: the items of a list flattened to text and joined
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"title": templateTitle,
+		"prop":  templateProp,
+		"plain": cellText,
+		"date":  templateDate,
+		"url":   templateURL,
+		"json":  templateJSON,
+		"join":  templateJoin,
+	}
+}
+
+func templateTitle(v interface{}) string {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return cellText(v)
+	}
+	if props, ok := m["properties"].(map[string]interface{}); ok && m["object"] == "page" {
+		for _, prop := range props {
+			if pv, ok := prop.(map[string]interface{}); ok && pv["type"] == "title" {
+				return cellText(pv)
+			}
+		}
+	}
+	if title, ok := m["title"]; ok {
+		return cellText(title)
+	}
+	if name, ok := m["name"].(string); ok {
+		return name
+	}
+	return ""
+}
+
+func templateProp(name string, v interface{}) string {
+	m, _ := v.(map[string]interface{})
+	props, _ := m["properties"].(map[string]interface{})
+	return cellText(props[name])
+}
+
+func templateDate(layout string, v interface{}) string {
+	var raw string
+	switch val := v.(type) {
+	case string:
+		raw = val
+	case map[string]interface{}:
+		if d, ok := val["date"].(map[string]interface{}); ok && val["type"] == "date" {
+			val = d
+		}
+		raw, _ = val["start"].(string)
+	}
+	if raw == "" {
+		return ""
+	}
+	for _, l := range dateLayouts {
+		if t, err := time.Parse(l, raw); err == nil {
+			return t.Format(layout)
+		}
+	}
+	return raw
+}
+
+func templateURL(v interface{}) string {
+	if m, ok := v.(map[string]interface{}); ok {
+		if u, ok := m["url"].(string); ok {
+			return u
+		}
+		if _, ok := m["type"].(string); ok {
+			return cellText(m)
+		}
+		return ""
+	}
+	return cellText(v)
+}
+
+func templateJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func templateJoin(sep string, v interface{}) string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return cellText(v)
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, cellText(item))
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func renderTemplateForTest(t *testing.T, ctx context.Context, tmpl, raw string) (string, error) {
+	t.Helper()
+	var data interface{}
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	var buf bytes.Buffer
+	ctx = WithTemplate(WithFormat(ctx, FormatTemplate), tmpl)
+	err := NewPrinter(&buf, FormatTemplate).Print(ctx, data)
+	return buf.String(), err
+}
+
+func TestPrintTemplate_NotionHelpers(t *testing.T) {
+	tmpl := `{{range .results}}- {{title .}} [{{. | prop "Status"}}] due {{date "Jan 2" (index .properties "Due")}} {{url .}}{{"\n"}}{{end}}`
+	got, err := renderTemplateForTest(t, context.Background(), tmpl, csvTestPages)
+	if err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	want := "- Launch, v2 [Done] due Jan 15 https://example.invalid/p1\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrintTemplate_Helpers(t *testing.T) {
+	raw := `{"created_time":"2024-03-05T10:20:00.000Z","tags":["a","b"],"rt":[{"plain_text":"x"},{"plain_text":"y"}],"name":"N"}`
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{date "2006-01-02" .created_time}}`, "2024-03-05"},
+		{`{{.created_time | date "15:04"}}`, "10:20"},
+		{`{{join ", " .tags}}`, "a, b"},
+		{`{{plain .rt}}`, "xy"},
+		{`{{json .tags}}`, `["a","b"]`},
+		{`{{title .}}`, "N"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			got, err := renderTemplateForTest(t, context.Background(), tt.tmpl, raw)
+			if err != nil {
+				t.Fatalf("Print: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintTemplate_RunsQueryFirst(t *testing.T) {
+	ctx := WithQuery(context.Background(), ".results[0]")
+	got, err := renderTemplateForTest(t, ctx, `{{.id}}`, csvTestPages)
+	if err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	if got != "p1" {
+		t.Errorf("got %q, want p1", got)
+	}
+}
+
+func TestPrintTemplate_Errors(t *testing.T) {
+	if _, err := renderTemplateForTest(t, context.Background(), "", `{}`); err == nil || !strings.Contains(err.Error(), "requires a template") {
+		t.Errorf("missing template: err = %v", err)
+	}
+	if _, err := renderTemplateForTest(t, context.Background(), "{{range}}", `{}`); err == nil || !strings.Contains(err.Error(), "invalid --template") {
+		t.Errorf("bad template: err = %v", err)
+	}
+}
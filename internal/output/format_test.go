@@ -87,6 +87,16 @@ func TestParseFormat(t *testing.T) {
 			input: "YAML",
 			want:  FormatYAML,
 		},
+		{
+			name:  "csv",
+			input: "csv",
+			want:  FormatCSV,
+		},
+		{
+			name:  "tsv uppercase",
+			input: "TSV",
+			want:  FormatTSV,
+		},
 		{
 			name:    "invalid format",
 			input:   "invalid",
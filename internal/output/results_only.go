@@ -6,10 +6,10 @@ import (
 )
 
 // ApplyResultsOnly extracts the "results" slice from common Notion list envelopes
-// when --results-only is enabled OR when the output format is table (since table
-// format requires a flat slice, not a list envelope).
+// when --results-only is enabled OR when the output format is table, csv, tsv
+// or markdown (since those formats require a flat slice, not a list envelope).
 func ApplyResultsOnly(ctx context.Context, data interface{}) interface{} {
-	if !ResultsOnlyFromContext(ctx) && FormatFromContext(ctx) != FormatTable {
+	if !ResultsOnlyFromContext(ctx) && !isRowFormat(FormatFromContext(ctx)) {
 		return data
 	}
 	results, ok := extractResults(data)
@@ -63,3 +63,8 @@ func extractResults(data interface{}) (interface{}, bool) {
 
 	return nil, false
 }
+
+// isRowFormat reports whether format prints one row per result.
+func isRowFormat(format Format) bool {
+	return format == FormatTable || format == FormatCSV || format == FormatTSV || format == FormatMarkdown
+}
@@ -0,0 +1,147 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/itchyny/gojq"
+
+	clierrors "github.com/salmonumbrella/notion-cli/internal/errors"
+)
+
+// Stream writes list results as NDJSON one item at a time, so paginated
+// commands can emit each page as soon as it arrives instead of buffering
+// the whole list. --query, --fields and --jsonpath are compiled once and
+// applied to every item.
+//
+// Close writes a trailing {"_meta": {...}} record carrying the cursor to
+// resume from, unless --results-only is set.
+type Stream struct {
+	ctx         context.Context
+	enc         *json.Encoder
+	code        *gojq.Code
+	specs       []fieldSpec
+	jsonPath    string
+	resultsOnly bool
+	count       int
+}
+
+// CanStream reports whether list output for ctx can be streamed item by item.
+// Only NDJSON streams; --sort-by needs every result before printing anything,
+// so it falls back to the buffered printer.
+func CanStream(ctx context.Context) bool {
+	if FormatFromContext(ctx) != FormatNDJSON {
+		return false
+	}
+	field, _ := SortFromContext(ctx)
+	return field == ""
+}
+
+// NewStream returns a Stream writing to w with the output options in ctx.
+func NewStream(ctx context.Context, w io.Writer) (*Stream, error) {
+	s := &Stream{
+		ctx:         ctx,
+		enc:         json.NewEncoder(w),
+		jsonPath:    strings.TrimSpace(JSONPathFromContext(ctx)),
+		resultsOnly: ResultsOnlyFromContext(ctx),
+	}
+	s.enc.SetEscapeHTML(false)
+
+	if query := QueryFromContext(ctx); query != "" {
+		query, _ = NormalizeQuery(query)
+		parsed, err := gojq.Parse(query)
+		if err != nil {
+			return nil, formatInvalidQueryErr(err)
+		}
+		s.code, err = gojq.Compile(parsed)
+		if err != nil {
+			return nil, formatInvalidQueryErr(err)
+		}
+	}
+
+	if fields := strings.TrimSpace(FieldsFromContext(ctx)); fields != "" {
+		specs, err := parseFieldSpecs(fields)
+		if err != nil {
+			return nil, clierrors.WrapUserError(err, "invalid --fields value", "Example: --fields id,name=properties.Name.title[0].plain_text")
+		}
+		s.specs = specs
+	}
+
+	return s, nil
+}
+
+// Write encodes a single result.
+func (s *Stream) Write(item interface{}) error {
+	s.count++
+
+	if s.code == nil && s.specs == nil && s.jsonPath == "" {
+		return s.enc.Encode(item)
+	}
+
+	normalized, err := normalizeToInterface(item)
+	if err != nil {
+		return err
+	}
+	if s.specs != nil {
+		normalized = projectOne(normalized, s.specs)
+	}
+	if s.jsonPath != "" {
+		normalized, err = applyJSONPath(normalized, s.jsonPath)
+		if err != nil {
+			return err
+		}
+	}
+	if s.code == nil {
+		return s.enc.Encode(normalized)
+	}
+
+	iter := s.code.Run(normalized)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if queryErr, isErr := v.(error); isErr {
+			return fmt.Errorf("query error: %s", safeErrorMessage(queryErr))
+		}
+		if err := s.enc.Encode(v); err != nil {
+			return err
+		}
+	}
+}
+
+// Count returns the number of results written so far.
+func (s *Stream) Count() int {
+	return s.count
+}
+
+// Close finishes the stream with the cursor to resume from. It is safe to
+// call after a failed fetch, so an interrupted run still reports where it
+// stopped.
+func (s *Stream) Close(nextCursor string, hasMore bool) error {
+	if !s.resultsOnly {
+		var cursor interface{}
+		if nextCursor != "" {
+			cursor = nextCursor
+		}
+		if err := s.enc.Encode(map[string]interface{}{
+			"_meta": map[string]interface{}{
+				"fetched_count": s.count,
+				"has_more":      hasMore,
+				"next_cursor":   cursor,
+				"timestamp":     time.Now().UTC().Format(time.RFC3339),
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if s.count == 0 && FailEmptyFromContext(s.ctx) {
+		return clierrors.NewUserError("no results", "Remove --fail-empty to allow empty output")
+	}
+	return nil
+}
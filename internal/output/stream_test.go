@@ -0,0 +1,95 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStream_AppliesFieldsPerItem(t *testing.T) {
+	ctx := WithFields(context.Background(), "id,name=properties.Name")
+	var buf bytes.Buffer
+	s, err := NewStream(ctx, &buf)
+	if err != nil {
+		t.Fatalf("NewStream: %v", err)
+	}
+	for _, item := range []map[string]interface{}{
+		{"id": "a", "properties": map[string]interface{}{"Name": "One"}},
+		{"id": "b"},
+	} {
+		if err := s.Write(item); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := s.Close("cur", true); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	if lines[0] != `{"id":"a","name":"One"}` || lines[1] != `{"id":"b","name":null}` {
+		t.Errorf("unexpected items:\n%s", buf.String())
+	}
+
+	var trailer map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[2]), &trailer); err != nil {
+		t.Fatalf("decode trailer: %v", err)
+	}
+	meta := trailer["_meta"]
+	if meta["next_cursor"] != "cur" || meta["has_more"] != true || meta["fetched_count"] != float64(2) {
+		t.Errorf("trailer = %v", meta)
+	}
+}
+
+func TestStream_QueryAndResultsOnly(t *testing.T) {
+	ctx := WithResultsOnly(WithQuery(context.Background(), "select(.n > 1) | .n"), true)
+	var buf bytes.Buffer
+	s, err := NewStream(ctx, &buf)
+	if err != nil {
+		t.Fatalf("NewStream: %v", err)
+	}
+	for _, n := range []int{1, 2, 3} {
+		if err := s.Write(map[string]interface{}{"n": n}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := s.Close("", false); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := buf.String(); got != "2\n3\n" {
+		t.Errorf("output = %q, want 2 and 3 without a trailer", got)
+	}
+}
+
+func TestStream_InvalidQuery(t *testing.T) {
+	if _, err := NewStream(WithQuery(context.Background(), ".["), &bytes.Buffer{}); err == nil {
+		t.Fatal("expected invalid query error")
+	}
+}
+
+func TestStream_FailEmpty(t *testing.T) {
+	s, err := NewStream(WithFailEmpty(context.Background(), true), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close("", false); err == nil {
+		t.Fatal("expected --fail-empty error")
+	}
+}
+
+func TestCanStream(t *testing.T) {
+	ctx := WithFormat(context.Background(), FormatNDJSON)
+	if !CanStream(ctx) {
+		t.Error("ndjson should stream")
+	}
+	if CanStream(WithSort(ctx, "created_time", true)) {
+		t.Error("--sort-by needs buffered output")
+	}
+	if CanStream(WithFormat(context.Background(), FormatJSON)) {
+		t.Error("json should not stream")
+	}
+}
@@ -0,0 +1,338 @@
+package output
+
+import (
+	"context"
+	"strings"
+
+	"github.com/muesli/termenv"
+	"github.com/rivo/uniseg"
+)
+
+// TerminalOptions describe the terminal -o table renders to. Commands attach
+// them with WithTerminal when stdout is a terminal (or colour is forced);
+// without them -o table keeps the plain tab-aligned layout.
+type TerminalOptions struct {
+	// Width is the terminal width in columns. Zero disables fitting.
+	Width int
+	// Color enables ANSI colours for headers and select/status options.
+	Color bool
+	// Wrap wraps long cells onto several lines instead of truncating them.
+	Wrap bool
+}
+
+type terminalKey struct{}
+
+// WithTerminal enables the width-aware table renderer for -o table.
+func WithTerminal(ctx context.Context, opts TerminalOptions) context.Context {
+	return context.WithValue(ctx, terminalKey{}, opts)
+}
+
+// TerminalFromContext returns the terminal options, if any were attached.
+func TerminalFromContext(ctx context.Context) (TerminalOptions, bool) {
+	opts, ok := ctx.Value(terminalKey{}).(TerminalOptions)
+	return opts, ok
+}
+
+const (
+	tableGap         = 2
+	minColumnWidth   = 6
+	ellipsis         = "…"
+	colorBackgrounds = "_background"
+)
+
+// notionColors maps Notion option colours to ANSI 256-colour codes.
+var notionColors = map[string]string{
+	"gray":   "245",
+	"brown":  "130",
+	"orange": "208",
+	"yellow": "178",
+	"green":  "35",
+	"blue":   "33",
+	"purple": "135",
+	"pink":   "205",
+	"red":    "196",
+}
+
+// glyph is one grapheme cluster of a cell with its display width and the
+// Notion colour it is painted in.
+type glyph struct {
+	s     string
+	w     int
+	color string
+}
+
+type segment struct {
+	text  string
+	color string
+}
+
+// printTerminalTable renders rows as a table fitted to the terminal width.
+// Columns wider than their share are truncated with an ellipsis, or wrapped
+// with Wrap, and select, multi_select and status values are painted in their
+// Notion colours when Color is set.
+func (p *Printer) printTerminalTable(ctx context.Context, data interface{}, opts TerminalOptions) error {
+	var headers []string
+	var cells [][][]glyph
+
+	switch t := data.(type) {
+	case *Table:
+		if t == nil {
+			return nil
+		}
+		data = *t
+	}
+	if t, ok := data.(Table); ok {
+		headers = t.Headers
+		for _, row := range t.Rows {
+			line := make([][]glyph, len(row))
+			for i, cell := range row {
+				line[i] = toGlyphs([]segment{{text: cell}})
+			}
+			cells = append(cells, line)
+		}
+	} else {
+		columns, rows, err := flattenRows(ctx, data)
+		if err != nil || len(columns) == 0 {
+			return err
+		}
+		for _, col := range columns {
+			headers = append(headers, strings.ToUpper(col))
+		}
+		for _, row := range rows {
+			line := make([][]glyph, len(columns))
+			for i, col := range columns {
+				line[i] = toGlyphs(cellSegments(row[col]))
+			}
+			cells = append(cells, line)
+		}
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = uniseg.StringWidth(h)
+	}
+	for _, row := range cells {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], glyphsWidth(cell))
+			}
+		}
+	}
+	fitWidths(widths, opts.Width)
+
+	var b strings.Builder
+	header := make([][]glyph, len(headers))
+	for i, h := range headers {
+		header[i] = toGlyphs([]segment{{text: h}})
+	}
+	writeTableRow(&b, header, widths, opts, true)
+	for _, row := range cells {
+		writeTableRow(&b, row, widths, opts, false)
+	}
+	_, err := p.w.Write([]byte(b.String()))
+	return err
+}
+
+// fitWidths shrinks the widest columns until the table fits in total,
+// keeping every column at least minColumnWidth wide (or its natural width).
+func fitWidths(widths []int, total int) {
+	if total <= 0 || len(widths) == 0 {
+		return
+	}
+	used := tableGap * (len(widths) - 1)
+	for _, w := range widths {
+		used += w
+	}
+	for used > total {
+		widest := -1
+		for i, w := range widths {
+			if w > minColumnWidth && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		used--
+	}
+}
+
+func writeTableRow(b *strings.Builder, row [][]glyph, widths []int, opts TerminalOptions, header bool) {
+	lines := make([][][]glyph, len(widths))
+	height := 1
+	for i := range widths {
+		var cell []glyph
+		if i < len(row) {
+			cell = row[i]
+		}
+		if opts.Wrap {
+			lines[i] = wrapGlyphs(cell, widths[i])
+		} else {
+			lines[i] = [][]glyph{truncateGlyphs(cell, widths[i])}
+		}
+		height = max(height, len(lines[i]))
+	}
+
+	for l := 0; l < height; l++ {
+		var line strings.Builder
+		pad := 0
+		for i := range widths {
+			if i > 0 {
+				line.WriteString(strings.Repeat(" ", pad+tableGap))
+			}
+			var part []glyph
+			if l < len(lines[i]) {
+				part = lines[i][l]
+			}
+			line.WriteString(paintGlyphs(part, opts.Color, header))
+			pad = widths[i] - glyphsWidth(part)
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteString("\n")
+	}
+}
+
+// cellSegments flattens a value like cellText, keeping the colour of each
+// select, multi_select or status option.
+func cellSegments(v interface{}) []segment {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return []segment{{text: cellText(v)}}
+	}
+	switch m["type"] {
+	case "select", "status":
+		if opt, ok := m[m["type"].(string)].(map[string]interface{}); ok {
+			return []segment{optionSegment(opt)}
+		}
+	case "multi_select":
+		opts, _ := m["multi_select"].([]interface{})
+		var segs []segment
+		for i, o := range opts {
+			opt, ok := o.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if i > 0 {
+				segs = append(segs, segment{text: csvListSeparator})
+			}
+			segs = append(segs, optionSegment(opt))
+		}
+		return segs
+	}
+	return []segment{{text: cellText(v)}}
+}
+
+func optionSegment(opt map[string]interface{}) segment {
+	name, _ := opt["name"].(string)
+	color, _ := opt["color"].(string)
+	return segment{text: name, color: strings.TrimSuffix(color, colorBackgrounds)}
+}
+
+func toGlyphs(segs []segment) []glyph {
+	var out []glyph
+	for _, seg := range segs {
+		text := tsvCellReplacer.Replace(seg.text)
+		g := uniseg.NewGraphemes(text)
+		for g.Next() {
+			out = append(out, glyph{s: g.Str(), w: g.Width(), color: seg.color})
+		}
+	}
+	return out
+}
+
+func glyphsWidth(gs []glyph) int {
+	n := 0
+	for _, g := range gs {
+		n += g.w
+	}
+	return n
+}
+
+// truncateGlyphs cuts gs to width, ending with an ellipsis when shortened.
+func truncateGlyphs(gs []glyph, width int) []glyph {
+	if glyphsWidth(gs) <= width {
+		return gs
+	}
+	var out []glyph
+	used := 0
+	for _, g := range gs {
+		if used+g.w > width-1 {
+			break
+		}
+		out = append(out, g)
+		used += g.w
+	}
+	color := ""
+	if len(out) > 0 {
+		color = out[len(out)-1].color
+	}
+	return append(out, glyph{s: ellipsis, w: 1, color: color})
+}
+
+// wrapGlyphs breaks gs into lines of at most width, preferring to break
+// after a space.
+func wrapGlyphs(gs []glyph, width int) [][]glyph {
+	if width <= 0 || glyphsWidth(gs) <= width {
+		return [][]glyph{gs}
+	}
+	var lines [][]glyph
+	for len(gs) > 0 {
+		used, cut, lastSpace := 0, 0, -1
+		for cut < len(gs) && used+gs[cut].w <= width {
+			if gs[cut].s == " " {
+				lastSpace = cut
+			}
+			used += gs[cut].w
+			cut++
+		}
+		if cut == len(gs) {
+			lines = append(lines, gs)
+			break
+		}
+		if cut == 0 {
+			cut = 1
+		}
+		next := cut
+		if lastSpace > 0 {
+			cut, next = lastSpace, lastSpace+1
+		}
+		lines = append(lines, gs[:cut])
+		gs = gs[next:]
+	}
+	return lines
+}
+
+// paintGlyphs renders gs, colouring runs of the same Notion colour.
+func paintGlyphs(gs []glyph, color, header bool) string {
+	if !color {
+		var b strings.Builder
+		for _, g := range gs {
+			b.WriteString(g.s)
+		}
+		return b.String()
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(gs); {
+		j := i
+		var run strings.Builder
+		for j < len(gs) && gs[j].color == gs[i].color {
+			run.WriteString(gs[j].s)
+			j++
+		}
+		style := termenv.ANSI256.String(run.String())
+		if header {
+			style = style.Bold()
+		}
+		if code, ok := notionColors[gs[i].color]; ok {
+			style = style.Foreground(termenv.ANSI256.Color(code))
+		}
+		b.WriteString(style.String())
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const terminalTestPages = `{"object":"list","results":[
+ {"object":"page","id":"p1","properties":{
+  "Name":{"type":"title","title":[{"plain_text":"A very long task name that will not fit"}]},
+  "Status":{"type":"status","status":{"name":"Done","color":"green"}},
+  "Tags":{"type":"multi_select","multi_select":[{"name":"x","color":"red"},{"name":"y","color":"blue_background"}]}}}
+]}`
+
+func printTerminalForTest(t *testing.T, opts TerminalOptions) string {
+	t.Helper()
+	var data interface{}
+	if err := json.Unmarshal([]byte(terminalTestPages), &data); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	ctx := WithTerminal(WithFormat(context.Background(), FormatTable), opts)
+	var buf bytes.Buffer
+	if err := NewPrinter(&buf, FormatTable).Print(ctx, data); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	return buf.String()
+}
+
+func TestTerminalTable_TruncatesToWidth(t *testing.T) {
+	got := printTerminalForTest(t, TerminalOptions{Width: 40})
+	lines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines:\n%s", len(lines), got)
+	}
+	for _, line := range lines {
+		if w := len([]rune(line)); w > 40 {
+			t.Errorf("line %q is %d wide, want <= 40", line, w)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "ID  NAME") || !strings.Contains(lines[1], "…") || !strings.HasSuffix(lines[1], "x; y") {
+		t.Errorf("unexpected table:\n%s", got)
+	}
+}
+
+func TestTerminalTable_Wraps(t *testing.T) {
+	got := printTerminalForTest(t, TerminalOptions{Width: 40, Wrap: true})
+	if strings.Contains(got, "…") {
+		t.Errorf("wrapped table should not truncate:\n%s", got)
+	}
+	if !strings.Contains(got, "fit") || strings.Count(got, "\n") < 3 {
+		t.Errorf("expected the name to wrap onto several lines:\n%s", got)
+	}
+}
+
+func TestTerminalTable_ColoursOptions(t *testing.T) {
+	got := printTerminalForTest(t, TerminalOptions{Color: true})
+	if !strings.Contains(got, "\x1b[1mSTATUS") {
+		t.Errorf("header should be bold: %q", got)
+	}
+	for _, want := range []string{"38;5;35mDone", "38;5;196mx", "38;5;33my"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+
+	plain := printTerminalForTest(t, TerminalOptions{})
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("colour disabled but got escapes: %q", plain)
+	}
+}
+
+func TestFitWidths(t *testing.T) {
+	widths := []int{40, 4, 20}
+	fitWidths(widths, 40)
+	if got := widths[0] + widths[1] + widths[2] + 2*tableGap; got != 40 {
+		t.Errorf("fitted total = %d (%v), want 40", got, widths)
+	}
+	if widths[1] != 4 {
+		t.Errorf("narrow column shrank: %v", widths)
+	}
+}
@@ -39,10 +39,11 @@ func applyOutputTransforms(ctx context.Context, data interface{}, format Format)
 		return data, nil
 	}
 
-	if format == FormatTable {
+	// A table needs rows; --jsonpath may extract anything.
+	if format == FormatTable && jsonPathRaw != "" {
 		return nil, clierrors.NewUserError(
-			"--fields/--jsonpath are not supported with table output",
-			"Use --output json|ndjson|jsonl|yaml|text instead",
+			"--jsonpath is not supported with table output",
+			"Use --fields to pick table columns, or --output json|ndjson|jsonl|yaml|text",
 		)
 	}
 
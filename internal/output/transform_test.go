@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 
 	clierrors "github.com/salmonumbrella/notion-cli/internal/errors"
@@ -267,3 +268,31 @@ func TestPrinter_FailEmpty(t *testing.T) {
 		t.Fatalf("expected user error, got %T", err)
 	}
 }
+
+func TestPrinter_TableFields(t *testing.T) {
+	data := []interface{}{
+		map[string]interface{}{"id": "p1", "name": "Alpha", "extra": "x"},
+		map[string]interface{}{"id": "p2", "name": "Beta", "extra": "y"},
+	}
+	ctx := WithFields(WithFormat(context.Background(), FormatTable), "name,id")
+
+	var plain bytes.Buffer
+	if err := NewPrinter(&plain, FormatTable).Print(ctx, data); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	if got := strings.Fields(strings.SplitN(plain.String(), "\n", 2)[0]); !reflect.DeepEqual(got, []string{"NAME", "ID"}) {
+		t.Errorf("plain table columns = %q:\n%s", got, plain.String())
+	}
+
+	var term bytes.Buffer
+	if err := NewPrinter(&term, FormatTable).Print(WithTerminal(ctx, TerminalOptions{Width: 80}), data); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	if got := strings.Fields(strings.SplitN(term.String(), "\n", 2)[0]); !reflect.DeepEqual(got, []string{"NAME", "ID"}) {
+		t.Errorf("terminal table columns = %q:\n%s", got, term.String())
+	}
+
+	if err := NewPrinter(&bytes.Buffer{}, FormatTable).Print(WithJSONPath(ctx, "$[0].id"), data); err == nil {
+		t.Error("expected --jsonpath to be rejected for table output")
+	}
+}
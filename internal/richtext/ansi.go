@@ -0,0 +1,99 @@
+package richtext
+
+import (
+	"strings"
+
+	"github.com/muesli/termenv"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+// TerminalColors maps Notion annotation and block colours to ANSI 256-colour
+// codes. Background variants ("red_background") use the same code as a
+// background.
+var TerminalColors = map[string]string{
+	"gray":   "245",
+	"brown":  "130",
+	"orange": "208",
+	"yellow": "178",
+	"green":  "35",
+	"blue":   "33",
+	"purple": "135",
+	"pink":   "205",
+	"red":    "196",
+}
+
+// codeColor is the colour of inline code spans.
+const codeColor = "203"
+
+// ANSI renders rich text for a terminal using profile: bold, italic,
+// underline, strikethrough and colour annotations become ANSI styles, inline
+// code is highlighted, and links are underlined with their URL after the
+// text. With termenv.Ascii the result is plain text with link URLs kept.
+func ANSI(items []notion.RichText, profile termenv.Profile) string {
+	var b strings.Builder
+	for _, item := range items {
+		text := notion.PlainText([]notion.RichText{item})
+		if text == "" {
+			continue
+		}
+
+		linkURL := item.Href
+		if linkURL == "" && item.Text != nil && item.Text.Link != nil {
+			linkURL = item.Text.Link.URL
+		}
+		// Page and user mentions carry an API URL as href; their text is
+		// already the readable name.
+		if item.Mention != nil && item.Mention.LinkPreview == nil {
+			linkURL = ""
+		}
+
+		var ann notion.Annotations
+		if item.Annotations != nil {
+			ann = *item.Annotations
+		}
+
+		style := profile.String(text)
+		if ann.Bold {
+			style = style.Bold()
+		}
+		if ann.Italic {
+			style = style.Italic()
+		}
+		if ann.Underline || linkURL != "" {
+			style = style.Underline()
+		}
+		if ann.Strikethrough {
+			style = style.CrossOut()
+		}
+		if ann.Code {
+			style = style.Foreground(profile.Color(codeColor))
+		}
+		style = colorStyle(style, profile, ann.Color)
+		b.WriteString(style.String())
+
+		if linkURL != "" && linkURL != text {
+			b.WriteString(profile.String(" (" + linkURL + ")").Faint().String())
+		}
+	}
+	return b.String()
+}
+
+// Colorize paints text in a Notion colour such as "blue" or
+// "yellow_background". Unknown colours and "default" leave text unchanged.
+func Colorize(text, color string, profile termenv.Profile) string {
+	return colorStyle(profile.String(text), profile, color).String()
+}
+
+func colorStyle(style termenv.Style, profile termenv.Profile, color string) termenv.Style {
+	if name, ok := strings.CutSuffix(color, "_background"); ok {
+		if code, ok := TerminalColors[name]; ok {
+			return style.Background(profile.Color(code))
+		}
+		return style
+	}
+	if code, ok := TerminalColors[color]; ok {
+		return style.Foreground(profile.Color(code))
+	}
+	return style
+}
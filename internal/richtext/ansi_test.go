@@ -0,0 +1,38 @@
+package richtext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+
+	"github.com/salmonumbrella/notion-cli/internal/notion"
+)
+
+func TestANSI(t *testing.T) {
+	items := []notion.RichText{
+		{PlainText: "Hello ", Annotations: &notion.Annotations{Bold: true}},
+		{PlainText: "red", Annotations: &notion.Annotations{Color: "red"}},
+		{PlainText: " docs", Href: "https://example.invalid/docs"},
+	}
+
+	if got, want := ANSI(items, termenv.Ascii), "Hello red docs (https://example.invalid/docs)"; got != want {
+		t.Errorf("ANSI(Ascii) = %q, want %q", got, want)
+	}
+
+	got := ANSI(items, termenv.ANSI256)
+	for _, want := range []string{"\x1b[1mHello \x1b[0m", "\x1b[38;5;196mred\x1b[0m", "\x1b[4m docs\x1b[0m"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ANSI(ANSI256) = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestColorize(t *testing.T) {
+	if got := Colorize("x", "blue_background", termenv.ANSI256); got != "\x1b[48;5;33mx\x1b[0m" {
+		t.Errorf("background = %q", got)
+	}
+	if got := Colorize("x", "default", termenv.ANSI256); got != "x" {
+		t.Errorf("default = %q", got)
+	}
+}